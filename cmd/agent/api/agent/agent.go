@@ -70,6 +70,12 @@ func SetupHandlers(r *mux.Router) *mux.Router {
 	r.HandleFunc("/workload-list/short", getShortWorkloadList).Methods("GET")
 	r.HandleFunc("/workload-list/verbose", getVerboseWorkloadList).Methods("GET")
 	r.HandleFunc("/secrets", secretInfo).Methods("GET")
+	// [sts] transactional check inspection
+	r.HandleFunc("/transactions", getTransactions).Methods("GET")
+	r.HandleFunc("/transactions/{id}", getTransaction).Methods("GET")
+	r.HandleFunc("/check-state/{check}", getCheckState).Methods("GET")
+	r.HandleFunc("/check-state/{check}", setCheckState).Methods("POST")
+	r.HandleFunc("/check-state/{check}", clearCheckState).Methods("DELETE")
 
 	return r
 }
@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"github.com/StackVista/stackstate-agent/cmd/agent/api/response"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+func getTransactions(w http.ResponseWriter, r *http.Request) {
+	txManager := transactionmanager.GetTransactionManager()
+	if txManager == nil {
+		writeJSONError(w, fmt.Errorf("check transactionality is disabled"), http.StatusNotFound)
+		return
+	}
+
+	checkID := r.URL.Query().Get("check")
+	transactions := make([]transactionmanager.TransactionSnapshot, 0)
+	for _, transaction := range txManager.Transactions() {
+		if checkID == "" || string(transaction.CheckID) == checkID {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	writeJSON(w, response.TransactionsResponse{Transactions: transactions})
+}
+
+func getTransaction(w http.ResponseWriter, r *http.Request) {
+	txManager := transactionmanager.GetTransactionManager()
+	if txManager == nil {
+		writeJSONError(w, fmt.Errorf("check transactionality is disabled"), http.StatusNotFound)
+		return
+	}
+
+	transactionID := mux.Vars(r)["id"]
+	for _, transaction := range txManager.Transactions() {
+		if transaction.TransactionID == transactionID {
+			writeJSON(w, transaction)
+			return
+		}
+	}
+
+	writeJSONError(w, transactionmanager.TransactionNotFound{TransactionID: transactionID}, http.StatusNotFound)
+}
+
+func getCheckState(w http.ResponseWriter, r *http.Request) {
+	checkStateManager := state.GetCheckStateManager()
+	if checkStateManager == nil {
+		writeJSONError(w, fmt.Errorf("check state manager is not initialized"), http.StatusNotFound)
+		return
+	}
+
+	check := mux.Vars(r)["check"]
+	states := make(map[string]string)
+	if key := r.URL.Query().Get("key"); key != "" {
+		value, err := checkStateManager.GetState(checkStateKey(check, key))
+		if err != nil {
+			writeJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+		states[checkStateKey(check, key)] = value
+	} else {
+		var err error
+		states, err = checkStateManager.ListStates(check)
+		if err != nil {
+			writeJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeJSON(w, response.CheckStateResponse{Check: check, States: states})
+}
+
+func setCheckState(w http.ResponseWriter, r *http.Request) {
+	checkStateManager := state.GetCheckStateManager()
+	if checkStateManager == nil {
+		writeJSONError(w, fmt.Errorf("check state manager is not initialized"), http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeJSONError(w, fmt.Errorf("error while reading HTTP request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	var request response.SetCheckStateRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeJSONError(w, fmt.Errorf("error while unmarshaling JSON from request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	if request.Key == "" {
+		writeJSONError(w, fmt.Errorf("a state key is required"), http.StatusBadRequest)
+		return
+	}
+
+	check := mux.Vars(r)["check"]
+	key := checkStateKey(check, request.Key)
+	log.Infof("Setting state %s for check %s through the agent API", key, check)
+	if err := checkStateManager.SetState(key, request.State); err != nil {
+		writeJSONError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, response.CheckStateResponse{Check: check, States: map[string]string{key: request.State}})
+}
+
+func clearCheckState(w http.ResponseWriter, r *http.Request) {
+	checkStateManager := state.GetCheckStateManager()
+	if checkStateManager == nil {
+		writeJSONError(w, fmt.Errorf("check state manager is not initialized"), http.StatusNotFound)
+		return
+	}
+
+	check := mux.Vars(r)["check"]
+	var keys []string
+	if key := r.URL.Query().Get("key"); key != "" {
+		keys = []string{checkStateKey(check, key)}
+	} else {
+		states, err := checkStateManager.ListStates(check)
+		if err != nil {
+			writeJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+		for key := range states {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	cleared := make(map[string]string, len(keys))
+	for _, key := range keys {
+		log.Infof("Clearing state %s for check %s through the agent API", key, check)
+		if err := checkStateManager.DeleteState(key); err != nil {
+			writeJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+		cleared[key] = ""
+	}
+
+	writeJSON(w, response.CheckStateResponse{Check: check, States: cleared})
+}
+
+// checkStateKey returns the check state manager key of a state of a check
+func checkStateKey(check, key string) string {
+	return check + ":" + key
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("Unable to marshal response: %s", err)
+		writeJSONError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func writeJSONError(w http.ResponseWriter, err error, code int) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	http.Error(w, string(body), code)
+}
@@ -7,6 +7,7 @@ package response
 
 import (
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
 )
 
 // ConfigCheckResponse holds the config check response
@@ -26,3 +27,23 @@ type TaggerListResponse struct {
 type TaggerListEntity struct {
 	Tags map[string][]string `json:"tags"`
 }
+
+// TransactionsResponse holds the transactions of the transaction manager
+// [sts]
+type TransactionsResponse struct {
+	Transactions []transactionmanager.TransactionSnapshot `json:"transactions"`
+}
+
+// CheckStateResponse holds the persisted states of a check
+// [sts]
+type CheckStateResponse struct {
+	Check  string            `json:"check"`
+	States map[string]string `json:"states"`
+}
+
+// SetCheckStateRequest holds the state to persist for a check
+// [sts]
+type SetCheckStateRequest struct {
+	Key   string `json:"key"`
+	State string `json:"state"`
+}
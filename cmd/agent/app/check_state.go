@@ -0,0 +1,152 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/StackVista/stackstate-agent/cmd/agent/api/response"
+	"github.com/StackVista/stackstate-agent/pkg/api/util"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	AgentCmd.AddCommand(checkStateCommand)
+	checkStateCommand.AddCommand(checkStateGetCommand, checkStateSetCommand, checkStateClearCommand)
+}
+
+var checkStateCommand = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and change the persisted state of checks of a running agent",
+	Long: `Inspect and change the persisted state of checks of a running agent. States are stored with a key in the
+form of <check>:<key>.`,
+}
+
+var checkStateGetCommand = &cobra.Command{
+	Use:   "get <check> [key]",
+	Short: "Print the persisted states of a check, or a single state when a key is given",
+	Long:  ``,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if len(args) == 2 {
+			query.Set("key", args[1])
+		}
+		body, err := doAgentGet("/agent/check-state/"+url.PathEscape(args[0]), query)
+		if err != nil {
+			return err
+		}
+
+		return printCheckState(body)
+	},
+}
+
+var checkStateSetCommand = &cobra.Command{
+	Use:   "set <check> <key> <state>",
+	Short: "Persist a state for a check",
+	Long: `Persist a state for a check. The state is read by the check on its next run, a check run that is in progress
+may overwrite it when its transaction completes.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		request, err := json.Marshal(response.SetCheckStateRequest{Key: args[1], State: args[2]})
+		if err != nil {
+			return err
+		}
+		endpoint, err := agentURL("/agent/check-state/"+url.PathEscape(args[0]), nil)
+		if err != nil {
+			return err
+		}
+		if err := util.SetAuthToken(); err != nil {
+			return err
+		}
+
+		c := util.GetClient(false) // FIX: get certificates right then make this true
+		body, err := util.DoPost(c, endpoint, "application/json", bytes.NewBuffer(request))
+		if err := agentRequestError(body, err); err != nil {
+			return err
+		}
+
+		return printCheckState(body)
+	},
+}
+
+var checkStateClearCommand = &cobra.Command{
+	Use:   "clear <check> [key]",
+	Short: "Remove the persisted states of a check, or a single state when a key is given",
+	Long:  ``,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if len(args) == 2 {
+			query.Set("key", args[1])
+		}
+		endpoint, err := agentURL("/agent/check-state/"+url.PathEscape(args[0]), query)
+		if err != nil {
+			return err
+		}
+		if err := util.SetAuthToken(); err != nil {
+			return err
+		}
+
+		c := util.GetClient(false) // FIX: get certificates right then make this true
+		body, err := util.DoDelete(c, endpoint)
+		if err := agentRequestError(body, err); err != nil {
+			return err
+		}
+
+		var checkState response.CheckStateResponse
+		if err := json.Unmarshal(body, &checkState); err != nil {
+			return err
+		}
+		if len(checkState.States) == 0 {
+			fmt.Fprintf(color.Output, "No states found for check %s\n", checkState.Check)
+			return nil
+		}
+		for _, key := range sortedStateKeys(checkState.States) {
+			fmt.Fprintf(color.Output, "Cleared %s\n", color.CyanString(key))
+		}
+		return nil
+	},
+}
+
+// printCheckState prints the states of a check state response
+func printCheckState(body []byte) error {
+	var checkState response.CheckStateResponse
+	if err := json.Unmarshal(body, &checkState); err != nil {
+		return err
+	}
+
+	if len(checkState.States) == 0 {
+		fmt.Fprintf(color.Output, "No states found for check %s\n", checkState.Check)
+		return nil
+	}
+	for _, key := range sortedStateKeys(checkState.States) {
+		fmt.Fprintf(color.Output, "%s: %s\n", color.CyanString(key), checkState.States[key])
+	}
+	return nil
+}
+
+// sortedStateKeys returns the keys of the states in alphabetical order
+func sortedStateKeys(states map[string]string) []string {
+	keys := make([]string, 0, len(states))
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -79,6 +79,7 @@ import (
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/disk"
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/filehandles"
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/memory"
+	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/processtopology"
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/uptime"
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/system/winproc"
 	_ "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/systemd"
@@ -467,7 +468,8 @@ func StartAgent() error {
 		transactionbatcher.InitTransactionalBatcher(hostname, "agent", config.GetMaxCapacity())
 		handler.InitCheckManager()
 		txChannelBufferSize, txTimeoutDuration, txEvictionDuration, txTickerInterval := config.GetTxManagerConfig()
-		transactionmanager.InitTransactionManager(txChannelBufferSize, txTickerInterval, txTimeoutDuration, txEvictionDuration)
+		transactionmanager.InitJournaledTransactionManager(txChannelBufferSize, txTickerInterval, txTimeoutDuration,
+			txEvictionDuration, transactionmanager.GetJournalConfig())
 	} else {
 		state.InitCheckStateManager()
 		handler.InitCheckManager()
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+
+	"github.com/StackVista/stackstate-agent/cmd/agent/api/response"
+	"github.com/StackVista/stackstate-agent/cmd/agent/common"
+	"github.com/StackVista/stackstate-agent/pkg/api/util"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	transactionsCheckID string
+	transactionsJSON    bool
+)
+
+func init() {
+	AgentCmd.AddCommand(transactionsCommand)
+	transactionsCommand.AddCommand(transactionsListCommand, transactionsShowCommand, transactionsJournalCommand)
+	transactionsCommand.PersistentFlags().BoolVarP(&transactionsJSON, "json", "j", false, "print out the transactions as json")
+	transactionsListCommand.Flags().StringVarP(&transactionsCheckID, "check", "c", "", "only show the transactions of the given check id")
+	transactionsJournalCommand.Flags().StringVarP(&transactionsCheckID, "check", "c", "", "only show the transactions of the given check id")
+}
+
+var transactionsCommand = &cobra.Command{
+	Use:   "transactions",
+	Short: "Inspect the check transactions of the transaction manager",
+	Long:  ``,
+}
+
+var transactionsListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Print the transactions of a running agent",
+	Long: `Print the transactions of a running agent with their status and actions. Transactions that were evicted
+recently are listed as well.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if transactionsCheckID != "" {
+			query.Set("check", transactionsCheckID)
+		}
+		body, err := doAgentGet("/agent/transactions", query)
+		if err != nil {
+			return err
+		}
+
+		var transactions response.TransactionsResponse
+		if err := json.Unmarshal(body, &transactions); err != nil {
+			return err
+		}
+
+		return printTransactions(transactions.Transactions)
+	},
+}
+
+var transactionsShowCommand = &cobra.Command{
+	Use:   "show <transaction id>",
+	Short: "Print a transaction of a running agent",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		body, err := doAgentGet("/agent/transactions/"+url.PathEscape(args[0]), nil)
+		if err != nil {
+			return err
+		}
+
+		var transaction transactionmanager.TransactionSnapshot
+		if err := json.Unmarshal(body, &transaction); err != nil {
+			return err
+		}
+
+		return printTransactions([]transactionmanager.TransactionSnapshot{transaction})
+	},
+}
+
+var transactionsJournalCommand = &cobra.Command{
+	Use:   "journal",
+	Short: "Print the check transactions journaled by the transaction manager",
+	Long: `Print the check transactions and their actions from the transaction manager journal. The journal is read
+from disk, so transactions that were left behind by an agent that stopped can be inspected as well. The journal is
+enabled with transaction_manager_journal_enabled.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupTransactionalCommand(); err != nil {
+			return err
+		}
+
+		journalConfig := transactionmanager.GetJournalConfig()
+		if !journalConfig.Enabled {
+			fmt.Fprintln(color.Output, "The transaction manager journal is disabled, set transaction_manager_journal_enabled to enable it")
+			return nil
+		}
+
+		transactions, err := transactionmanager.ReadJournal(journalConfig.Path)
+		if err != nil {
+			return err
+		}
+
+		filtered := make([]transactionmanager.TransactionSnapshot, 0, len(transactions))
+		for _, transaction := range transactions {
+			if transactionsCheckID == "" || string(transaction.CheckID) == transactionsCheckID {
+				filtered = append(filtered, transaction)
+			}
+		}
+
+		return printTransactions(filtered)
+	},
+}
+
+// setupTransactionalCommand sets up the configuration and the logger for the transactions and state commands
+func setupTransactionalCommand() error {
+	if flagNoColor {
+		color.NoColor = true
+	}
+
+	err := common.SetupConfigWithoutSecrets(confFilePath, "")
+	if err != nil {
+		return fmt.Errorf("unable to set up global agent configuration: %v", err)
+	}
+
+	err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+	if err != nil {
+		fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// agentURL returns the url of an endpoint of the running agent's IPC API
+func agentURL(path string, query url.Values) (string, error) {
+	ipcAddress, err := config.GetIPCAddress()
+	if err != nil {
+		return "", err
+	}
+	endpoint := fmt.Sprintf("https://%v:%v%s", ipcAddress, config.Datadog.GetInt("cmd_port"), path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint, nil
+}
+
+// doAgentGet queries an endpoint of the running agent's IPC API
+func doAgentGet(path string, query url.Values) ([]byte, error) {
+	endpoint, err := agentURL(path, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := util.SetAuthToken(); err != nil {
+		return nil, err
+	}
+
+	c := util.GetClient(false) // FIX: get certificates right then make this true
+	body, err := util.DoGet(c, endpoint)
+	return body, agentRequestError(body, err)
+}
+
+// agentRequestError returns a readable error for a failed request to the running agent
+func agentRequestError(body []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if len(body) > 0 {
+		var errMap = make(map[string]string)
+		if json.Unmarshal(body, &errMap) == nil && errMap["error"] != "" {
+			return fmt.Errorf("the agent ran into an error: %s", errMap["error"])
+		}
+		return fmt.Errorf("the agent ran into an error: %s", string(body))
+	}
+	return fmt.Errorf("could not reach agent: %v. Make sure the agent is running before requesting this and try again", err)
+}
+
+// printTransactions prints the transactions as json or in a human readable format
+func printTransactions(transactions []transactionmanager.TransactionSnapshot) error {
+	if transactionsJSON {
+		out, err := json.MarshalIndent(transactions, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(color.Output, string(out))
+		return nil
+	}
+
+	writeTransactions(color.Output, transactions)
+	return nil
+}
+
+// writeTransactions writes a human readable overview of the transactions and their actions
+func writeTransactions(w io.Writer, transactions []transactionmanager.TransactionSnapshot) {
+	if len(transactions) == 0 {
+		fmt.Fprintln(w, "No transactions found")
+		return
+	}
+
+	for _, transaction := range transactions {
+		fmt.Fprintf(w, "%s %s\n", color.HiWhiteString("Transaction"), color.CyanString(transaction.TransactionID))
+		fmt.Fprintf(w, "  Check:        %s\n", transaction.CheckID)
+		fmt.Fprintf(w, "  Status:       %s\n", transactionStatusColor(transaction.Status))
+		fmt.Fprintf(w, "  Last updated: %s\n", transaction.LastUpdatedTimestamp.Format("2006-01-02 15:04:05 MST"))
+		if transaction.State != nil {
+			fmt.Fprintf(w, "  State:        %s\n", transaction.State.Key)
+		}
+
+		actionIDs := make([]string, 0, len(transaction.Actions))
+		outstanding := 0
+		for actionID, action := range transaction.Actions {
+			actionIDs = append(actionIDs, actionID)
+			if action.Status == transactionmanager.Committed {
+				outstanding++
+			}
+		}
+		sort.Slice(actionIDs, func(i, j int) bool {
+			return transaction.Actions[actionIDs[i]].CommittedTimestamp.Before(transaction.Actions[actionIDs[j]].CommittedTimestamp)
+		})
+
+		fmt.Fprintf(w, "  Actions:      %d (%d outstanding)\n", len(actionIDs), outstanding)
+		for _, actionID := range actionIDs {
+			action := transaction.Actions[actionID]
+			fmt.Fprintf(w, "    %s: %s, committed %s, updated %s\n", action.ActionID, action.Status,
+				action.CommittedTimestamp.Format("2006-01-02 15:04:05 MST"),
+				action.StatusUpdatedTimestamp.Format("2006-01-02 15:04:05 MST"))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// transactionStatusColor colors the transaction status for the terminal
+func transactionStatusColor(status transactionmanager.TransactionStatus) string {
+	switch status {
+	case transactionmanager.Succeeded:
+		return color.GreenString(status.String())
+	case transactionmanager.Failed, transactionmanager.Evicted:
+		return color.RedString(status.String())
+	case transactionmanager.Stale:
+		return color.YellowString(status.String())
+	default:
+		return status.String()
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"net/http"
 
 	"github.com/StackVista/stackstate-agent/cmd/agent/common"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
 	"github.com/StackVista/stackstate-agent/pkg/status"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"github.com/gorilla/mux"
@@ -45,6 +48,7 @@ func getCLCRunnerStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	s := flattenCLCStats(stats)
+	markTransactionalChecks(s) // sts
 	jsonStats, err := json.Marshal(s)
 	if err != nil {
 		log.Errorf("Error marshalling stats. Error: %v, Stats: %v", err, s)
@@ -67,3 +71,24 @@ func flattenCLCStats(stats status.CLCChecks) map[string]status.CLCStats {
 
 	return flatened
 }
+
+// sts
+// markTransactionalChecks marks the checks that are transactional and the checks that have a transaction in progress,
+// so the Cluster Agent does not move them to another runner in the middle of a transaction
+func markTransactionalChecks(stats map[string]status.CLCStats) {
+	inTransaction := make(map[check.ID]bool)
+	if txManager := transactionmanager.GetTransactionManager(); txManager != nil {
+		for _, transaction := range txManager.Transactions() {
+			if transaction.Status == transactionmanager.InProgress {
+				inTransaction[transaction.CheckID] = true
+			}
+		}
+	}
+
+	checkManager := handler.GetCheckManager()
+	for checkID, checkStats := range stats {
+		checkStats.IsTransactional = checkManager != nil && checkManager.IsTransactional(check.ID(checkID))
+		checkStats.InTransaction = inTransaction[check.ID(checkID)]
+		stats[checkID] = checkStats
+	}
+}
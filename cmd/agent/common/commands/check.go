@@ -34,6 +34,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"                      // sts
 	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"                        // sts
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/dryrun"               // sts
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionbatcher"   // sts
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionforwarder" // sts
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"   // sts
@@ -74,6 +75,8 @@ var (
 	discoveryTimeout       uint
 	discoveryRetryInterval uint
 	discoveryMinInstances  uint
+	topologyFormat         string // sts
+	topologyFile           string // sts
 )
 
 func setupCmd(cmd *cobra.Command) {
@@ -92,6 +95,9 @@ func setupCmd(cmd *cobra.Command) {
 	cmd.Flags().UintVarP(&discoveryRetryInterval, "discovery-retry-interval", "", 1, "duration between retries until Autodiscovery resolves the check template (in seconds)")
 	cmd.Flags().UintVarP(&discoveryMinInstances, "discovery-min-instances", "", 1, "minimum number of config instances to be discovered before running the check(s)")
 	config.Datadog.BindPFlag("cmd.check.fullsketches", cmd.Flags().Lookup("full-sketches")) //nolint:errcheck
+	// [sts] dry-run topology output
+	cmd.Flags().StringVar(&topologyFormat, "topology-format", "", "write the submitted topology, health and raw metrics as dot, graphml or json-summary, with a summary table, instead of printing the payloads")
+	cmd.Flags().StringVar(&topologyFile, "topology-file", "", "file to write the topology to with --topology-format (default '<check_name>-topology' with the extension of the format)")
 
 	// Power user flags - mark as hidden
 	createHiddenStringFlag(cmd, &profileMemoryDir, "m-dir", "", "an existing directory in which to store memory profiling data, ignoring clean-up")
@@ -152,12 +158,25 @@ func Check(loggerName config.LoggerName, confFilePath *string, flagNoColor *bool
 			agg := aggregator.InitAggregatorWithFlushInterval(s, eventPlatformForwarder, hostname, 0)
 			common.LoadComponents(config.Datadog.GetString("confd_path"))
 
+			// [sts] collect the submitted topology to write it as a graph instead of printing the payloads
+			var topologyCollector *dryrun.Collector
+			var format dryrun.Format
+			if topologyFormat != "" {
+				if format, err = dryrun.ParseFormat(topologyFormat); err != nil {
+					return err
+				}
+				topologyCollector = dryrun.NewCollector()
+			}
+
 			// [sts] init the batcher without the real serializer
-			batcher.InitBatcher(&printingAgentV1Serializer{}, hostname, "agent", config.GetMaxCapacity())
+			batcher.InitBatcher(&printingAgentV1Serializer{collector: topologyCollector}, hostname, "agent", config.GetMaxCapacity())
 			// [sts] create the global transactional components
 			state.InitCheckStateManager()
 			handler.InitCheckManager()
-			transactionforwarder.NewPrintingTransactionalForwarder() // use the printing transactional forwarder for the agent check command
+			printingForwarder := transactionforwarder.NewPrintingTransactionalForwarder() // use the printing transactional forwarder for the agent check command
+			if topologyCollector != nil {
+				printingForwarder.Collect = topologyCollector.Add
+			}
 			transactionbatcher.InitTransactionalBatcher(hostname, "agent", config.GetMaxCapacity())
 			txChannelBufferSize, txTimeoutDuration, txEvictionDuration, txTickerInterval := config.GetTxManagerConfig()
 			transactionmanager.InitTransactionManager(txChannelBufferSize, txTickerInterval, txTimeoutDuration, txEvictionDuration)
@@ -420,6 +439,13 @@ func Check(loggerName config.LoggerName, confFilePath *string, flagNoColor *bool
 				}
 			}
 
+			// [sts] write the collected topology
+			if topologyCollector != nil {
+				if err := writeTopology(topologyCollector, format, &checkFileOutput); err != nil {
+					return err
+				}
+			}
+
 			if runtime.GOOS == "windows" {
 				standalone.PrintWindowsUserWarning("check")
 			}
@@ -485,15 +511,53 @@ func runCheck(c check.Check, agg *aggregator.BufferedAggregator) *check.Stats {
 }
 
 // sts begin
-type printingAgentV1Serializer struct{}
+type printingAgentV1Serializer struct {
+	// collector receives the payloads instead of them being printed when it is set
+	collector *dryrun.Collector
+}
+
+func (s printingAgentV1Serializer) SendJSONToV1Intake(data interface{}) error {
+	if s.collector != nil {
+		body, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return s.collector.AddJSON(body)
+	}
 
-func (printingAgentV1Serializer) SendJSONToV1Intake(data interface{}) error {
 	fmt.Fprintln(color.Output, fmt.Sprintf("=== %s ===", color.BlueString("Topology")))
 	j, _ := json.MarshalIndent(data, "", "  ")
 	fmt.Println(string(j))
 	return nil
 }
 
+// writeTopology writes the collected topology to the topology file in the given format and prints its summary
+func writeTopology(collector *dryrun.Collector, format dryrun.Format, checkFileOutput *bytes.Buffer) error {
+	path := topologyFile
+	if path == "" {
+		path = checkName + "-topology" + format.Extension()
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create the topology file: %s", err)
+	}
+	defer f.Close()
+	if err := collector.WriteGraph(f, format); err != nil {
+		return fmt.Errorf("could not write the topology file: %s", err)
+	}
+
+	var summary bytes.Buffer
+	if err := collector.Summary().WriteTable(&summary); err != nil {
+		return err
+	}
+	fmt.Fprintln(color.Output, fmt.Sprintf("=== %s ===", color.BlueString("Topology")))
+	fmt.Println(summary.String())
+	fmt.Println("topology written to:", path)
+	checkFileOutput.WriteString("=== Topology ===\n" + summary.String() + "\n")
+	return nil
+}
+
 // sts end
 
 func printMetrics(agg *aggregator.BufferedAggregator, checkFileOutput *bytes.Buffer) {
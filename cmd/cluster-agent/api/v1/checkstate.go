@@ -0,0 +1,138 @@
+//go:build clusterchecks
+// +build clusterchecks
+
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent"
+	apiv1 "github.com/StackVista/stackstate-agent/pkg/clusteragent/api/v1"
+)
+
+// installCheckStateEndpoints registers the v1 API endpoints of the check state store, the key of a state is passed
+// as a query parameter as it can contain any character
+func installCheckStateEndpoints(r *mux.Router, sc clusteragent.ServerContext) {
+	r.HandleFunc("/checkstate/states", getCheckStates(sc)).Methods("GET")
+	r.HandleFunc("/checkstate/state", getCheckState(sc)).Methods("GET")
+	r.HandleFunc("/checkstate/state", postCheckState(sc)).Methods("POST")
+	r.HandleFunc("/checkstate/state", deleteCheckState(sc)).Methods("DELETE")
+}
+
+// getCheckStates is used by the node-agent's cluster agent check state manager to list the states of a check
+func getCheckStates(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.CheckStateStore == nil {
+		return checkStateDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		check := r.URL.Query().Get("check")
+		if check == "" {
+			http.Error(w, "missing check", http.StatusBadRequest)
+			incrementRequestMetric("getCheckStates", http.StatusBadRequest)
+			return
+		}
+
+		states, err := sc.CheckStateStore.ListStates(check)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("getCheckStates", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, apiv1.CheckStatesResponse{Check: check, States: states}, "getCheckStates")
+	}
+}
+
+// getCheckState is used by the node-agent's cluster agent check state manager to get the state of a key
+func getCheckState(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.CheckStateStore == nil {
+		return checkStateDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := checkStateKey(w, r, "getCheckState")
+		if !ok {
+			return
+		}
+
+		value, err := sc.CheckStateStore.GetState(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("getCheckState", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, apiv1.CheckStateResponse{Key: key, State: value}, "getCheckState")
+	}
+}
+
+// postCheckState is used by the node-agent's cluster agent check state manager to store the state of a key
+func postCheckState(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.CheckStateStore == nil {
+		return checkStateDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := checkStateKey(w, r, "postCheckState")
+		if !ok {
+			return
+		}
+
+		var request apiv1.CheckStateRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			incrementRequestMetric("postCheckState", http.StatusBadRequest)
+			return
+		}
+
+		if err := sc.CheckStateStore.SetState(key, request.State); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("postCheckState", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, apiv1.CheckStateResponse{Key: key, State: request.State}, "postCheckState")
+	}
+}
+
+// deleteCheckState is used by the node-agent's cluster agent check state manager to remove the state of a key
+func deleteCheckState(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.CheckStateStore == nil {
+		return checkStateDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := checkStateKey(w, r, "deleteCheckState")
+		if !ok {
+			return
+		}
+
+		if err := sc.CheckStateStore.DeleteState(key); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("deleteCheckState", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, apiv1.CheckStateResponse{Key: key}, "deleteCheckState")
+	}
+}
+
+// checkStateKey returns the key query parameter of a request, a bad request is written when it is missing
+func checkStateKey(w http.ResponseWriter, r *http.Request, handler string) (string, bool) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key", http.StatusBadRequest)
+		incrementRequestMetric(handler, http.StatusBadRequest)
+		return "", false
+	}
+	return key, true
+}
+
+func checkStateDisabledHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("The check state store is not enabled")) //nolint:errcheck
+}
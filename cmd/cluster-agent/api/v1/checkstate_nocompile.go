@@ -0,0 +1,12 @@
+//go:build !clusterchecks
+// +build !clusterchecks
+
+package v1
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent"
+	"github.com/gorilla/mux"
+)
+
+// installCheckStateEndpoints not implemented
+func installCheckStateEndpoints(_ *mux.Router, _ clusteragent.ServerContext) {}
@@ -0,0 +1,74 @@
+//go:build clusterchecks
+// +build clusterchecks
+
+package v1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent"
+	apiv1 "github.com/StackVista/stackstate-agent/pkg/clusteragent/api/v1"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+)
+
+func TestCheckStateEndpoints(t *testing.T) {
+	stateRoot, err := ioutil.TempDir("", "cluster-agent-check-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(stateRoot)
+
+	router := mux.NewRouter()
+	installCheckStateEndpoints(router, clusteragent.ServerContext{CheckStateStore: &state.CheckStateManager{
+		Config: state.Config{StateRootPath: stateRoot},
+		Cache:  cache.New(time.Minute, time.Minute),
+	}})
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return recorder
+	}
+
+	response := do("POST", "/checkstate/state?key=mycheck:abc:cursor", `{"state":"{\"page\":2}"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	response = do("GET", "/checkstate/state?key=mycheck:abc:cursor", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var stateResponse apiv1.CheckStateResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &stateResponse))
+	assert.Equal(t, apiv1.CheckStateResponse{Key: "mycheck:abc:cursor", State: `{"page":2}`}, stateResponse)
+
+	response = do("GET", "/checkstate/states?check=mycheck", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var statesResponse apiv1.CheckStatesResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &statesResponse))
+	assert.Equal(t, map[string]string{"mycheck:abccursor": `{"page":2}`}, statesResponse.States)
+
+	response = do("DELETE", "/checkstate/state?key=mycheck:abc:cursor", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	response = do("GET", "/checkstate/state?key=mycheck:abc:cursor", "")
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &stateResponse))
+	assert.Equal(t, "{}", stateResponse.State)
+
+	assert.Equal(t, http.StatusBadRequest, do("GET", "/checkstate/state", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/checkstate/state?key=mycheck:abc:cursor", "not json").Code)
+}
+
+func TestCheckStateEndpointsDisabled(t *testing.T) {
+	router := mux.NewRouter()
+	installCheckStateEndpoints(router, clusteragent.ServerContext{})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/checkstate/state?key=mycheck:cursor", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
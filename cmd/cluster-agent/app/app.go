@@ -37,7 +37,9 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/clusteragent"
 	admissionpkg "github.com/StackVista/stackstate-agent/pkg/clusteragent/admission"
 	"github.com/StackVista/stackstate-agent/pkg/clusteragent/admission/mutate"
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/checkstate" // sts
 	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state" // sts
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/StackVista/stackstate-agent/pkg/config/resolver"
 	"github.com/StackVista/stackstate-agent/pkg/forwarder"
@@ -235,6 +237,17 @@ func start(cmd *cobra.Command, args []string) error {
 	// [sts] init the batcher for topology production
 	batcher.InitBatcher(s, hostname, "agent", config.GetMaxCapacity())
 
+	// [sts] the checks of the cluster agent keep their state in ConfigMaps when the check state store is enabled, so it
+	// is kept when another cluster agent becomes the leader. The store is shared with the cluster check runners.
+	var checkStateStore state.CheckStateAPI
+	if config.Datadog.GetBool("cluster_checks.check_state_store_enabled") {
+		checkStateStore = checkstate.NewConfigMapStore(apiCl.Cl, apicommon.GetResourcesNamespace(),
+			config.Datadog.GetString("cluster_checks.check_state_configmap_prefix"))
+		state.InitCheckStateManagerWith(checkStateStore)
+	} else {
+		state.InitCheckStateManager()
+	}
+
 	isLeader := func() bool {
 		return false
 	}
@@ -324,7 +337,10 @@ func start(cmd *cobra.Command, args []string) error {
 		clusterCheckHandler, err := setupClusterCheck(mainCtx)
 		if err == nil {
 			api.ModifyAPIRouter(func(r *mux.Router) {
-				dcav1.InstallChecksEndpoints(r, clusteragent.ServerContext{ClusterCheckHandler: clusterCheckHandler})
+				dcav1.InstallChecksEndpoints(r, clusteragent.ServerContext{
+					ClusterCheckHandler: clusterCheckHandler,
+					CheckStateStore:     checkStateStore, // sts
+				})
 			})
 		} else {
 			log.Errorf("Error while setting up cluster check Autodiscovery, CLC API endpoints won't be available, err: %v", err)
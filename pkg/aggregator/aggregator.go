@@ -26,6 +26,7 @@ import (
 
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
 	"github.com/StackVista/stackstate-agent/pkg/serializer"
 	"github.com/StackVista/stackstate-agent/pkg/status/health"
@@ -252,6 +253,7 @@ type BufferedAggregator struct {
 	// [sts]
 	MetricPrefix string // The prefix used for metrics generated in the aggregator.
 	// We use this prefix to override datadog metrics we can't brand when using the agent as a dependency in the process-agent
+	healthDeriver *deriver.Deriver // derives health states from gauges, nil when no metric health rules are configured
 	// [sts]
 }
 
@@ -306,7 +308,8 @@ func NewBufferedAggregator(s serializer.MetricSerializer, eventPlatformForwarder
 		ServerlessFlushDone:     make(chan struct{}),
 
 		// [sts]
-		MetricPrefix: "datadog",
+		MetricPrefix:  "datadog",
+		healthDeriver: newMetricHealthDeriver(hostname, flushInterval),
 	}
 
 	return aggregator
@@ -405,6 +408,7 @@ func (agg *BufferedAggregator) handleSenderSample(ss senderMetricSample) {
 		} else {
 			ss.metricSample.Tags = util.SortUniqInPlace(ss.metricSample.Tags)
 			checkSampler.addSample(ss.metricSample)
+			agg.observeMetricHealth(ss.metricSample) // [sts]
 		}
 	} else {
 		log.Debugf("CheckSampler with ID '%s' doesn't exist, can't handle senderMetricSample", ss.id)
@@ -715,6 +719,7 @@ func (agg *BufferedAggregator) Flush(start time.Time, waitForSerializer bool) {
 	agg.flushSeriesAndSketches(start, waitForSerializer)
 	agg.flushServiceChecks(start, waitForSerializer)
 	agg.flushEvents(start, waitForSerializer)
+	agg.flushMetricHealth() // [sts]
 	agg.updateChecksTelemetry()
 }
 
@@ -0,0 +1,67 @@
+package aggregator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// [sts] health states derived from metric thresholds
+
+// metricHealthCheckID is the check id the health derived from metrics is submitted with
+const metricHealthCheckID check.ID = "metric_health"
+
+// metricHealthRetentionFlushes is the number of flushes a metric value is used for when it is not reported again
+const metricHealthRetentionFlushes = 4
+
+// newMetricHealthDeriver creates the deriver for the configured `metric_health_rules`, nil when none are configured
+func newMetricHealthDeriver(hostname string, flushInterval time.Duration) *deriver.Deriver {
+	var configs []deriver.RuleConfig
+	if err := config.Datadog.UnmarshalKey("metric_health_rules", &configs); err != nil {
+		_ = log.Warnf("Could not parse metric_health_rules, no health is derived from metrics: %v", err)
+		return nil
+	}
+	rules, errs := deriver.NewRules(configs)
+	for _, err := range errs {
+		_ = log.Warnf("Skipping metric health rule: %v", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if flushInterval == 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	stream := health.Stream{Urn: fmt.Sprintf("urn:health:agent:%s", hostname), SubStream: "metrics"}
+	return deriver.NewRetainingDeriver(stream, rules, int(flushInterval.Seconds()), metricHealthRetentionFlushes*flushInterval)
+}
+
+// observeMetricHealth records the gauges that health rules are configured for, other metric types are aggregated
+// before they are sent and their raw samples are not meaningful to compare with a threshold
+func (agg *BufferedAggregator) observeMetricHealth(sample *metrics.MetricSample) {
+	if agg.healthDeriver == nil || sample.Mtype != metrics.GaugeType {
+		return
+	}
+	agg.healthDeriver.ObserveMetric(sample.Name, sample.Value, sample.Host)
+}
+
+// flushMetricHealth submits the health derived from metrics to the batcher
+func (agg *BufferedAggregator) flushMetricHealth() {
+	if agg.healthDeriver == nil {
+		return
+	}
+	b := batcher.GetBatcher()
+	if b == nil {
+		log.Debugf("No batcher instance available, skipping the health derived from metrics")
+		return
+	}
+	agg.healthDeriver.Flush(deriver.NewBatcherSubmitter(b, metricHealthCheckID))
+	b.SubmitComplete(metricHealthCheckID)
+}
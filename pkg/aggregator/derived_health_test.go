@@ -0,0 +1,61 @@
+// +build test
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+)
+
+func TestMetricHealthDeriver(t *testing.T) {
+	mockBatcher := batcher.NewMockBatcher()
+	config.Datadog.Set("metric_health_rules", []map[string]interface{}{
+		{"name": "Load", "source": "system.load.1", "deviating_above": 2, "critical_above": 4},
+	})
+	defer config.Datadog.Set("metric_health_rules", nil)
+
+	agg := NewBufferedAggregator(nil, nil, "hostname", time.Second)
+	assert.NotNil(t, agg.healthDeriver)
+
+	checkID := check.ID("load_check")
+	assert.NoError(t, agg.registerSender(checkID))
+	for _, sample := range []*metrics.MetricSample{
+		{Name: "system.load.1", Value: 3, Mtype: metrics.GaugeType, Host: "host-a", Timestamp: 12345.0},
+		{Name: "system.load.1", Value: 10, Mtype: metrics.RateType, Host: "host-b", Timestamp: 12345.0},
+		{Name: "system.cpu.user", Value: 10, Mtype: metrics.GaugeType, Host: "host-a", Timestamp: 12345.0},
+	} {
+		agg.handleSenderSample(senderMetricSample{id: checkID, metricSample: sample})
+	}
+
+	agg.flushMetricHealth()
+
+	stream := health.Stream{Urn: "urn:health:agent:hostname", SubStream: "metrics"}
+	producedHealth := mockBatcher.CollectedTopology.Flush()[metricHealthCheckID].Health[stream.GoString()]
+	assert.Equal(t, health.Health{
+		StartSnapshot: &health.StartSnapshotMetadata{RepeatIntervalS: 1},
+		StopSnapshot:  &health.StopSnapshotMetadata{},
+		Stream:        stream,
+		CheckStates: []health.CheckData{
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Load:urn:host:/host-a",
+				Message:                   "system.load.1 is 3, above the deviating threshold of 2",
+				Health:                    health.Deviating,
+				TopologyElementIdentifier: "urn:host:/host-a",
+				Name:                      "Load",
+			}},
+		},
+	}, producedHealth)
+}
+
+func TestMetricHealthDeriverWithoutRules(t *testing.T) {
+	agg := NewBufferedAggregator(nil, nil, "hostname", time.Second)
+	assert.Nil(t, agg.healthDeriver)
+}
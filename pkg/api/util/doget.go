@@ -77,6 +77,31 @@ func DoPost(c *http.Client, url string, contentType string, body io.Reader) (res
 	return resp, nil
 }
 
+// DoDelete is a wrapper around performing HTTP DELETE requests
+// [sts]
+func DoDelete(c *http.Client, url string) (resp []byte, e error) {
+	req, e := http.NewRequest("DELETE", url, nil)
+	if e != nil {
+		return resp, e
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+GetAuthToken())
+
+	r, e := c.Do(req)
+	if e != nil {
+		return resp, e
+	}
+	resp, e = ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if e != nil {
+		return resp, e
+	}
+	if r.StatusCode >= 400 {
+		return resp, fmt.Errorf("%s", resp)
+	}
+	return resp, nil
+}
+
 // DoPostChunked is a wrapper around performing HTTP POST requests that stream chunked data
 func DoPostChunked(c *http.Client, url string, contentType string, body io.Reader, onChunk func([]byte)) error {
 	req, e := http.NewRequest("POST", url, body)
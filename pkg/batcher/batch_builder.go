@@ -151,6 +151,13 @@ func (builder *BatchBuilder) Delete(checkID check.ID, instance topology.Instance
 	return builder.incrementAndTryFlush()
 }
 
+// AddUnchanged adds the external id of a component or relation that was left out because it did not change
+func (builder *BatchBuilder) AddUnchanged(checkID check.ID, instance topology.Instance, externalID string) CheckInstanceBatchStates {
+	topologyData := builder.getOrCreateTopology(checkID, instance)
+	topologyData.UnchangedIDs = append(topologyData.UnchangedIDs, externalID)
+	return builder.incrementAndTryFlush()
+}
+
 // AddHealthCheckData adds a component
 func (builder *BatchBuilder) AddHealthCheckData(checkID check.ID, stream health.Stream, data health.CheckData) CheckInstanceBatchStates {
 	healthData := builder.getOrCreateHealth(checkID, stream)
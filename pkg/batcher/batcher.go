@@ -11,6 +11,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/util"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"sync"
+	"time"
 )
 
 var (
@@ -51,6 +52,12 @@ func InitBatcher(serializer serializer.AgentV1Serializer, hostname, agentName st
 }
 
 func newAsynchronousBatcher(serializer serializer.AgentV1Serializer, hostname, agentName string, maxCapacity int) AsynchronousBatcher {
+	dedupEnabled, dedupRefreshInterval := config.GetBatcherDedupConfig()
+	return newAsynchronousBatcherWithDedup(serializer, hostname, agentName, maxCapacity, dedupEnabled, dedupRefreshInterval)
+}
+
+func newAsynchronousBatcherWithDedup(serializer serializer.AgentV1Serializer, hostname, agentName string, maxCapacity int,
+	dedupEnabled bool, dedupRefreshInterval time.Duration) AsynchronousBatcher {
 	batcher := AsynchronousBatcher{
 		builder:    NewBatchBuilder(maxCapacity),
 		hostname:   hostname,
@@ -58,6 +65,10 @@ func newAsynchronousBatcher(serializer serializer.AgentV1Serializer, hostname, a
 		input:      make(chan interface{}),
 		serializer: serializer,
 	}
+	if dedupEnabled {
+		log.Infof("Topology deduplication is enabled in the batcher, refreshing all elements every %s", dedupRefreshInterval)
+		batcher.dedup = NewTopologyDeduplicator(dedupRefreshInterval)
+	}
 	go batcher.run()
 	return batcher
 }
@@ -80,6 +91,8 @@ type AsynchronousBatcher struct {
 	hostname, agentName string
 	input               chan interface{}
 	serializer          serializer.AgentV1Serializer
+	// dedup leaves out unchanged topology elements, nil when deduplication is disabled
+	dedup *TopologyDeduplicator
 }
 
 type submitComponent struct {
@@ -212,14 +225,31 @@ func (batcher *AsynchronousBatcher) run() {
 		s := <-batcher.input
 		switch submission := s.(type) {
 		case submitComponent:
-			batcher.sendState(batcher.builder.AddComponent(submission.checkID, submission.instance, submission.component))
+			if batcher.dedup != nil && batcher.dedup.UnchangedComponent(submission.checkID, submission.instance, submission.component) {
+				batcher.sendState(batcher.builder.AddUnchanged(submission.checkID, submission.instance, submission.component.ExternalID))
+			} else {
+				batcher.sendState(batcher.builder.AddComponent(submission.checkID, submission.instance, submission.component))
+			}
 		case submitRelation:
-			batcher.sendState(batcher.builder.AddRelation(submission.checkID, submission.instance, submission.relation))
+			if batcher.dedup != nil && batcher.dedup.UnchangedRelation(submission.checkID, submission.instance, submission.relation) {
+				batcher.sendState(batcher.builder.AddUnchanged(submission.checkID, submission.instance, submission.relation.ExternalID))
+			} else {
+				batcher.sendState(batcher.builder.AddRelation(submission.checkID, submission.instance, submission.relation))
+			}
 		case submitStartSnapshot:
+			if batcher.dedup != nil {
+				batcher.dedup.StartSnapshot(submission.checkID, submission.instance)
+			}
 			batcher.sendState(batcher.builder.TopologyStartSnapshot(submission.checkID, submission.instance))
 		case submitStopSnapshot:
+			if batcher.dedup != nil {
+				batcher.dedup.StopSnapshot(submission.checkID, submission.instance)
+			}
 			batcher.sendState(batcher.builder.TopologyStopSnapshot(submission.checkID, submission.instance))
 		case submitDelete:
+			if batcher.dedup != nil {
+				batcher.dedup.Delete(submission.checkID, submission.instance, submission.deleteID)
+			}
 			batcher.sendState(batcher.builder.Delete(submission.checkID, submission.instance, submission.deleteID))
 
 		case submitHealthCheckData:
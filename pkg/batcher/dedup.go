@@ -0,0 +1,109 @@
+package batcher
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+// dedupState is the deduplication state of a single check instance
+type dedupState struct {
+	// sent keeps the hash of the components and relations that were sent, by external id
+	sent map[string]uint64
+	// snapshot keeps the hash of the components and relations of the running snapshot, nil when there is no snapshot
+	snapshot map[string]uint64
+	// refresh is true when the running snapshot forwards all the elements
+	refresh     bool
+	lastRefresh time.Time
+}
+
+// TopologyDeduplicator keeps track of the topology elements that were sent for each check instance, so elements that
+// did not change since they were last sent can be replaced by their external id. Every refresh interval a snapshot
+// forwards all the elements again, so the receiver recovers from any payloads that got lost. The
+// TopologyDeduplicator is not thread safe and is only used from within the batcher's run loop.
+type TopologyDeduplicator struct {
+	states          map[string]*dedupState
+	refreshInterval time.Duration
+	now             func() time.Time
+}
+
+// NewTopologyDeduplicator constructs a TopologyDeduplicator
+func NewTopologyDeduplicator(refreshInterval time.Duration) *TopologyDeduplicator {
+	return &TopologyDeduplicator{
+		states:          make(map[string]*dedupState),
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+	}
+}
+
+func (d *TopologyDeduplicator) getOrCreateState(checkID check.ID, instance topology.Instance) *dedupState {
+	key := string(checkID) + "/" + instance.GoString()
+	if state, ok := d.states[key]; ok {
+		return state
+	}
+	state := &dedupState{sent: make(map[string]uint64)}
+	d.states[key] = state
+	return state
+}
+
+// StartSnapshot starts tracking the elements of a new snapshot
+func (d *TopologyDeduplicator) StartSnapshot(checkID check.ID, instance topology.Instance) {
+	state := d.getOrCreateState(checkID, instance)
+	state.snapshot = make(map[string]uint64)
+	now := d.now()
+	state.refresh = now.Sub(state.lastRefresh) >= d.refreshInterval
+	if state.refresh {
+		state.lastRefresh = now
+	}
+}
+
+// StopSnapshot stops the snapshot. The elements that were not part of the snapshot are removed by the receiver, so
+// only the elements of this snapshot are known to be sent afterwards.
+func (d *TopologyDeduplicator) StopSnapshot(checkID check.ID, instance topology.Instance) {
+	state := d.getOrCreateState(checkID, instance)
+	if state.snapshot != nil {
+		state.sent = state.snapshot
+		state.snapshot = nil
+		state.refresh = false
+	}
+}
+
+// UnchangedComponent returns true if the exact same component was sent before and can be left out of the batch
+func (d *TopologyDeduplicator) UnchangedComponent(checkID check.ID, instance topology.Instance, component topology.Component) bool {
+	return d.unchanged(checkID, instance, "component:"+component.ExternalID, component.JSONString())
+}
+
+// UnchangedRelation returns true if the exact same relation was sent before and can be left out of the batch
+func (d *TopologyDeduplicator) UnchangedRelation(checkID check.ID, instance topology.Instance, relation topology.Relation) bool {
+	return d.unchanged(checkID, instance, "relation:"+relation.ExternalID, relation.JSONString())
+}
+
+// Delete forgets a deleted topology element
+func (d *TopologyDeduplicator) Delete(checkID check.ID, instance topology.Instance, externalID string) {
+	state := d.getOrCreateState(checkID, instance)
+	for _, key := range []string{"component:" + externalID, "relation:" + externalID} {
+		delete(state.sent, key)
+		delete(state.snapshot, key)
+	}
+}
+
+func (d *TopologyDeduplicator) unchanged(checkID check.ID, instance topology.Instance, key string, json string) bool {
+	state := d.getOrCreateState(checkID, instance)
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(json))
+	hash := h.Sum64()
+
+	previous, sent := state.sent[key]
+	unchanged := sent && previous == hash
+
+	if state.snapshot != nil {
+		state.snapshot[key] = hash
+		return unchanged && !state.refresh
+	}
+
+	state.sent[key] = hash
+	return unchanged
+}
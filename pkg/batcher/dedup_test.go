@@ -0,0 +1,97 @@
+package batcher
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	serializer2 "github.com/StackVista/stackstate-agent/pkg/serializer"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func submitTestSnapshot(batcher AsynchronousBatcher, components ...topology.Component) {
+	batcher.SubmitStartSnapshot(testID, testInstance)
+	for _, component := range components {
+		batcher.SubmitComponent(testID, testInstance, component)
+	}
+	batcher.SubmitRelation(testID, testInstance, testRelation)
+	batcher.SubmitStopSnapshot(testID, testInstance)
+}
+
+func TestBatcherDedupUnchangedSnapshot(t *testing.T) {
+	serializer := serializer2.NewAgentV1MockSerializer()
+	batcher := newAsynchronousBatcherWithDedup(serializer, testHost, testAgent, 100, true, time.Hour)
+
+	// the first snapshot sends everything
+	submitTestSnapshot(batcher, testComponent, testComponent2)
+	message := serializer.GetJSONToV1IntakeMessage()
+	topologies := message.(map[string]interface{})["topologies"].([]topology.Topology)
+	assert.Equal(t, []topology.Component{testComponent, testComponent2}, topologies[0].Components)
+	assert.Equal(t, []topology.Relation{testRelation}, topologies[0].Relations)
+	assert.Empty(t, topologies[0].UnchangedIDs)
+
+	// the second snapshot only sends the changed component and marks the rest as unchanged
+	changedComponent := testComponent2
+	changedComponent.Data = map[string]interface{}{"changed": true}
+	submitTestSnapshot(batcher, testComponent, changedComponent)
+	message = serializer.GetJSONToV1IntakeMessage()
+
+	assert.Equal(t, message,
+		map[string]interface{}{
+			"internalHostname": "myhost",
+			"topologies": []topology.Topology{
+				{
+					StartSnapshot: true,
+					StopSnapshot:  true,
+					Instance:      testInstance,
+					Components:    []topology.Component{changedComponent},
+					Relations:     []topology.Relation{},
+					DeleteIDs:     []string{},
+					UnchangedIDs:  []string{testComponent.ExternalID, testRelation.ExternalID},
+				},
+			},
+			"health":  []health.Health{},
+			"metrics": []interface{}{},
+		})
+
+	batcher.Shutdown()
+}
+
+func TestTopologyDeduplicator(t *testing.T) {
+	now := time.Now()
+	dedup := NewTopologyDeduplicator(time.Hour)
+	dedup.now = func() time.Time { return now }
+
+	dedup.StartSnapshot(testID, testInstance)
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance, testComponent2))
+	dedup.StopSnapshot(testID, testInstance)
+
+	dedup.StartSnapshot(testID, testInstance)
+	assert.True(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	dedup.StopSnapshot(testID, testInstance)
+
+	// components that were left out of the previous snapshot are sent again
+	dedup.StartSnapshot(testID, testInstance)
+	assert.True(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance, testComponent2))
+	dedup.StopSnapshot(testID, testInstance)
+
+	// other instances are tracked separately
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance2, testComponent))
+
+	// deleted components are sent again
+	dedup.Delete(testID, testInstance, testComponent.ExternalID)
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	assert.True(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+
+	// after the refresh interval a snapshot sends everything again
+	now = now.Add(2 * time.Hour)
+	dedup.StartSnapshot(testID, testInstance)
+	assert.False(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	dedup.StopSnapshot(testID, testInstance)
+
+	dedup.StartSnapshot(testID, testInstance)
+	assert.True(t, dedup.UnchangedComponent(testID, testInstance, testComponent))
+	dedup.StopSnapshot(testID, testInstance)
+}
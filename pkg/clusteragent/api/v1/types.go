@@ -134,3 +134,22 @@ func NewMetadataResponse() *MetadataResponse {
 		Nodes: make(map[string]*MetadataResponseBundle),
 	}
 }
+
+// sts
+
+// CheckStateRequest is used to store the state of a check state key in the cluster agent
+type CheckStateRequest struct {
+	State string `json:"state"`
+}
+
+// CheckStateResponse holds the state of a check state key stored in the cluster agent
+type CheckStateResponse struct {
+	Key   string `json:"key"`
+	State string `json:"state"`
+}
+
+// CheckStatesResponse holds the states of a check stored in the cluster agent, by their key
+type CheckStatesResponse struct {
+	Check  string            `json:"check"`
+	States map[string]string `json:"states"`
+}
@@ -0,0 +1,214 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+// Package checkstate implements the check state store of the cluster agent. It keeps the state of cluster checks,
+// like the cursors and pagination tokens of a check, when the check is rescheduled on another cluster check runner.
+package checkstate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	// emptyState is the state of a key that is not stored, the same as the CheckStateManager
+	emptyState = "{}"
+	// CheckStateLabel marks the ConfigMaps that hold check states
+	CheckStateLabel = "stackstate.com/check-state"
+	// checkAnnotation is the check of the states held by a ConfigMap, the name of the ConfigMap is derived from it
+	checkAnnotation = "stackstate.com/check"
+	// checkHashLength is the length of the hash of the check in the name of its ConfigMap
+	checkHashLength = 10
+	// maxConfigMapDataSize is the maximum size of the keys and values of a ConfigMap that is accepted by the API server
+	maxConfigMapDataSize = 1024 * 1024
+)
+
+// ConfigMapStore is an implementation of the CheckStateAPI that keeps the states of every check in a ConfigMap, so
+// they outlive the cluster check runners and the cluster agent. The states of a check are stored by their name, the
+// part of the key after the check, the same way the CheckStateManager stores them in a file per state.
+type ConfigMapStore struct {
+	client corev1.ConfigMapInterface
+	prefix string
+}
+
+// NewConfigMapStore returns a store that keeps the check states in ConfigMaps in the given namespace, named after the
+// prefix and the check
+func NewConfigMapStore(client kubernetes.Interface, namespace, prefix string) *ConfigMapStore {
+	return &ConfigMapStore{
+		client: client.CoreV1().ConfigMaps(namespace),
+		prefix: prefix,
+	}
+}
+
+// configMapName returns the name of the ConfigMap of a check, a valid DNS subdomain. It ends with a hash of the check,
+// so checks of which the names only differ in their case, underscores or after the maximum length do not share it.
+func (s *ConfigMapStore) configMapName(check string) string {
+	if check == "" {
+		return strings.TrimRight(strings.ToLower(s.prefix), "-")
+	}
+
+	suffix := fmt.Sprintf("-%x", sha256.Sum256([]byte(check)))[:checkHashLength+1]
+	name := strings.ToLower(fmt.Sprintf("%s-%s", s.prefix, strings.ReplaceAll(check, "_", "-")))
+	if len(name) > validation.DNS1123SubdomainMaxLength-len(suffix) {
+		name = name[:validation.DNS1123SubdomainMaxLength-len(suffix)]
+	}
+	return strings.TrimRight(name, "-.") + suffix
+}
+
+// getConfigMap returns the ConfigMap of a check, or nil when it does not exist
+func (s *ConfigMapStore) getConfigMap(check string) (*v1.ConfigMap, error) {
+	configMap, err := s.client.Get(context.TODO(), s.configMapName(check), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner := configMap.Annotations[checkAnnotation]; owner != check {
+		return nil, fmt.Errorf("ConfigMap %s holds the check states of '%s' instead of '%s'", configMap.Name, owner, check)
+	}
+	return configMap, nil
+}
+
+// GetState returns the state of a key, or an empty state when it is not stored
+func (s *ConfigMapStore) GetState(key string) (string, error) {
+	check, name := state.SplitKey(key)
+	configMap, err := s.getConfigMap(check)
+	if err != nil {
+		return emptyState, err
+	}
+	if configMap == nil {
+		return emptyState, nil
+	}
+	value, found := configMap.Data[name]
+	if !found {
+		return emptyState, nil
+	}
+	return value, nil
+}
+
+// SetState stores the state of a key, the ConfigMap of the check is created when it does not exist yet. Concurrent
+// updates of the ConfigMap are retried.
+func (s *ConfigMapStore) SetState(key, value string) error {
+	check, name := state.SplitKey(key)
+	if name == "" {
+		return fmt.Errorf("invalid check state key '%s'", key)
+	}
+
+	return s.update(check, func(data map[string]string) {
+		data[name] = value
+	})
+}
+
+// ListStates returns the states of a check by their key
+func (s *ConfigMapStore) ListStates(check string) (map[string]string, error) {
+	cleanedCheck := state.CleanName(check)
+	configMap, err := s.getConfigMap(cleanedCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]string)
+	if configMap == nil {
+		return states, nil
+	}
+	for name, value := range configMap.Data {
+		states[cleanedCheck+":"+name] = value
+	}
+	return states, nil
+}
+
+// DeleteState removes the state of a key, the ConfigMap of the check is removed with its last state
+func (s *ConfigMapStore) DeleteState(key string) error {
+	check, name := state.SplitKey(key)
+	return s.update(check, func(data map[string]string) {
+		delete(data, name)
+	})
+}
+
+// Clear is a noop, the states are not cached by the store
+func (s *ConfigMapStore) Clear() {}
+
+// update applies a change to the states of a check and writes the ConfigMap of the check, it is retried when the
+// ConfigMap was changed or created in the meantime
+func (s *ConfigMapStore) update(check string, change func(data map[string]string)) error {
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		configMap, err := s.getConfigMap(check)
+		if err != nil {
+			return err
+		}
+
+		if configMap == nil {
+			data := make(map[string]string)
+			change(data)
+			if len(data) == 0 {
+				return nil
+			}
+			if err := checkDataSize(check, data); err != nil {
+				return err
+			}
+			_, err = s.client.Create(context.TODO(), s.newConfigMap(check, data), metav1.CreateOptions{})
+			return err
+		}
+
+		configMap = configMap.DeepCopy()
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		change(configMap.Data)
+		if len(configMap.Data) == 0 {
+			log.Debugf("Removing the check state ConfigMap %s without states", configMap.Name)
+			err = s.client.Delete(context.TODO(), configMap.Name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{ResourceVersion: &configMap.ResourceVersion},
+			})
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if err := checkDataSize(check, configMap.Data); err != nil {
+			return err
+		}
+		_, err = s.client.Update(context.TODO(), configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// checkDataSize returns an error when the states of a check do not fit in a ConfigMap
+func checkDataSize(check string, data map[string]string) error {
+	size := 0
+	for name, value := range data {
+		size += len(name) + len(value)
+	}
+	if size > maxConfigMapDataSize {
+		return fmt.Errorf("the check states of '%s' take %d bytes, more than the %d bytes a ConfigMap can hold", check,
+			size, maxConfigMapDataSize)
+	}
+	return nil
+}
+
+func (s *ConfigMapStore) newConfigMap(check string, data map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        s.configMapName(check),
+			Labels:      map[string]string{CheckStateLabel: "true"},
+			Annotations: map[string]string{checkAnnotation: check},
+		},
+		Data: data,
+	}
+}
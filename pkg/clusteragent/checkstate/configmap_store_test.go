@@ -0,0 +1,138 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package checkstate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "stackstate", "sts-check-state")
+
+	// a state that is not stored is empty
+	value, err := store.GetState("kubernetes_api_events:abc123:event_resource_version")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", value)
+
+	require.NoError(t, store.SetState("kubernetes_api_events:abc123:event_resource_version", `{"resource_version":"10"}`))
+	require.NoError(t, store.SetState("kubernetes_api_events:abc123:last_event_time", `{"ts":1700000000}`))
+	require.NoError(t, store.SetState("other_check:def456:cursor", `{"page":"token"}`))
+
+	value, err = store.GetState("kubernetes_api_events:abc123:event_resource_version")
+	require.NoError(t, err)
+	assert.Equal(t, `{"resource_version":"10"}`, value)
+
+	// the states of a check are kept in a ConfigMap of the check, by their name without invalid characters
+	configMap, err := client.CoreV1().ConfigMaps("stackstate").Get(context.TODO(), store.configMapName("kubernetes_api_events"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"abc123event_resource_version": `{"resource_version":"10"}`,
+		"abc123last_event_time":        `{"ts":1700000000}`,
+	}, configMap.Data)
+	assert.Equal(t, "true", configMap.Labels[CheckStateLabel])
+	assert.Equal(t, "kubernetes_api_events", configMap.Annotations[checkAnnotation])
+
+	states, err := store.ListStates("kubernetes_api_events")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kubernetes_api_events:abc123event_resource_version": `{"resource_version":"10"}`,
+		"kubernetes_api_events:abc123last_event_time":        `{"ts":1700000000}`,
+	}, states)
+
+	states, err = store.ListStates("unknown_check")
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	// the ConfigMap is removed with the last state of the check
+	require.NoError(t, store.DeleteState("other_check:def456:cursor"))
+	_, err = client.CoreV1().ConfigMaps("stackstate").Get(context.TODO(), store.configMapName("other_check"), metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, store.DeleteState("other_check:def456:cursor"))
+
+	require.NoError(t, store.DeleteState("kubernetes_api_events:abc123:last_event_time"))
+	states, err = store.ListStates("kubernetes_api_events")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"kubernetes_api_events:abc123event_resource_version": `{"resource_version":"10"}`}, states)
+
+	assert.Error(t, store.SetState("check:", "{}"))
+}
+
+func TestConfigMapStoreRetriesConflicts(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "stackstate", "sts-check-state")
+	require.NoError(t, store.SetState("check:first", "1"))
+
+	// the first update conflicts with an update of another cluster agent
+	conflicts := 0
+	client.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, store.configMapName("check"), nil)
+	})
+
+	require.NoError(t, store.SetState("check:second", "2"))
+	assert.Equal(t, 1, conflicts)
+	states, err := store.ListStates("check")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"check:first": "1", "check:second": "2"}, states)
+}
+
+func TestConfigMapName(t *testing.T) {
+	store := NewConfigMapStore(fake.NewSimpleClientset(), "stackstate", "sts-check-state")
+	assert.Equal(t, "sts-check-state", store.configMapName(""))
+	assert.Regexp(t, "^sts-check-state-mycheck-a-[0-9a-f]{10}$", store.configMapName("MyCheck_A"))
+
+	// checks that only differ in their case, underscores or after the maximum length have their own ConfigMap
+	assert.NotEqual(t, store.configMapName("MyCheck_A"), store.configMapName("mycheck-a"))
+	name := store.configMapName(strings.Repeat("a", 300))
+	assert.Len(t, name, 253)
+	assert.NotEqual(t, name, store.configMapName(strings.Repeat("a", 301)))
+}
+
+func TestConfigMapStoreOtherCheck(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "stackstate", "sts-check-state")
+	require.NoError(t, store.SetState("mycheck:cursor", "1"))
+
+	// a ConfigMap that holds the states of another check is not used
+	configMap, err := client.CoreV1().ConfigMaps("stackstate").Get(context.TODO(), store.configMapName("mycheck"), metav1.GetOptions{})
+	require.NoError(t, err)
+	configMap.Annotations[checkAnnotation] = "othercheck"
+	_, err = client.CoreV1().ConfigMaps("stackstate").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = store.GetState("mycheck:cursor")
+	assert.Error(t, err)
+	assert.Error(t, store.SetState("mycheck:cursor", "2"))
+}
+
+func TestConfigMapStoreSizeLimit(t *testing.T) {
+	store := NewConfigMapStore(fake.NewSimpleClientset(), "stackstate", "sts-check-state")
+	require.NoError(t, store.SetState("mycheck:small", "1"))
+
+	err := store.SetState("mycheck:large", strings.Repeat("a", maxConfigMapDataSize))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than the 1048576 bytes a ConfigMap can hold")
+	err = store.SetState("othercheck:large", strings.Repeat("a", maxConfigMapDataSize))
+	require.Error(t, err)
+
+	// the states that fit are kept
+	states, err := store.ListStates("mycheck")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"mycheck:small": "1"}, states)
+}
@@ -27,8 +27,9 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 	defer d.store.RUnlock()
 
 	response := types.StateResponse{
-		Warmup:   !d.store.active,
-		Dangling: makeConfigArray(d.store.danglingConfigs),
+		Warmup:    !d.store.active,
+		Dangling:  makeConfigArray(d.store.danglingConfigs),
+		Decisions: d.decisions.get(), // sts
 	}
 	for _, node := range d.store.nodes {
 		n := types.StateNodeResponse{
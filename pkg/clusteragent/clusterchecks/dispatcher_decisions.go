@@ -0,0 +1,130 @@
+//go:build clusterchecks
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"sync"
+
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+const (
+	// maxDispatchDecisions is the number of dispatching decisions kept for the clusterchecks cmd
+	maxDispatchDecisions = 50
+
+	decisionDispatched = "dispatched"
+	decisionUnassigned = "unassigned"
+	decisionMoved      = "moved"
+	decisionPinned     = "pinned"
+)
+
+// dispatchDecisions keeps the latest dispatching decisions, so the clusterchecks cmd can show
+// why checks run where they run
+type dispatchDecisions struct {
+	sync.Mutex
+	decisions []types.DispatchDecision
+}
+
+func newDispatchDecisions() *dispatchDecisions {
+	return &dispatchDecisions{}
+}
+
+// record adds a decision, dropping the oldest one when the maximum is reached
+func (d *dispatchDecisions) record(decision types.DispatchDecision) {
+	d.Lock()
+	defer d.Unlock()
+
+	if decision.Timestamp == 0 {
+		decision.Timestamp = timestampNow()
+	}
+	d.decisions = append(d.decisions, decision)
+	if len(d.decisions) > maxDispatchDecisions {
+		d.decisions = d.decisions[len(d.decisions)-maxDispatchDecisions:]
+	}
+}
+
+// recordChange adds a decision unless it repeats the latest decision for the same config,
+// so a check that stays pinned on every rebalancing is reported once
+func (d *dispatchDecisions) recordChange(decision types.DispatchDecision) {
+	d.Lock()
+	for i := len(d.decisions) - 1; i >= 0; i-- {
+		latest := d.decisions[i]
+		if latest.Digest != decision.Digest {
+			continue
+		}
+		if latest.Action == decision.Action && latest.SourceNodeName == decision.SourceNodeName && latest.Reason == decision.Reason {
+			d.Unlock()
+			return
+		}
+		break
+	}
+	d.Unlock()
+
+	d.record(decision)
+}
+
+// get returns a copy of the decisions, oldest first
+func (d *dispatchDecisions) get() []types.DispatchDecision {
+	d.Lock()
+	defer d.Unlock()
+
+	decisions := make([]types.DispatchDecision, len(d.decisions))
+	copy(decisions, d.decisions)
+	return decisions
+}
+
+// reset forgets all decisions
+func (d *dispatchDecisions) reset() {
+	d.Lock()
+	defer d.Unlock()
+	d.decisions = nil
+}
+
+// pinReason returns why a check can not be moved to another node, or an empty string when it can be moved.
+// A check that is moved loses its transaction in progress, and its check state stays on the runner it ran on.
+func (d *dispatcher) pinReason(stats types.CLCRunnerStats) string {
+	switch {
+	case stats.InTransaction:
+		return "transaction in progress"
+	case stats.IsTransactional && d.pinTransactionalChecks:
+		return "transactional check"
+	default:
+		return ""
+	}
+}
+
+// isPinned returns true when a check can not be moved to another node
+func (d *dispatcher) isPinned(stats types.CLCRunnerStats) bool {
+	return d.pinReason(stats) != ""
+}
+
+// recordPinnedChecks records the cluster checks of a node that are kept on it because they are pinned
+func (d *dispatcher) recordPinnedChecks(nodeName string) {
+	d.store.RLock()
+	node, found := d.store.getNodeStore(nodeName)
+	d.store.RUnlock()
+	if !found {
+		return
+	}
+
+	pinned := make(map[string]string)
+	node.RLock()
+	for checkID, stats := range node.clcRunnerStats {
+		if reason := d.pinReason(stats); stats.IsClusterCheck && reason != "" {
+			pinned[checkID] = reason
+		}
+	}
+	node.RUnlock()
+
+	for checkID, reason := range pinned {
+		config, digest := d.getConfigAndDigest(checkID)
+		d.decisions.recordChange(types.DispatchDecision{
+			Action:         decisionPinned,
+			CheckName:      config.Name,
+			Digest:         digest,
+			SourceNodeName: nodeName,
+			Reason:         reason,
+		})
+	}
+}
@@ -0,0 +1,88 @@
+//go:build clusterchecks
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestDispatchDecisions(t *testing.T) {
+	dispatcher := newDispatcher()
+	config := generateIntegration("A")
+	patched, err := dispatcher.patchConfiguration(config)
+	require.NoError(t, err)
+
+	// No node available yet
+	dispatcher.Schedule([]integration.Config{config})
+
+	// Dispatched to the only node
+	dispatcher.processNodeStatus("nodeA", "10.0.0.1", types.NodeStatus{})
+	dispatcher.reschedule(dispatcher.retrieveAndClearDangling())
+
+	// Orphaned when the node expires
+	dispatcher.store.nodes["nodeA"].heartbeat = timestampNow() - 35
+	dispatcher.expireNodes()
+
+	state, err := dispatcher.getState()
+	require.NoError(t, err)
+	require.Len(t, state.Decisions, 3)
+	for _, decision := range state.Decisions {
+		assert.Equal(t, "A", decision.CheckName)
+		assert.Equal(t, patched.Digest(), decision.Digest)
+		assert.NotZero(t, decision.Timestamp)
+	}
+	assert.Equal(t, decisionUnassigned, state.Decisions[0].Action)
+	assert.Equal(t, "no node available", state.Decisions[0].Reason)
+	assert.Equal(t, decisionDispatched, state.Decisions[1].Action)
+	assert.Equal(t, "nodeA", state.Decisions[1].DestNodeName)
+	assert.Equal(t, decisionUnassigned, state.Decisions[2].Action)
+	assert.Equal(t, "nodeA", state.Decisions[2].SourceNodeName)
+	assert.Equal(t, "node expired", state.Decisions[2].Reason)
+
+	// Decisions are forgotten with the dispatching state
+	dispatcher.reset()
+	assert.Empty(t, dispatcher.decisions.get())
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestDispatchDecisionsRecordChange(t *testing.T) {
+	decisions := newDispatchDecisions()
+	pinned := types.DispatchDecision{Action: decisionPinned, Digest: "digest", SourceNodeName: "nodeA", Reason: "transactional check"}
+
+	// A repeated decision is only recorded once
+	decisions.recordChange(pinned)
+	decisions.recordChange(pinned)
+	assert.Len(t, decisions.get(), 1)
+
+	// Decisions of other configs do not hide the latest decision of a config
+	decisions.record(types.DispatchDecision{Action: decisionDispatched, Digest: "other"})
+	decisions.recordChange(pinned)
+	assert.Len(t, decisions.get(), 2)
+
+	// The decision is recorded again after a different decision
+	decisions.record(types.DispatchDecision{Action: decisionMoved, Digest: "digest"})
+	decisions.recordChange(pinned)
+	assert.Len(t, decisions.get(), 4)
+}
+
+func TestDispatchDecisionsLimit(t *testing.T) {
+	decisions := newDispatchDecisions()
+	for i := 0; i < maxDispatchDecisions+10; i++ {
+		decisions.record(types.DispatchDecision{Action: decisionDispatched, Digest: fmt.Sprintf("digest-%d", i)})
+	}
+
+	// Only the latest decisions are kept, oldest first
+	kept := decisions.get()
+	require.Len(t, kept, maxDispatchDecisions)
+	assert.Equal(t, "digest-10", kept[0].Digest)
+	assert.Equal(t, fmt.Sprintf("digest-%d", maxDispatchDecisions+9), kept[maxDispatchDecisions-1].Digest)
+}
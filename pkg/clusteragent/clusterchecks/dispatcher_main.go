@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks/types"
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/StackVista/stackstate-agent/pkg/status/health"
 	"github.com/StackVista/stackstate-agent/pkg/util"
@@ -33,11 +34,15 @@ type dispatcher struct {
 	extraTags             []string
 	clcRunnersClient      clusteragent.CLCRunnerClientInterface
 	advancedDispatching   bool
+	// sts
+	pinTransactionalChecks bool
+	decisions              *dispatchDecisions
 }
 
 func newDispatcher() *dispatcher {
 	d := &dispatcher{
-		store: newClusterStore(),
+		store:     newClusterStore(),
+		decisions: newDispatchDecisions(), // sts
 	}
 	d.nodeExpirationSeconds = config.Datadog.GetInt64("cluster_checks.node_expiration_timeout")
 	d.extraTags = config.Datadog.GetStringSlice("cluster_checks.extra_tags")
@@ -53,6 +58,7 @@ func newDispatcher() *dispatcher {
 		d.extraTags = append(d.extraTags, fmt.Sprintf("kube_cluster_name:%s", clusterTagValue))
 	}
 
+	d.pinTransactionalChecks = config.Datadog.GetBool("cluster_checks.pin_transactional_checks") // sts
 	d.advancedDispatching = config.Datadog.GetBool("cluster_checks.advanced_dispatching_enabled")
 	if !d.advancedDispatching {
 		return d
@@ -132,12 +138,16 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
 	target := d.getLeastBusyNode()
+	decision := types.DispatchDecision{CheckName: config.Name, Digest: config.Digest(), DestNodeName: target} // sts
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
 		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		decision.Action, decision.Reason = decisionUnassigned, "no node available"
 	} else {
 		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
+		decision.Action, decision.Reason = decisionDispatched, "least busy node"
 	}
+	d.decisions.record(decision)
 
 	d.addConfig(config, target)
 }
@@ -154,6 +164,7 @@ func (d *dispatcher) reset() {
 	d.store.Lock()
 	defer d.store.Unlock()
 	d.store.reset()
+	d.decisions.reset() // sts
 }
 
 // run is the main management goroutine for the dispatcher
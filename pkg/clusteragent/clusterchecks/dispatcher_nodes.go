@@ -124,6 +124,13 @@ func (d *dispatcher) expireNodes() {
 				delete(d.store.digestToNode, digest)
 				log.Debugf("Adding %s:%s as a dangling Cluster Check config", config.Name, digest)
 				d.store.danglingConfigs[digest] = config
+				d.decisions.record(types.DispatchDecision{ // sts
+					Action:         decisionUnassigned,
+					CheckName:      config.Name,
+					Digest:         digest,
+					SourceNodeName: name,
+					Reason:         "node expired",
+				})
 				danglingConfigs.Inc(le.JoinLeaderValue)
 			}
 			delete(d.store.nodes, name)
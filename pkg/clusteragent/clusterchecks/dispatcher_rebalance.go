@@ -105,6 +105,7 @@ func (d *dispatcher) updateDiff(avg int) map[string]int {
 // A check Xi running on a node N is chosen to move to another node if it satisfies the following
 // Weight(Xi) >  Weight(Xj) (for each j != i, 0 <= j < len(weights))
 // where Weight(X) is the busyness value caused by running the check X.
+// Pinned checks, like transactional checks with a transaction in progress, are never chosen.
 func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 	d.store.RLock()
 	node, found := d.store.getNodeStore(nodeName)
@@ -115,7 +116,7 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 		return "", -1, fmt.Errorf("node %s not found in store", nodeName)
 	}
 
-	return node.GetMostWeightedClusterCheck(busynessFunc)
+	return node.GetMostWeightedClusterCheck(busynessFunc, d.isPinned)
 }
 
 // pickNode select the most appropriate node to receive a specific check.
@@ -200,6 +201,7 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 		for diffMap[nodeWeight.nodeName] > 0 {
 			// try to move checks from a node only of the node busyness is above the average
 			sourceNodeName := nodeWeight.nodeName
+			d.recordPinnedChecks(sourceNodeName) // sts
 			checkID, checkWeight, err := d.pickCheckToMove(sourceNodeName)
 			if err != nil {
 				log.Debugf("Cannot pick a check to move from node %s: %v", sourceNodeName, err)
@@ -228,6 +230,16 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 					checkID, checkWeight, totalAvg, sourceDiff, destDiff)
 				// diffMap needs to be updated on every check moved
 				diffMap = d.updateDiff(totalAvg)
+				config, digest := d.getConfigAndDigest(checkID) // sts
+				d.decisions.record(types.DispatchDecision{
+					Action:         decisionMoved,
+					CheckName:      config.Name,
+					Digest:         digest,
+					SourceNodeName: sourceNodeName,
+					DestNodeName:   destNodeName,
+					Reason: fmt.Sprintf("check weight %d, source busyness %d above average, destination busyness %d from average",
+						checkWeight, sourceDiff, destDiff),
+				})
 				checksMoved = append(checksMoved, types.RebalanceResponse{
 					CheckID:        checkID,
 					CheckWeight:    checkWeight,
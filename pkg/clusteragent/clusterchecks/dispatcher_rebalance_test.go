@@ -1448,3 +1448,89 @@ func TestMoveCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestRebalancePinnedChecks(t *testing.T) {
+	heavyConfig := integration.Config{
+		Name:       "heavy",
+		Instances:  []integration.Data{integration.Data("heavy: true")},
+		InitConfig: integration.Data(""),
+	}
+	lightConfig := integration.Config{
+		Name:       "light",
+		Instances:  []integration.Data{integration.Data("light: true")},
+		InitConfig: integration.Data(""),
+	}
+	heavyID := string(check.BuildID(heavyConfig.Name, heavyConfig.Instances[0], heavyConfig.InitConfig))
+	lightID := string(check.BuildID(lightConfig.Name, lightConfig.Instances[0], lightConfig.InitConfig))
+
+	for _, tc := range []struct {
+		name                   string
+		pinTransactionalChecks bool
+		inTransaction          bool
+		heavyNode, lightNode   string
+		decisions              []string
+	}{
+		{
+			name:                   "transactional checks are pinned",
+			pinTransactionalChecks: true,
+			heavyNode:              "A",
+			lightNode:              "B",
+			decisions:              []string{"pinned heavy A  transactional check", "moved light A B"},
+		},
+		{
+			name:      "transactional checks move between snapshots",
+			heavyNode: "B",
+			lightNode: "A",
+			decisions: []string{"moved heavy A B"},
+		},
+		{
+			name:          "transactional checks do not move in a transaction",
+			inTransaction: true,
+			heavyNode:     "A",
+			lightNode:     "B",
+			decisions:     []string{"pinned heavy A  transaction in progress", "moved light A B"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dispatcher := newDispatcher()
+			dispatcher.pinTransactionalChecks = tc.pinTransactionalChecks
+
+			// prepare store
+			dispatcher.store.active = true
+			dispatcher.store.nodes["B"] = newNodeStore("B", "")
+			dispatcher.addConfig(heavyConfig, "A")
+			dispatcher.addConfig(lightConfig, "A")
+			dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+				heavyID: types.CLCRunnerStats{
+					AverageExecutionTime: 300,
+					IsClusterCheck:       true,
+					IsTransactional:      true,
+					InTransaction:        tc.inTransaction,
+				},
+				lightID: types.CLCRunnerStats{
+					AverageExecutionTime: 100,
+					IsClusterCheck:       true,
+				},
+			}
+
+			// rebalance checks
+			dispatcher.rebalance()
+
+			assert.EqualValues(t, tc.heavyNode, dispatcher.store.digestToNode[heavyConfig.Digest()])
+			assert.EqualValues(t, tc.lightNode, dispatcher.store.digestToNode[lightConfig.Digest()])
+
+			// assert the decisions are kept for the clusterchecks cmd
+			decisions := []string{}
+			for _, decision := range dispatcher.decisions.get() {
+				description := fmt.Sprintf("%s %s %s %s", decision.Action, decision.CheckName, decision.SourceNodeName, decision.DestNodeName)
+				if decision.Action == decisionPinned {
+					description += " " + decision.Reason
+				}
+				decisions = append(decisions, description)
+			}
+			assert.Equal(t, tc.decisions, decisions)
+
+			requireNotLocked(t, dispatcher.store)
+		})
+	}
+}
@@ -163,9 +163,9 @@ func (s *nodeStore) GetBusyness(busynessFunc func(stats types.CLCRunnerStats) in
 	return busyness
 }
 
-// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node
+// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node, pinned checks are skipped
 // The nodeStore handles thread safety for this public method
-func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int) (string, int, error) {
+func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int, isPinned func(stats types.CLCRunnerStats) bool) (string, int, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if len(s.clcRunnerStats) == 0 {
@@ -177,8 +177,8 @@ func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CL
 	checkWeight := 0
 	for id, stats := range s.clcRunnerStats {
 		busyness := busynessFunc(stats)
-		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck {
-			// Only consider Cluster Checks
+		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck && !isPinned(stats) {
+			// Only consider Cluster Checks that are not pinned to the node
 			checkWeight = busyness
 			checkID = id
 			firstItr = false
@@ -43,6 +43,7 @@ type StateResponse struct {
 	Warmup     bool                 `json:"warmup"`
 	Nodes      []StateNodeResponse  `json:"nodes"`
 	Dangling   []integration.Config `json:"dangling"`
+	Decisions  []DispatchDecision   `json:"decisions"` // sts
 }
 
 // StateNodeResponse is a chunk of StateResponse
@@ -51,6 +52,19 @@ type StateNodeResponse struct {
 	Configs []integration.Config `json:"configs"`
 }
 
+// sts
+// DispatchDecision is a chunk of StateResponse, it describes why a config was dispatched to, moved between or kept
+// on a node
+type DispatchDecision struct {
+	Timestamp      int64  `json:"timestamp"`
+	Action         string `json:"action"`
+	CheckName      string `json:"check_name"`
+	Digest         string `json:"digest"`
+	SourceNodeName string `json:"source_node_name,omitempty"`
+	DestNodeName   string `json:"dest_node_name,omitempty"`
+	Reason         string `json:"reason"`
+}
+
 // Stats holds statistics for the agent status command
 type Stats struct {
 	// Following
@@ -80,4 +94,6 @@ type CLCRunnerStats struct {
 	MetricSamples        int  `json:"MetricSamples"`
 	IsClusterCheck       bool `json:"IsClusterCheck"`
 	LastExecFailed       bool `json:"LastExecFailed"`
+	IsTransactional      bool `json:"IsTransactional"` // sts
+	InTransaction        bool `json:"InTransaction"`   // sts
 }
@@ -5,9 +5,13 @@
 
 package clusteragent
 
-import "github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks"
+import (
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+)
 
 // ServerContext holds business logic classes required to setup API endpoints
 type ServerContext struct {
 	ClusterCheckHandler *clusterchecks.Handler
+	CheckStateStore     state.CheckStateAPI // sts
 }
@@ -19,4 +19,6 @@ type CheckHandler interface {
 	CheckAPI
 	Name() string
 	GetConfig() (config, initConfig integration.Data)
+	GetWarnings() []error
+	GetAgentIntegrationID() (string, bool)
 }
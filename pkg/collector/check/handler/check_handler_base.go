@@ -1,16 +1,105 @@
 package handler
 
 import (
+	"sync"
+
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+const (
+	// agentIntegrationType and agentIntegrationInstanceType are the types of the components the python checks submit
+	// for their integration and their instance
+	agentIntegrationType         = "agent-integration"
+	agentIntegrationInstanceType = "agent-integration-instance"
 )
 
 // CheckHandlerBase forms the base of the transactional and non-transactional check handler
 type CheckHandlerBase struct {
 	CheckIdentifier
 	config, initConfig integration.Data
+	validator          *TopologyValidator
+	// warnings are reported by the check handler itself, i.e. when a transaction of the check is discarded after an
+	// agent restart
+	warnings    []error
+	warningsMux sync.Mutex
+	// agentIntegrationID is the external id of the agent-integration instance component, or the agent-integration
+	// component when the check does not submit an instance component
+	agentIntegrationID           string
+	agentIntegrationInstanceSeen bool
+	agentIntegrationMux          sync.RWMutex
 }
 
 // GetConfig returns the config and the init config of the check
 func (ch *CheckHandlerBase) GetConfig() (integration.Data, integration.Data) {
 	return ch.config, ch.initConfig
 }
+
+// GetWarnings returns the warnings of the check handler and the problems found in the topology and health submitted by
+// the check since the last call
+func (ch *CheckHandlerBase) GetWarnings() []error {
+	ch.warningsMux.Lock()
+	warnings := ch.warnings
+	ch.warnings = nil
+	ch.warningsMux.Unlock()
+
+	return append(warnings, ch.validator.Warnings()...)
+}
+
+// GetAgentIntegrationID returns the external id of the agent-integration instance or agent-integration component that
+// was submitted by the check, if any
+func (ch *CheckHandlerBase) GetAgentIntegrationID() (string, bool) {
+	ch.agentIntegrationMux.RLock()
+	defer ch.agentIntegrationMux.RUnlock()
+	return ch.agentIntegrationID, ch.agentIntegrationID != ""
+}
+
+// observeComponent keeps the external id of the agent-integration components submitted by the check, an instance
+// component is preferred over the integration component
+func (ch *CheckHandlerBase) observeComponent(component topology.Component) {
+	switch component.Type.Name {
+	case agentIntegrationInstanceType:
+		ch.agentIntegrationMux.Lock()
+		ch.agentIntegrationID = component.ExternalID
+		ch.agentIntegrationInstanceSeen = true
+		ch.agentIntegrationMux.Unlock()
+	case agentIntegrationType:
+		ch.agentIntegrationMux.Lock()
+		if !ch.agentIntegrationInstanceSeen {
+			ch.agentIntegrationID = component.ExternalID
+		}
+		ch.agentIntegrationMux.Unlock()
+	}
+}
+
+// base returns the CheckHandlerBase of a transactional or non-transactional check handler
+func (ch *CheckHandlerBase) base() *CheckHandlerBase {
+	return ch
+}
+
+// takeOver continues the topology validation, the pending warnings and the agent-integration component of the previous
+// check handler of the check, when it is replaced by a transactional check handler
+func (ch *CheckHandlerBase) takeOver(previous *CheckHandlerBase) {
+	ch.validator = previous.validator
+
+	previous.warningsMux.Lock()
+	ch.warningsMux.Lock()
+	ch.warnings = append(previous.warnings, ch.warnings...)
+	previous.warnings = nil
+	ch.warningsMux.Unlock()
+	previous.warningsMux.Unlock()
+
+	previous.agentIntegrationMux.RLock()
+	ch.agentIntegrationMux.Lock()
+	ch.agentIntegrationID = previous.agentIntegrationID
+	ch.agentIntegrationInstanceSeen = previous.agentIntegrationInstanceSeen
+	ch.agentIntegrationMux.Unlock()
+	previous.agentIntegrationMux.RUnlock()
+}
+
+// addWarning reports a warning of the check handler on the next run of the check
+func (ch *CheckHandlerBase) addWarning(warning error) {
+	ch.warningsMux.Lock()
+	defer ch.warningsMux.Unlock()
+	ch.warnings = append(ch.warnings, warning)
+}
@@ -21,6 +21,7 @@ func MakeNonTransactionalCheckHandler(check CheckIdentifier, config, initConfig
 			CheckIdentifier: check,
 			config:          config,
 			initConfig:      initConfig,
+			validator:       NewTopologyValidator(check.String(), GetCheckManagerConfig().TopologyValidation),
 		},
 	}
 }
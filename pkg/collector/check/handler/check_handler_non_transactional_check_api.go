@@ -56,21 +56,33 @@ func (ch *NonTransactionalCheckHandler) GetState(key string) string {
 
 // SubmitComponent submits a component to the Global Batcher to be batched.
 func (ch *NonTransactionalCheckHandler) SubmitComponent(instance topology.Instance, component topology.Component) {
+	if !ch.validator.ValidateComponent(instance, component) {
+		return
+	}
+	ch.observeComponent(component)
 	batcher.GetBatcher().SubmitComponent(ch.ID(), instance, component)
 }
 
 // SubmitRelation submits a relation to the Global Batcher to be batched.
 func (ch *NonTransactionalCheckHandler) SubmitRelation(instance topology.Instance, relation topology.Relation) {
+	if !ch.validator.ValidateRelation(instance, relation) {
+		return
+	}
 	batcher.GetBatcher().SubmitRelation(ch.ID(), instance, relation)
 }
 
 // SubmitStartSnapshot submits a start snapshot to the Global Batcher to be batched.
 func (ch *NonTransactionalCheckHandler) SubmitStartSnapshot(instance topology.Instance) {
+	ch.validator.StartSnapshot(instance)
 	batcher.GetBatcher().SubmitStartSnapshot(ch.ID(), instance)
 }
 
-// SubmitStopSnapshot submits a stop snapshot to the Global Batcher to be batched.
+// SubmitStopSnapshot submits a stop snapshot to the Global Batcher to be batched, preceded by the relations that were
+// held back by the topology validation.
 func (ch *NonTransactionalCheckHandler) SubmitStopSnapshot(instance topology.Instance) {
+	for _, relation := range ch.validator.StopSnapshot(instance) {
+		batcher.GetBatcher().SubmitRelation(ch.ID(), instance, relation)
+	}
 	batcher.GetBatcher().SubmitStopSnapshot(ch.ID(), instance)
 }
 
@@ -81,6 +93,9 @@ func (ch *NonTransactionalCheckHandler) SubmitDelete(instance topology.Instance,
 
 // SubmitHealthCheckData submits health check data to the Global Batcher to be batched.
 func (ch *NonTransactionalCheckHandler) SubmitHealthCheckData(stream health.Stream, data health.CheckData) {
+	if !ch.validator.ValidateHealthCheckData(stream, data) {
+		return
+	}
 	batcher.GetBatcher().SubmitHealthCheckData(ch.ID(), stream, data)
 }
 
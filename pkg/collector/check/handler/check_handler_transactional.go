@@ -28,6 +28,7 @@ func NewTransactionalCheckHandler(check CheckIdentifier, config, initConfig inte
 			CheckIdentifier: check,
 			config:          config,
 			initConfig:      initConfig,
+			validator:       NewTopologyValidator(check.String(), GetCheckManagerConfig().TopologyValidation),
 		},
 		shutdownChannel:           make(chan bool, 1),
 		transactionChannel:        make(chan StartTransaction, 1),
@@ -223,6 +224,14 @@ currentTxHandler:
 
 				break currentTxHandler
 
+			case transactionmanager.RecoveredTransactionDiscarded:
+				_ = log.Warnf("Transaction %s for check %s was discarded after an agent restart: %s", msg.TransactionID,
+					ch.ID(), msg.Reason)
+
+				// let the check report it, the data of the discarded transaction is sent again by the next snapshot
+				ch.addWarning(fmt.Errorf("transaction %s was discarded after an agent restart: %s", msg.TransactionID,
+					msg.Reason))
+
 			case transactionmanager.CompleteTransaction:
 				if msg.TransactionID != ch.GetCurrentTransaction() {
 					_ = log.Warnf("Attempting to complete transaction that is not the current transaction for this"+
@@ -66,6 +66,10 @@ func (ch *TransactionalCheckHandler) GetState(key string) string {
 
 // SubmitComponent submits a component to the current transaction channel to be forwarded.
 func (ch *TransactionalCheckHandler) SubmitComponent(instance topology.Instance, component topology.Component) {
+	if !ch.validator.ValidateComponent(instance, component) {
+		return
+	}
+	ch.observeComponent(component)
 	ch.currentTransactionChannel <- SubmitComponent{
 		Instance:  instance,
 		Component: component,
@@ -74,6 +78,9 @@ func (ch *TransactionalCheckHandler) SubmitComponent(instance topology.Instance,
 
 // SubmitRelation submits a relation to the current transaction channel to be forwarded.
 func (ch *TransactionalCheckHandler) SubmitRelation(instance topology.Instance, relation topology.Relation) {
+	if !ch.validator.ValidateRelation(instance, relation) {
+		return
+	}
 	ch.currentTransactionChannel <- SubmitRelation{
 		Instance: instance,
 		Relation: relation,
@@ -83,12 +90,20 @@ func (ch *TransactionalCheckHandler) SubmitRelation(instance topology.Instance,
 
 // SubmitStartSnapshot submits a start snapshot to the current transaction channel to be forwarded.
 func (ch *TransactionalCheckHandler) SubmitStartSnapshot(instance topology.Instance) {
+	ch.validator.StartSnapshot(instance)
 	ch.currentTransactionChannel <- SubmitStartSnapshot{Instance: instance}
 
 }
 
-// SubmitStopSnapshot submits a stop snapshot to the current transaction channel to be forwarded.
+// SubmitStopSnapshot submits a stop snapshot to the current transaction channel to be forwarded, preceded by the
+// relations that were held back by the topology validation.
 func (ch *TransactionalCheckHandler) SubmitStopSnapshot(instance topology.Instance) {
+	for _, relation := range ch.validator.StopSnapshot(instance) {
+		ch.currentTransactionChannel <- SubmitRelation{
+			Instance: instance,
+			Relation: relation,
+		}
+	}
 	ch.currentTransactionChannel <- SubmitStopSnapshot{Instance: instance}
 }
 
@@ -102,6 +117,9 @@ func (ch *TransactionalCheckHandler) SubmitDelete(instance topology.Instance, to
 
 // SubmitHealthCheckData submits health check data to the current transaction channel to be forwarded.
 func (ch *TransactionalCheckHandler) SubmitHealthCheckData(stream health.Stream, data health.CheckData) {
+	if !ch.validator.ValidateHealthCheckData(stream, data) {
+		return
+	}
 	ch.currentTransactionChannel <- SubmitHealthCheckData{
 		Stream: stream,
 		Data:   data,
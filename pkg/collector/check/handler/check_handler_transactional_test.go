@@ -188,6 +188,41 @@ func TestCheckHandler_TransactionsIncorrectComplete(t *testing.T) {
 	ch.Stop()
 }
 
+func TestCheckHandler_RecoveredTransactionDiscarded(t *testing.T) {
+	testTxManager := transactionmanager.NewMockTransactionManager()
+	transactionbatcher.NewMockTransactionalBatcher()
+
+	ch := NewTransactionalCheckHandler(&check.STSTestCheck{Name: "my-check-handler-recovered-discard-test-check"},
+		integration.Data{1, 2, 3}, integration.Data{0, 0, 0}).(*TransactionalCheckHandler)
+
+	transaction := ch.StartTransaction()
+	assert.Eventually(t, func() bool {
+		return transaction == testTxManager.GetCurrentTransaction()
+	}, 50*time.Millisecond, 10*time.Millisecond)
+
+	// the transaction manager notifies the check of a journaled transaction that was discarded after a restart
+	testTxManager.GetCurrentTransactionNotifyChannel() <- transactionmanager.RecoveredTransactionDiscarded{
+		TransactionID: "recovered-transaction", Reason: "in progress when the agent stopped",
+	}
+
+	var warnings []error
+	assert.Eventually(t, func() bool {
+		warnings = append(warnings, ch.GetWarnings()...)
+		return len(warnings) > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	assert.Len(t, warnings, 1)
+	assert.EqualError(t, warnings[0], "transaction recovered-transaction was discarded after an agent restart: "+
+		"in progress when the agent stopped")
+
+	// the warning is reported once and the current transaction is not affected
+	assert.Empty(t, ch.GetWarnings())
+	assert.Equal(t, transaction, ch.GetCurrentTransaction())
+
+	testTxManager.GetCurrentTransactionNotifyChannel() <- transactionmanager.CompleteTransaction{TransactionID: transaction}
+	time.Sleep(50 * time.Millisecond)
+	ch.Stop()
+}
+
 func TestCheckHandler_State(t *testing.T) {
 	os.Setenv("DD_CHECK_STATE_ROOT_PATH", "./testdata")
 	state.InitCheckStateManager()
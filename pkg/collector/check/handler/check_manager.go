@@ -34,6 +34,8 @@ func GetCheckManager() *CheckManager {
 type CheckManager struct {
 	checkHandlers map[string]CheckHandler
 	config        Config
+	// mux guards the check handlers, the checks get and register their check handlers concurrently
+	mux sync.RWMutex
 }
 
 // newCheckManager returns a instance of the Check Manager
@@ -53,15 +55,76 @@ func (cm *CheckManager) GetCheckHandler(checkID check.ID) CheckHandler {
 		return nil
 	}
 
+	cm.mux.RLock()
 	ch, found := cm.checkHandlers[string(checkID)]
+	cm.mux.RUnlock()
+	if found {
+		return ch
+	}
+
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+	// another check run can have registered the check handler in the meantime
+	if ch, found := cm.checkHandlers[string(checkID)]; found {
+		return ch
+	}
+	log.Debugf(fmt.Sprintf("No check handler found for %s. Registering a non-transactional check handler.", checkID))
+	return cm.registerNonTransactionalCheckHandler(NewCheckIdentifier(checkID), nil, nil)
+}
+
+// GetCheckWarnings returns the warnings, including the topology validation warnings, of the check handler of a check,
+// without registering a check handler when the check does not have one
+func (cm *CheckManager) GetCheckWarnings(checkID check.ID) []error {
+	if !cmInitialized {
+		return nil
+	}
+
+	ch, found := cm.lookupCheckHandler(checkID)
 	if !found {
-		log.Debugf(fmt.Sprintf("No check handler found for %s. Registering a non-transactional check handler.", checkID))
-		return cm.registerNonTransactionalCheckHandler(NewCheckIdentifier(checkID), nil, nil)
+		return nil
 	}
-	return ch
+	return ch.GetWarnings()
+}
+
+// GetAgentIntegrationID returns the external id of the agent-integration component submitted by a check, without
+// registering a check handler when the check does not have one
+func (cm *CheckManager) GetAgentIntegrationID(checkID check.ID) (string, bool) {
+	if !cmInitialized {
+		return "", false
+	}
+
+	ch, found := cm.lookupCheckHandler(checkID)
+	if !found {
+		return "", false
+	}
+	return ch.GetAgentIntegrationID()
 }
 
-// registerNonTransactionalCheckHandler registers a non-transactional check handler for a given check
+// IsTransactional returns true when the check has a transactional check handler, without registering a check handler
+// when the check does not have one
+func (cm *CheckManager) IsTransactional(checkID check.ID) bool {
+	if !cmInitialized {
+		return false
+	}
+
+	ch, found := cm.lookupCheckHandler(checkID)
+	if !found {
+		return false
+	}
+	_, transactional := ch.(*TransactionalCheckHandler)
+	return transactional
+}
+
+// lookupCheckHandler returns the check handler of a check, if it has one
+func (cm *CheckManager) lookupCheckHandler(checkID check.ID) (CheckHandler, bool) {
+	cm.mux.RLock()
+	defer cm.mux.RUnlock()
+	ch, found := cm.checkHandlers[string(checkID)]
+	return ch, found
+}
+
+// registerNonTransactionalCheckHandler registers a non-transactional check handler for a given check, the caller holds
+// the lock of the check handlers
 func (cm *CheckManager) registerNonTransactionalCheckHandler(check CheckIdentifier, config, initConfig integration.Data) CheckHandler {
 	ch := MakeNonTransactionalCheckHandler(check, config, initConfig)
 	cm.checkHandlers[string(check.ID())] = ch
@@ -75,6 +138,8 @@ func (cm *CheckManager) MakeCheckHandlerTransactional(checkID check.ID) CheckHan
 		return nil
 	}
 
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
 	ch, found := cm.checkHandlers[string(checkID)]
 	if !found {
 		_ = log.Errorf("No check handler found for %s.", checkID)
@@ -83,6 +148,9 @@ func (cm *CheckManager) MakeCheckHandlerTransactional(checkID check.ID) CheckHan
 
 	config, initConfig := ch.GetConfig()
 	transactionalCheckHandler := NewTransactionalCheckHandler(ch, config, initConfig)
+	if previous, ok := ch.(interface{ base() *CheckHandlerBase }); ok {
+		transactionalCheckHandler.(*TransactionalCheckHandler).takeOver(previous.base())
+	}
 	cm.checkHandlers[string(checkID)] = transactionalCheckHandler
 
 	return transactionalCheckHandler
@@ -95,22 +163,27 @@ func (cm *CheckManager) RegisterCheckHandler(check CheckIdentifier, config, init
 		return nil
 	}
 
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
 	ch := cm.registerNonTransactionalCheckHandler(check, config, initConfig)
 	log.Debugf("Registering Check Handler for: %s", ch.ID())
-	cm.checkHandlers[string(check.ID())] = ch
 	return ch
 }
 
 // UnsubscribeCheckHandler removes a check handler for the given check
 func (cm *CheckManager) UnsubscribeCheckHandler(checkID check.ID) {
 	log.Debugf("Removing Check Handler for: %s", checkID)
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
 	delete(cm.checkHandlers, string(checkID))
 }
 
 // Stop clears the check handlers and re-initializes the singleton init
 func (cm *CheckManager) Stop() {
 	log.Debug("Removing all Check Handlers")
+	cm.mux.Lock()
 	cm.checkHandlers = make(map[string]CheckHandler)
+	cm.mux.Unlock()
 	cmInit = new(sync.Once)
 	cmInitialized = false
 }
@@ -5,11 +5,13 @@ import "github.com/StackVista/stackstate-agent/pkg/config"
 // Config contains all the configuration values for the check manager
 type Config struct {
 	CheckTransactionalityEnabled bool
+	TopologyValidation           TopologyValidationMode
 }
 
 // GetCheckManagerConfig returns the configuration for the checkmanager
 func GetCheckManagerConfig() Config {
 	return Config{
 		CheckTransactionalityEnabled: config.Datadog.GetBool("check_transactionality_enabled"),
+		TopologyValidation:           TopologyValidationMode(config.Datadog.GetString("check_topology_validation")),
 	}
 }
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
 )
 
@@ -91,3 +93,39 @@ func TestCheckManagerSubscriptionTransactionalityDisabled(t *testing.T) {
 	// default to true again
 	config.Datadog.Set("check_transactionality_enabled", true)
 }
+
+func TestCheckManagerIsTransactional(t *testing.T) {
+	checkManager := newCheckManager()
+	testCheck := &check.STSTestCheck{Name: "test-check"}
+
+	// assert that a check without a check handler is not transactional, and that no check handler is registered for it
+	assert.False(t, checkManager.IsTransactional(testCheck.ID()))
+	assert.Equal(t, 0, len(checkManager.checkHandlers))
+
+	checkManager.RegisterCheckHandler(testCheck, integration.Data{1, 2, 3}, integration.Data{0, 0, 0})
+	assert.False(t, checkManager.IsTransactional(testCheck.ID()))
+
+	ch := checkManager.MakeCheckHandlerTransactional(testCheck.ID())
+	defer ch.(*TransactionalCheckHandler).Stop()
+	assert.True(t, checkManager.IsTransactional(testCheck.ID()))
+}
+
+func TestCheckManagerConcurrentCheckHandlers(t *testing.T) {
+	checkManager := newCheckManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			testCheck := &check.STSTestCheck{Name: fmt.Sprintf("concurrent-check-%d", i%5)}
+			checkManager.RegisterCheckHandler(testCheck, nil, nil)
+			assert.NotNil(t, checkManager.GetCheckHandler(testCheck.ID()))
+			checkManager.GetCheckWarnings(testCheck.ID())
+			checkManager.IsTransactional(testCheck.ID())
+			checkManager.UnsubscribeCheckHandler(testCheck.ID())
+		}(i)
+	}
+	wg.Wait()
+	checkManager.Stop()
+}
@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+// TopologyValidationMode defines what the check handler does with malformed topology and health elements
+type TopologyValidationMode string
+
+const (
+	// TopologyValidationOff does not validate the submitted elements
+	TopologyValidationOff TopologyValidationMode = "off"
+	// TopologyValidationWarn reports the malformed elements as check warnings and submits them anyway
+	TopologyValidationWarn TopologyValidationMode = "warn"
+	// TopologyValidationReject reports the malformed elements as check warnings and does not submit them
+	TopologyValidationReject TopologyValidationMode = "reject"
+)
+
+// maxProblemExamples is the number of elements that is listed in the warning of a problem
+const maxProblemExamples = 3
+
+var tlmValidationProblems = telemetry.NewCounter("check_handler", "topology_validation_problems",
+	[]string{"check_name", "problem"}, "Number of malformed topology and health elements submitted by checks")
+
+// topologyProblem is a kind of malformed element that is found by the TopologyValidator
+type topologyProblem struct {
+	tag         string
+	description string
+}
+
+var (
+	problemComponentWithoutExternalID = topologyProblem{"component_without_external_id", "component(s) without an external id"}
+	problemRelationWithoutExternalID  = topologyProblem{"relation_without_external_id", "relation(s) without an external id"}
+	problemConflictingType            = topologyProblem{"conflicting_type", "element(s) submitted again with a different type"}
+	problemUnknownRelationEndpoint    = topologyProblem{"unknown_relation_endpoint", "relation(s) with a source or target that was not submitted in the snapshot"}
+	problemMissingCheckStateID        = topologyProblem{"missing_check_state_id", "health check state(s) without a checkStateId"}
+)
+
+// problemKey groups the occurrences of a problem in a topology instance or health stream
+type problemKey struct {
+	scope   string
+	problem topologyProblem
+}
+
+// problemOccurrences counts the occurrences of a problem and keeps the first elements as examples
+type problemOccurrences struct {
+	count    int
+	examples []string
+}
+
+// snapshotValidation is the state of the topology snapshot of an instance that is being validated
+type snapshotValidation struct {
+	// types are the type names of the components and relations by external id
+	types map[string]string
+	// identifiers are the external ids and identifiers of the components that relations can refer to
+	identifiers map[string]bool
+	// urnTypes are the urn types, i.e. "host" for "urn:host:/abc", of the identifiers submitted in the snapshot
+	urnTypes map[string]bool
+	// relations are checked for their source and target when the snapshot is stopped
+	relations []topology.Relation
+}
+
+// TopologyValidator finds malformed topology and health elements submitted by a check: elements without an external
+// id, an external id that is submitted again with a different type, relations of which the source or target was never
+// submitted in the snapshot while other components of its urn type were and health check states without a checkStateId. The problems are reported as warnings of
+// the next check run and, when rejecting, the malformed elements are not submitted.
+type TopologyValidator struct {
+	mode      TopologyValidationMode
+	checkName string
+
+	mux       sync.Mutex
+	snapshots map[string]*snapshotValidation
+	problems  map[problemKey]*problemOccurrences
+}
+
+// NewTopologyValidator returns a TopologyValidator for the check with the given name
+func NewTopologyValidator(checkName string, mode TopologyValidationMode) *TopologyValidator {
+	switch mode {
+	case TopologyValidationWarn, TopologyValidationReject:
+	default:
+		mode = TopologyValidationOff
+	}
+
+	return &TopologyValidator{
+		mode:      mode,
+		checkName: checkName,
+		snapshots: make(map[string]*snapshotValidation),
+		problems:  make(map[problemKey]*problemOccurrences),
+	}
+}
+
+// StartSnapshot starts validating the topology snapshot of an instance
+func (v *TopologyValidator) StartSnapshot(instance topology.Instance) {
+	if v.mode == TopologyValidationOff {
+		return
+	}
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.snapshots[instance.GoString()] = &snapshotValidation{
+		types:       make(map[string]string),
+		identifiers: make(map[string]bool),
+		urnTypes:    make(map[string]bool),
+	}
+}
+
+// ValidateComponent validates a component and returns whether it should be submitted
+func (v *TopologyValidator) ValidateComponent(instance topology.Instance, component topology.Component) bool {
+	if v.mode == TopologyValidationOff {
+		return true
+	}
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	scope := fmt.Sprintf("topology instance %s", instance.GoString())
+	if component.ExternalID == "" {
+		v.addProblem(scope, problemComponentWithoutExternalID, fmt.Sprintf("component of type '%s'", component.Type.Name))
+		return v.mode != TopologyValidationReject
+	}
+
+	snapshot, found := v.snapshots[instance.GoString()]
+	if !found {
+		return true
+	}
+	if !v.validateType(scope, snapshot, component.ExternalID, component.Type.Name) {
+		return false
+	}
+	snapshot.addIdentifier(component.ExternalID)
+	for _, identifier := range componentIdentifiers(component) {
+		snapshot.addIdentifier(identifier)
+	}
+	return true
+}
+
+// ValidateRelation validates a relation and returns whether it should be submitted. When rejecting, the relations of a
+// snapshot are held back until the snapshot is stopped, because their source and target can be submitted after them.
+func (v *TopologyValidator) ValidateRelation(instance topology.Instance, relation topology.Relation) bool {
+	if v.mode == TopologyValidationOff {
+		return true
+	}
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	scope := fmt.Sprintf("topology instance %s", instance.GoString())
+	if relation.ExternalID == "" {
+		v.addProblem(scope, problemRelationWithoutExternalID, fmt.Sprintf("%s->%s", relation.SourceID, relation.TargetID))
+		return v.mode != TopologyValidationReject
+	}
+
+	snapshot, found := v.snapshots[instance.GoString()]
+	if !found {
+		return true
+	}
+	if !v.validateType(scope, snapshot, relation.ExternalID, relation.Type.Name) {
+		return false
+	}
+	snapshot.relations = append(snapshot.relations, relation)
+	return v.mode != TopologyValidationReject
+}
+
+// StopSnapshot checks the source and target of the relations of the snapshot of an instance. When rejecting, it returns
+// the relations that were held back and have a known source and target, to be submitted before the stop snapshot.
+func (v *TopologyValidator) StopSnapshot(instance topology.Instance) []topology.Relation {
+	if v.mode == TopologyValidationOff {
+		return nil
+	}
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	snapshot, found := v.snapshots[instance.GoString()]
+	if !found {
+		return nil
+	}
+	delete(v.snapshots, instance.GoString())
+
+	scope := fmt.Sprintf("topology instance %s", instance.GoString())
+	valid := make([]topology.Relation, 0, len(snapshot.relations))
+	for _, relation := range snapshot.relations {
+		if snapshot.isUnknown(relation.SourceID) || snapshot.isUnknown(relation.TargetID) {
+			v.addProblem(scope, problemUnknownRelationEndpoint, relation.ExternalID)
+			continue
+		}
+		valid = append(valid, relation)
+	}
+
+	if v.mode != TopologyValidationReject {
+		return nil
+	}
+	return valid
+}
+
+// ValidateHealthCheckData validates health check data and returns whether it should be submitted
+func (v *TopologyValidator) ValidateHealthCheckData(stream health.Stream, data health.CheckData) bool {
+	if v.mode == TopologyValidationOff {
+		return true
+	}
+
+	if checkStateID(data) != "" {
+		return true
+	}
+
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	v.addProblem(fmt.Sprintf("health stream %s", stream.GoString()), problemMissingCheckStateID, data.JSONString())
+	return v.mode != TopologyValidationReject
+}
+
+// Warnings returns the problems found since the last call as warnings, one per problem for every topology instance or
+// health stream
+func (v *TopologyValidator) Warnings() []error {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	warnings := make([]string, 0, len(v.problems))
+	for key, occurrences := range v.problems {
+		warning := fmt.Sprintf("%s has %d %s: %s", key.scope, occurrences.count, key.problem.description,
+			strings.Join(occurrences.examples, ", "))
+		if occurrences.count > len(occurrences.examples) {
+			warning += ", ..."
+		}
+		if v.mode == TopologyValidationReject {
+			warning += ". These were not submitted"
+		}
+		warnings = append(warnings, warning)
+	}
+	v.problems = make(map[problemKey]*problemOccurrences)
+
+	sort.Strings(warnings)
+	errs := make([]error, 0, len(warnings))
+	for _, warning := range warnings {
+		errs = append(errs, fmt.Errorf("topology validation: %s", warning))
+	}
+	return errs
+}
+
+// validateType records the type of an element of the snapshot and returns false when an element with the same external
+// id but a different type was submitted before and the element should be rejected
+func (v *TopologyValidator) validateType(scope string, snapshot *snapshotValidation, externalID, typeName string) bool {
+	previousType, found := snapshot.types[externalID]
+	if !found {
+		snapshot.types[externalID] = typeName
+		return true
+	}
+	if previousType == typeName {
+		return true
+	}
+
+	v.addProblem(scope, problemConflictingType, fmt.Sprintf("%s ('%s' and '%s')", externalID, previousType, typeName))
+	return v.mode != TopologyValidationReject
+}
+
+func (v *TopologyValidator) addProblem(scope string, problem topologyProblem, example string) {
+	tlmValidationProblems.Inc(v.checkName, problem.tag)
+
+	key := problemKey{scope: scope, problem: problem}
+	occurrences, found := v.problems[key]
+	if !found {
+		occurrences = &problemOccurrences{}
+		v.problems[key] = occurrences
+	}
+	occurrences.count++
+	if len(occurrences.examples) < maxProblemExamples {
+		occurrences.examples = append(occurrences.examples, example)
+	}
+}
+
+// addIdentifier records an identifier that relations of the snapshot can refer to
+func (s *snapshotValidation) addIdentifier(identifier string) {
+	s.identifiers[identifier] = true
+	s.urnTypes[urnType(identifier)] = true
+}
+
+// isUnknown returns whether a relation endpoint is missing from the snapshot. An endpoint of which the urn type was not
+// submitted in the snapshot, i.e. "urn:host:/abc" in the snapshot of a check that does not submit hosts, refers to a
+// component of another check and is not unknown.
+func (s *snapshotValidation) isUnknown(identifier string) bool {
+	return !s.identifiers[identifier] && s.urnTypes[urnType(identifier)]
+}
+
+// urnType returns the type of an urn identifier, i.e. "host" for "urn:host:/abc", or "" when it is not an urn
+func urnType(identifier string) string {
+	if !strings.HasPrefix(identifier, "urn:") {
+		return ""
+	}
+	rest := strings.TrimPrefix(identifier, "urn:")
+	if i := strings.Index(rest, ":"); i >= 0 {
+		return rest[:i]
+	}
+	return ""
+}
+
+// componentIdentifiers returns the identifiers of a component, these are a []string for Go checks and a []interface{}
+// for Python checks
+func componentIdentifiers(component topology.Component) []string {
+	switch identifiers := component.Data["identifiers"].(type) {
+	case []string:
+		return identifiers
+	case []interface{}:
+		result := make([]string, 0, len(identifiers))
+		for _, identifier := range identifiers {
+			if s, ok := identifier.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// checkStateID returns the checkStateId of structured or unstructured health check data
+func checkStateID(data health.CheckData) string {
+	switch {
+	case data.CheckState != nil:
+		return data.CheckState.CheckStateID
+	case data.CheckStateDeleted != nil:
+		return data.CheckStateDeleted.CheckStateID
+	}
+	if id, ok := data.Unstructured["checkStateId"].(string); ok {
+		return id
+	}
+	return ""
+}
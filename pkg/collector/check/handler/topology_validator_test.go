@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	validComponent = topology.Component{
+		ExternalID: "urn:service:/checkout",
+		Type:       topology.Type{Name: "service"},
+		Data:       topology.Data{"identifiers": []interface{}{"urn:service:/checkout-alias"}},
+	}
+	validRelation = topology.Relation{
+		ExternalID: "urn:service:/checkout->urn:service:/checkout-alias",
+		SourceID:   "urn:service:/checkout",
+		TargetID:   "urn:service:/checkout-alias",
+		Type:       topology.Type{Name: "calls"},
+	}
+	componentWithoutID   = topology.Component{Type: topology.Type{Name: "service"}}
+	conflictingComponent = topology.Component{
+		ExternalID: "urn:service:/checkout",
+		Type:       topology.Type{Name: "database"},
+	}
+	danglingRelation = topology.Relation{
+		ExternalID: "urn:service:/checkout->urn:service:/unknown",
+		SourceID:   "urn:service:/checkout",
+		TargetID:   "urn:service:/unknown",
+		Type:       topology.Type{Name: "calls"},
+	}
+	crossCheckRelation = topology.Relation{
+		ExternalID: "urn:service:/checkout->urn:host:/my-host",
+		SourceID:   "urn:service:/checkout",
+		TargetID:   "urn:host:/my-host",
+		Type:       topology.Type{Name: "runs_on"},
+	}
+	checkDataWithoutID = health.CheckData{Unstructured: map[string]interface{}{"name": "cpu", "health": "CLEAR"}}
+)
+
+func TestTopologyValidatorOff(t *testing.T) {
+	validator := NewTopologyValidator("my-check", TopologyValidationOff)
+
+	validator.StartSnapshot(instance)
+	assert.True(t, validator.ValidateComponent(instance, componentWithoutID))
+	assert.True(t, validator.ValidateRelation(instance, danglingRelation))
+	assert.Empty(t, validator.StopSnapshot(instance))
+	assert.True(t, validator.ValidateHealthCheckData(testStream, checkDataWithoutID))
+	assert.Empty(t, validator.Warnings())
+
+	// an unknown mode does not validate
+	assert.Equal(t, TopologyValidationOff, NewTopologyValidator("my-check", "strict").mode)
+}
+
+func TestTopologyValidatorWarn(t *testing.T) {
+	validator := NewTopologyValidator("my-check", TopologyValidationWarn)
+
+	validator.StartSnapshot(instance)
+	assert.True(t, validator.ValidateComponent(instance, validComponent))
+	assert.True(t, validator.ValidateComponent(instance, componentWithoutID))
+	assert.True(t, validator.ValidateComponent(instance, conflictingComponent))
+	assert.True(t, validator.ValidateRelation(instance, validRelation))
+	assert.True(t, validator.ValidateRelation(instance, danglingRelation))
+	assert.Empty(t, validator.StopSnapshot(instance))
+	assert.True(t, validator.ValidateHealthCheckData(testStream, checkDataWithoutID))
+	assert.True(t, validator.ValidateHealthCheckData(testStream, health.CheckData{
+		CheckState: &health.CheckState{CheckStateID: "cpu", Health: health.Clear},
+	}))
+
+	assert.Equal(t, []error{
+		errors.New("topology validation: health stream {\"urn\":\"urn\",\"sub_stream_id\":\"bla\"} has 1 health check state(s) without a checkStateId: {\"health\":\"CLEAR\",\"name\":\"cpu\"}"),
+		errors.New("topology validation: topology instance type_mytype_url_myurl has 1 component(s) without an external id: component of type 'service'"),
+		errors.New("topology validation: topology instance type_mytype_url_myurl has 1 element(s) submitted again with a different type: urn:service:/checkout ('service' and 'database')"),
+		errors.New("topology validation: topology instance type_mytype_url_myurl has 1 relation(s) with a source or target that was not submitted in the snapshot: urn:service:/checkout->urn:service:/unknown"),
+	}, validator.Warnings())
+
+	// the warnings are reported once
+	assert.Empty(t, validator.Warnings())
+}
+
+func TestTopologyValidatorExamples(t *testing.T) {
+	validator := NewTopologyValidator("my-check", TopologyValidationWarn)
+
+	for i := 0; i < 5; i++ {
+		validator.ValidateComponent(instance, componentWithoutID)
+	}
+	assert.Equal(t, []error{
+		errors.New("topology validation: topology instance type_mytype_url_myurl has 5 component(s) without an external id: " +
+			"component of type 'service', component of type 'service', component of type 'service', ..."),
+	}, validator.Warnings())
+}
+
+func TestTopologyValidatorReject(t *testing.T) {
+	validator := NewTopologyValidator("my-check", TopologyValidationReject)
+
+	validator.StartSnapshot(instance)
+	assert.True(t, validator.ValidateComponent(instance, validComponent))
+	assert.False(t, validator.ValidateComponent(instance, componentWithoutID))
+	assert.False(t, validator.ValidateComponent(instance, conflictingComponent))
+	// the relations are held back until the stop snapshot
+	assert.False(t, validator.ValidateRelation(instance, validRelation))
+	assert.False(t, validator.ValidateRelation(instance, danglingRelation))
+	assert.Equal(t, []topology.Relation{validRelation}, validator.StopSnapshot(instance))
+	assert.False(t, validator.ValidateHealthCheckData(testStream, checkDataWithoutID))
+	assert.Len(t, validator.Warnings(), 4)
+
+	// relations to components of another check, of which the urn type is not submitted in the snapshot, are submitted
+	validator.StartSnapshot(instance)
+	assert.True(t, validator.ValidateComponent(instance, validComponent))
+	assert.False(t, validator.ValidateRelation(instance, crossCheckRelation))
+	assert.Equal(t, []topology.Relation{crossCheckRelation}, validator.StopSnapshot(instance))
+	assert.Empty(t, validator.Warnings())
+
+	// relations outside of a snapshot can not be checked for their source and target
+	assert.True(t, validator.ValidateRelation(instance, danglingRelation))
+	assert.Empty(t, validator.StopSnapshot(instance))
+	assert.Empty(t, validator.Warnings())
+}
+
+func TestCheckHandlerTopologyValidation(t *testing.T) {
+	config.Datadog.Set("check_topology_validation", "reject")
+	defer config.Datadog.Set("check_topology_validation", "off")
+
+	testCheck := &check.STSTestCheck{Name: "my-check-handler-topology-validation-check"}
+	ch := MakeNonTransactionalCheckHandler(testCheck, nil, nil)
+	mockBatcher := batcher.NewMockBatcher()
+
+	ch.SubmitStartSnapshot(instance)
+	ch.SubmitRelation(instance, validRelation)
+	ch.SubmitRelation(instance, danglingRelation)
+	ch.SubmitComponent(instance, validComponent)
+	ch.SubmitComponent(instance, componentWithoutID)
+	ch.SubmitHealthCheckData(testStream, checkDataWithoutID)
+	ch.SubmitStopSnapshot(instance)
+
+	assert.Equal(t, batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
+		ch.ID(): {
+			Topology: &topology.Topology{
+				StartSnapshot: true,
+				StopSnapshot:  true,
+				Instance:      instance,
+				Components:    []topology.Component{validComponent},
+				Relations:     []topology.Relation{validRelation},
+				DeleteIDs:     []string{},
+			},
+			Health: map[string]health.Health{},
+		},
+	}), mockBatcher.CollectedTopology.Flush())
+
+	warnings := ch.GetWarnings()
+	assert.Len(t, warnings, 3)
+	for _, warning := range warnings {
+		assert.Contains(t, warning.Error(), "These were not submitted")
+	}
+}
+
+func TestCheckManagerTopologyValidationWarnings(t *testing.T) {
+	config.Datadog.Set("check_topology_validation", "warn")
+	defer config.Datadog.Set("check_topology_validation", "off")
+
+	checkManager := newCheckManager()
+	testCheck := &check.STSTestCheck{Name: "my-check-manager-topology-validation-check"}
+
+	// checks without a check handler have no warnings, and do not get a check handler
+	assert.Empty(t, checkManager.GetCheckWarnings(testCheck.ID()))
+	assert.Empty(t, checkManager.checkHandlers)
+
+	ch := checkManager.RegisterCheckHandler(testCheck, nil, nil)
+	_ = batcher.NewMockBatcher()
+	ch.SubmitComponent(instance, componentWithoutID)
+	assert.Len(t, checkManager.GetCheckWarnings(testCheck.ID()), 1)
+}
+
+func TestCheckManagerTopologyValidationTransactional(t *testing.T) {
+	config.Datadog.Set("check_topology_validation", "warn")
+	defer config.Datadog.Set("check_topology_validation", "off")
+
+	checkManager := newCheckManager()
+	checkManager.config.CheckTransactionalityEnabled = true
+	testCheck := &check.STSTestCheck{Name: "my-check-manager-topology-validation-transactional-check"}
+
+	ch := checkManager.RegisterCheckHandler(testCheck, nil, nil)
+	_ = batcher.NewMockBatcher()
+	ch.SubmitComponent(instance, componentWithoutID)
+
+	// the warnings found before the check handler became transactional are still reported
+	transactionalCheckHandler := checkManager.MakeCheckHandlerTransactional(testCheck.ID())
+	defer transactionalCheckHandler.(*TransactionalCheckHandler).Stop()
+	assert.Len(t, checkManager.GetCheckWarnings(testCheck.ID()), 1)
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	// DiskBackend is the check_state_backend that stores the check states on disk, in the check_state_root_path
+	DiskBackend = "disk"
+	// ClusterAgentBackend is the check_state_backend that stores the check states in the check state store of the
+	// cluster agent, so they are kept when a cluster check is dispatched to another cluster check runner
+	ClusterAgentBackend = "cluster_agent"
+)
+
+// ClusterAgentClient contains the functions of the cluster agent client to use its check state store
+type ClusterAgentClient interface {
+	GetCheckState(key string) (string, error)
+	SetCheckState(key, state string) error
+	DeleteCheckState(key string) error
+	ListCheckStates(check string) (map[string]string, error)
+}
+
+// ClusterAgentCheckStateManager is the implementation of the CheckStateAPI that read / writes state from / to the
+// check state store of the cluster agent. The states are not cached, another cluster check runner can have changed
+// them since the check was dispatched to this one.
+type ClusterAgentCheckStateManager struct {
+	Client ClusterAgentClient
+}
+
+// NewClusterAgentCheckStateManager returns a pointer to an instance of ClusterAgentCheckStateManager which implements
+// the CheckStateAPI
+func NewClusterAgentCheckStateManager(client ClusterAgentClient) *ClusterAgentCheckStateManager {
+	return &ClusterAgentCheckStateManager{
+		Client: client,
+	}
+}
+
+// SetState stores data in the check state store of the cluster agent
+func (cs *ClusterAgentCheckStateManager) SetState(key, value string) error {
+	err := cs.Client.SetCheckState(key, value)
+	if err != nil {
+		_ = log.Errorf("Unable to set a new state in the cluster agent. Error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetState returns a value previously stored, or an error that occurred when trying to retrieve the state for a given
+// key
+func (cs *ClusterAgentCheckStateManager) GetState(key string) (string, error) {
+	state, err := cs.Client.GetCheckState(key)
+	if err != nil {
+		_ = log.Errorf("Error occurred loading state from the cluster agent. Error: %v", err)
+		return "{}", err
+	}
+	return state, nil
+}
+
+// ListStates returns the states that are stored in the cluster agent for a check, by their key without the
+// characters that are not allowed
+func (cs *ClusterAgentCheckStateManager) ListStates(check string) (map[string]string, error) {
+	return cs.Client.ListCheckStates(check)
+}
+
+// DeleteState removes the state for a given key from the cluster agent
+func (cs *ClusterAgentCheckStateManager) DeleteState(key string) error {
+	return cs.Client.DeleteCheckState(key)
+}
+
+// Clear is a noop, the states are not cached
+func (cs *ClusterAgentCheckStateManager) Clear() {}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClusterAgentClient struct {
+	states map[string]string
+	gets   int
+	err    error
+}
+
+func (c *fakeClusterAgentClient) GetCheckState(key string) (string, error) {
+	c.gets++
+	if c.err != nil {
+		return "", c.err
+	}
+	if value, found := c.states[key]; found {
+		return value, nil
+	}
+	return "{}", nil
+}
+
+func (c *fakeClusterAgentClient) SetCheckState(key, state string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.states[key] = state
+	return nil
+}
+
+func (c *fakeClusterAgentClient) DeleteCheckState(key string) error {
+	if c.err != nil {
+		return c.err
+	}
+	delete(c.states, key)
+	return nil
+}
+
+func (c *fakeClusterAgentClient) ListCheckStates(check string) (map[string]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	states := make(map[string]string)
+	for key, value := range c.states {
+		if keyCheck, _ := SplitKey(key); keyCheck == check {
+			states[key] = value
+		}
+	}
+	return states, nil
+}
+
+func TestClusterAgentCheckStateManager(t *testing.T) {
+	client := &fakeClusterAgentClient{states: map[string]string{}}
+	csm := NewClusterAgentCheckStateManager(client)
+
+	state, err := csm.GetState("mycheck:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", state)
+
+	assert.NoError(t, csm.SetState("mycheck:cursor", `{"page":2}`))
+	assert.Equal(t, `{"page":2}`, client.states["mycheck:cursor"])
+
+	state, err = csm.GetState("mycheck:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"page":2}`, state)
+
+	// the state is not cached, a change by another cluster check runner is read from the cluster agent
+	client.states["mycheck:cursor"] = `{"page":3}`
+	state, err = csm.GetState("mycheck:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"page":3}`, state)
+	assert.Equal(t, 3, client.gets)
+
+	states, err := csm.ListStates("mycheck")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"mycheck:cursor": `{"page":3}`}, states)
+
+	assert.NoError(t, csm.DeleteState("mycheck:cursor"))
+	state, err = csm.GetState("mycheck:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", state)
+}
+
+func TestClusterAgentCheckStateManager_Errors(t *testing.T) {
+	client := &fakeClusterAgentClient{states: map[string]string{}, err: errors.New("cluster agent unavailable")}
+	csm := NewClusterAgentCheckStateManager(client)
+
+	state, err := csm.GetState("mycheck:cursor")
+	assert.Error(t, err)
+	assert.Equal(t, "{}", state)
+	assert.Error(t, csm.SetState("mycheck:cursor", `{"page":2}`))
+	assert.Error(t, csm.DeleteState("mycheck:cursor"))
+
+	client.err = nil
+	state, err = csm.GetState("mycheck:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", state)
+}
+
+func TestConfiguredCheckStateManager(t *testing.T) {
+	config := Config{StateRootPath: "/my/custom/path", CacheExpirationDuration: time.Minute, CachePurgeDuration: time.Minute}
+
+	config.Backend = DiskBackend
+	assert.IsType(t, &CheckStateManager{}, newConfiguredCheckStateManager(config))
+
+	// an unknown backend falls back to disk
+	config.Backend = "s3"
+	assert.IsType(t, &CheckStateManager{}, newConfiguredCheckStateManager(config))
+}
+
+func TestInitCheckStateManagerWith(t *testing.T) {
+	csm := NewClusterAgentCheckStateManager(&fakeClusterAgentClient{states: map[string]string{}})
+	InitCheckStateManagerWith(csm)
+	assert.Same(t, csm, GetCheckStateManager())
+
+	// the check state manager is initialized once
+	InitCheckStateManager()
+	assert.Same(t, csm, GetCheckStateManager())
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"github.com/StackVista/stackstate-agent/pkg/util/clusteragent"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"github.com/patrickmn/go-cache"
 	"io/ioutil"
@@ -18,13 +19,43 @@ var (
 	csmInit      sync.Once
 )
 
-// InitCheckStateManager initialized the default implementation of the CheckStateAPI
+// InitCheckStateManager initialized the implementation of the CheckStateAPI of the configured check_state_backend,
+// the CheckStateManager by default
 func InitCheckStateManager() {
 	csmInit.Do(func() {
-		csmInstance = NewCheckStateManager()
+		csmInstance = newConfiguredCheckStateManager(GetStateConfig())
 	})
 }
 
+// InitCheckStateManagerWith initializes the given implementation of the CheckStateAPI, like the check state store of
+// the cluster agent. It has no effect when a check state manager is initialized already.
+func InitCheckStateManagerWith(csm CheckStateAPI) {
+	csmInit.Do(func() {
+		csmInstance = csm
+	})
+}
+
+// newConfiguredCheckStateManager returns the implementation of the CheckStateAPI of a backend, it falls back to the
+// CheckStateManager when the cluster agent can not be reached
+func newConfiguredCheckStateManager(config Config) CheckStateAPI {
+	switch config.Backend {
+	case "", DiskBackend:
+	case ClusterAgentBackend:
+		dcaClient, err := clusteragent.GetClusterAgentClient()
+		if err == nil {
+			log.Infof("Storing the check states in the cluster agent")
+			return NewClusterAgentCheckStateManager(dcaClient)
+		}
+		_ = log.Warnf("Unable to use the cluster agent to store the check states, storing them on disk: %v", err)
+	default:
+		_ = log.Warnf("Unknown check_state_backend '%s', storing the check states on disk", config.Backend)
+	}
+	return &CheckStateManager{
+		Config: config,
+		Cache:  cache.New(config.CacheExpirationDuration, config.CachePurgeDuration),
+	}
+}
+
 // GetCheckStateManager returns a handle on the global check checkmanager Instance
 func GetCheckStateManager() CheckStateAPI {
 	return csmInstance
@@ -34,6 +65,8 @@ func GetCheckStateManager() CheckStateAPI {
 type CheckStateAPI interface {
 	GetState(key string) (string, error)
 	SetState(key, value string) error
+	ListStates(check string) (map[string]string, error)
+	DeleteState(key string) error
 	Clear()
 }
 
@@ -52,23 +85,35 @@ func NewCheckStateManager() *CheckStateManager {
 	}
 }
 
-// Return a file where to store the data. We split the key by ":", using the
-// first prefix as directory, if present. This is useful for integrations, which
-// use the check_id formed with $check_name:$hash
-func (cs *CheckStateManager) getFileForKey(key string) (string, error) {
+// CleanName removes the characters that are not allowed in the check and state names of a state key
+func CleanName(name string) string {
+	return invalidChars.ReplaceAllString(name, "")
+}
+
+// SplitKey splits a state key by the first ":" into the check and the name of the state, without the characters that
+// are not allowed. This is useful for integrations, which use the check_id formed with $check_name:$hash. The check
+// is empty when the key has no ":".
+func SplitKey(key string) (check, name string) {
 	paths := strings.SplitN(key, ":", 2)
-	cleanedPath := invalidChars.ReplaceAllString(paths[0], "")
 	if len(paths) == 1 {
-		// If there is no colon, just return the key
-		return filepath.Join(cs.Config.StateRootPath, cleanedPath), nil
+		return "", CleanName(paths[0])
 	}
-	// Otherwise, create the directory with a prefix
-	err := os.MkdirAll(filepath.Join(cs.Config.StateRootPath, cleanedPath), 0700)
+	return CleanName(paths[0]), CleanName(paths[1])
+}
+
+// Return a file where to store the data, using the check of the key as directory, if present.
+func (cs *CheckStateManager) getFileForKey(key string) (string, error) {
+	check, name := SplitKey(key)
+	if check == "" {
+		// If there is no check, just return the key
+		return filepath.Join(cs.Config.StateRootPath, name), nil
+	}
+	// Otherwise, create the directory of the check
+	err := os.MkdirAll(filepath.Join(cs.Config.StateRootPath, check), 0700)
 	if err != nil {
 		return "", err
 	}
-	cleanedFile := invalidChars.ReplaceAllString(paths[1], "")
-	return filepath.Join(cs.Config.StateRootPath, cleanedPath, cleanedFile), nil
+	return filepath.Join(cs.Config.StateRootPath, check, name), nil
 }
 
 // SetState stores data on disk in the config.StateRootPath directory
@@ -133,6 +178,48 @@ func (cs *CheckStateManager) readFromDisk(key string) (string, error) {
 	return strings.TrimSuffix(string(content), "\n"), nil
 }
 
+// ListStates returns the states that are stored on disk for a check, that is all the states with a key prefixed by
+// "<check>:". The keys are returned as they are stored on disk, without the characters that are not allowed in file
+// names.
+func (cs *CheckStateManager) ListStates(check string) (map[string]string, error) {
+	cleanedCheck := CleanName(check)
+	files, err := ioutil.ReadDir(filepath.Join(cs.Config.StateRootPath, cleanedCheck))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]string, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		key := cleanedCheck + ":" + file.Name()
+		state, err := cs.readFromDisk(key)
+		if err != nil {
+			return nil, err
+		}
+		states[key] = state
+	}
+	return states, nil
+}
+
+// DeleteState removes the state for a given key from disk and from the CheckStateManager Cache
+func (cs *CheckStateManager) DeleteState(key string) error {
+	path, err := cs.getFileForKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	cs.Cache.Delete(key)
+
+	return nil
+}
+
 // Clear removes all the elements in the CheckStateManager Cache
 func (cs *CheckStateManager) Clear() {
 	cs.Cache.Flush()
@@ -10,6 +10,7 @@ type Config struct {
 	StateRootPath           string
 	CacheExpirationDuration time.Duration
 	CachePurgeDuration      time.Duration
+	Backend                 string
 }
 
 // GetStateConfig returns the configuration for the CheckState
@@ -18,5 +19,6 @@ func GetStateConfig() Config {
 		StateRootPath:           config.Datadog.GetString("check_state_root_path"),
 		CacheExpirationDuration: config.Datadog.GetDuration("check_state_expiration_duration"),
 		CachePurgeDuration:      config.Datadog.GetDuration("check_state_purge_duration"),
+		Backend:                 config.Datadog.GetString("check_state_backend"),
 	}
 }
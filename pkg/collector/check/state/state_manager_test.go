@@ -34,11 +34,8 @@ func TestGetFileForKey(t *testing.T) {
 }
 
 func TestWriteToDisk(t *testing.T) {
-	// Create a directory for the temp write to disk tests
-	// We do not need to check for an error here, if this fails then the rest will
-	var _ = os.Mkdir("temp-write-to-disk-test", os.ModePerm)
-
-	os.Setenv("DD_CHECK_STATE_ROOT_PATH", "./temp-write-to-disk-test")
+	// Use a temp directory for the write to disk tests, it is removed when the test completes
+	t.Setenv("DD_CHECK_STATE_ROOT_PATH", t.TempDir())
 
 	// Create a file on the disk
 	csm := NewCheckStateManager()
@@ -137,3 +134,42 @@ func TestCheckStateManager_SetStateWithUpdate(t *testing.T) {
 	err = os.RemoveAll("./testdata/setstatecheck")
 	assert.NoError(t, err, "error cleaning up test data file")
 }
+
+func TestCheckStateManager_ListAndDeleteStates(t *testing.T) {
+	os.Setenv("DD_CHECK_STATE_ROOT_PATH", "./testdata")
+
+	csm := NewCheckStateManager()
+
+	states, err := csm.ListStates("mycheck")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"mycheck:state": "{\"a\":\"b\"}"}, states)
+
+	states, err = csm.ListStates("check-without-state")
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+
+	stateKey := "deletestatecheck:state"
+	err = csm.SetState(stateKey, "{\"c\":\"d\"}")
+	assert.NoError(t, err, "unexpected error occurred when setting state for %s", stateKey)
+
+	err = csm.DeleteState(stateKey)
+	assert.NoError(t, err, "unexpected error occurred when deleting state for %s", stateKey)
+
+	_, found := csm.Cache.Get(stateKey)
+	assert.False(t, found, "%s found in the check state manager cache after deleting it", stateKey)
+
+	states, err = csm.ListStates("deletestatecheck")
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+
+	state, err := csm.GetState(stateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", state)
+
+	// deleting a state that does not exist is not an error
+	err = csm.DeleteState(stateKey)
+	assert.NoError(t, err)
+
+	err = os.RemoveAll("./testdata/deletestatecheck")
+	assert.NoError(t, err, "error cleaning up test data file")
+}
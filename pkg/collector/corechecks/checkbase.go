@@ -15,6 +15,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check/defaults"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler" // sts
 	telemetry_utils "github.com/StackVista/stackstate-agent/pkg/telemetry/utils"
 	"github.com/StackVista/stackstate-agent/pkg/util/features"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
@@ -46,6 +47,7 @@ type CheckBase struct {
 	source         string
 	telemetry      bool
 	features       features.Features
+	checkHandler   handler.CheckHandler // sts - fallback check handler when the check manager is not initialized
 }
 
 // NewCheckBase returns a check base struct with a given check name
@@ -228,11 +230,15 @@ func (c *CheckBase) IsTelemetryEnabled() bool {
 
 // GetWarnings grabs the latest integration warnings for the check.
 func (c *CheckBase) GetWarnings() []error {
-	if len(c.latestWarnings) == 0 {
-		return nil
-	}
 	w := c.latestWarnings
 	c.latestWarnings = []error{}
+	// [sts] report the warnings of the check handler, i.e. problems found in the submitted topology and health
+	if handler.GetCheckManager() != nil {
+		w = append(w, handler.GetCheckManager().GetCheckWarnings(c.checkID)...)
+	}
+	if len(w) == 0 {
+		return nil
+	}
 	return w
 }
 
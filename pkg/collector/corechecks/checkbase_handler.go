@@ -0,0 +1,197 @@
+package corechecks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// [sts] The methods below expose the check handler to Go checks, so they submit their topology, health, raw metrics and
+// state through the same (transactional) pipeline as Python checks do through the rtloader.
+//
+// A typical transactional check run looks like:
+//
+//	return c.WithTransaction(func() error {
+//		return c.WithTopologySnapshot(instance, func() error {
+//			c.SubmitComponent(instance, component)
+//			return nil
+//		})
+//	})
+
+// GetCheckHandler returns the check handler of this check instance. Go checks are registered in the check manager by
+// the GoCheckLoader, a non-transactional check handler is used when the check manager is not initialized, like in the
+// cluster agent.
+func (c *CheckBase) GetCheckHandler() handler.CheckHandler {
+	if cm := handler.GetCheckManager(); cm != nil {
+		if ch := cm.GetCheckHandler(c.checkID); ch != nil {
+			return ch
+		}
+	}
+	if c.checkHandler == nil {
+		c.checkHandler = handler.MakeNonTransactionalCheckHandler(c, nil, nil)
+	}
+	return c.checkHandler
+}
+
+// StartTransaction starts a transaction for this check and returns its id. The check handler is made transactional
+// on the first transaction, an empty id is returned when check transactionality is disabled.
+func (c *CheckBase) StartTransaction() string {
+	return c.GetCheckHandler().StartTransaction()
+}
+
+// StopTransaction completes the current transaction of this check
+func (c *CheckBase) StopTransaction() {
+	c.GetCheckHandler().StopTransaction()
+}
+
+// DiscardTransaction fails the current transaction of this check with the given reason
+func (c *CheckBase) DiscardTransaction(reason string) {
+	c.GetCheckHandler().DiscardTransaction(reason)
+}
+
+// WithTransaction runs fn within a transaction of this check. The transaction is stopped when fn succeeds and
+// discarded with the error as reason when fn fails. When check transactionality is disabled fn is run as is.
+func (c *CheckBase) WithTransaction(fn func() error) error {
+	if c.StartTransaction() == "" {
+		return fn()
+	}
+
+	if err := fn(); err != nil {
+		c.DiscardTransaction(err.Error())
+		return err
+	}
+	c.StopTransaction()
+	return nil
+}
+
+// StateKey returns the check state manager key for a state of this check
+func (c *CheckBase) StateKey(key string) string {
+	return fmt.Sprintf("%s:%s", c.checkID, key)
+}
+
+// GetState returns the persisted state of this check for the given key
+func (c *CheckBase) GetState(key string) string {
+	return c.GetCheckHandler().GetState(c.StateKey(key))
+}
+
+// SetState persists the state of this check for the given key immediately
+func (c *CheckBase) SetState(key, state string) {
+	c.GetCheckHandler().SetState(c.StateKey(key), state)
+}
+
+// SetTransactionState persists the state of this check for the given key once the current transaction succeeds
+func (c *CheckBase) SetTransactionState(key, state string) {
+	c.GetCheckHandler().SetTransactionState(c.StateKey(key), state)
+}
+
+// GetStateValue decodes the json state of this check for the given key into value. Value is left untouched when there
+// is no state for the key.
+func (c *CheckBase) GetStateValue(key string, value interface{}) error {
+	state := c.GetState(key)
+	if state == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(state), value); err != nil {
+		return fmt.Errorf("could not decode state %s of check %s: %s", key, c.checkID, err)
+	}
+	return nil
+}
+
+// SetStateValue encodes value as json and persists it as the state of this check for the given key immediately
+func (c *CheckBase) SetStateValue(key string, value interface{}) error {
+	state, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not encode state %s of check %s: %s", key, c.checkID, err)
+	}
+	c.SetState(key, string(state))
+	return nil
+}
+
+// SetTransactionStateValue encodes value as json and persists it as the state of this check for the given key once the
+// current transaction succeeds
+func (c *CheckBase) SetTransactionStateValue(key string, value interface{}) error {
+	state, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not encode state %s of check %s: %s", key, c.checkID, err)
+	}
+	c.SetTransactionState(key, string(state))
+	return nil
+}
+
+// WithTopologySnapshot submits the components and relations of fn as a topology snapshot for the instance. The stop
+// snapshot is only submitted when fn succeeds, so a failing run does not remove topology that could not be collected.
+func (c *CheckBase) WithTopologySnapshot(instance topology.Instance, fn func() error) error {
+	ch := c.GetCheckHandler()
+	ch.SubmitStartSnapshot(instance)
+	if err := fn(); err != nil {
+		log.Debugf("Skipping the topology stop snapshot of check %s for %s: %s", c.checkID, instance.GoString(), err)
+		return err
+	}
+	c.GetCheckHandler().SubmitStopSnapshot(instance)
+	return nil
+}
+
+// SubmitComponent submits a topology component for the instance
+func (c *CheckBase) SubmitComponent(instance topology.Instance, component topology.Component) {
+	c.GetCheckHandler().SubmitComponent(instance, component)
+}
+
+// SubmitRelation submits a topology relation for the instance
+func (c *CheckBase) SubmitRelation(instance topology.Instance, relation topology.Relation) {
+	c.GetCheckHandler().SubmitRelation(instance, relation)
+}
+
+// SubmitDelete submits the deletion of a topology element for the instance
+func (c *CheckBase) SubmitDelete(instance topology.Instance, topologyElementID string) {
+	c.GetCheckHandler().SubmitDelete(instance, topologyElementID)
+}
+
+// WithHealthSnapshot submits the health check data of fn as a health snapshot for the stream. The stop snapshot is only
+// submitted when fn succeeds.
+func (c *CheckBase) WithHealthSnapshot(stream health.Stream, intervalSeconds, expirySeconds int, fn func() error) error {
+	c.GetCheckHandler().SubmitHealthStartSnapshot(stream, intervalSeconds, expirySeconds)
+	if err := fn(); err != nil {
+		log.Debugf("Skipping the health stop snapshot of check %s for %s: %s", c.checkID, stream.GoString(), err)
+		return err
+	}
+	c.GetCheckHandler().SubmitHealthStopSnapshot(stream)
+	return nil
+}
+
+// SubmitHealthCheckData submits health check data for the stream
+func (c *CheckBase) SubmitHealthCheckData(stream health.Stream, data health.CheckData) {
+	c.GetCheckHandler().SubmitHealthCheckData(stream, data)
+}
+
+// SubmitRawMetric submits a raw metric value for the host, timestamped now
+func (c *CheckBase) SubmitRawMetric(name string, value float64, hostname string, tags []string) {
+	c.SubmitRawMetricsData(telemetry.RawMetrics{
+		Name:      name,
+		Timestamp: time.Now().Unix(),
+		HostName:  hostname,
+		Value:     value,
+		Tags:      tags,
+	})
+}
+
+// SubmitRawMetricsData submits raw metrics data
+func (c *CheckBase) SubmitRawMetricsData(data telemetry.RawMetrics) {
+	c.GetCheckHandler().SubmitRawMetricsData(data)
+}
+
+// SubmitEvent submits a (topology) event
+func (c *CheckBase) SubmitEvent(event metrics.Event) {
+	c.GetCheckHandler().SubmitEvent(event)
+}
+
+// SubmitComplete signals the check run is complete
+func (c *CheckBase) SubmitComplete() {
+	c.GetCheckHandler().SubmitComplete()
+}
@@ -0,0 +1,176 @@
+package corechecks
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionbatcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+func TestCheckBaseHandlerTopologyAndHealth(t *testing.T) {
+	// without check transactionality the data goes through the global batcher
+	config.Datadog.Set("check_transactionality_enabled", false)
+	defer config.Datadog.Set("check_transactionality_enabled", true)
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+	mockBatcher := batcher.NewMockBatcher()
+	defer mockBatcher.Shutdown()
+
+	c := NewCheckBase("sdk-check")
+	handler.GetCheckManager().RegisterCheckHandler(&c, integration.Data{}, integration.Data{})
+
+	instance := topology.Instance{Type: "sdk", URL: "local"}
+	component := topology.Component{ExternalID: "urn:sdk:component", Type: topology.Type{Name: "sdk-component"}}
+	relation := topology.Relation{ExternalID: "urn:sdk:a->urn:sdk:b", SourceID: "urn:sdk:a", TargetID: "urn:sdk:b",
+		Type: topology.Type{Name: "uses"}}
+	stream := health.Stream{Urn: "urn:health:sdk:stream"}
+	checkData := health.CheckData{Unstructured: map[string]interface{}{"checkStateId": "sdk", "health": "CLEAR"}}
+	rawMetric := telemetry.RawMetrics{Name: "sdk.metric", Timestamp: 1600000000, HostName: "host", Value: 1}
+
+	err := c.WithTransaction(func() error {
+		if err := c.WithTopologySnapshot(instance, func() error {
+			c.SubmitComponent(instance, component)
+			c.SubmitRelation(instance, relation)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return c.WithHealthSnapshot(stream, 30, 60, func() error {
+			c.SubmitHealthCheckData(stream, checkData)
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+	c.SubmitRawMetricsData(rawMetric)
+
+	actualState := mockBatcher.CollectedTopology.Flush()
+	expectedState := batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
+		"sdk-check": {
+			Topology: &topology.Topology{
+				StartSnapshot: true,
+				StopSnapshot:  true,
+				Instance:      instance,
+				Components:    []topology.Component{component},
+				Relations:     []topology.Relation{relation},
+				DeleteIDs:     []string{},
+			},
+			Metrics: &[]telemetry.RawMetrics{rawMetric},
+			Health: map[string]health.Health{
+				stream.GoString(): {
+					StartSnapshot: &health.StartSnapshotMetadata{RepeatIntervalS: 30, ExpiryIntervalS: 60},
+					StopSnapshot:  &health.StopSnapshotMetadata{},
+					Stream:        stream,
+					CheckStates:   []health.CheckData{checkData},
+				},
+			},
+		},
+	})
+	assert.Equal(t, expectedState, actualState)
+
+	// a failing snapshot does not submit the stop snapshot
+	err = c.WithTopologySnapshot(instance, func() error {
+		return errors.New("could not collect topology")
+	})
+	assert.EqualError(t, err, "could not collect topology")
+	actualTopology := mockBatcher.CollectedTopology.Flush()["sdk-check"].Topology
+	assert.True(t, actualTopology.StartSnapshot)
+	assert.False(t, actualTopology.StopSnapshot)
+}
+
+func TestCheckBaseHandlerTransaction(t *testing.T) {
+	testTxManager := transactionmanager.NewMockTransactionManager()
+	testTxBatcher := transactionbatcher.NewMockTransactionalBatcher()
+	defer testTxBatcher.Stop()
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+
+	c := NewCheckBase("sdk-transactional-check")
+	handler.GetCheckManager().RegisterCheckHandler(&c, integration.Data{}, integration.Data{})
+
+	instance := topology.Instance{Type: "sdk", URL: "local"}
+	component := topology.Component{ExternalID: "urn:sdk:component", Type: topology.Type{Name: "sdk-component"}}
+
+	var transactionID string
+	err := c.WithTransaction(func() error {
+		transactionID = testTxManager.GetCurrentTransaction()
+		c.SubmitComponent(instance, component)
+		return c.SetTransactionStateValue("cursor", map[string]int{"offset": 10})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "TransactionalCheckHandler", c.GetCheckHandler().Name())
+
+	assert.Eventually(t, func() bool {
+		checkState, ok := testTxBatcher.GetCheckState(c.ID())
+		return ok && checkState.Transaction != nil && checkState.Transaction.CompletedTransaction
+	}, time.Second, 10*time.Millisecond)
+
+	checkState, _ := testTxBatcher.GetCheckState(c.ID())
+	assert.Equal(t, []topology.Component{component}, checkState.Topology.Components)
+	assert.Equal(t, &transactionmanager.TransactionState{Key: "sdk-transactional-check:cursor", State: `{"offset":10}`},
+		testTxManager.GetCurrentTransactionState())
+
+	testTxManager.GetCurrentTransactionNotifyChannel() <- transactionmanager.CompleteTransaction{TransactionID: transactionID}
+	c.GetCheckHandler().(*handler.TransactionalCheckHandler).Stop()
+}
+
+func TestCheckBaseHandlerState(t *testing.T) {
+	stateRoot, err := ioutil.TempDir("", "checkbase-state")
+	assert.NoError(t, err)
+	defer os.RemoveAll(stateRoot)
+	config.Datadog.Set("check_state_root_path", stateRoot)
+	defer config.Datadog.Set("check_state_root_path", "")
+	state.InitCheckStateManager()
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+
+	c := NewCheckBase("sdk-state-check")
+	handler.GetCheckManager().RegisterCheckHandler(&c, integration.Data{}, integration.Data{})
+
+	type cursor struct {
+		Offset int    `json:"offset"`
+		Token  string `json:"token"`
+	}
+
+	var actual cursor
+	assert.NoError(t, c.GetStateValue("cursor", &actual))
+	assert.Equal(t, cursor{}, actual)
+
+	assert.NoError(t, c.SetStateValue("cursor", cursor{Offset: 10, Token: "abc"}))
+	assert.NoError(t, c.GetStateValue("cursor", &actual))
+	assert.Equal(t, cursor{Offset: 10, Token: "abc"}, actual)
+	assert.Equal(t, `{"offset":10,"token":"abc"}`, c.GetState("cursor"))
+
+	persisted, err := state.GetCheckStateManager().GetState("sdk-state-check:cursor")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"offset":10,"token":"abc"}`, persisted)
+
+	c.SetState("cursor", "not-json")
+	assert.Error(t, c.GetStateValue("cursor", &actual))
+}
+
+func TestCheckBaseHandlerWithoutCheckManager(t *testing.T) {
+	// the check manager is stopped, like it is not initialized in the cluster agent
+	handler.InitCheckManager()
+	handler.GetCheckManager().Stop()
+
+	c := NewCheckBase("sdk-cluster-agent-check")
+	ch := c.GetCheckHandler()
+	assert.Equal(t, "NonTransactionalCheckHandler", ch.Name())
+	// the fallback check handler is reused by the next calls
+	assert.Same(t, ch, c.GetCheckHandler())
+}
@@ -110,6 +110,10 @@ func (s *SwarmCheck) Configure(config, initConfig integration.Data, source strin
 		}
 		s.topologyCollector = topologyCollector
 	}
+	if s.topologyCollector != nil {
+		// sts - the replica health stream is repeated at the interval of the check
+		s.topologyCollector.healthInterval = s.Interval()
+	}
 
 	return nil
 }
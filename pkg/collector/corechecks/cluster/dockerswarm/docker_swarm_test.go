@@ -13,8 +13,6 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/batcher"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/config"
-	"github.com/StackVista/stackstate-agent/pkg/health"
-	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 	"os"
@@ -48,25 +46,7 @@ func TestDockerSwarmCheck_True(t *testing.T) {
 	swarmcheck.Run()
 
 	producedTopology := mockBatcher.CollectedTopology.Flush()
-	expectedTopology := batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
-		"swarm_topology": {
-			Health: make(map[string]health.Health),
-			Topology: &topology.Topology{
-				StartSnapshot: false,
-				StopSnapshot:  false,
-				Instance:      topology.Instance{Type: "docker-swarm", URL: "agents"},
-				Components: []topology.Component{
-					*serviceComponent,
-					*containerComponent,
-				},
-				Relations: []topology.Relation{
-					*serviceRelation,
-				},
-				DeleteIDs: []string{},
-			},
-		},
-	})
-	assert.EqualValues(t, expectedTopology, producedTopology)
+	assert.EqualValues(t, expectedSwarmBatchState(), producedTopology)
 	sender.AssertExpectations(t)
 }
 
@@ -97,25 +77,7 @@ func TestDockerSwarmCheck_FromEnv(t *testing.T) {
 	swarmcheck.Run()
 
 	producedTopology := mockBatcher.CollectedTopology.Flush()
-	expectedTopology := batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
-		"swarm_topology": {
-			Health: make(map[string]health.Health),
-			Topology: &topology.Topology{
-				StartSnapshot: false,
-				StopSnapshot:  false,
-				Instance:      topology.Instance{Type: "docker-swarm", URL: "agents"},
-				Components: []topology.Component{
-					*serviceComponent,
-					*containerComponent,
-				},
-				Relations: []topology.Relation{
-					*serviceRelation,
-				},
-				DeleteIDs: []string{},
-			},
-		},
-	})
-	assert.EqualValues(t, expectedTopology, producedTopology)
+	assert.EqualValues(t, expectedSwarmBatchState(), producedTopology)
 	sender.AssertExpectations(t)
 
 	os.Unsetenv("DD_COLLECT_SWARM_TOPOLOGY")
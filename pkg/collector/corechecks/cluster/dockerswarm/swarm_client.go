@@ -14,4 +14,8 @@ import (
 // SwarmClient represents a docker client that can retrieve docker swarm information from the docker API
 type SwarmClient interface {
 	ListSwarmServices() ([]*containers.SwarmService, error)
+	ListSwarmNodes() ([]*containers.SwarmNode, error)
+	ListSwarmNetworks() ([]*containers.SwarmNetwork, error)
+	ListSwarmSecrets() ([]*containers.SwarmSecret, error)
+	ListSwarmConfigs() ([]*containers.SwarmConfig, error)
 }
@@ -3,6 +3,7 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2016-2019 Datadog, Inc.
 
+//go:build docker
 // +build docker
 
 package dockerswarm
@@ -37,10 +38,50 @@ var swarmService = containers.SwarmService{
 				PID:         341,
 			},
 			DesiredState: swarm.TaskStateRunning,
+			NodeID:       "node1",
+			Networks:     []string{"backend"},
 		},
 	},
 	DesiredTasks: 2,
 	RunningTasks: 2,
+	Networks:     []string{"backend"},
+	Secrets:      []string{"secret1"},
+	Configs:      []string{"config1"},
+}
+
+var swarmNode = containers.SwarmNode{
+	ID:            "node1",
+	Hostname:      "mock-host",
+	Role:          swarm.NodeRoleManager,
+	Availability:  swarm.NodeAvailabilityActive,
+	State:         swarm.NodeStateReady,
+	Address:       "10.0.0.1",
+	EngineVersion: "20.10.7",
+	Leader:        true,
+	CreatedAt:     time.Date(2021, time.March, 10, 20, 0, 0, 0, time.UTC),
+}
+
+var swarmNetwork = containers.SwarmNetwork{
+	ID:        "backend",
+	Name:      "agent_backend",
+	Driver:    "overlay",
+	Scope:     "swarm",
+	Labels:    map[string]string{"com.docker.stack.namespace": "agent"},
+	CreatedAt: time.Date(2021, time.March, 10, 22, 0, 0, 0, time.UTC),
+}
+
+var swarmSecret = containers.SwarmSecret{
+	ID:        "secret1",
+	Name:      "agent_api_key",
+	CreatedAt: time.Date(2021, time.March, 10, 22, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2021, time.March, 10, 22, 0, 0, 0, time.UTC),
+}
+
+var swarmConfig = containers.SwarmConfig{
+	ID:        "config1",
+	Name:      "agent_conf",
+	CreatedAt: time.Date(2021, time.March, 10, 22, 0, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2021, time.March, 10, 22, 0, 0, 0, time.UTC),
 }
 
 // MockSwarmClient - used in testing
@@ -55,6 +96,26 @@ func (m *MockSwarmClient) ListSwarmServices() ([]*containers.SwarmService, error
 	return swarmServices, nil
 }
 
+// ListSwarmNodes returns a mock list of nodes
+func (m *MockSwarmClient) ListSwarmNodes() ([]*containers.SwarmNode, error) {
+	return []*containers.SwarmNode{&swarmNode}, nil
+}
+
+// ListSwarmNetworks returns a mock list of networks
+func (m *MockSwarmClient) ListSwarmNetworks() ([]*containers.SwarmNetwork, error) {
+	return []*containers.SwarmNetwork{&swarmNetwork}, nil
+}
+
+// ListSwarmSecrets returns a mock list of secrets
+func (m *MockSwarmClient) ListSwarmSecrets() ([]*containers.SwarmSecret, error) {
+	return []*containers.SwarmSecret{&swarmSecret}, nil
+}
+
+// ListSwarmConfigs returns a mock list of configs
+func (m *MockSwarmClient) ListSwarmConfigs() ([]*containers.SwarmConfig, error) {
+	return []*containers.SwarmConfig{&swarmConfig}, nil
+}
+
 // MockSwarmFactory is exported for unit testing with MockSwarmClient to produce mock outputs
 func MockSwarmFactory() check.Check {
 	return &SwarmCheck{
@@ -14,23 +14,35 @@ import (
 	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/aggregator"
 	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/defaults"
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks"
+	"github.com/StackVista/stackstate-agent/pkg/health"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/containers"
 	"github.com/StackVista/stackstate-agent/pkg/util/docker"
 	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/clustername"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"time"
 )
 
 // const for check name and component type
 const (
 	SwarmTopologyCheckName = "swarm_topology"
 	swarmServiceType       = "swarm-service"
+	swarmNodeType          = "swarm-node"
+	swarmNetworkType       = "swarm-network"
+	swarmSecretType        = "swarm-secret"
+	swarmConfigType        = "swarm-config"
+	// swarmReplicasCheckName is the name of the check state with the replica health of a swarm service
+	swarmReplicasCheckName = "Service replicas"
 )
 
 // SwarmTopologyCollector contains the checkID and topology instance for the swarm topology check
 type SwarmTopologyCollector struct {
 	corechecks.CheckTopologyCollector
 	swarmClient SwarmClient
+	// healthInterval is the repeat interval of the service replica health stream, the interval of the check
+	healthInterval time.Duration
 }
 
 // MakeSwarmTopologyCollector returns a new instance of SwarmTopologyCollector
@@ -44,11 +56,12 @@ func MakeSwarmTopologyCollector() (*SwarmTopologyCollector, error) {
 
 func makeSwarmTopologyCollector(client SwarmClient) *SwarmTopologyCollector {
 	return &SwarmTopologyCollector{
-		corechecks.MakeCheckTopologyCollector(SwarmTopologyCheckName, topology.Instance{
+		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector(SwarmTopologyCheckName, topology.Instance{
 			Type: "docker-swarm",
 			URL:  "agents",
 		}),
-		client,
+		swarmClient:    client,
+		healthInterval: defaults.DefaultCheckInterval,
 	}
 }
 
@@ -59,38 +72,154 @@ func (dt *SwarmTopologyCollector) BuildSwarmTopology(hostname string, metrics ag
 		return errors.New("no batcher instance available, skipping BuildSwarmTopology")
 	}
 
+	// collect the swarm nodes, networks, secrets and configs as topology components
+	clusterComponents, err := dt.collectSwarmCluster()
+	if err != nil {
+		return err
+	}
+
 	// collect all swarm services as topology components
-	swarmComponents, swarmRelations, err := dt.collectSwarmServices(hostname, metrics)
+	swarmComponents, swarmRelations, replicaHealth, err := dt.collectSwarmServices(hostname, metrics)
 	if err != nil {
 		return err
 	}
 
-	// submit all collected topology components
-	for _, component := range swarmComponents {
+	// submit all collected topology as a snapshot, so removed nodes, networks, secrets, configs and services are removed
+	sender.SubmitStartSnapshot(dt.CheckID, dt.TopologyInstance)
+	for _, component := range append(clusterComponents, swarmComponents...) {
 		sender.SubmitComponent(dt.CheckID, dt.TopologyInstance, *component)
 	}
-	// submit all collected topology relations
 	for _, relation := range swarmRelations {
 		sender.SubmitRelation(dt.CheckID, dt.TopologyInstance, *relation)
 	}
+	sender.SubmitStopSnapshot(dt.CheckID, dt.TopologyInstance)
+
+	// submit the replica health of all services as a snapshot, so the health of removed services is cleared
+	stream := dt.replicaHealthStream()
+	sender.SubmitHealthStartSnapshot(dt.CheckID, stream, int(dt.healthInterval.Seconds()), 0)
+	for _, checkData := range replicaHealth {
+		sender.SubmitHealthCheckData(dt.CheckID, stream, checkData)
+	}
+	sender.SubmitHealthStopSnapshot(dt.CheckID, stream)
 
 	sender.SubmitComplete(dt.CheckID)
 
 	return nil
 }
 
-// collectSwarmServices collects swarm services from the docker util and produces topology.Component
-func (dt *SwarmTopologyCollector) collectSwarmServices(hostname string, sender aggregator.Sender) ([]*topology.Component, []*topology.Relation, error) {
+// collectSwarmCluster collects the swarm nodes, overlay networks, secrets and configs from the docker util and produces
+// topology.Component
+func (dt *SwarmTopologyCollector) collectSwarmCluster() ([]*topology.Component, error) {
+	nodes, err := dt.swarmClient.ListSwarmNodes()
+	if err != nil {
+		return nil, err
+	}
+	networks, err := dt.swarmClient.ListSwarmNetworks()
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := dt.swarmClient.ListSwarmSecrets()
+	if err != nil {
+		return nil, err
+	}
+	configs, err := dt.swarmClient.ListSwarmConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName := clustername.GetClusterName(context.TODO(), "")
+	components := make([]*topology.Component, 0, len(nodes)+len(networks)+len(secrets)+len(configs))
+	for _, n := range nodes {
+		// ------------ Create a component structure for Swarm Node, identified by its host
+		nodeComponent := &topology.Component{
+			ExternalID: buildSwarmExternalID(swarmNodeType, n.ID),
+			Type:       topology.Type{Name: swarmNodeType},
+			Data: topology.Data{
+				"name":          n.Hostname,
+				"role":          n.Role,
+				"availability":  n.Availability,
+				"state":         n.State,
+				"address":       n.Address,
+				"engineVersion": n.EngineVersion,
+				"labels":        n.Labels,
+				"leader":        n.Leader,
+				"created":       n.CreatedAt,
+				"clusterName":   clusterName,
+				"identifiers":   []string{fmt.Sprintf("urn:host:/%s", n.Hostname)},
+			},
+		}
+		if !n.UpdatedAt.IsZero() {
+			nodeComponent.Data["updated"] = n.UpdatedAt
+		}
+		components = append(components, nodeComponent)
+	}
+	for _, n := range networks {
+		// ------------ Create a component structure for Swarm overlay Network
+		components = append(components, &topology.Component{
+			ExternalID: buildSwarmExternalID(swarmNetworkType, n.ID),
+			Type:       topology.Type{Name: swarmNetworkType},
+			Data: topology.Data{
+				"name":        n.Name,
+				"driver":      n.Driver,
+				"scope":       n.Scope,
+				"labels":      n.Labels,
+				"ingress":     n.Ingress,
+				"internal":    n.Internal,
+				"attachable":  n.Attachable,
+				"created":     n.CreatedAt,
+				"clusterName": clusterName,
+			},
+		})
+	}
+	for _, s := range secrets {
+		// ------------ Create a component structure for Swarm Secret, the secret data is never collected
+		components = append(components, &topology.Component{
+			ExternalID: buildSwarmExternalID(swarmSecretType, s.ID),
+			Type:       topology.Type{Name: swarmSecretType},
+			Data: topology.Data{
+				"name":        s.Name,
+				"labels":      s.Labels,
+				"created":     s.CreatedAt,
+				"updated":     s.UpdatedAt,
+				"clusterName": clusterName,
+			},
+		})
+	}
+	for _, c := range configs {
+		// ------------ Create a component structure for Swarm Config
+		components = append(components, &topology.Component{
+			ExternalID: buildSwarmExternalID(swarmConfigType, c.ID),
+			Type:       topology.Type{Name: swarmConfigType},
+			Data: topology.Data{
+				"name":        c.Name,
+				"labels":      c.Labels,
+				"created":     c.CreatedAt,
+				"updated":     c.UpdatedAt,
+				"clusterName": clusterName,
+			},
+		})
+	}
+
+	return components, nil
+}
+
+// collectSwarmServices collects swarm services from the docker util and produces topology.Component, the relations of
+// the services to their containers, networks, secrets and configs and of the networks to the nodes they span, and the
+// replica health of the services
+func (dt *SwarmTopologyCollector) collectSwarmServices(hostname string, sender aggregator.Sender) ([]*topology.Component, []*topology.Relation, []health.CheckData, error) {
 
 	sList, err := dt.swarmClient.ListSwarmServices()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	clusterName := clustername.GetClusterName(context.TODO(), "")
 	taskContainerComponents := make([]*topology.Component, 0)
 	swarmServiceComponents := make([]*topology.Component, 0)
 	swarmServiceRelations := make([]*topology.Relation, 0)
+	replicaHealth := make([]health.CheckData, 0, len(sList))
+	// networks span the nodes the tasks attached to them run on, a network and node pair is related only once
+	networkNodeRelations := make(map[string]bool)
 	for _, s := range sList {
 		tags := make([]string, 0)
 		// ------------ Create a component structure for Swarm Service
@@ -153,7 +282,32 @@ func (dt *SwarmTopologyCollector) collectSwarmServices(hostname string, sender a
 				Data:       topology.Data{},
 			}
 			swarmServiceRelations = append(swarmServiceRelations, swarmServiceRelation)
+
+			if taskContainer.NodeID == "" {
+				continue
+			}
+			nodeExternalID := buildSwarmExternalID(swarmNodeType, taskContainer.NodeID)
+			for _, networkID := range taskContainer.Networks {
+				relation := buildSwarmRelation(buildSwarmExternalID(swarmNetworkType, networkID), nodeExternalID, "spans")
+				if !networkNodeRelations[relation.ExternalID] {
+					networkNodeRelations[relation.ExternalID] = true
+					swarmServiceRelations = append(swarmServiceRelations, relation)
+				}
+			}
+		}
+
+		// ------------ Create the relations of the Swarm Service to its networks, secrets and configs
+		for _, networkID := range s.Networks {
+			swarmServiceRelations = append(swarmServiceRelations, buildSwarmRelation(sourceExternalID, buildSwarmExternalID(swarmNetworkType, networkID), "uses"))
+		}
+		for _, secretID := range s.Secrets {
+			swarmServiceRelations = append(swarmServiceRelations, buildSwarmRelation(sourceExternalID, buildSwarmExternalID(swarmSecretType, secretID), "uses"))
 		}
+		for _, configID := range s.Configs {
+			swarmServiceRelations = append(swarmServiceRelations, buildSwarmRelation(sourceExternalID, buildSwarmExternalID(swarmConfigType, configID), "uses"))
+		}
+
+		replicaHealth = append(replicaHealth, serviceReplicaHealth(sourceExternalID, s))
 		log.Infof("Creating a running metric for Service %s with value %d", s.Name, s.RunningTasks)
 		log.Infof("Creating a desired metric for Service %s with value %d", s.Name, s.DesiredTasks)
 		metricTags := []string{"serviceName:" + s.Name, "clusterName:" + clusterName}
@@ -164,5 +318,44 @@ func (dt *SwarmTopologyCollector) collectSwarmServices(hostname string, sender a
 	// Append TaskContainer components to same Service Component list
 	swarmServiceComponents = append(swarmServiceComponents, taskContainerComponents...)
 
-	return swarmServiceComponents, swarmServiceRelations, nil
+	return swarmServiceComponents, swarmServiceRelations, replicaHealth, nil
+}
+
+// replicaHealthStream returns the health stream with the replica health of the swarm services of the cluster
+func (dt *SwarmTopologyCollector) replicaHealthStream() health.Stream {
+	clusterName := clustername.GetClusterName(context.TODO(), "")
+	return health.Stream{Urn: fmt.Sprintf("urn:health:%s:%s", dt.TopologyInstance.Type, clusterName), SubStream: "replicas"}
+}
+
+// serviceReplicaHealth is CLEAR when all desired tasks of a service are running, DEVIATING when some of them are and
+// CRITICAL when none of them are
+func serviceReplicaHealth(serviceExternalID string, s *containers.SwarmService) health.CheckData {
+	state := health.Clear
+	if s.RunningTasks < s.DesiredTasks {
+		state = health.Deviating
+		if s.RunningTasks == 0 {
+			state = health.Critical
+		}
+	}
+	return health.CheckData{CheckState: &health.CheckState{
+		CheckStateID:              fmt.Sprintf("%s:%s", swarmReplicasCheckName, serviceExternalID),
+		Message:                   fmt.Sprintf("%d of %d desired replicas of %s are running", s.RunningTasks, s.DesiredTasks, s.Name),
+		Health:                    state,
+		TopologyElementIdentifier: serviceExternalID,
+		Name:                      swarmReplicasCheckName,
+	}}
+}
+
+func buildSwarmExternalID(componentType, id string) string {
+	return fmt.Sprintf("urn:%s:/%s", componentType, id)
+}
+
+func buildSwarmRelation(sourceExternalID, targetExternalID, typeName string) *topology.Relation {
+	return &topology.Relation{
+		ExternalID: fmt.Sprintf("%s->%s", sourceExternalID, targetExternalID),
+		SourceID:   sourceExternalID,
+		TargetID:   targetExternalID,
+		Type:       topology.Type{Name: typeName},
+		Data:       topology.Data{},
+	}
 }
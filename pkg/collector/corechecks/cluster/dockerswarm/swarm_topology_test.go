@@ -9,12 +9,15 @@
 package dockerswarm
 
 import (
+	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/aggregator/mocksender"
 	"github.com/StackVista/stackstate-agent/pkg/batcher"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/defaults"
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/StackVista/stackstate-agent/pkg/health"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/containers"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -58,8 +61,135 @@ var (
 		Type:       topology.Type{Name: "creates"},
 		Data:       topology.Data{},
 	}
+	nodeComponent = &topology.Component{
+		ExternalID: "urn:swarm-node:/node1",
+		Type:       topology.Type{Name: swarmNodeType},
+		Data: topology.Data{
+			"name":          "mock-host",
+			"role":          swarmNode.Role,
+			"availability":  swarmNode.Availability,
+			"state":         swarmNode.State,
+			"address":       "10.0.0.1",
+			"engineVersion": "20.10.7",
+			"labels":        map[string]string(nil),
+			"leader":        true,
+			"created":       swarmNode.CreatedAt,
+			"clusterName":   "agent-swarm",
+			"identifiers":   []string{"urn:host:/mock-host"},
+		},
+	}
+	networkComponent = &topology.Component{
+		ExternalID: "urn:swarm-network:/backend",
+		Type:       topology.Type{Name: swarmNetworkType},
+		Data: topology.Data{
+			"name":        "agent_backend",
+			"driver":      "overlay",
+			"scope":       "swarm",
+			"labels":      swarmNetwork.Labels,
+			"ingress":     false,
+			"internal":    false,
+			"attachable":  false,
+			"created":     swarmNetwork.CreatedAt,
+			"clusterName": "agent-swarm",
+		},
+	}
+	secretComponent = &topology.Component{
+		ExternalID: "urn:swarm-secret:/secret1",
+		Type:       topology.Type{Name: swarmSecretType},
+		Data: topology.Data{
+			"name":        "agent_api_key",
+			"labels":      map[string]string(nil),
+			"created":     swarmSecret.CreatedAt,
+			"updated":     swarmSecret.UpdatedAt,
+			"clusterName": "agent-swarm",
+		},
+	}
+	configComponent = &topology.Component{
+		ExternalID: "urn:swarm-config:/config1",
+		Type:       topology.Type{Name: swarmConfigType},
+		Data: topology.Data{
+			"name":        "agent_conf",
+			"labels":      map[string]string(nil),
+			"created":     swarmConfig.CreatedAt,
+			"updated":     swarmConfig.UpdatedAt,
+			"clusterName": "agent-swarm",
+		},
+	}
+	networkNodeRelation = &topology.Relation{
+		ExternalID: "urn:swarm-network:/backend->urn:swarm-node:/node1",
+		SourceID:   "urn:swarm-network:/backend",
+		TargetID:   "urn:swarm-node:/node1",
+		Type:       topology.Type{Name: "spans"},
+		Data:       topology.Data{},
+	}
+	serviceNetworkRelation = &topology.Relation{
+		ExternalID: "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e->urn:swarm-network:/backend",
+		SourceID:   "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e",
+		TargetID:   "urn:swarm-network:/backend",
+		Type:       topology.Type{Name: "uses"},
+		Data:       topology.Data{},
+	}
+	serviceSecretRelation = &topology.Relation{
+		ExternalID: "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e->urn:swarm-secret:/secret1",
+		SourceID:   "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e",
+		TargetID:   "urn:swarm-secret:/secret1",
+		Type:       topology.Type{Name: "uses"},
+		Data:       topology.Data{},
+	}
+	serviceConfigRelation = &topology.Relation{
+		ExternalID: "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e->urn:swarm-config:/config1",
+		SourceID:   "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e",
+		TargetID:   "urn:swarm-config:/config1",
+		Type:       topology.Type{Name: "uses"},
+		Data:       topology.Data{},
+	}
+	serviceReplicaCheckData = health.CheckData{CheckState: &health.CheckState{
+		CheckStateID:              "Service replicas:urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e",
+		Message:                   "2 of 2 desired replicas of agent_stackstate-agent are running",
+		Health:                    health.Clear,
+		TopologyElementIdentifier: "urn:swarm-service:/klbo61rrhksdmc9ho3pq97t6e",
+		Name:                      "Service replicas",
+	}}
+	replicaStream = health.Stream{Urn: "urn:health:docker-swarm:agent-swarm", SubStream: "replicas"}
 )
 
+// expectedSwarmBatchState returns the topology and replica health the swarm topology collector produces for the mocks
+func expectedSwarmBatchState() batcher.CheckInstanceBatchStates {
+	return batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
+		"swarm_topology": {
+			Health: map[string]health.Health{
+				replicaStream.GoString(): {
+					StartSnapshot: &health.StartSnapshotMetadata{RepeatIntervalS: int(defaults.DefaultCheckInterval.Seconds())},
+					StopSnapshot:  &health.StopSnapshotMetadata{},
+					Stream:        replicaStream,
+					CheckStates:   []health.CheckData{serviceReplicaCheckData},
+				},
+			},
+			Topology: &topology.Topology{
+				StartSnapshot: true,
+				StopSnapshot:  true,
+				Instance:      topology.Instance{Type: "docker-swarm", URL: "agents"},
+				Components: []topology.Component{
+					*nodeComponent,
+					*networkComponent,
+					*secretComponent,
+					*configComponent,
+					*serviceComponent,
+					*containerComponent,
+				},
+				Relations: []topology.Relation{
+					*serviceRelation,
+					*networkNodeRelation,
+					*serviceNetworkRelation,
+					*serviceSecretRelation,
+					*serviceConfigRelation,
+				},
+				DeleteIDs: []string{},
+			},
+		},
+	})
+}
+
 func TestMakeSwarmTopologyCollector(t *testing.T) {
 	st := makeSwarmTopologyCollector(&MockSwarmClient{})
 	assert.Equal(t, check.ID("swarm_topology"), st.CheckID)
@@ -84,7 +214,7 @@ func TestSwarmTopologyCollector_CollectSwarmServices(t *testing.T) {
 	// check for produced metrics
 	sender.On("Gauge", "swarm.service.running_replicas", 2.0, "", expectedTags).Return().Times(1)
 	sender.On("Gauge", "swarm.service.desired_replicas", 2.0, "", expectedTags).Return().Times(1)
-	comps, relations, err := st.collectSwarmServices(testHostname, sender)
+	comps, relations, replicaHealth, err := st.collectSwarmServices(testHostname, sender)
 
 	// list of swamr service components
 	serviceComponents := []*topology.Component{
@@ -97,6 +227,10 @@ func TestSwarmTopologyCollector_CollectSwarmServices(t *testing.T) {
 	// list of swamr service and task container relation
 	serviceRelations := []*topology.Relation{
 		serviceRelation,
+		networkNodeRelation,
+		serviceNetworkRelation,
+		serviceSecretRelation,
+		serviceConfigRelation,
 	}
 	// append container components to service components
 	serviceComponents = append(serviceComponents, containerComponents...)
@@ -106,6 +240,8 @@ func TestSwarmTopologyCollector_CollectSwarmServices(t *testing.T) {
 	assert.EqualValues(t, comps, serviceComponents)
 	// relations should be serviceRelations
 	assert.EqualValues(t, relations, serviceRelations)
+	// replica health should be clear
+	assert.EqualValues(t, []health.CheckData{serviceReplicaCheckData}, replicaHealth)
 	// metrics assertion
 	sender.AssertExpectations(t)
 	sender.AssertNumberOfCalls(t, "Gauge", 2)
@@ -132,26 +268,36 @@ func TestSwarmTopologyCollector_BuildSwarmTopology(t *testing.T) {
 	assert.NoError(t, err)
 
 	producedTopology := mockBatcher.CollectedTopology.Flush()
-	expectedTopology := batcher.CheckInstanceBatchStates(map[check.ID]batcher.CheckInstanceBatchState{
-		"swarm_topology": {
-			Health: make(map[string]health.Health),
-			Topology: &topology.Topology{
-				StartSnapshot: false,
-				StopSnapshot:  false,
-				Instance:      topology.Instance{Type: "docker-swarm", URL: "agents"},
-				Components: []topology.Component{
-					*serviceComponent,
-					*containerComponent,
-				},
-				Relations: []topology.Relation{
-					*serviceRelation,
-				},
-				DeleteIDs: []string{},
-			},
-		},
-	})
-	assert.EqualValues(t, producedTopology, expectedTopology)
+	assert.EqualValues(t, expectedSwarmBatchState(), producedTopology)
 	// metrics assertion
 	sender.AssertExpectations(t)
 	sender.AssertNumberOfCalls(t, "Gauge", 2)
 }
+
+func TestSwarmTopologyCollector_CollectSwarmCluster(t *testing.T) {
+	st := makeSwarmTopologyCollector(&MockSwarmClient{})
+	config.Datadog.Set("cluster_name", "agent-swarm")
+
+	comps, err := st.collectSwarmCluster()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*topology.Component{nodeComponent, networkComponent, secretComponent, configComponent}, comps)
+}
+
+func TestServiceReplicaHealth(t *testing.T) {
+	for _, tc := range []struct {
+		running, desired uint64
+		expected         health.State
+	}{
+		{running: 3, desired: 3, expected: health.Clear},
+		{running: 0, desired: 0, expected: health.Clear},
+		{running: 1, desired: 3, expected: health.Deviating},
+		{running: 0, desired: 3, expected: health.Critical},
+	} {
+		t.Run(fmt.Sprintf("%d of %d", tc.running, tc.desired), func(t *testing.T) {
+			service := &containers.SwarmService{ID: "service1", Name: "web", RunningTasks: tc.running, DesiredTasks: tc.desired}
+			checkData := serviceReplicaHealth("urn:swarm-service:/service1", service)
+			assert.Equal(t, tc.expected, checkData.CheckState.Health)
+			assert.Equal(t, fmt.Sprintf("%d of %d desired replicas of web are running", tc.running, tc.desired), checkData.CheckState.Message)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/StackVista/stackstate-agent/pkg/aggregator"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver/common"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	// specChangedReason is the event type of the change events that are synthesized for updated resources
+	specChangedReason = "SpecChanged"
+	// maxSpecChanges is the maximum number of changed fields that is reported in a change event
+	maxSpecChanges = 50
+	// maxSpecValueLength is the maximum length of the before and after values of a changed field
+	maxSpecValueLength = 256
+	// checkStateLabel marks the ConfigMaps the check state store of the cluster agent keeps the check states in
+	checkStateLabel = "stackstate.com/check-state"
+)
+
+// specChange is a field of the spec that was added, removed or changed by an update
+type specChange struct {
+	path          string
+	before, after interface{}
+}
+
+// changeEventWatcher synthesizes change events with a diff of the spec when Deployments, StatefulSets, DaemonSets or,
+// when enabled, ConfigMaps are updated. Updates that do not change the spec, like status updates, and updates of the
+// ConfigMaps of the cluster agent are skipped.
+type changeEventWatcher struct {
+	factory    informers.SharedInformerFactory
+	mapper     *kubernetesEventMapper
+	handle     func(event metrics.Event)
+	stopCh     chan struct{}
+	configMaps bool
+	// agentNamespace and agentConfigMaps are the namespace and the names of the ConfigMaps the cluster agent keeps its
+	// own state in, their updates are not changes
+	agentNamespace  string
+	agentConfigMaps map[string]struct{}
+}
+
+func newChangeEventWatcher(client kubernetes.Interface, mapper *kubernetesEventMapper, configMaps bool, handle func(event metrics.Event)) *changeEventWatcher {
+	agentConfigMaps := map[string]struct{}{
+		"datadogtoken":            {}, // event collection tokens
+		"datadog-leader-election": {}, // leader election token
+		"datadog-cluster-id":      {},
+	}
+	agentConfigMaps[config.Datadog.GetString("hpa_configmap_name")] = struct{}{}
+
+	return &changeEventWatcher{
+		factory:         informers.NewSharedInformerFactory(client, 0),
+		mapper:          mapper,
+		handle:          handle,
+		stopCh:          make(chan struct{}),
+		configMaps:      configMaps,
+		agentNamespace:  common.GetResourcesNamespace(),
+		agentConfigMaps: agentConfigMaps,
+	}
+}
+
+// isAgentConfigMap returns whether the ConfigMap holds state of the cluster agent, like the event collection tokens,
+// the leader election token or the check states of the check state store
+func (w *changeEventWatcher) isAgentConfigMap(configMap *v1.ConfigMap) bool {
+	if _, found := configMap.Labels[checkStateLabel]; found {
+		return true
+	}
+	if configMap.Namespace != w.agentNamespace {
+		return false
+	}
+	_, found := w.agentConfigMaps[configMap.Name]
+	return found
+}
+
+// start starts the informers of the watched resources
+func (w *changeEventWatcher) start() {
+	w.onUpdate(w.factory.Apps().V1().Deployments().Informer(), func(oldObj, newObj interface{}) {
+		before, okBefore := oldObj.(*appsV1.Deployment)
+		after, okAfter := newObj.(*appsV1.Deployment)
+		if okBefore && okAfter {
+			w.changed("Deployment", after, before.Spec, after.Spec)
+		}
+	})
+	w.onUpdate(w.factory.Apps().V1().StatefulSets().Informer(), func(oldObj, newObj interface{}) {
+		before, okBefore := oldObj.(*appsV1.StatefulSet)
+		after, okAfter := newObj.(*appsV1.StatefulSet)
+		if okBefore && okAfter {
+			w.changed("StatefulSet", after, before.Spec, after.Spec)
+		}
+	})
+	w.onUpdate(w.factory.Apps().V1().DaemonSets().Informer(), func(oldObj, newObj interface{}) {
+		before, okBefore := oldObj.(*appsV1.DaemonSet)
+		after, okAfter := newObj.(*appsV1.DaemonSet)
+		if okBefore && okAfter {
+			w.changed("DaemonSet", after, before.Spec, after.Spec)
+		}
+	})
+	if w.configMaps {
+		w.onUpdate(w.factory.Core().V1().ConfigMaps().Informer(), func(oldObj, newObj interface{}) {
+			before, okBefore := oldObj.(*v1.ConfigMap)
+			after, okAfter := newObj.(*v1.ConfigMap)
+			if okBefore && okAfter && !w.isAgentConfigMap(after) {
+				w.changed("ConfigMap", after, configMapContent(before), configMapContent(after))
+			}
+		})
+	}
+	w.factory.Start(w.stopCh)
+}
+
+// stop stops the informers
+func (w *changeEventWatcher) stop() {
+	close(w.stopCh)
+}
+
+func (w *changeEventWatcher) onUpdate(informer cache.SharedIndexInformer, update func(oldObj, newObj interface{})) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{UpdateFunc: update})
+}
+
+// changed submits a change event when the spec of the object differs before and after the update
+func (w *changeEventWatcher) changed(kind string, object metav1.Object, before, after interface{}) {
+	changes, err := diffSpecs(before, after)
+	if err != nil {
+		_ = log.Warnf("Could not compare the spec of %s %s/%s: %s", kind, object.GetNamespace(), object.GetName(), err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	event, err := w.mapper.mapSpecChange(kind, object, changes, time.Now())
+	if err != nil {
+		_ = log.Warnf("Error while mapping the change of %s %s/%s, %s.", kind, object.GetNamespace(), object.GetName(), err)
+		return
+	}
+	log.Debugf("Sending change event: %s", event.String())
+	w.handle(event)
+}
+
+// startChangeEvents starts synthesizing the change events on the first run as leader
+func (k *EventsCheck) startChangeEvents(sender aggregator.Sender) {
+	if k.changeEvents != nil {
+		return
+	}
+	log.Infof("Starting the change events of the check '%s'", k.ID())
+	mapper := k.mapperFactory(k.ac, k.clusterName, k.instance.EventCategories)
+	k.changeEvents = newChangeEventWatcher(k.ac.Cl, mapper, k.instance.ChangeEventsConfigMaps, sender.Event)
+	k.changeEvents.start()
+}
+
+// stopChangeEvents stops synthesizing the change events
+func (k *EventsCheck) stopChangeEvents() {
+	if k.changeEvents == nil {
+		return
+	}
+	log.Infof("Stopping the change events of the check '%s'", k.ID())
+	k.changeEvents.stop()
+	k.changeEvents = nil
+}
+
+// configMapContent returns the keys of the data of a config map with a hash of their value, which take the place of the
+// spec of the workloads. The values are left out, as config maps can hold credentials.
+func configMapContent(configMap *v1.ConfigMap) map[string]interface{} {
+	data := make(map[string]string, len(configMap.Data))
+	for key, value := range configMap.Data {
+		data[key] = hashConfigMapValue([]byte(value))
+	}
+	binaryData := make(map[string]string, len(configMap.BinaryData))
+	for key, value := range configMap.BinaryData {
+		binaryData[key] = hashConfigMapValue(value)
+	}
+	return map[string]interface{}{
+		"data":       data,
+		"binaryData": binaryData,
+	}
+}
+
+func hashConfigMapValue(value []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(value))
+}
+
+// mapSpecChange maps the changes of the spec of an object onto a change event of the component of the object. At most
+// maxSpecChanges changes are reported.
+func (k *kubernetesEventMapper) mapSpecChange(kind string, object metav1.Object, changes []specChange, now time.Time) (metrics.Event, error) {
+	externalID, err := k.urn.BuildExternalID(kind, object.GetNamespace(), object.GetName())
+	if err != nil {
+		return metrics.Event{}, err
+	}
+
+	reported := changes
+	if len(reported) > maxSpecChanges {
+		reported = reported[:maxSpecChanges]
+	}
+	changeData := make([]interface{}, 0, len(reported))
+	lines := make([]string, 0, len(reported))
+	for _, change := range reported {
+		changeData = append(changeData, map[string]interface{}{
+			"path":   change.path,
+			"before": change.before,
+			"after":  change.after,
+		})
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", change.path, formatSpecValue(change.before),
+			formatSpecValue(change.after)))
+	}
+
+	return metrics.Event{
+		Title:          fmt.Sprintf("%s - %s %s (%d field(s) changed)", specChangedReason, object.GetName(), kind, len(changes)),
+		SourceTypeName: k.sourceType,
+		Priority:       metrics.EventPriorityNormal,
+		AlertType:      metrics.EventAlertTypeInfo,
+		EventType:      specChangedReason,
+		Ts:             now.Unix(),
+		Tags: []string{
+			fmt.Sprintf("kube_namespace:%s", object.GetNamespace()),
+			fmt.Sprintf("kube_object_name:%s", object.GetName()),
+			fmt.Sprintf("kube_object_kind:%s", kind),
+			fmt.Sprintf("kube_cluster_name:%s", k.clusterName),
+			fmt.Sprintf("kube_reason:%s", specChangedReason),
+		},
+		EventContext: &metrics.EventContext{
+			Source:             k.sourceType,
+			Category:           string(Changes),
+			SourceIdentifier:   fmt.Sprintf("%s/%s", object.GetUID(), object.GetResourceVersion()),
+			ElementIdentifiers: []string{externalID},
+			SourceLinks:        []metrics.SourceLink{},
+			Data: map[string]interface{}{
+				"kind":             kind,
+				"namespace":        object.GetNamespace(),
+				"name":             object.GetName(),
+				"resource_version": object.GetResourceVersion(),
+				"changes":          changeData,
+				"truncated":        len(reported) < len(changes),
+			},
+		},
+		Text: strings.Join(lines, "\n"),
+	}, nil
+}
+
+// diffSpecs returns the fields that differ between two specs ordered by their path, long values are truncated
+func diffSpecs(before, after interface{}) ([]specChange, error) {
+	beforeFields, err := flattenSpec(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := flattenSpec(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []specChange
+	for path, beforeValue := range beforeFields {
+		afterValue, found := afterFields[path]
+		if !found || !reflect.DeepEqual(beforeValue, afterValue) {
+			changes = append(changes, specChange{path: path, before: truncateSpecValue(beforeValue), after: truncateSpecValue(afterValue)})
+		}
+	}
+	for path, afterValue := range afterFields {
+		if _, found := beforeFields[path]; !found {
+			changes = append(changes, specChange{path: path, after: truncateSpecValue(afterValue)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes, nil
+}
+
+// flattenSpec returns the leaf values of the json representation of a spec by their path
+func flattenSpec(spec interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	flattenValue("", value, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenValue(childPath, child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	case nil:
+		// empty values are left out, so a field that is set to null is reported as removed
+	default:
+		fields[path] = v
+	}
+}
+
+func truncateSpecValue(value interface{}) interface{} {
+	if s, ok := value.(string); ok && len(s) > maxSpecValueLength {
+		return s[:maxSpecValueLength] + "..."
+	}
+	return value
+}
+
+func formatSpecValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%v", value)
+}
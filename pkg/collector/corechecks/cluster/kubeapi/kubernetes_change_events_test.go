@@ -0,0 +1,219 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/urn"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver/common"
+)
+
+func testChangeEventMapper() *kubernetesEventMapper {
+	return &kubernetesEventMapper{
+		urn:         urn.NewURNBuilder(urn.Kubernetes, "testCluster"),
+		clusterName: "testCluster",
+		sourceType:  string(urn.Kubernetes),
+	}
+}
+
+func testDeployment(image string, replicas int32, env ...v1.EnvVar) *appsV1.Deployment {
+	return &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", UID: "1234", ResourceVersion: "42"},
+		Spec: appsV1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "nginx", Image: image, Env: env}},
+				},
+			},
+		},
+	}
+}
+
+func TestDiffSpecs(t *testing.T) {
+	before := testDeployment("nginx:1.24", 2)
+	after := testDeployment("nginx:1.25", 3, v1.EnvVar{Name: "MODE", Value: "production"})
+
+	changes, err := diffSpecs(before.Spec, after.Spec)
+	require.NoError(t, err)
+	assert.Equal(t, []specChange{
+		{path: "replicas", before: float64(2), after: float64(3)},
+		{path: "template.spec.containers[0].env[0].name", after: "MODE"},
+		{path: "template.spec.containers[0].env[0].value", after: "production"},
+		{path: "template.spec.containers[0].image", before: "nginx:1.24", after: "nginx:1.25"},
+	}, changes)
+
+	changes, err = diffSpecs(after.Spec, after.Spec)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// removed config map data and long values
+	changes, err = diffSpecs(
+		map[string]interface{}{"data": map[string]string{"removed": "value", "long": "a"}},
+		map[string]interface{}{"data": map[string]string{"long": strings.Repeat("b", 300)}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []specChange{
+		{path: "data.long", before: "a", after: strings.Repeat("b", maxSpecValueLength) + "..."},
+		{path: "data.removed", before: "value"},
+	}, changes)
+}
+
+func TestMapSpecChange(t *testing.T) {
+	now := time.Unix(709662600, 0)
+	event, err := testChangeEventMapper().mapSpecChange("Deployment", testDeployment("nginx:1.25", 3), []specChange{
+		{path: "template.spec.containers[0].image", before: "nginx:1.24", after: "nginx:1.25"},
+		{path: "template.spec.containers[0].env[0].name", after: "MODE"},
+	}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, metrics.Event{
+		Title:          "SpecChanged - nginx Deployment (2 field(s) changed)",
+		SourceTypeName: "kubernetes",
+		Priority:       metrics.EventPriorityNormal,
+		AlertType:      metrics.EventAlertTypeInfo,
+		EventType:      "SpecChanged",
+		Ts:             709662600,
+		Tags: []string{
+			"kube_namespace:default",
+			"kube_object_name:nginx",
+			"kube_object_kind:Deployment",
+			"kube_cluster_name:testCluster",
+			"kube_reason:SpecChanged",
+		},
+		EventContext: &metrics.EventContext{
+			Source:             "kubernetes",
+			Category:           "Changes",
+			SourceIdentifier:   "1234/42",
+			ElementIdentifiers: []string{"urn:kubernetes:/testCluster:default:deployment/nginx"},
+			SourceLinks:        []metrics.SourceLink{},
+			Data: map[string]interface{}{
+				"kind":             "Deployment",
+				"namespace":        "default",
+				"name":             "nginx",
+				"resource_version": "42",
+				"changes": []interface{}{
+					map[string]interface{}{"path": "template.spec.containers[0].image", "before": "nginx:1.24", "after": "nginx:1.25"},
+					map[string]interface{}{"path": "template.spec.containers[0].env[0].name", "before": nil, "after": "MODE"},
+				},
+				"truncated": false,
+			},
+		},
+		Text: "template.spec.containers[0].image: nginx:1.24 -> nginx:1.25\ntemplate.spec.containers[0].env[0].name: <none> -> MODE",
+	}, event)
+
+	_, err = testChangeEventMapper().mapSpecChange("Unknown", testDeployment("nginx:1.25", 3), nil, now)
+	assert.Error(t, err)
+}
+
+func TestChangeEventWatcher(t *testing.T) {
+	deployment := testDeployment("nginx:1.24", 2)
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx-config", Namespace: "default"},
+		Data:       map[string]string{"nginx.conf": "worker_processes 1;"},
+	}
+	tokenConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadogtoken", Namespace: common.GetResourcesNamespace()},
+		Data:       map[string]string{"event.tokenKey": "10"},
+	}
+	checkStateConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "sts-check-state-mycheck", Namespace: "other",
+			Labels: map[string]string{checkStateLabel: "true"}},
+		Data: map[string]string{"cursor": "1"},
+	}
+	client := fake.NewSimpleClientset(deployment, configMap, tokenConfigMap, checkStateConfigMap)
+
+	events := make(chan metrics.Event, 10)
+	watcher := newChangeEventWatcher(client, testChangeEventMapper(), true, func(event metrics.Event) { events <- event })
+	watcher.start()
+	defer watcher.stop()
+	watcher.factory.WaitForCacheSync(watcher.stopCh)
+
+	nextEvent := func() metrics.Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no change event was submitted")
+			return metrics.Event{}
+		}
+	}
+
+	// the updates of the ConfigMaps of the cluster agent are not changes
+	tokenConfigMap = tokenConfigMap.DeepCopy()
+	tokenConfigMap.Data["event.tokenKey"] = "11"
+	_, err := client.CoreV1().ConfigMaps(tokenConfigMap.Namespace).Update(context.TODO(), tokenConfigMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	checkStateConfigMap = checkStateConfigMap.DeepCopy()
+	checkStateConfigMap.Data["cursor"] = "2"
+	_, err = client.CoreV1().ConfigMaps("other").Update(context.TODO(), checkStateConfigMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// a status update is not a change
+	statusUpdate := deployment.DeepCopy()
+	statusUpdate.Status.ReadyReplicas = 2
+	_, err = client.AppsV1().Deployments("default").UpdateStatus(context.TODO(), statusUpdate, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = client.AppsV1().Deployments("default").Update(context.TODO(), testDeployment("nginx:1.25", 2), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	event := nextEvent()
+	assert.Equal(t, []string{"urn:kubernetes:/testCluster:default:deployment/nginx"}, event.EventContext.ElementIdentifiers)
+	assert.Equal(t, "template.spec.containers[0].image: nginx:1.24 -> nginx:1.25", event.Text)
+
+	configMap = configMap.DeepCopy()
+	configMap.Data["nginx.conf"] = "worker_processes 2;"
+	_, err = client.CoreV1().ConfigMaps("default").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	event = nextEvent()
+	assert.Equal(t, []string{"urn:kubernetes:/testCluster:default:configmap/nginx-config"}, event.EventContext.ElementIdentifiers)
+	// only the changed keys and the hashes of their values are submitted
+	assert.Equal(t, "data.nginx.conf: "+hashConfigMapValue([]byte("worker_processes 1;"))+" -> "+
+		hashConfigMapValue([]byte("worker_processes 2;")), event.Text)
+	assert.NotContains(t, event.Text, "worker_processes")
+
+	assert.Empty(t, events)
+}
+
+func TestChangeEventWatcherWithoutConfigMaps(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx-config", Namespace: "default"},
+		Data:       map[string]string{"nginx.conf": "worker_processes 1;"},
+	}
+	client := fake.NewSimpleClientset(testDeployment("nginx:1.24", 2), configMap)
+
+	events := make(chan metrics.Event, 10)
+	watcher := newChangeEventWatcher(client, testChangeEventMapper(), false, func(event metrics.Event) { events <- event })
+	watcher.start()
+	defer watcher.stop()
+	watcher.factory.WaitForCacheSync(watcher.stopCh)
+
+	// the config maps are not watched
+	configMap = configMap.DeepCopy()
+	configMap.Data["nginx.conf"] = "worker_processes 2;"
+	_, err := client.CoreV1().ConfigMaps("default").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = client.AppsV1().Deployments("default").Update(context.TODO(), testDeployment("nginx:1.25", 2), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	select {
+	case event := <-events:
+		assert.Equal(t, "template.spec.containers[0].image: nginx:1.24 -> nginx:1.25", event.Text)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no change event was submitted")
+	}
+	assert.Empty(t, events)
+}
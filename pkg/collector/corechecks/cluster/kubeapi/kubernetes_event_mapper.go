@@ -170,6 +170,7 @@ func (k *kubernetesEventMapper) mapKubernetesEvent(event *v1.Event) (metrics.Eve
 
 	// Map Category to event type
 	//
+	category := k.getCategory(event)
 
 	mEvent := metrics.Event{
 		Title:          fmt.Sprintf("%s - %s %s (%dx)", event.Reason, event.InvolvedObject.Name, event.InvolvedObject.Kind, event.Count),
@@ -182,9 +183,9 @@ func (k *kubernetesEventMapper) mapKubernetesEvent(event *v1.Event) (metrics.Eve
 		Tags:           k.getTags(event),
 		EventContext: &metrics.EventContext{
 			Source:             k.sourceType,
-			Category:           string(k.getCategory(event)),
+			Category:           string(category),
 			SourceIdentifier:   string(event.GetUID()),
-			ElementIdentifiers: k.externalIdentifierForInvolvedObject(event),
+			ElementIdentifiers: k.elementIdentifiersForCategory(event, category),
 			SourceLinks:        []metrics.SourceLink{},
 			Data:               map[string]interface{}{},
 		},
@@ -297,6 +298,18 @@ func (k *kubernetesEventMapper) externalIdentifierForInvolvedObject(event *v1.Ev
 	return identifiers
 }
 
+// elementIdentifiersForCategory links changes to the exact component of the involved object, the other events are
+// linked to the involved object and its container. [sts]
+func (k *kubernetesEventMapper) elementIdentifiersForCategory(event *v1.Event, category EventCategory) []string {
+	if category == Changes {
+		obj := event.InvolvedObject
+		if externalID, err := k.urn.BuildExternalID(obj.Kind, obj.Namespace, obj.Name); err == nil {
+			return []string{externalID}
+		}
+	}
+	return k.externalIdentifierForInvolvedObject(event)
+}
+
 func getContainerNameFromEvent(event *v1.Event) string {
 
 	containerName := ""
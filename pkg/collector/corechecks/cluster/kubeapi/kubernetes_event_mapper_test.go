@@ -93,3 +93,24 @@ func TestEventElementIdentifiers(t *testing.T) {
 	assert.Equal(t, 1, len(elementIdentifier3))
 	assert.Contains(t, elementIdentifier3, "urn:kubernetes:/testCluster:default:pod/dca-789976f5d7-2ljx6")
 }
+
+func TestChangeEventElementIdentifiers(t *testing.T) {
+	mapper := &kubernetesEventMapper{
+		urn:                     urn.NewURNBuilder(urn.Kubernetes, "testCluster"),
+		clusterName:             "testCluster",
+		sourceType:              string(urn.Kubernetes),
+		eventCategoriesOverride: nil,
+	}
+
+	// changes are linked to the component of the involved object only, not to its container
+	changeEvent := createEvent(1, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "SandboxChanged", "Pod sandbox changed", firstTimestamp, firstTimestamp, "Normal", eventContainer1)
+	mapped, err := mapper.mapKubernetesEvent(changeEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "Changes", mapped.EventContext.Category)
+	assert.Equal(t, []string{"urn:kubernetes:/testCluster:default:pod/dca-789976f5d7-2ljx6"}, mapped.EventContext.ElementIdentifiers)
+
+	// other events keep the identifiers of the container
+	mapped, err = mapper.mapKubernetesEvent(event1)
+	assert.NoError(t, err)
+	assert.Len(t, mapped.EventContext.ElementIdentifiers, 2)
+}
@@ -53,6 +53,15 @@ type EventsConfig struct {
 	MaxEventCollection       int                      `yaml:"max_events_per_run"`
 	FilteredEventTypes       []string                 `yaml:"filtered_event_types"`
 	EventCategories          map[string]EventCategory `yaml:"event_categories"`
+	// sts - stream the events with a long-lived watch instead of collecting them on every run
+	WatchEvents        bool `yaml:"watch_events_enabled"`
+	MaxEventsPerRelist int  `yaml:"max_events_per_relist"`
+	// sts - synthesize change events with a spec diff for updated workloads and config maps
+	ChangeEvents           bool `yaml:"change_events_enabled"`
+	ChangeEventsConfigMaps bool `yaml:"change_events_configmaps_enabled"`
+	// sts - health stream of the pods and containers
+	PodHealth                  bool `yaml:"pod_health_enabled"`
+	PodPendingThresholdSeconds int  `yaml:"pod_pending_threshold_s"`
 
 	// Pod Events
 	ResyncPeriodPodEvent        int `yaml:"kubernetes_pod_event_resync_period_s"`
@@ -76,6 +85,11 @@ type EventsCheck struct {
 	providerIDCache          *cache.Cache
 	mapperFactory            KubernetesEventMapperFactory
 	clusterName              string
+	// sts
+	eventWatch      *eventWatcher
+	persistedResVer string
+	changeEvents    *changeEventWatcher
+	podHealth       *podHealth
 }
 
 func (c *EventsConfig) parse(data []byte) error {
@@ -148,6 +162,14 @@ func (k *EventsCheck) setDefaults() {
 		k.instance.MaxEventCollection = maxEventCardinality
 	}
 
+	if k.instance.MaxEventsPerRelist == 0 {
+		k.instance.MaxEventsPerRelist = defaultMaxEventsPerRelist
+	}
+
+	if k.instance.PodPendingThresholdSeconds == 0 {
+		k.instance.PodPendingThresholdSeconds = defaultPodPendingThresholdSeconds
+	}
+
 	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
 }
 
@@ -203,6 +225,9 @@ func (k *EventsCheck) Run() error {
 			if errLeader == apiserver.ErrNotLeader {
 				// Only the leader can instantiate the apiserver client.
 				log.Debug("Agent is not leader, will not run the check")
+				// sts - the new leader resumes watching the events
+				k.stopEventWatch()
+				k.stopChangeEvents()
 				return nil
 			}
 			return err
@@ -220,6 +245,15 @@ func (k *EventsCheck) Run() error {
 		return nil
 	}
 
+	// sts - the change events are synthesized by informers in between the check runs
+	if k.instance.ChangeEvents {
+		k.startChangeEvents(sender)
+	}
+	// sts - the pod health observes the probe failures of the events, so it is created before they are collected
+	if k.instance.PodHealth {
+		k.getPodHealth()
+	}
+
 	log.Info("Running kubernetes custom pod event collector ...")
 	// Get pods from the API server to produce custom events
 	pods, podCollectionErr := k.podEventsCollectionCheck()
@@ -230,11 +264,24 @@ func (k *EventsCheck) Run() error {
 	}
 
 	log.Info("Running kubernetes event collector ...")
-	// Get the events from the API server
-	events, eventCollectionErr := k.eventCollectionCheck()
-	if eventCollectionErr == nil {
-		// Process the events to have a Datadog format.
-		k.processEvents(sender, events)
+	var eventCollectionErr error
+	if k.instance.WatchEvents {
+		// sts - the events are streamed by a long-lived watch in between the check runs
+		eventCollectionErr = k.runEventWatch(sender)
+	} else {
+		// Get the events from the API server
+		var events []*v1.Event
+		events, eventCollectionErr = k.eventCollectionCheck()
+		if eventCollectionErr == nil {
+			// Process the events to have a Datadog format.
+			k.processEvents(sender, events)
+		}
+	}
+
+	// sts - submit the health of the pods and containers
+	var podHealthErr error
+	if k.instance.PodHealth {
+		podHealthErr = k.runPodHealth()
 	}
 
 	// Determine if either one of the custom pod events or event collector failed
@@ -244,10 +291,20 @@ func (k *EventsCheck) Run() error {
 	if eventCollectionErr != nil {
 		return eventCollectionErr
 	}
+	if podHealthErr != nil {
+		return podHealthErr
+	}
 
 	return nil
 }
 
+// Cancel stops the event watch and the informers of the change events
+func (k *EventsCheck) Cancel() {
+	k.stopEventWatch()
+	k.stopChangeEvents()
+	k.CommonCancel()
+}
+
 func (k *EventsCheck) runLeaderElection() error {
 
 	leaderEngine, err := leaderelection.GetLeaderEngine()
@@ -305,6 +362,7 @@ func (k *EventsCheck) eventCollectionCheck() (newEvents []*v1.Event, err error)
 // - convert each K8s event to a metrics event to be processed by the intake
 func (k *EventsCheck) processEvents(sender aggregator.Sender, events []*v1.Event) {
 	mapper := k.mapperFactory(k.ac, k.clusterName, k.instance.EventCategories)
+	k.observePodHealthEvents(events)
 	for _, event := range events {
 		mappedEvent, err := mapper.mapKubernetesEvent(event)
 		if err != nil {
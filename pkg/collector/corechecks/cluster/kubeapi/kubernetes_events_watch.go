@@ -0,0 +1,264 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/StackVista/stackstate-agent/pkg/aggregator"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	// eventResourceVersionStateKey is the check state key of the resource version the event watch got to
+	eventResourceVersionStateKey = "event_resource_version"
+	defaultMaxEventsPerRelist    = 1000
+	defaultEventWatchRetryDelay  = 5 * time.Second
+)
+
+// eventWatchState is the persisted state of the event watch
+type eventWatchState struct {
+	ResourceVersion string `json:"resource_version"`
+}
+
+// eventWatcher streams the events of the API server to the handle function with a long-lived watch. When the watch is
+// closed it resumes watching from the last resource version it received, when that resource version is too old
+// (410 Gone) it re-lists the events in pages of relistLimit events and submits the ones it did not receive yet.
+type eventWatcher struct {
+	ac          *apiserver.APIClient
+	filter      string
+	relistLimit int64
+	retryDelay  time.Duration
+	handle      func(events []*v1.Event)
+
+	mux    sync.Mutex
+	resVer string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newEventWatcher(ac *apiserver.APIClient, filter string, relistLimit int64, handle func(events []*v1.Event)) *eventWatcher {
+	return &eventWatcher{
+		ac:          ac,
+		filter:      filter,
+		relistLimit: relistLimit,
+		retryDelay:  defaultEventWatchRetryDelay,
+		handle:      handle,
+	}
+}
+
+// start watches the events from the given resource version, the current events are listed when it is empty
+func (w *eventWatcher) start(resVer string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.setResourceVersion(resVer)
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go w.run(ctx)
+}
+
+// stop stops the watch and waits until the events it received are handled
+func (w *eventWatcher) stop() {
+	w.cancel()
+	<-w.done
+}
+
+// ResourceVersion returns the resource version of the last event that was handled
+func (w *eventWatcher) ResourceVersion() string {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.resVer
+}
+
+func (w *eventWatcher) setResourceVersion(resVer string) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.resVer = resVer
+}
+
+func (w *eventWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	for {
+		err := w.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			// the API server closes watches after a while, resume watching right away
+			continue
+		}
+
+		_ = log.Warnf("Could not watch the events of the api server, retrying in %s: %s", w.retryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryDelay):
+		}
+	}
+}
+
+// watch handles the events of a single watch until it is closed
+func (w *eventWatcher) watch(ctx context.Context) error {
+	resVer := w.ResourceVersion()
+	if resVer == "" {
+		return w.relist(ctx)
+	}
+
+	evWatcher, err := w.ac.WatchEvents(ctx, resVer, w.filter)
+	if err != nil {
+		if isResourceVersionExpired(err) {
+			return w.relist(ctx)
+		}
+		return err
+	}
+	defer evWatcher.Stop()
+	log.Debugf("Watching events from resource version %s", resVer)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case rcv, ok := <-evWatcher.ResultChan():
+			if !ok {
+				return nil
+			}
+
+			switch rcv.Type {
+			case watch.Error:
+				err := apierrors.FromObject(rcv.Object)
+				if isResourceVersionExpired(err) {
+					return w.relist(ctx)
+				}
+				return fmt.Errorf("received an unexpected status while watching the events: %s", err)
+			case watch.Bookmark:
+				if accessor, err := meta.Accessor(rcv.Object); err == nil {
+					w.setResourceVersion(accessor.GetResourceVersion())
+				}
+			case watch.Deleted:
+				// Events are deleted when they reach the events TTL of the API server, they were handled when added.
+				continue
+			default:
+				ev, ok := rcv.Object.(*v1.Event)
+				if !ok {
+					_ = log.Errorf("The event object for %v cannot be safely converted, skipping it.", rcv.Object)
+					continue
+				}
+				w.handle([]*v1.Event{ev})
+				w.setResourceVersion(ev.ResourceVersion)
+			}
+		}
+	}
+}
+
+// relist lists the events in pages of at most relistLimit events, handles the ones newer than the last handled resource
+// version and resumes from the resource version of the list. When listing a page fails, the events are listed again from
+// the first page on the next attempt.
+func (w *eventWatcher) relist(ctx context.Context) error {
+	resVer := w.ResourceVersion()
+	log.Debugf("Resource version '%s' of the events is too old or unknown, listing the events", resVer)
+
+	var listResVer, continueToken string
+	for {
+		events, pageResVer, nextContinueToken, err := w.ac.RelistEvents(ctx, resVer, w.relistLimit, continueToken, w.filter)
+		if err != nil {
+			return err
+		}
+		w.handle(events)
+		if listResVer == "" {
+			// the pages are a consistent snapshot of the resource version of the first page
+			listResVer = pageResVer
+		}
+		if nextContinueToken == "" {
+			break
+		}
+		continueToken = nextContinueToken
+	}
+
+	w.setResourceVersion(listResVer)
+	return nil
+}
+
+// isResourceVersionExpired returns true when the error is the 410 Gone status of a resource version that is too old
+func isResourceVersionExpired(err error) bool {
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return true
+	}
+	var status apierrors.APIStatus
+	return errors.As(err, &status) && status.Status().Code == http.StatusGone
+}
+
+// runEventWatch starts the event watch from the persisted resource version on the first run as leader, on the other
+// runs it persists the resource version the watch got to
+func (k *EventsCheck) runEventWatch(sender aggregator.Sender) error {
+	if k.eventWatch != nil {
+		return k.persistEventResourceVersion()
+	}
+
+	var state eventWatchState
+	if err := k.GetStateValue(eventResourceVersionStateKey, &state); err != nil {
+		_ = k.Warnf("Could not read the resource version to resume watching events from: %s", err) //nolint:errcheck
+	}
+	resVer := state.ResourceVersion
+	k.persistedResVer = resVer
+
+	mapper := k.mapperFactory(k.ac, k.clusterName, k.instance.EventCategories)
+	k.eventWatch = newEventWatcher(k.ac, k.ignoredEvents, int64(k.instance.MaxEventsPerRelist), func(events []*v1.Event) {
+		k.streamEvents(sender, mapper, events)
+	})
+	log.Infof("Starting the watch of the kubernetes events from resource version '%s'", resVer)
+	k.eventWatch.start(resVer)
+	return nil
+}
+
+// stopEventWatch stops the event watch and persists the resource version it got to, so the next leader resumes from it
+func (k *EventsCheck) stopEventWatch() {
+	if k.eventWatch == nil {
+		return
+	}
+	log.Infof("Stopping the watch of the kubernetes events of the check '%s'", k.ID())
+	k.eventWatch.stop()
+	if err := k.persistEventResourceVersion(); err != nil {
+		_ = log.Warnf("Could not persist the resource version of the kubernetes events: %s", err)
+	}
+	k.eventWatch = nil
+}
+
+// persistEventResourceVersion persists the resource version of the event watch in the check state when it changed
+func (k *EventsCheck) persistEventResourceVersion() error {
+	resVer := k.eventWatch.ResourceVersion()
+	if resVer == "" || resVer == k.persistedResVer {
+		return nil
+	}
+	if err := k.SetStateValue(eventResourceVersionStateKey, eventWatchState{ResourceVersion: resVer}); err != nil {
+		return err
+	}
+	k.persistedResVer = resVer
+	return nil
+}
+
+// streamEvents submits the events received by the event watch. It runs in between the check runs, so mapping errors
+// are logged instead of reported as warnings of the check.
+func (k *EventsCheck) streamEvents(sender aggregator.Sender, mapper *kubernetesEventMapper, events []*v1.Event) {
+	k.observePodHealthEvents(events)
+	for _, event := range events {
+		mappedEvent, err := mapper.mapKubernetesEvent(event)
+		if err != nil {
+			_ = log.Warnf("Error while mapping event, %s.", err.Error())
+			continue
+		}
+
+		log.Debugf("Sending event: %s", mappedEvent.String())
+		sender.Event(mappedEvent)
+	}
+}
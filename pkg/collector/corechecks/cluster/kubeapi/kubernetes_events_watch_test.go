@@ -0,0 +1,236 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	obj "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/aggregator/mocksender"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+)
+
+// fakeEventWatches returns a new fake watch for every watch of the events, and keeps the resource versions watched from
+type fakeEventWatches struct {
+	mux      sync.Mutex
+	watches  chan *watch.FakeWatcher
+	resVers  []string
+	listFunc func() *v1.EventList
+}
+
+func mockEventWatchAPIClient(watches *fakeEventWatches) *apiserver.APIClient {
+	ac := MockAPIClient(nil)
+	fakeClient := ac.Cl.(clientSetHTTP).Clientset
+	fakeClient.PrependWatchReactor("events", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watches.mux.Lock()
+		watches.resVers = append(watches.resVers, action.(k8stesting.WatchActionImpl).WatchRestrictions.ResourceVersion)
+		watches.mux.Unlock()
+		fakeWatch := watch.NewFakeWithChanSize(10, false)
+		watches.watches <- fakeWatch
+		return true, fakeWatch, nil
+	})
+	fakeClient.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, watches.listFunc(), nil
+	})
+	return ac
+}
+
+func newFakeEventWatches() *fakeEventWatches {
+	return &fakeEventWatches{
+		watches:  make(chan *watch.FakeWatcher, 10),
+		listFunc: func() *v1.EventList { return &v1.EventList{} },
+	}
+}
+
+// next returns the next watch of the events that is started
+func (f *fakeEventWatches) next(t *testing.T) *watch.FakeWatcher {
+	select {
+	case fakeWatch := <-f.watches:
+		return fakeWatch
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the events were not watched")
+		return nil
+	}
+}
+
+func (f *fakeEventWatches) resourceVersions() []string {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return append([]string{}, f.resVers...)
+}
+
+func eventWithResourceVersion(reason, resVer string) *v1.Event {
+	event := createEvent(1, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4",
+		"default-scheduler", "machine-blue", reason, reason, 709662600, 709662600, "Normal", "")
+	event.ResourceVersion = resVer
+	return event
+}
+
+// handledEvents collects the resource versions of the events that are handled by an event watcher
+type handledEvents struct {
+	mux     sync.Mutex
+	resVers []string
+}
+
+func (h *handledEvents) handle(events []*v1.Event) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for _, event := range events {
+		h.resVers = append(h.resVers, event.ResourceVersion)
+	}
+}
+
+func (h *handledEvents) get() []string {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return append([]string{}, h.resVers...)
+}
+
+func TestEventWatcherStreamsEvents(t *testing.T) {
+	watches := newFakeEventWatches()
+	handled := &handledEvents{}
+	watcher := newEventWatcher(mockEventWatchAPIClient(watches), "", 100, handled.handle)
+	watcher.start("10")
+
+	fakeWatch := watches.next(t)
+	fakeWatch.Add(eventWithResourceVersion("Scheduled", "11"))
+	fakeWatch.Modify(eventWithResourceVersion("Scheduled", "12"))
+	// deleted events were handled when they were added
+	fakeWatch.Delete(eventWithResourceVersion("Started", "13"))
+	fakeWatch.Action(watch.Bookmark, &v1.Event{ObjectMeta: obj.ObjectMeta{ResourceVersion: "15"}})
+
+	assert.Eventually(t, func() bool { return watcher.ResourceVersion() == "15" }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"11", "12"}, handled.get())
+
+	// a closed watch is resumed from the last resource version
+	fakeWatch.Stop()
+	fakeWatch = watches.next(t)
+	fakeWatch.Add(eventWithResourceVersion("Started", "16"))
+	assert.Eventually(t, func() bool { return watcher.ResourceVersion() == "16" }, time.Second, 10*time.Millisecond)
+
+	watcher.stop()
+	assert.Equal(t, []string{"10", "15"}, watches.resourceVersions())
+	assert.Equal(t, []string{"11", "12", "16"}, handled.get())
+}
+
+func TestEventWatcherRelistsExpiredResourceVersion(t *testing.T) {
+	// the fake client does not pass the continue token, the pages are returned in the order they are listed
+	pages := []*v1.EventList{
+		{
+			ListMeta: obj.ListMeta{ResourceVersion: "20", Continue: "more-events"},
+			Items: []v1.Event{
+				*eventWithResourceVersion("Scheduled", "9"),
+				*eventWithResourceVersion("Pulled", "11"),
+			},
+		},
+		{
+			ListMeta: obj.ListMeta{ResourceVersion: "20"},
+			Items:    []v1.Event{*eventWithResourceVersion("Started", "12")},
+		},
+	}
+	watches := newFakeEventWatches()
+	watches.listFunc = func() *v1.EventList {
+		page := pages[0]
+		pages = pages[1:]
+		return page
+	}
+	handled := &handledEvents{}
+	watcher := newEventWatcher(mockEventWatchAPIClient(watches), "", 2, handled.handle)
+	watcher.start("10")
+
+	fakeWatch := watches.next(t)
+	fakeWatch.Error(&obj.Status{Status: obj.StatusFailure, Code: 410, Reason: obj.StatusReasonExpired})
+
+	// all the pages are listed, only the events newer than the last resource version are handled and watching resumes
+	// from the list
+	watches.next(t)
+	assert.Empty(t, pages)
+	assert.Equal(t, "20", watcher.ResourceVersion())
+	assert.Equal(t, []string{"11", "12"}, handled.get())
+
+	watcher.stop()
+	assert.Equal(t, []string{"10", "20"}, watches.resourceVersions())
+}
+
+func TestEventWatcherListsWithoutResourceVersion(t *testing.T) {
+	watches := newFakeEventWatches()
+	watches.listFunc = func() *v1.EventList {
+		return &v1.EventList{
+			ListMeta: obj.ListMeta{ResourceVersion: "20"},
+			Items:    []v1.Event{*eventWithResourceVersion("Scheduled", "9")},
+		}
+	}
+	handled := &handledEvents{}
+	watcher := newEventWatcher(mockEventWatchAPIClient(watches), "", 100, handled.handle)
+	watcher.start("")
+
+	watches.next(t)
+	watcher.stop()
+	assert.Equal(t, []string{"9"}, handled.get())
+	assert.Equal(t, []string{"20"}, watches.resourceVersions())
+}
+
+func TestEventsCheckWatchPersistsResourceVersion(t *testing.T) {
+	stateRoot, err := ioutil.TempDir("", "kubernetes-events-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(stateRoot)
+	config.Datadog.Set("check_state_root_path", stateRoot)
+	defer config.Datadog.Set("check_state_root_path", "")
+	state.InitCheckStateManager()
+
+	watches := newFakeEventWatches()
+	newCheck := func() *EventsCheck {
+		evCheck := KubernetesAPIEventsFactory().(*EventsCheck)
+		evCheck.ac = mockEventWatchAPIClient(watches)
+		require.NoError(t, evCheck.Configure([]byte("watch_events_enabled: true"), nil, ""))
+		return evCheck
+	}
+
+	evCheck := newCheck()
+	assert.Equal(t, defaultMaxEventsPerRelist, evCheck.instance.MaxEventsPerRelist)
+	require.NoError(t, evCheck.SetStateValue(eventResourceVersionStateKey, eventWatchState{ResourceVersion: "10"}))
+
+	mockSender := mocksender.NewMockSender(evCheck.ID())
+	mockSender.On("Event", mock.AnythingOfType("metrics.Event"))
+
+	// the first run starts the watch from the persisted resource version
+	require.NoError(t, evCheck.runEventWatch(mockSender))
+	fakeWatch := watches.next(t)
+	fakeWatch.Add(eventWithResourceVersion("Scheduled", "11"))
+	assert.Eventually(t, func() bool { return evCheck.eventWatch.ResourceVersion() == "11" }, time.Second, 10*time.Millisecond)
+	mockSender.AssertNumberOfCalls(t, "Event", 1)
+
+	// the next runs persist the resource version the watch got to
+	require.NoError(t, evCheck.runEventWatch(mockSender))
+	var persisted eventWatchState
+	require.NoError(t, evCheck.GetStateValue(eventResourceVersionStateKey, &persisted))
+	assert.Equal(t, "11", persisted.ResourceVersion)
+
+	fakeWatch.Add(eventWithResourceVersion("Started", "12"))
+	assert.Eventually(t, func() bool { return evCheck.eventWatch.ResourceVersion() == "12" }, time.Second, 10*time.Millisecond)
+
+	// cancelling the check persists the resource version, so the next check resumes from it
+	evCheck.Cancel()
+	assert.Nil(t, evCheck.eventWatch)
+
+	nextCheck := newCheck()
+	require.NoError(t, nextCheck.runEventWatch(mockSender))
+	watches.next(t)
+	nextCheck.Cancel()
+	assert.Equal(t, []string{"10", "12"}, watches.resourceVersions())
+}
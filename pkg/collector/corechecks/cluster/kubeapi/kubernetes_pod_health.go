@@ -0,0 +1,269 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/urn"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	podHealthSubStream                = "pods"
+	podStatusCheckName                = "Pod status"
+	containerStatusCheckName          = "Container status"
+	defaultPodPendingThresholdSeconds = 300
+	// probeFailureRetention is how long a failed liveness or readiness probe affects the health of a container
+	probeFailureRetention = 5 * time.Minute
+	// podHealthExpiryIntervals is the number of check intervals after which the pod health expires when the check no
+	// longer submits it, i.e. when the cluster agent is gone
+	podHealthExpiryIntervals = 3
+)
+
+// podHealth derives the health of every pod and container from their status and the probe failures reported by the
+// Kubernetes events. It keeps the restart counts of the previous run, so only new restarts affect the health.
+type podHealth struct {
+	urn              urn.Builder
+	stream           health.Stream
+	pendingThreshold time.Duration
+	restartCounts    map[string]int32
+
+	mux           sync.Mutex
+	probeFailures map[string]probeFailure
+}
+
+// probeFailure is the last failed probe of a container
+type probeFailure struct {
+	message  string
+	observed time.Time
+}
+
+func newPodHealth(urnBuilder urn.Builder, stream health.Stream, pendingThreshold time.Duration) *podHealth {
+	return &podHealth{
+		urn:              urnBuilder,
+		stream:           stream,
+		pendingThreshold: pendingThreshold,
+		restartCounts:    make(map[string]int32),
+		probeFailures:    make(map[string]probeFailure),
+	}
+}
+
+// observeEvent records the liveness and readiness probe failures of containers, the events are observed by the event
+// collection or the event watch
+func (p *podHealth) observeEvent(event *v1.Event, now time.Time) {
+	if event.Reason != "Unhealthy" || event.InvolvedObject.Kind != "Pod" {
+		return
+	}
+	if !strings.HasPrefix(event.Message, "Liveness probe") && !strings.HasPrefix(event.Message, "Readiness probe") {
+		return
+	}
+	containerName := getContainerNameFromEvent(event)
+	if containerName == "" {
+		return
+	}
+
+	containerID := p.urn.BuildContainerExternalID(event.InvolvedObject.Namespace, event.InvolvedObject.Name, containerName)
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.probeFailures[containerID] = probeFailure{message: event.Message, observed: now}
+}
+
+// checkStates derives a check state for every pod and container, sorted by pod
+func (p *podHealth) checkStates(pods []v1.Pod, now time.Time) []*health.CheckState {
+	p.mux.Lock()
+	for containerID, failure := range p.probeFailures {
+		if now.Sub(failure.observed) > probeFailureRetention {
+			delete(p.probeFailures, containerID)
+		}
+	}
+	p.mux.Unlock()
+
+	restartCounts := make(map[string]int32)
+	checkStates := make([]*health.CheckState, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		podID := p.urn.BuildPodExternalID(pod.Namespace, pod.Name)
+		state, message := p.podState(pod, now)
+		checkStates = append(checkStates, &health.CheckState{
+			CheckStateID:              fmt.Sprintf("%s:%s", podStatusCheckName, podID),
+			Message:                   message,
+			Health:                    state,
+			TopologyElementIdentifier: podID,
+			Name:                      podStatusCheckName,
+		})
+
+		for _, status := range pod.Status.ContainerStatuses {
+			containerID := p.urn.BuildContainerExternalID(pod.Namespace, pod.Name, status.Name)
+			state, message := p.containerState(containerID, status)
+			restartCounts[containerID] = status.RestartCount
+			checkStates = append(checkStates, &health.CheckState{
+				CheckStateID:              fmt.Sprintf("%s:%s", containerStatusCheckName, containerID),
+				Message:                   message,
+				Health:                    state,
+				TopologyElementIdentifier: containerID,
+				Name:                      containerStatusCheckName,
+			})
+		}
+	}
+	// the containers that are gone are forgotten
+	p.restartCounts = restartCounts
+	return checkStates
+}
+
+// podState is CRITICAL for failed pods and DEVIATING for pods that are pending for too long or are not ready
+func (p *podHealth) podState(pod *v1.Pod, now time.Time) (health.State, string) {
+	switch pod.Status.Phase {
+	case v1.PodFailed:
+		return health.Critical, podStatusMessage("Pod failed", pod.Status.Reason, pod.Status.Message)
+	case v1.PodSucceeded:
+		return health.Clear, "Pod completed"
+	case v1.PodPending:
+		pending := now.Sub(pod.CreationTimestamp.Time)
+		if pending <= p.pendingThreshold {
+			return health.Clear, "Pod is pending"
+		}
+		message := fmt.Sprintf("Pod has been pending for %s", pending.Truncate(time.Second))
+		if condition := podCondition(pod, v1.PodScheduled); condition != nil && condition.Status == v1.ConditionFalse {
+			message = podStatusMessage(message, condition.Reason, condition.Message)
+		}
+		return health.Deviating, message
+	}
+
+	if condition := podCondition(pod, v1.PodReady); condition != nil && condition.Status == v1.ConditionFalse {
+		return health.Deviating, podStatusMessage("Pod is not ready", condition.Reason, condition.Message)
+	}
+	return health.Clear, "Pod is running"
+}
+
+// containerState is CRITICAL for containers in CrashLoopBackOff or of which the image can not be pulled, and DEVIATING
+// for containers that are not ready, failed a probe, terminated with an error or restarted since the previous run
+func (p *podHealth) containerState(containerID string, status v1.ContainerStatus) (health.State, string) {
+	state := health.Clear
+	var messages []string
+	problem := func(problemState health.State, message string) {
+		if healthSeverity(problemState) > healthSeverity(state) {
+			state = problemState
+		}
+		messages = append(messages, message)
+	}
+
+	if waiting := status.State.Waiting; waiting != nil {
+		switch waiting.Reason {
+		case "CrashLoopBackOff":
+			problem(health.Critical, fmt.Sprintf("Container is in CrashLoopBackOff: %s", waiting.Message))
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+			problem(health.Critical, fmt.Sprintf("Image %s can not be pulled (%s): %s", status.Image, waiting.Reason, waiting.Message))
+		}
+	}
+	if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		problem(health.Deviating, fmt.Sprintf("Container terminated with exit code %d (%s)", terminated.ExitCode, terminated.Reason))
+	}
+	if status.State.Running != nil && !status.Ready {
+		problem(health.Deviating, "Container is running but not ready")
+	}
+
+	p.mux.Lock()
+	failure, failed := p.probeFailures[containerID]
+	p.mux.Unlock()
+	if failed {
+		problem(health.Deviating, failure.message)
+	}
+
+	if previous, found := p.restartCounts[containerID]; found && status.RestartCount > previous {
+		problem(health.Deviating, fmt.Sprintf("Container restarted %d time(s) since the last check, %d time(s) in total",
+			status.RestartCount-previous, status.RestartCount))
+	}
+
+	if len(messages) == 0 {
+		return state, "Container is healthy"
+	}
+	return state, strings.Join(messages, "\n")
+}
+
+func podCondition(pod *v1.Pod, conditionType v1.PodConditionType) *v1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// podStatusMessage appends the reason and message of a pod status or condition, when set, to the summary
+func podStatusMessage(summary, reason, message string) string {
+	details := make([]string, 0, 2)
+	for _, detail := range []string{reason, message} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return summary
+	}
+	return fmt.Sprintf("%s: %s", summary, strings.Join(details, ", "))
+}
+
+// healthSeverity orders the health states from clear to critical
+func healthSeverity(state health.State) int {
+	switch state {
+	case health.Deviating:
+		return 1
+	case health.Critical:
+		return 2
+	}
+	return 0
+}
+
+// getPodHealth returns the pod health of the check, it is created on the first run so it keeps the restart counts and
+// probe failures in between runs
+func (k *EventsCheck) getPodHealth() *podHealth {
+	if k.podHealth == nil {
+		mapper := k.mapperFactory(k.ac, k.clusterName, k.instance.EventCategories)
+		stream := health.Stream{
+			Urn:       fmt.Sprintf("urn:health:%s:%s", mapper.sourceType, k.clusterName),
+			SubStream: podHealthSubStream,
+		}
+		k.podHealth = newPodHealth(mapper.urn, stream, time.Duration(k.instance.PodPendingThresholdSeconds)*time.Second)
+	}
+	return k.podHealth
+}
+
+// observePodHealthEvents records the probe failures of the events when the pod health is enabled
+func (k *EventsCheck) observePodHealthEvents(events []*v1.Event) {
+	if k.podHealth == nil {
+		return
+	}
+	now := time.Now()
+	for _, event := range events {
+		k.podHealth.observeEvent(event, now)
+	}
+}
+
+// runPodHealth lists the pods once and submits the health of all pods and containers as a health snapshot, so the
+// health of pods and containers that are gone is removed
+func (k *EventsCheck) runPodHealth() error {
+	pods, err := k.ac.GetPods()
+	if err != nil {
+		_ = k.Warnf("Could not collect pods from the api server for the pod health: %s", err.Error()) //nolint:errcheck
+		return err
+	}
+
+	podHealth := k.getPodHealth()
+	checkStates := podHealth.checkStates(pods, time.Now())
+	log.Debugf("Submitting the health of %d pods and containers", len(checkStates))
+	intervalSeconds := int(k.Interval().Seconds())
+	return k.WithHealthSnapshot(podHealth.stream, intervalSeconds, podHealthExpiryIntervals*intervalSeconds, func() error {
+		for _, checkState := range checkStates {
+			k.SubmitHealthCheckData(podHealth.stream, health.CheckData{CheckState: checkState})
+		}
+		return nil
+	})
+}
@@ -0,0 +1,163 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/urn"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+var podHealthNow = time.Unix(1700000000, 0)
+
+func testPod(name string, phase v1.PodPhase, conditions []v1.PodCondition, containers ...v1.ContainerStatus) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(podHealthNow.Add(-10 * time.Minute)),
+		},
+		Status: v1.PodStatus{Phase: phase, Conditions: conditions, ContainerStatuses: containers},
+	}
+}
+
+func runningContainer(name string, ready bool, restarts int32) v1.ContainerStatus {
+	return v1.ContainerStatus{
+		Name:         name,
+		Ready:        ready,
+		RestartCount: restarts,
+		State:        v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+	}
+}
+
+func waitingContainer(name, reason, message string) v1.ContainerStatus {
+	return v1.ContainerStatus{
+		Name:  name,
+		Image: "nginx:unknown",
+		State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason, Message: message}},
+	}
+}
+
+// healthByElement returns the health and message of the check states by topology element
+func healthByElement(checkStates []*health.CheckState) map[string][2]string {
+	result := make(map[string][2]string, len(checkStates))
+	for _, checkState := range checkStates {
+		result[checkState.TopologyElementIdentifier] = [2]string{string(checkState.Health), checkState.Message}
+	}
+	return result
+}
+
+func TestPodHealthCheckStates(t *testing.T) {
+	podHealth := newPodHealth(urn.NewURNBuilder(urn.Kubernetes, "mycluster"), health.Stream{}, 5*time.Minute)
+	notReady := []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady"}}
+	unschedulable := []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"}}
+	pods := []v1.Pod{
+		testPod("healthy", v1.PodRunning, nil, runningContainer("app", true, 3)),
+		testPod("unschedulable", v1.PodPending, unschedulable),
+		testPod("failed", v1.PodFailed, nil),
+		testPod("completed", v1.PodSucceeded, nil),
+		testPod("crashing", v1.PodRunning, notReady,
+			waitingContainer("app", "CrashLoopBackOff", "back-off 5m0s restarting failed container"),
+			waitingContainer("sidecar", "ImagePullBackOff", "Back-off pulling image"),
+			runningContainer("proxy", false, 0)),
+	}
+
+	// the first run only knows the restart counts
+	podHealth.observeEvent(&v1.Event{
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "healthy", FieldPath: "spec.containers{app}"},
+		Reason:         "Unhealthy",
+		Message:        "Liveness probe failed: HTTP probe failed with statuscode: 500",
+	}, podHealthNow.Add(-10*time.Minute))
+	assert.Equal(t, map[string][2]string{
+		"urn:kubernetes:/mycluster:default:pod/healthy":                    {"CLEAR", "Pod is running"},
+		"urn:kubernetes:/mycluster:default:pod/healthy:container/app":      {"CLEAR", "Container is healthy"},
+		"urn:kubernetes:/mycluster:default:pod/unschedulable":              {"DEVIATING", "Pod has been pending for 10m0s: Unschedulable, 0/3 nodes are available"},
+		"urn:kubernetes:/mycluster:default:pod/failed":                     {"CRITICAL", "Pod failed"},
+		"urn:kubernetes:/mycluster:default:pod/completed":                  {"CLEAR", "Pod completed"},
+		"urn:kubernetes:/mycluster:default:pod/crashing":                   {"DEVIATING", "Pod is not ready: ContainersNotReady"},
+		"urn:kubernetes:/mycluster:default:pod/crashing:container/app":     {"CRITICAL", "Container is in CrashLoopBackOff: back-off 5m0s restarting failed container"},
+		"urn:kubernetes:/mycluster:default:pod/crashing:container/sidecar": {"CRITICAL", "Image nginx:unknown can not be pulled (ImagePullBackOff): Back-off pulling image"},
+		"urn:kubernetes:/mycluster:default:pod/crashing:container/proxy":   {"DEVIATING", "Container is running but not ready"},
+	}, healthByElement(podHealth.checkStates(pods, podHealthNow)))
+
+	// restarts since the previous run and recent probe failures make the container deviating
+	pods[0].Status.ContainerStatuses[0].RestartCount = 4
+	podHealth.observeEvent(&v1.Event{
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "healthy", FieldPath: "spec.containers{app}"},
+		Reason:         "Unhealthy",
+		Message:        "Liveness probe failed: HTTP probe failed with statuscode: 500",
+	}, podHealthNow)
+	checkStates := healthByElement(podHealth.checkStates(pods[:1], podHealthNow.Add(time.Minute)))
+	assert.Equal(t, [2]string{"DEVIATING", "Liveness probe failed: HTTP probe failed with statuscode: 500\n" +
+		"Container restarted 1 time(s) since the last check, 4 time(s) in total"},
+		checkStates["urn:kubernetes:/mycluster:default:pod/healthy:container/app"])
+
+	// the probe failure expires and the restart count is known
+	checkStates = healthByElement(podHealth.checkStates(pods[:1], podHealthNow.Add(10*time.Minute)))
+	assert.Equal(t, [2]string{"CLEAR", "Container is healthy"}, checkStates["urn:kubernetes:/mycluster:default:pod/healthy:container/app"])
+}
+
+func TestEventsCheckPodHealth(t *testing.T) {
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+	mBatcher := batcher.NewMockBatcher()
+
+	evCheck := KubernetesAPIEventsFactory().(*EventsCheck)
+	evCheck.ac = MockAPIClient(nil)
+	require.NoError(t, evCheck.Configure([]byte("pod_health_enabled: true"), nil, ""))
+	evCheck.clusterName = "mycluster"
+	assert.Equal(t, defaultPodPendingThresholdSeconds, evCheck.instance.PodPendingThresholdSeconds)
+
+	pod := testPod("crashing", v1.PodRunning, nil, waitingContainer("app", "CrashLoopBackOff", "back-off"))
+	_, err := evCheck.ac.Cl.CoreV1().Pods("default").Create(context.TODO(), &pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, evCheck.runPodHealth())
+	// the pods are listed once per run
+	podLists := 0
+	for _, action := range evCheck.ac.Cl.(clientSetHTTP).Clientset.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			podLists++
+		}
+	}
+	assert.Equal(t, 1, podLists)
+
+	stream := health.Stream{Urn: "urn:health:kubernetes:mycluster", SubStream: "pods"}
+	podExternalID := "urn:kubernetes:/mycluster:default:pod/crashing"
+	containerExternalID := podExternalID + ":container/app"
+	assert.Equal(t, health.Health{
+		StartSnapshot: &health.StartSnapshotMetadata{
+			RepeatIntervalS: int(evCheck.Interval().Seconds()),
+			ExpiryIntervalS: podHealthExpiryIntervals * int(evCheck.Interval().Seconds()),
+		},
+		StopSnapshot:  &health.StopSnapshotMetadata{},
+		Stream:        stream,
+		CheckStates: []health.CheckData{
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Pod status:" + podExternalID,
+				Message:                   "Pod is running",
+				Health:                    health.Clear,
+				TopologyElementIdentifier: podExternalID,
+				Name:                      "Pod status",
+			}},
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Container status:" + containerExternalID,
+				Message:                   "Container is in CrashLoopBackOff: back-off",
+				Health:                    health.Critical,
+				TopologyElementIdentifier: containerExternalID,
+				Name:                      "Container status",
+			}},
+		},
+	}, mBatcher.CollectedTopology.Flush()[evCheck.ID()].Health[stream.GoString()])
+}
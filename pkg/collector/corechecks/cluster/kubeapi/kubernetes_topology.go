@@ -8,6 +8,12 @@
 package kubeapi
 
 import (
+	"fmt"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
 	"sync"
 	"time"
@@ -30,8 +36,10 @@ const (
 // TopologyCheck grabs events from the API server.
 type TopologyCheck struct {
 	CommonCheck
-	instance  *TopologyConfig
-	submitter TopologySubmitter
+	instance    *TopologyConfig
+	submitter   TopologySubmitter
+	incremental *incrementalTopology
+	healthRules []deriver.Rule
 }
 
 func warnDisabledResource(name string, additionalWarning string, isEnabled bool) {
@@ -71,6 +79,30 @@ func (t *TopologyCheck) Configure(config, initConfig integration.Data, source st
 	warnDisabledResource("jobs", "", t.instance.Resources.Jobs)
 	warnDisabledResource("cronjobs", "", t.instance.Resources.CronJobs)
 	warnDisabledResource("secrets", "", t.instance.Resources.Secrets)
+	warnDisabledResource("horizontalpodautoscalers", "", t.instance.Resources.HorizontalPodAutoscalers)
+	warnDisabledResource("poddisruptionbudgets", "", t.instance.Resources.PodDisruptionBudgets)
+	warnDisabledResource("networkpolicies", "", t.instance.Resources.NetworkPolicies)
+	warnDisabledResource("serviceaccounts", "it won't be possible to connect pods and roles to service accounts", t.instance.Resources.ServiceAccounts)
+	warnDisabledResource("roles", "", t.instance.Resources.Roles)
+	warnDisabledResource("clusterroles", "", t.instance.Resources.ClusterRoles)
+	warnDisabledResource("storageclasses", "", t.instance.Resources.StorageClasses)
+	warnDisabledResource("gatewayapi", "", t.instance.Resources.GatewayAPI)
+
+	var validCustomResources []collectors.CustomResourceConfig
+	for _, cr := range t.instance.CustomResources {
+		if err := cr.Validate(); err != nil {
+			_ = log.Warnf("Skipping collection of custom resource: %v", err)
+			continue
+		}
+		validCustomResources = append(validCustomResources, cr)
+	}
+	t.instance.CustomResources = validCustomResources
+
+	var errs []error
+	t.healthRules, errs = deriver.NewRules(t.instance.HealthRules)
+	for _, err := range errs {
+		_ = log.Warnf("Skipping Kubernetes health rule: %v", err)
+	}
 
 	log.Debugf("Running config %s", config)
 	return nil
@@ -130,6 +162,18 @@ func (t *TopologyCheck) Run() error {
 	// set up the batcher for this instance
 	t.submitter = NewBatchTopologySubmitter(t.instance.CheckID, t.instance.Instance)
 
+	// in incremental mode the collectors read from the informer caches in between the full snapshots, which are always
+	// listed from the API server
+	var collectorClient apiserver.APICollectorClient = t.ac
+	var incrementalSubmitter *IncrementalTopologySubmitter
+	if t.instance.IncrementalTopology {
+		incrementalSubmitter = t.getIncrementalTopology().Submitter(t.submitter, time.Now())
+		t.submitter = incrementalSubmitter
+		if !incrementalSubmitter.FullSnapshot() {
+			collectorClient = t.incremental.client
+		}
+	}
+
 	// start the topology snapshot with the batch-er
 	t.submitter.SubmitStartSnapshot()
 
@@ -157,7 +201,14 @@ func (t *TopologyCheck) Run() error {
 	case apiserver.NotOpenShift:
 		instanceClusterType = collectors.Kubernetes
 	}
-	clusterTopologyCommon := collectors.NewClusterTopologyCommon(t.instance.Instance, instanceClusterType, t.ac, t.instance.SourcePropertiesEnabled, componentChannel, relationChannel, t.getKubernetesVersion(), t.GetFeatures().FeatureEnabled(features.ExposeKubernetesStatus))
+	clusterTopologyCommon := collectors.NewClusterTopologyCommon(t.instance.Instance, instanceClusterType, collectorClient, t.instance.SourcePropertiesEnabled, componentChannel, relationChannel, t.getKubernetesVersion(), t.GetFeatures().FeatureEnabled(features.ExposeKubernetesStatus))
+	// health states are derived from what the collectors observe when health rules are configured
+	var healthDeriver *deriver.Deriver
+	if len(t.healthRules) > 0 {
+		stream := health.Stream{Urn: fmt.Sprintf("urn:health:%s:%s", t.instance.Instance.Type, t.instance.ClusterName)}
+		healthDeriver = deriver.NewDeriver(stream, t.healthRules, int(t.Interval().Seconds()))
+		clusterTopologyCommon.SetHealthDeriver(healthDeriver)
+	}
 	commonClusterCollector := collectors.NewClusterTopologyCollector(clusterTopologyCommon)
 	clusterCollectors := []collectors.ClusterTopologyCollector{
 		// Register Cluster Component Collector
@@ -180,6 +231,7 @@ func (t *TopologyCheck) Run() error {
 		collectors.NewServiceCollector(
 			endpointCorrelationChannel,
 			commonClusterCollector,
+			t.instance.Resources.Endpoints,
 		),
 	}
 
@@ -255,6 +307,76 @@ func (t *TopologyCheck) Run() error {
 				commonClusterCollector,
 			))
 	}
+	if t.instance.Resources.HorizontalPodAutoscalers {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewHorizontalPodAutoscalerCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.PodDisruptionBudgets {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewPodDisruptionBudgetCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.NetworkPolicies {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewNetworkPolicyCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.ServiceAccounts {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewServiceAccountCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.Roles {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewRoleCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.ClusterRoles {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewClusterRoleCollector(
+				commonClusterCollector,
+			))
+	}
+	if t.instance.Resources.StorageClasses {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewStorageClassCollector(
+				commonClusterCollector,
+				t.instance.Resources.Persistentvolumes,
+			))
+	}
+	if t.instance.Resources.GatewayAPI {
+		clusterCollectors = append(clusterCollectors,
+			collectors.NewGatewayClassCollector(
+				commonClusterCollector,
+			),
+			collectors.NewGatewayCollector(
+				commonClusterCollector,
+			),
+			collectors.NewHTTPRouteCollector(
+				commonClusterCollector,
+			),
+			collectors.NewGRPCRouteCollector(
+				commonClusterCollector,
+			))
+	}
+	if len(t.instance.CustomResources) > 0 && t.ac.DynamicCl == nil {
+		_ = log.Warnf("Skipping the collection of %d custom resources, the apiserver dynamic client is not available", len(t.instance.CustomResources))
+	} else {
+		for _, cr := range t.instance.CustomResources {
+			clusterCollectors = append(clusterCollectors,
+				collectors.NewCustomResourceCollector(
+					commonClusterCollector,
+					cr,
+					t.instance.CustomResources,
+				))
+		}
+	}
 
 	commonClusterCorrelator := collectors.NewClusterTopologyCorrelator(clusterTopologyCommon)
 	clusterCorrelators := []collectors.ClusterTopologyCorrelator{
@@ -280,9 +402,16 @@ func (t *TopologyCheck) Run() error {
 	t.RunClusterCollectors(clusterCollectors, clusterCorrelators, &waitGroup, errChannel, commonClusterCollector, collectorsDoneChannel)
 
 	// receive all the components, will return once the wait group notifies
-	t.WaitForTopology(componentChannel, relationChannel, errChannel, &waitGroup, waitGroupChannel)
+	timedOut := t.WaitForTopology(componentChannel, relationChannel, errChannel, &waitGroup, waitGroupChannel)
+	if timedOut && incrementalSubmitter != nil {
+		incrementalSubmitter.MarkIncomplete()
+	}
 
 	t.submitter.SubmitStopSnapshot()
+	// the derived health is only complete when all collectors finished
+	if healthDeriver != nil && !timedOut {
+		healthDeriver.Flush(deriver.NewBatcherSubmitter(batcher.GetBatcher(), t.instance.CheckID))
+	}
 	t.submitter.SubmitComplete()
 
 	log.Infof("Topology Check for cluster: %s completed successfully", t.instance.ClusterName)
@@ -297,8 +426,9 @@ func (t *TopologyCheck) Run() error {
 }
 
 // WaitForTopology sets up the receiver that handles the component and relation channel and publishes it to StackState, returns when all the collectors have finished or the timeout was reached.
+// Returns true if waiting timed out.
 func (t *TopologyCheck) WaitForTopology(componentChannel <-chan *topology.Component, relationChannel <-chan *topology.Relation,
-	errorChannel <-chan error, waitGroup *sync.WaitGroup, waitGroupChannel chan bool) {
+	errorChannel <-chan error, waitGroup *sync.WaitGroup, waitGroupChannel chan bool) bool {
 	log.Debugf("Waiting for Cluster Collectors to Finish")
 	go func() {
 	loop:
@@ -325,7 +455,29 @@ func (t *TopologyCheck) WaitForTopology(componentChannel <-chan *topology.Compon
 
 	timeout := time.Duration(t.instance.CollectTimeout) * time.Minute
 	log.Debugf("Waiting for Cluster Collectors to Finish")
-	waitGroupChannel <- waitTimeout(waitGroup, timeout)
+	timedOut := waitTimeout(waitGroup, timeout)
+	waitGroupChannel <- timedOut
+	return timedOut
+}
+
+// Cancel stops the informers of the incremental topology collection
+func (t *TopologyCheck) Cancel() {
+	if t.incremental != nil {
+		log.Infof("Shutting down informers used by the check '%s'", t.ID())
+		t.incremental.client.Stop()
+		t.incremental = nil
+	}
+	t.CommonCancel()
+}
+
+// getIncrementalTopology returns the state of the incremental topology collection, which is kept in between check runs
+func (t *TopologyCheck) getIncrementalTopology() *incrementalTopology {
+	if t.incremental == nil {
+		syncTimeout := time.Duration(config.Datadog.GetInt("kubernetes_topology_informer_sync_timeout")) * time.Second
+		client := apiserver.NewInformerCollectorClient(t.ac.Cl, t.ac, syncTimeout)
+		t.incremental = newIncrementalTopology(client, time.Duration(t.instance.FullResyncInterval)*time.Minute)
+	}
+	return t.incremental
 }
 
 // waitTimeout waits for the waitgroup for the specified max timeout.
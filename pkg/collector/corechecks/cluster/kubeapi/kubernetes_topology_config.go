@@ -6,7 +6,9 @@ package kubeapi
 import (
 	"github.com/StackVista/stackstate-agent/pkg/batcher"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	collectors "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/topologycollectors"
 	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"gopkg.in/yaml.v2"
@@ -18,13 +20,17 @@ const (
 
 // TopologyConfig is the config of the API server.
 type TopologyConfig struct {
-	ClusterName             string          `yaml:"cluster_name"`
-	CollectTopology         bool            `yaml:"collect_topology"`
-	CollectTimeout          int             `yaml:"collect_timeout"`
-	SourcePropertiesEnabled bool            `yaml:"source_properties_enabled"`
-	ConfigMapMaxDataSize    int             `yaml:"configmap_max_datasize"`
-	CSIPVMapperEnabled      bool            `yaml:"csi_pv_mapper_enabled"`
-	Resources               ResourcesConfig `yaml:"resources"`
+	ClusterName             string                            `yaml:"cluster_name"`
+	CollectTopology         bool                              `yaml:"collect_topology"`
+	CollectTimeout          int                               `yaml:"collect_timeout"`
+	SourcePropertiesEnabled bool                              `yaml:"source_properties_enabled"`
+	ConfigMapMaxDataSize    int                               `yaml:"configmap_max_datasize"`
+	CSIPVMapperEnabled      bool                              `yaml:"csi_pv_mapper_enabled"`
+	IncrementalTopology     bool                              `yaml:"incremental_topology_enabled"`
+	FullResyncInterval      int                               `yaml:"full_resync_interval"`
+	Resources               ResourcesConfig                   `yaml:"resources"`
+	CustomResources         []collectors.CustomResourceConfig `yaml:"custom_resources"`
+	HealthRules             []deriver.RuleConfig              `yaml:"health_rules"`
 	CheckID                 check.ID
 	Instance                topology.Instance
 }
@@ -43,6 +49,16 @@ type ResourcesConfig struct {
 	Jobs                   bool `yaml:"jobs"`
 	CronJobs               bool `yaml:"cronjobs"`
 	Secrets                bool `yaml:"secrets"`
+	// sts
+	HorizontalPodAutoscalers bool `yaml:"horizontalpodautoscalers"`
+	PodDisruptionBudgets     bool `yaml:"poddisruptionbudgets"`
+	NetworkPolicies          bool `yaml:"networkpolicies"`
+	ServiceAccounts          bool `yaml:"serviceaccounts"`
+	Roles                    bool `yaml:"roles"`
+	ClusterRoles             bool `yaml:"clusterroles"`
+	StorageClasses           bool `yaml:"storageclasses"`
+	// GatewayAPI collects GatewayClasses, Gateways, HTTPRoutes and GRPCRoutes when the cluster serves them
+	GatewayAPI bool `yaml:"gatewayapi"`
 }
 
 var defaultResourcesConfig = ResourcesConfig{
@@ -59,6 +75,15 @@ var defaultResourcesConfig = ResourcesConfig{
 	Jobs:                   true,
 	CronJobs:               true,
 	Secrets:                true,
+	// sts - the resources below are opt-in, the cluster agent needs to be granted to list and watch them
+	HorizontalPodAutoscalers: false,
+	PodDisruptionBudgets:     false,
+	NetworkPolicies:          false,
+	ServiceAccounts:          false,
+	Roles:                    false,
+	ClusterRoles:             false,
+	StorageClasses:           false,
+	GatewayAPI:               false,
 }
 
 func (c *TopologyConfig) parse(data []byte) error {
@@ -70,6 +95,8 @@ func (c *TopologyConfig) parse(data []byte) error {
 	c.SourcePropertiesEnabled = config.Datadog.GetBool("kubernetes_source_properties_enabled")
 	c.ConfigMapMaxDataSize = config.Datadog.GetInt("configmap_max_datasize")
 	c.CSIPVMapperEnabled = config.Datadog.GetBool("kubernetes_csi_pv_mapper_enabled")
+	c.IncrementalTopology = config.Datadog.GetBool("kubernetes_topology_incremental_enabled")
+	c.FullResyncInterval = config.Datadog.GetInt("kubernetes_topology_full_resync_interval")
 	if c.ConfigMapMaxDataSize == 0 {
 		c.ConfigMapMaxDataSize = DefaultConfigMapDataSizeLimit
 	}
@@ -84,6 +111,7 @@ type TopologySubmitter interface {
 	SubmitComplete()
 	SubmitComponent(component *topology.Component)
 	SubmitRelation(relation *topology.Relation)
+	SubmitDelete(externalID string)
 	HandleError(err error)
 }
 
@@ -128,6 +156,12 @@ func (b *BatchTopologySubmitter) SubmitRelation(relation *topology.Relation) {
 	batcher.GetBatcher().SubmitRelation(b.CheckID, b.Instance, *relation)
 }
 
+// SubmitDelete takes the external id of a component or relation and submits its deletion with the Batcher
+func (b *BatchTopologySubmitter) SubmitDelete(externalID string) {
+	log.Debugf("Publishing StackState deletion of %s", externalID)
+	batcher.GetBatcher().SubmitDelete(b.CheckID, b.Instance, externalID)
+}
+
 // HandleError handles any errors during topology gathering
 func (b *BatchTopologySubmitter) HandleError(err error) {
 	_ = log.Errorf("Error occurred in during topology collection: %s", err.Error())
@@ -0,0 +1,177 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// topologyModel keeps a hash of every component and relation that was submitted to StackState, by external id
+type topologyModel struct {
+	components map[string]uint64
+	relations  map[string]uint64
+}
+
+func newTopologyModel() *topologyModel {
+	return &topologyModel{
+		components: make(map[string]uint64),
+		relations:  make(map[string]uint64),
+	}
+}
+
+// incrementalTopology keeps the state of the incremental topology collection in between check runs
+type incrementalTopology struct {
+	client              *apiserver.InformerCollectorClient
+	model               *topologyModel
+	fullResyncInterval  time.Duration
+	lastFullSnapshot    time.Time
+	requireFullSnapshot bool
+}
+
+func newIncrementalTopology(client *apiserver.InformerCollectorClient, fullResyncInterval time.Duration) *incrementalTopology {
+	return &incrementalTopology{
+		client:              client,
+		model:               newTopologyModel(),
+		fullResyncInterval:  fullResyncInterval,
+		requireFullSnapshot: true,
+	}
+}
+
+// needsFullSnapshot returns true when the next run has to submit a full snapshot: on the first run, after a failed
+// run, when a watch was broken or when the full resync interval has passed.
+func (i *incrementalTopology) needsFullSnapshot(now time.Time) bool {
+	if i.requireFullSnapshot {
+		return true
+	}
+	if i.client.WatchBroken() {
+		log.Infof("Watch of the Kubernetes API is broken, submitting a full topology snapshot")
+		return true
+	}
+	return now.Sub(i.lastFullSnapshot) >= i.fullResyncInterval
+}
+
+// Submitter wraps the given submitter in an IncrementalTopologySubmitter for a single check run
+func (i *incrementalTopology) Submitter(submitter TopologySubmitter, now time.Time) *IncrementalTopologySubmitter {
+	fullSnapshot := i.needsFullSnapshot(now)
+	if fullSnapshot {
+		i.client.ResetWatchBroken()
+		i.lastFullSnapshot = now
+		i.requireFullSnapshot = false
+	}
+	return &IncrementalTopologySubmitter{
+		submitter:      submitter,
+		state:          i,
+		fullSnapshot:   fullSnapshot,
+		seenComponents: make(map[string]bool),
+		seenRelations:  make(map[string]bool),
+	}
+}
+
+// IncrementalTopologySubmitter submits only the topology changes compared to the previous check run to the
+// underlying submitter. Components and relations that were not changed are skipped, the ones that were not collected
+// anymore are deleted. On a full snapshot everything is forwarded within a start and stop snapshot.
+type IncrementalTopologySubmitter struct {
+	submitter      TopologySubmitter
+	state          *incrementalTopology
+	fullSnapshot   bool
+	incomplete     bool
+	seenComponents map[string]bool
+	seenRelations  map[string]bool
+}
+
+// FullSnapshot returns true if this submitter submits a full snapshot
+func (s *IncrementalTopologySubmitter) FullSnapshot() bool {
+	return s.fullSnapshot
+}
+
+// MarkIncomplete marks the collection of this check run as incomplete, so nothing is deleted and the next run
+// submits a full snapshot
+func (s *IncrementalTopologySubmitter) MarkIncomplete() {
+	s.incomplete = true
+	s.state.requireFullSnapshot = true
+}
+
+// SubmitStartSnapshot starts a snapshot and clears the topology model when submitting a full snapshot
+func (s *IncrementalTopologySubmitter) SubmitStartSnapshot() {
+	if s.fullSnapshot {
+		s.state.model = newTopologyModel()
+		s.submitter.SubmitStartSnapshot()
+	}
+}
+
+// SubmitStopSnapshot stops the snapshot when submitting a full snapshot, otherwise it deletes all the relations and
+// components that were submitted before but have not been collected in this run
+func (s *IncrementalTopologySubmitter) SubmitStopSnapshot() {
+	if s.fullSnapshot {
+		s.submitter.SubmitStopSnapshot()
+		return
+	}
+	if s.incomplete {
+		log.Infof("Topology collection was incomplete, skipping the deletion of topology elements")
+		return
+	}
+
+	model := s.state.model
+	for externalID := range model.relations {
+		if !s.seenRelations[externalID] {
+			s.submitter.SubmitDelete(externalID)
+			delete(model.relations, externalID)
+		}
+	}
+	for externalID := range model.components {
+		if !s.seenComponents[externalID] {
+			s.submitter.SubmitDelete(externalID)
+			delete(model.components, externalID)
+		}
+	}
+}
+
+// SubmitComplete submits the completion of the check run
+func (s *IncrementalTopologySubmitter) SubmitComplete() {
+	s.submitter.SubmitComplete()
+}
+
+// SubmitComponent submits the component if it is new or it changed since it was last submitted
+func (s *IncrementalTopologySubmitter) SubmitComponent(component *topology.Component) {
+	s.seenComponents[component.ExternalID] = true
+	hash := hashTopologyElement(component.JSONString())
+	if previous, ok := s.state.model.components[component.ExternalID]; s.fullSnapshot || !ok || previous != hash {
+		s.state.model.components[component.ExternalID] = hash
+		s.submitter.SubmitComponent(component)
+	}
+}
+
+// SubmitRelation submits the relation if it is new or it changed since it was last submitted
+func (s *IncrementalTopologySubmitter) SubmitRelation(relation *topology.Relation) {
+	s.seenRelations[relation.ExternalID] = true
+	hash := hashTopologyElement(relation.JSONString())
+	if previous, ok := s.state.model.relations[relation.ExternalID]; s.fullSnapshot || !ok || previous != hash {
+		s.state.model.relations[relation.ExternalID] = hash
+		s.submitter.SubmitRelation(relation)
+	}
+}
+
+// SubmitDelete submits the deletion of a topology element
+func (s *IncrementalTopologySubmitter) SubmitDelete(externalID string) {
+	delete(s.state.model.components, externalID)
+	delete(s.state.model.relations, externalID)
+	s.submitter.SubmitDelete(externalID)
+}
+
+// HandleError handles any errors during topology gathering, the collection of this run is incomplete in that case
+func (s *IncrementalTopologySubmitter) HandleError(err error) {
+	s.MarkIncomplete()
+	s.submitter.HandleError(err)
+}
+
+func hashTopologyElement(json string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(json))
+	return h.Sum64()
+}
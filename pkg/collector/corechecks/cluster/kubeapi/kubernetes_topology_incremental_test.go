@@ -0,0 +1,141 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubeapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// recordingTopologySubmitter keeps track of everything that was submitted
+type recordingTopologySubmitter struct {
+	snapshots  int
+	components []string
+	relations  []string
+	deletes    []string
+	errors     []error
+}
+
+func (r *recordingTopologySubmitter) SubmitStartSnapshot() { r.snapshots++ }
+func (r *recordingTopologySubmitter) SubmitStopSnapshot()  {}
+func (r *recordingTopologySubmitter) SubmitComplete()      {}
+func (r *recordingTopologySubmitter) SubmitComponent(component *topology.Component) {
+	r.components = append(r.components, component.ExternalID)
+}
+func (r *recordingTopologySubmitter) SubmitRelation(relation *topology.Relation) {
+	r.relations = append(r.relations, relation.ExternalID)
+}
+func (r *recordingTopologySubmitter) SubmitDelete(externalID string) {
+	r.deletes = append(r.deletes, externalID)
+}
+func (r *recordingTopologySubmitter) HandleError(err error) { r.errors = append(r.errors, err) }
+
+func testComponent(externalID string, version string) *topology.Component {
+	return &topology.Component{
+		ExternalID: externalID,
+		Type:       topology.Type{Name: "pod"},
+		Data:       topology.Data{"version": version},
+	}
+}
+
+func testRelation(sourceID, targetID string) *topology.Relation {
+	return &topology.Relation{
+		ExternalID: sourceID + "->" + targetID,
+		SourceID:   sourceID,
+		TargetID:   targetID,
+		Type:       topology.Type{Name: "scheduled_on"},
+		Data:       topology.Data{},
+	}
+}
+
+func runIncremental(state *incrementalTopology, now time.Time, collect func(s *IncrementalTopologySubmitter)) (*recordingTopologySubmitter, bool) {
+	recorder := &recordingTopologySubmitter{}
+	submitter := state.Submitter(recorder, now)
+	submitter.SubmitStartSnapshot()
+	collect(submitter)
+	submitter.SubmitStopSnapshot()
+	submitter.SubmitComplete()
+	return recorder, submitter.FullSnapshot()
+}
+
+func TestIncrementalTopologySubmitter(t *testing.T) {
+	client := apiserver.NewInformerCollectorClient(fake.NewSimpleClientset(), nil, time.Second)
+	defer client.Stop()
+	state := newIncrementalTopology(client, 30*time.Minute)
+	start := time.Now()
+
+	// the first run is always a full snapshot
+	recorder, full := runIncremental(state, start, func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.SubmitComponent(testComponent("b", "1"))
+		s.SubmitRelation(testRelation("a", "b"))
+	})
+	assert.True(t, full)
+	assert.Equal(t, 1, recorder.snapshots)
+	assert.Equal(t, []string{"a", "b"}, recorder.components)
+	assert.Equal(t, []string{"a->b"}, recorder.relations)
+
+	// unchanged elements are not submitted again, changed and new ones are
+	recorder, full = runIncremental(state, start.Add(time.Minute), func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.SubmitComponent(testComponent("b", "2"))
+		s.SubmitComponent(testComponent("c", "1"))
+		s.SubmitRelation(testRelation("a", "b"))
+	})
+	assert.False(t, full)
+	assert.Equal(t, 0, recorder.snapshots)
+	assert.Equal(t, []string{"b", "c"}, recorder.components)
+	assert.Empty(t, recorder.relations)
+	assert.Empty(t, recorder.deletes)
+
+	// elements that are not collected anymore are deleted, relations first
+	recorder, _ = runIncremental(state, start.Add(2*time.Minute), func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.SubmitComponent(testComponent("c", "1"))
+	})
+	assert.Empty(t, recorder.components)
+	assert.Equal(t, []string{"a->b", "b"}, recorder.deletes)
+
+	// after the full resync interval everything is submitted within a snapshot again
+	recorder, full = runIncremental(state, start.Add(31*time.Minute), func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.SubmitComponent(testComponent("c", "1"))
+	})
+	assert.True(t, full)
+	assert.Equal(t, 1, recorder.snapshots)
+	assert.Equal(t, []string{"a", "c"}, recorder.components)
+}
+
+func TestIncrementalTopologySubmitterIncompleteCollection(t *testing.T) {
+	client := apiserver.NewInformerCollectorClient(fake.NewSimpleClientset(), nil, time.Second)
+	defer client.Stop()
+	state := newIncrementalTopology(client, 30*time.Minute)
+	start := time.Now()
+
+	runIncremental(state, start, func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.SubmitComponent(testComponent("b", "1"))
+	})
+
+	// nothing is deleted when a collector failed, and the next run is a full snapshot
+	recorder, full := runIncremental(state, start.Add(time.Minute), func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+		s.HandleError(errors.New("collector failed"))
+	})
+	assert.False(t, full)
+	assert.Empty(t, recorder.deletes)
+	assert.Len(t, recorder.errors, 1)
+
+	recorder, full = runIncremental(state, start.Add(2*time.Minute), func(s *IncrementalTopologySubmitter) {
+		s.SubmitComponent(testComponent("a", "1"))
+	})
+	assert.True(t, full)
+	assert.Equal(t, []string{"a"}, recorder.components)
+}
@@ -8,6 +8,7 @@
 package kubeapi
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync"
@@ -17,10 +18,13 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	collectors "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/topologycollectors"
 	agentConfig "github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util/features"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 )
 
@@ -40,6 +44,17 @@ var optionalRules = []string{
 	"extensions/ingresses+get,list,watch",
 	"batch/cronjobs+get,list,watch",
 	"batch/jobs+get,list,watch",
+	"autoscaling/horizontalpodautoscalers+get,list,watch",
+	"policy/poddisruptionbudgets+get,list,watch",
+	"networking/networkpolicies+get,list,watch",
+	"serviceaccounts+get,list,watch",
+	"rbac/roles+get,list,watch",
+	"rbac/clusterroles+get,list,watch",
+	"storage/storageclasses+get,list,watch",
+	"gateway/gatewayclasses+list",
+	"gateway/gateways+list",
+	"gateway/httproutes+list",
+	"gateway/grpcroutes+list",
 }
 
 func TestDisablingAnyResourceWithoutDisablingCollectorCauseAnError(t *testing.T) {
@@ -52,6 +67,15 @@ func TestDisablingAnyResourceWithoutDisablingCollectorCauseAnError(t *testing.T)
 cluster_name: mycluster
 collect_topology: true
 csi_pv_mapper_enabled: true
+resources:
+  horizontalpodautoscalers: true
+  poddisruptionbudgets: true
+  networkpolicies: true
+  serviceaccounts: true
+  roles: true
+  clusterroles: true
+  storageclasses: true
+  gatewayapi: true
 `
 		err := check.Configure([]byte(nothingIsDisabledConfig), nil, "")
 		check.SetFeatures(features.All())
@@ -86,6 +110,14 @@ resources:
   jobs: false
   cronjobs: false
   secrets: false
+  horizontalpodautoscalers: false
+  poddisruptionbudgets: false
+  networkpolicies: false
+  serviceaccounts: false
+  roles: false
+  clusterroles: false
+  storageclasses: false
+  gatewayapi: false
 `
 	err := check.Configure([]byte(allResourcesAreDisabledConfig), nil, "")
 	check.SetFeatures(features.All())
@@ -97,6 +129,59 @@ resources:
 	assert.Empty(t, mBatcher.Errors, "No errors are expected because all resources are disabled in config")
 }
 
+func TestDerivedPodHealth(t *testing.T) {
+	mBatcher := batcher.NewMockBatcher()
+	check := KubernetesAPITopologyFactory().(*TopologyCheck)
+	check.ac = MockAPIClient(nil)
+	_, err := check.ac.Cl.CoreV1().Pods("default").Create(context.TODO(), &coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "crashing-pod", Namespace: "default"},
+		Status: coreV1.PodStatus{
+			Phase: coreV1.PodRunning,
+			ContainerStatuses: []coreV1.ContainerStatus{
+				{Name: "app", RestartCount: 4},
+				{Name: "sidecar", RestartCount: 3},
+			},
+		},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	healthRulesConfig := `
+cluster_name: mycluster
+collect_topology: true
+health_rules:
+  - name: Pod restarts
+    source: pod_restarts
+    deviating_above: 0
+    critical_above: 5
+  - name: Invalid
+    source: pod_restarts
+`
+	err = check.Configure([]byte(healthRulesConfig), nil, "")
+	check.SetFeatures(features.All())
+	assert.NoError(t, err)
+
+	err = check.Run()
+	assert.NoError(t, err)
+
+	stream := health.Stream{Urn: "urn:health:kubernetes:mycluster"}
+	podExternalID := "urn:kubernetes:/mycluster:default:pod/crashing-pod"
+	producedHealth := mBatcher.CollectedTopology.Flush()[kubernetesAPITopologyCheckName].Health[stream.GoString()]
+	assert.Equal(t, health.Health{
+		StartSnapshot: &health.StartSnapshotMetadata{RepeatIntervalS: int(check.Interval().Seconds())},
+		StopSnapshot:  &health.StopSnapshotMetadata{},
+		Stream:        stream,
+		CheckStates: []health.CheckData{
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Pod restarts:" + podExternalID,
+				Message:                   "pod_restarts is 7, above the critical threshold of 5",
+				Health:                    health.Critical,
+				TopologyElementIdentifier: podExternalID,
+				Name:                      "Pod restarts",
+			}},
+		},
+	}, producedHealth)
+}
+
 func TestRunClusterCollectors(t *testing.T) {
 	t.Run("with sourceProperties enabled", func(t *testing.T) {
 		testRunClusterCollectors(t, true, true)
@@ -128,6 +213,8 @@ func TestConfigurationParsing(t *testing.T) {
 		SourcePropertiesEnabled: agentConfig.Datadog.GetBool("kubernetes_source_properties_enabled"),
 		ConfigMapMaxDataSize:    DefaultConfigMapDataSizeLimit,
 		CSIPVMapperEnabled:      agentConfig.Datadog.GetBool("kubernetes_csi_pv_mapper_enabled"),
+		IncrementalTopology:     agentConfig.Datadog.GetBool("kubernetes_topology_incremental_enabled"),
+		FullResyncInterval:      agentConfig.Datadog.GetInt("kubernetes_topology_full_resync_interval"),
 		Resources: ResourcesConfig{
 			Persistentvolumes:      true,
 			Persistentvolumeclaims: true,
@@ -163,12 +250,57 @@ resources:
   jobs: false
   cronjobs: false
   secrets: false
+  horizontalpodautoscalers: false
+  poddisruptionbudgets: false
+  networkpolicies: false
+  serviceaccounts: false
+  roles: false
+  clusterroles: false
+  storageclasses: false
+  gatewayapi: false
 `
 	expectedSimple := defaultConfig
 	expectedSimple.ClusterName = "mycluster"
 	expectedSimple.SourcePropertiesEnabled = false
 	expectedSimple.Resources = ResourcesConfig{}
 	testConfigParsed(t, allResourcesAreDisabledConfig, expectedSimple)
+
+	customResourcesConfig := `
+custom_resources:
+  - group: argoproj.io
+    version: v1alpha1
+    resource: rollouts
+    kind: Rollout
+    data:
+      replicas: spec.replicas
+    identifiers:
+      - status.url
+    owner_relation_type: controls
+    selector_relations:
+      - selector_path: spec.selector
+        target_kind: Pod
+        relation_type: controls
+  - group: cert-manager.io
+    resource: certificates
+    kind: Certificate
+`
+	expectedCustomResources := defaultConfig
+	expectedCustomResources.CustomResources = []collectors.CustomResourceConfig{
+		{
+			Group:             "argoproj.io",
+			Version:           "v1alpha1",
+			Resource:          "rollouts",
+			Kind:              "Rollout",
+			Data:              map[string]string{"replicas": "spec.replicas"},
+			Identifiers:       []string{"status.url"},
+			OwnerRelationType: "controls",
+			SelectorRelations: []collectors.CustomResourceSelectorConfig{
+				{SelectorPath: "spec.selector", TargetKind: "Pod", RelationType: "controls"},
+			},
+		},
+		// the certificate is skipped because it has no version
+	}
+	testConfigParsed(t, customResourcesConfig, expectedCustomResources)
 }
 
 func testRunClusterCollectors(t *testing.T, sourceProperties bool, exposeKubernetesStatus bool) {
@@ -231,6 +363,7 @@ type TestTopologySubmitter struct {
 func (b *TestTopologySubmitter) SubmitStartSnapshot() {}
 func (b *TestTopologySubmitter) SubmitStopSnapshot()  {}
 func (b *TestTopologySubmitter) SubmitComplete()      {}
+func (b *TestTopologySubmitter) SubmitDelete(string)  {}
 
 // SubmitRelation takes a component and submits it with the Batcher
 func (b *TestTopologySubmitter) SubmitComponent(component *topology.Component) {
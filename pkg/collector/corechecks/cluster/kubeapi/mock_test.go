@@ -5,12 +5,19 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	fake4 "k8s.io/client-go/kubernetes/typed/apps/v1/fake"
+	fakeautoscalingv1 "k8s.io/client-go/kubernetes/typed/autoscaling/v1/fake"
 	fake2 "k8s.io/client-go/kubernetes/typed/batch/v1/fake"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
 	fake3 "k8s.io/client-go/kubernetes/typed/extensions/v1beta1/fake"
+	fakenetworkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1/fake"
+	fakepolicyv1 "k8s.io/client-go/kubernetes/typed/policy/v1/fake"
+	fakerbacv1 "k8s.io/client-go/kubernetes/typed/rbac/v1/fake"
+	fakestoragev1 "k8s.io/client-go/kubernetes/typed/storage/v1/fake"
 	"k8s.io/client-go/rest"
 	core "k8s.io/client-go/testing"
 	k8stesting "k8s.io/client-go/testing"
@@ -35,7 +42,15 @@ func (c coveV1NoRestClient) RESTClient() rest.Interface {
 	return nil
 }
 
-func getReactor(fakeClient *fake.Clientset, group string) *k8stesting.Fake {
+// gatewayAPIListKinds registers the Gateway API resources with the fake dynamic client
+var gatewayAPIListKinds = map[schema.GroupVersionResource]string{
+	{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gatewayclasses"}: "GatewayClassList",
+	{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}:       "GatewayList",
+	{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}:     "HTTPRouteList",
+	{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "grpcroutes"}:     "GRPCRouteList",
+}
+
+func getReactor(fakeClient *fake.Clientset, fakeDynamicClient *dynamicfake.FakeDynamicClient, group string) *k8stesting.Fake {
 	switch group {
 	case "":
 		return fakeClient.CoreV1().(*fakecorev1.FakeCoreV1).Fake
@@ -45,6 +60,18 @@ func getReactor(fakeClient *fake.Clientset, group string) *k8stesting.Fake {
 		return fakeClient.ExtensionsV1beta1().(*fake3.FakeExtensionsV1beta1).Fake
 	case "apps":
 		return fakeClient.AppsV1().(*fake4.FakeAppsV1).Fake
+	case "autoscaling":
+		return fakeClient.AutoscalingV1().(*fakeautoscalingv1.FakeAutoscalingV1).Fake
+	case "policy":
+		return fakeClient.PolicyV1().(*fakepolicyv1.FakePolicyV1).Fake
+	case "networking":
+		return fakeClient.NetworkingV1().(*fakenetworkingv1.FakeNetworkingV1).Fake
+	case "rbac":
+		return fakeClient.RbacV1().(*fakerbacv1.FakeRbacV1).Fake
+	case "storage":
+		return fakeClient.StorageV1().(*fakestoragev1.FakeStorageV1).Fake
+	case "gateway":
+		return &fakeDynamicClient.Fake
 	default:
 		return nil
 	}
@@ -53,9 +80,10 @@ func getReactor(fakeClient *fake.Clientset, group string) *k8stesting.Fake {
 // MockAPIClient create a K8s API Client that can return errors for specified resource rules
 func MockAPIClient(restrictRules []Rule) *apiserver.APIClient {
 	fakeClient := fake.NewSimpleClientset()
+	fakeDynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gatewayAPIListKinds)
 
 	for _, rule := range restrictRules {
-		reactor := getReactor(fakeClient, rule.Group)
+		reactor := getReactor(fakeClient, fakeDynamicClient, rule.Group)
 		for _, verb := range rule.Verbs {
 			reactor.
 				PrependReactor(verb, rule.ResourceName, func(action core.Action) (handled bool, ret runtime.Object, err error) {
@@ -67,7 +95,8 @@ func MockAPIClient(restrictRules []Rule) *apiserver.APIClient {
 	x := clientSetHTTP{fakeClient}
 
 	return &apiserver.APIClient{
-		Cl: x,
+		Cl:        x,
+		DynamicCl: fakeDynamicClient,
 	}
 }
 
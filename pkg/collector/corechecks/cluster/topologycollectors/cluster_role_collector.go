@@ -0,0 +1,95 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	rbacV1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterRoleCollector implements the ClusterTopologyCollector interface.
+type ClusterRoleCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewClusterRoleCollector creates a new ClusterRole collector
+func NewClusterRoleCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &ClusterRoleCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*ClusterRoleCollector) GetName() string {
+	return "ClusterRole Collector"
+}
+
+// CollectorFunction Collects and publishes ClusterRole components and the ClusterRoleBindings of service accounts as relations
+func (crc *ClusterRoleCollector) CollectorFunction() error {
+	clusterRoles, err := crc.GetAPIClient().GetClusterRoles()
+	if err != nil {
+		return err
+	}
+
+	for _, clusterRole := range clusterRoles {
+		crc.SubmitComponent(crc.clusterRoleToStackStateComponent(clusterRole))
+	}
+
+	clusterRoleBindings, err := crc.GetAPIClient().GetClusterRoleBindings()
+	if err != nil {
+		return err
+	}
+
+	for _, clusterRoleBinding := range clusterRoleBindings {
+		if clusterRoleBinding.RoleRef.Kind != "ClusterRole" {
+			log.Debugf("Skipping ClusterRoleBinding %s with unsupported role reference kind '%s'", clusterRoleBinding.Name, clusterRoleBinding.RoleRef.Kind)
+			continue
+		}
+		clusterRoleExternalID := crc.buildClusterRoleExternalID(clusterRoleBinding.RoleRef.Name)
+
+		for _, serviceAccountExternalID := range serviceAccountSubjectExternalIDs(crc, clusterRoleBinding.Subjects, "") {
+			crc.SubmitRelation(serviceAccountToRoleStackStateRelation(crc, serviceAccountExternalID, clusterRoleExternalID, clusterRoleBinding.Name))
+		}
+	}
+
+	return nil
+}
+
+// clusterRoleToStackStateComponent Creates a StackState ClusterRole component from a Kubernetes / OpenShift Cluster
+func (crc *ClusterRoleCollector) clusterRoleToStackStateComponent(clusterRole rbacV1.ClusterRole) *topology.Component {
+	log.Tracef("Mapping ClusterRole to StackState component: %s", clusterRole.String())
+
+	tags := crc.initTags(clusterRole.ObjectMeta, metav1.TypeMeta{Kind: "ClusterRole"})
+
+	clusterRoleExternalID := crc.buildClusterRoleExternalID(clusterRole.Name)
+	component := &topology.Component{
+		ExternalID: clusterRoleExternalID,
+		Type:       topology.Type{Name: "cluster-role"},
+		Data: map[string]interface{}{
+			"name": clusterRole.Name,
+			"tags": tags,
+		},
+	}
+
+	if crc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if crc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&clusterRole)
+		} else {
+			sourceProperties = makeSourceProperties(&clusterRole)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", clusterRole.Kind)
+		component.Data.PutNonEmpty("uid", clusterRole.UID)
+		component.Data.PutNonEmpty("creationTimestamp", clusterRole.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", clusterRole.GenerateName)
+	}
+
+	log.Tracef("Created StackState ClusterRole component %s: %v", clusterRoleExternalID, component.JSONString())
+
+	return component
+}
@@ -0,0 +1,103 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	rbacV1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClusterRoleCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+	clusterRoleExternalID := "urn:kubernetes:/test-cluster-name:cluster-role/test-cluster-role"
+	serviceAccountExternalID := "urn:kubernetes:/test-cluster-name:kube-system:service-account/test-service-account"
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockClusterRoleAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		crc := NewClusterRoleCollector(commonClusterCollector)
+		expectedCollectorName := "ClusterRole Collector"
+		RunCollectorTest(t, crc, expectedCollectorName)
+
+		t.Run(testCaseName("Test ClusterRole and ClusterRoleBindings", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			if sourcePropertiesEnabled {
+				assert.Equal(t, clusterRoleExternalID, component.ExternalID)
+				assert.Equal(t, "ClusterRole", component.SourceProperties["kind"])
+			} else {
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: clusterRoleExternalID,
+					Type:       topology.Type{Name: "cluster-role"},
+					Data: topology.Data{
+						"name":              "test-cluster-role",
+						"kind":              "ClusterRole",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-clusterrole",
+						},
+						"uid": types.UID("test-cluster-role"),
+					},
+				}, component)
+			}
+
+			actualRelation := <-relationChannel
+			assert.EqualValues(t, &topology.Relation{
+				ExternalID: serviceAccountExternalID + "->" + clusterRoleExternalID,
+				Type:       topology.Type{Name: "bound_to"},
+				SourceID:   serviceAccountExternalID,
+				TargetID:   clusterRoleExternalID,
+				Data:       map[string]interface{}{"binding": "test-cluster-role-binding"},
+			}, actualRelation)
+		})
+	}
+}
+
+type MockClusterRoleAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockClusterRoleAPICollectorClient) GetClusterRoles() ([]rbacV1.ClusterRole, error) {
+	return []rbacV1.ClusterRole{
+		{
+			TypeMeta: v1.TypeMeta{Kind: "ClusterRole"},
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "test-cluster-role",
+				CreationTimestamp: creationTime,
+				Labels: map[string]string{
+					"test": "label",
+				},
+				UID: types.UID("test-cluster-role"),
+			},
+		},
+	}, nil
+}
+
+func (m MockClusterRoleAPICollectorClient) GetClusterRoleBindings() ([]rbacV1.ClusterRoleBinding, error) {
+	return []rbacV1.ClusterRoleBinding{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-cluster-role-binding"},
+			RoleRef:    rbacV1.RoleRef{Kind: "ClusterRole", Name: "test-cluster-role"},
+			Subjects: []rbacV1.Subject{
+				{Kind: rbacV1.ServiceAccountKind, Name: "test-service-account", Namespace: "kube-system"},
+				{Kind: rbacV1.UserKind, Name: "jane"},
+			},
+		},
+	}, nil
+}
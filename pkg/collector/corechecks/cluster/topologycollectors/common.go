@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/urn"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
@@ -62,12 +63,25 @@ type ClusterTopologyCommon interface {
 	buildPersistentVolumeExternalID(persistentVolumeName string) string
 	buildPersistentVolumeClaimExternalID(namespace, persistentVolumeName string) string
 	buildEndpointExternalID(endpointID string) string
+	buildHorizontalPodAutoscalerExternalID(namespace, horizontalPodAutoscalerName string) string
+	buildPodDisruptionBudgetExternalID(namespace, podDisruptionBudgetName string) string
+	buildNetworkPolicyExternalID(namespace, networkPolicyName string) string
+	buildServiceAccountExternalID(namespace, serviceAccountName string) string
+	buildRoleExternalID(namespace, roleName string) string
+	buildClusterRoleExternalID(clusterRoleName string) string
+	buildStorageClassExternalID(storageClassName string) string
+	buildGatewayClassExternalID(gatewayClassName string) string
+	buildGatewayExternalID(namespace, gatewayName string) string
+	buildHTTPRouteExternalID(namespace, httpRouteName string) string
+	buildGRPCRouteExternalID(namespace, grpcRouteName string) string
 	maximumMinorVersion(version int) bool
 	minimumMinorVersion(version int) bool
 	SubmitComponent(component *topology.Component)
 	SubmitRelation(relation *topology.Relation)
 	SetUseRelationCache(value bool)
 	CorrelateRelations()
+	SetHealthDeriver(healthDeriver *deriver.Deriver)
+	ObserveHealth(observation deriver.Observation)
 }
 
 type clusterTopologyCommon struct {
@@ -84,6 +98,7 @@ type clusterTopologyCommon struct {
 	useRelationCache              bool
 	relationCacheWG               sync.WaitGroup
 	exposeKubernetesStatusEnabled bool
+	healthDeriver                 *deriver.Deriver
 }
 
 // NewClusterTopologyCommon creates a clusterTopologyCommon
@@ -161,6 +176,18 @@ func (c *clusterTopologyCommon) SetUseRelationCache(value bool) {
 	c.useRelationCache = value
 }
 
+// SetHealthDeriver sets the deriver that health observations are recorded with, nil disables recording them
+func (c *clusterTopologyCommon) SetHealthDeriver(healthDeriver *deriver.Deriver) {
+	c.healthDeriver = healthDeriver
+}
+
+// ObserveHealth records an observation that health states are derived from, if health is derived at all
+func (c *clusterTopologyCommon) ObserveHealth(observation deriver.Observation) {
+	if c.healthDeriver != nil {
+		c.healthDeriver.Observe(observation)
+	}
+}
+
 // GetName returns the collector name
 func (*clusterTopologyCommon) GetName() string {
 	return "Unknown Collector"
@@ -317,6 +344,61 @@ func (c *clusterTopologyCommon) buildEndpointExternalID(endpointID string) strin
 	return c.urn.BuildEndpointExternalID(endpointID)
 }
 
+// buildHorizontalPodAutoscalerExternalID creates the urn external identifier for a cluster horizontal pod autoscaler
+func (c *clusterTopologyCommon) buildHorizontalPodAutoscalerExternalID(namespace, horizontalPodAutoscalerName string) string {
+	return c.urn.BuildHorizontalPodAutoscalerExternalID(namespace, horizontalPodAutoscalerName)
+}
+
+// buildPodDisruptionBudgetExternalID creates the urn external identifier for a cluster pod disruption budget
+func (c *clusterTopologyCommon) buildPodDisruptionBudgetExternalID(namespace, podDisruptionBudgetName string) string {
+	return c.urn.BuildPodDisruptionBudgetExternalID(namespace, podDisruptionBudgetName)
+}
+
+// buildNetworkPolicyExternalID creates the urn external identifier for a cluster network policy
+func (c *clusterTopologyCommon) buildNetworkPolicyExternalID(namespace, networkPolicyName string) string {
+	return c.urn.BuildNetworkPolicyExternalID(namespace, networkPolicyName)
+}
+
+// buildServiceAccountExternalID creates the urn external identifier for a cluster service account
+func (c *clusterTopologyCommon) buildServiceAccountExternalID(namespace, serviceAccountName string) string {
+	return c.urn.BuildServiceAccountExternalID(namespace, serviceAccountName)
+}
+
+// buildRoleExternalID creates the urn external identifier for a cluster role
+func (c *clusterTopologyCommon) buildRoleExternalID(namespace, roleName string) string {
+	return c.urn.BuildRoleExternalID(namespace, roleName)
+}
+
+// buildClusterRoleExternalID creates the urn external identifier for a cluster cluster role
+func (c *clusterTopologyCommon) buildClusterRoleExternalID(clusterRoleName string) string {
+	return c.urn.BuildClusterRoleExternalID(clusterRoleName)
+}
+
+// buildStorageClassExternalID creates the urn external identifier for a cluster storage class
+func (c *clusterTopologyCommon) buildStorageClassExternalID(storageClassName string) string {
+	return c.urn.BuildStorageClassExternalID(storageClassName)
+}
+
+// buildGatewayClassExternalID creates the urn external identifier for a cluster gateway class
+func (c *clusterTopologyCommon) buildGatewayClassExternalID(gatewayClassName string) string {
+	return c.urn.BuildGatewayClassExternalID(gatewayClassName)
+}
+
+// buildGatewayExternalID creates the urn external identifier for a cluster gateway
+func (c *clusterTopologyCommon) buildGatewayExternalID(namespace, gatewayName string) string {
+	return c.urn.BuildGatewayExternalID(namespace, gatewayName)
+}
+
+// buildHTTPRouteExternalID creates the urn external identifier for a cluster http route
+func (c *clusterTopologyCommon) buildHTTPRouteExternalID(namespace, httpRouteName string) string {
+	return c.urn.BuildHTTPRouteExternalID(namespace, httpRouteName)
+}
+
+// buildGRPCRouteExternalID creates the urn external identifier for a cluster grpc route
+func (c *clusterTopologyCommon) buildGRPCRouteExternalID(namespace, grpcRouteName string) string {
+	return c.urn.BuildGRPCRouteExternalID(namespace, grpcRouteName)
+}
+
 type ClusterObjectBase struct {
 	metav1.TypeMeta
 	metav1.ObjectMeta
@@ -0,0 +1,324 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResourceConfig describes a custom resource that is collected as topology and how its objects are mapped to
+// components and relations.
+type CustomResourceConfig struct {
+	Group   string `yaml:"group"`
+	Version string `yaml:"version"`
+	// Resource is the plural resource name, i.e. "rollouts"
+	Resource string `yaml:"resource"`
+	Kind     string `yaml:"kind"`
+	// ComponentType is the component type name and urn element, defaults to the lower-cased kind
+	ComponentType string `yaml:"component_type"`
+	// Data maps a component data key to a dot separated field path in the object, i.e. "replicas": "spec.replicas"
+	Data map[string]string `yaml:"data"`
+	// Identifiers are dot separated field paths of string (or string list) fields that become component identifiers
+	Identifiers []string `yaml:"identifiers"`
+	// OwnerRelationType is the type of the relation from the owners of an object to the object, when empty no relations
+	// are created for owner references
+	OwnerRelationType string                         `yaml:"owner_relation_type"`
+	SelectorRelations []CustomResourceSelectorConfig `yaml:"selector_relations"`
+}
+
+// CustomResourceSelectorConfig creates relations from a custom resource object to the objects in the same namespace that
+// are selected by a label selector in the custom resource.
+type CustomResourceSelectorConfig struct {
+	// SelectorPath is the dot separated path of either a LabelSelector or a plain label map, i.e. "spec.selector"
+	SelectorPath string `yaml:"selector_path"`
+	// TargetKind is the kind of the selected objects
+	TargetKind   string `yaml:"target_kind"`
+	RelationType string `yaml:"relation_type"`
+}
+
+// GroupVersionResource returns the resource that is listed for this custom resource
+func (c CustomResourceConfig) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: c.Group, Version: c.Version, Resource: c.Resource}
+}
+
+// GroupVersionKind returns the kind of the objects of this custom resource
+func (c CustomResourceConfig) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: c.Group, Version: c.Version, Kind: c.Kind}
+}
+
+// GetComponentType returns the configured component type or the lower-cased kind when none is configured
+func (c CustomResourceConfig) GetComponentType() string {
+	if c.ComponentType != "" {
+		return c.ComponentType
+	}
+	return strings.ToLower(c.Kind)
+}
+
+// Validate checks that the custom resource can be collected
+func (c CustomResourceConfig) Validate() error {
+	if c.Version == "" || c.Resource == "" || c.Kind == "" {
+		return fmt.Errorf("custom resource %s requires a version, resource and kind", c.GroupVersionResource().String())
+	}
+	for _, sr := range c.SelectorRelations {
+		if sr.SelectorPath == "" || sr.RelationType == "" {
+			return fmt.Errorf("selector relation of custom resource %s requires a selector_path and relation_type", c.Kind)
+		}
+		if _, ok := selectableKinds[sr.TargetKind]; !ok {
+			return fmt.Errorf("selector relation of custom resource %s has unsupported target kind '%s'", c.Kind, sr.TargetKind)
+		}
+	}
+	return nil
+}
+
+// selectableKinds are the kinds that can be the target of a selector relation, mapped to a function listing their objects
+var selectableKinds = map[string]func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error){
+	"Pod": func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error) {
+		pods, err := collector.GetAPIClient().GetPods()
+		metas := make([]metav1.ObjectMeta, 0, len(pods))
+		for _, o := range pods {
+			metas = append(metas, o.ObjectMeta)
+		}
+		return metas, err
+	},
+	"Service": func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error) {
+		services, err := collector.GetAPIClient().GetServices()
+		metas := make([]metav1.ObjectMeta, 0, len(services))
+		for _, o := range services {
+			metas = append(metas, o.ObjectMeta)
+		}
+		return metas, err
+	},
+	"Deployment": func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error) {
+		deployments, err := collector.GetAPIClient().GetDeployments()
+		metas := make([]metav1.ObjectMeta, 0, len(deployments))
+		for _, o := range deployments {
+			metas = append(metas, o.ObjectMeta)
+		}
+		return metas, err
+	},
+	"StatefulSet": func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error) {
+		statefulSets, err := collector.GetAPIClient().GetStatefulSets()
+		metas := make([]metav1.ObjectMeta, 0, len(statefulSets))
+		for _, o := range statefulSets {
+			metas = append(metas, o.ObjectMeta)
+		}
+		return metas, err
+	},
+	"ReplicaSet": func(collector ClusterTopologyCollector) ([]metav1.ObjectMeta, error) {
+		replicaSets, err := collector.GetAPIClient().GetReplicaSets()
+		metas := make([]metav1.ObjectMeta, 0, len(replicaSets))
+		for _, o := range replicaSets {
+			metas = append(metas, o.ObjectMeta)
+		}
+		return metas, err
+	},
+}
+
+// CustomResourceCollector implements the ClusterTopologyCollector interface.
+type CustomResourceCollector struct {
+	config CustomResourceConfig
+	// componentTypesByGVK resolves owner references to the other collected custom resources, keyed by group, version and
+	// kind so a custom resource never shadows a built-in or other kind with the same name
+	componentTypesByGVK map[schema.GroupVersionKind]string
+	ClusterTopologyCollector
+}
+
+// NewCustomResourceCollector creates a new collector for the configured custom resource. All the configured custom
+// resources are passed to be able to relate objects to owners that are custom resources themselves.
+func NewCustomResourceCollector(clusterTopologyCollector ClusterTopologyCollector, config CustomResourceConfig, allConfigs []CustomResourceConfig) ClusterTopologyCollector {
+	componentTypesByGVK := make(map[schema.GroupVersionKind]string, len(allConfigs))
+	for _, c := range allConfigs {
+		componentTypesByGVK[c.GroupVersionKind()] = c.GetComponentType()
+	}
+	return &CustomResourceCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+		config:                   config,
+		componentTypesByGVK:      componentTypesByGVK,
+	}
+}
+
+// GetName returns the name of the Collector
+func (crc *CustomResourceCollector) GetName() string {
+	return fmt.Sprintf("CustomResource %s Collector", crc.config.Kind)
+}
+
+// CollectorFunction Collects and publishes the objects of the custom resource as components
+func (crc *CustomResourceCollector) CollectorFunction() error {
+	objects, err := crc.GetAPIClient().GetCustomResources(crc.config.GroupVersionResource())
+	if err != nil {
+		return err
+	}
+
+	selectables := make(map[string][]metav1.ObjectMeta)
+	for _, sr := range crc.config.SelectorRelations {
+		if _, ok := selectables[sr.TargetKind]; ok {
+			continue
+		}
+		selectables[sr.TargetKind], err = selectableKinds[sr.TargetKind](crc)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range objects {
+		object := &objects[i]
+		component := crc.customResourceToStackStateComponent(object)
+		crc.SubmitComponent(component)
+
+		if object.GetNamespace() != "" {
+			crc.SubmitRelation(crc.CreateRelation(crc.buildNamespaceExternalID(object.GetNamespace()), component.ExternalID, "encloses"))
+		}
+
+		if crc.config.OwnerRelationType != "" {
+			for _, ownerRef := range object.GetOwnerReferences() {
+				ownerExternalID, err := crc.buildOwnerExternalID(ownerRef, object.GetNamespace())
+				if err != nil {
+					log.Debugf("Skipping owner of %s %s/%s: %v", crc.config.Kind, object.GetNamespace(), object.GetName(), err)
+					continue
+				}
+				crc.SubmitRelation(crc.CreateRelation(ownerExternalID, component.ExternalID, crc.config.OwnerRelationType))
+			}
+		}
+
+		for _, sr := range crc.config.SelectorRelations {
+			selector, err := selectorFromField(object, sr.SelectorPath)
+			if err != nil {
+				_ = log.Warnf("Invalid selector '%s' in %s %s/%s: %v", sr.SelectorPath, crc.config.Kind, object.GetNamespace(), object.GetName(), err)
+				continue
+			}
+			if selector == nil {
+				continue
+			}
+			for _, target := range selectables[sr.TargetKind] {
+				if target.Namespace != object.GetNamespace() || !selector.Matches(labels.Set(target.Labels)) {
+					continue
+				}
+				targetExternalID, err := crc.GetURNBuilder().BuildExternalID(sr.TargetKind, target.Namespace, target.Name)
+				if err != nil {
+					log.Debugf("Skipping selected %s %s/%s: %v", sr.TargetKind, target.Namespace, target.Name, err)
+					continue
+				}
+				crc.SubmitRelation(crc.CreateRelation(component.ExternalID, targetExternalID, sr.RelationType))
+			}
+		}
+	}
+
+	return nil
+}
+
+// customResourceToStackStateComponent Creates a StackState component from a custom resource object
+func (crc *CustomResourceCollector) customResourceToStackStateComponent(object *unstructured.Unstructured) *topology.Component {
+	log.Tracef("Mapping %s to StackState component: %s/%s", crc.config.Kind, object.GetNamespace(), object.GetName())
+
+	meta := metav1.ObjectMeta{Namespace: object.GetNamespace(), Labels: object.GetLabels()}
+	tags := crc.initTags(meta, metav1.TypeMeta{Kind: crc.config.Kind})
+
+	externalID := crc.GetURNBuilder().BuildComponentExternalID(crc.config.GetComponentType(), object.GetNamespace(), object.GetName())
+
+	identifiers := make([]string, 0)
+	for _, path := range crc.config.Identifiers {
+		identifiers = append(identifiers, stringsFromField(object, path)...)
+	}
+
+	component := &topology.Component{
+		ExternalID: externalID,
+		Type:       topology.Type{Name: crc.config.GetComponentType()},
+		Data: map[string]interface{}{
+			"name":        object.GetName(),
+			"tags":        tags,
+			"identifiers": identifiers,
+		},
+	}
+
+	if crc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if crc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(object)
+		} else {
+			sourceProperties = makeSourceProperties(object)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", object.GetKind())
+		component.Data.PutNonEmpty("uid", object.GetUID())
+		component.Data.PutNonEmpty("creationTimestamp", object.GetCreationTimestamp())
+		component.Data.PutNonEmpty("generateName", object.GetGenerateName())
+	}
+
+	// the configured data fields are added regardless of source properties, they are explicitly asked for
+	for key, path := range crc.config.Data {
+		value, found, err := unstructured.NestedFieldCopy(object.Object, fieldPath(path)...)
+		if err != nil || !found {
+			continue
+		}
+		component.Data.PutNonEmpty(key, value)
+	}
+
+	log.Tracef("Created StackState %s component %s: %v", crc.config.Kind, externalID, component.JSONString())
+
+	return component
+}
+
+// buildOwnerExternalID resolves an owner reference either to a built-in kind or to one of the collected custom resources
+func (crc *CustomResourceCollector) buildOwnerExternalID(ownerRef metav1.OwnerReference, namespace string) (string, error) {
+	if gv, err := schema.ParseGroupVersion(ownerRef.APIVersion); err == nil {
+		if componentType, ok := crc.componentTypesByGVK[gv.WithKind(ownerRef.Kind)]; ok {
+			return crc.GetURNBuilder().BuildComponentExternalID(componentType, namespace, ownerRef.Name), nil
+		}
+	}
+	return crc.GetURNBuilder().BuildExternalID(ownerRef.Kind, namespace, ownerRef.Name)
+}
+
+func fieldPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// stringsFromField returns the value of a string or string list field, or nothing if the field is not set
+func stringsFromField(object *unstructured.Unstructured, path string) []string {
+	if values, found, err := unstructured.NestedStringSlice(object.Object, fieldPath(path)...); err == nil && found {
+		return values
+	}
+	if value, found, err := unstructured.NestedString(object.Object, fieldPath(path)...); err == nil && found && value != "" {
+		return []string{value}
+	}
+	return nil
+}
+
+// selectorFromField reads either a LabelSelector (with matchLabels / matchExpressions) or a plain label map from the
+// object. It returns nil when the field is not set.
+func selectorFromField(object *unstructured.Unstructured, path string) (labels.Selector, error) {
+	field, found, err := unstructured.NestedMap(object.Object, fieldPath(path)...)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	_, hasMatchLabels := field["matchLabels"]
+	_, hasMatchExpressions := field["matchExpressions"]
+	if !hasMatchLabels && !hasMatchExpressions {
+		labelMap := make(map[string]string, len(field))
+		for k, v := range field {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("label '%s' is not a string", k)
+			}
+			labelMap[k] = s
+		}
+		return labels.SelectorFromSet(labelMap), nil
+	}
+
+	var labelSelector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, &labelSelector); err != nil {
+		return nil, err
+	}
+	return metav1.LabelSelectorAsSelector(&labelSelector)
+}
@@ -0,0 +1,217 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var rolloutConfig = CustomResourceConfig{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+	Kind:     "Rollout",
+	Data: map[string]string{
+		"replicas": "spec.replicas",
+		"phase":    "status.phase",
+		"missing":  "status.notThere",
+	},
+	Identifiers:       []string{"status.url", "status.aliases"},
+	OwnerRelationType: "controls",
+	SelectorRelations: []CustomResourceSelectorConfig{
+		{SelectorPath: "spec.selector", TargetKind: "Pod", RelationType: "controls"},
+	},
+}
+
+var analysisConfig = CustomResourceConfig{
+	Group:         "argoproj.io",
+	Version:       "v1alpha1",
+	Resource:      "analysistemplates",
+	Kind:          "AnalysisTemplate",
+	ComponentType: "argo-analysis-template",
+}
+
+func TestCustomResourceCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour).Truncate(time.Second)}
+	rolloutExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:rollout/test-rollout"
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockCustomResourceAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		crc := NewCustomResourceCollector(commonClusterCollector, rolloutConfig, []CustomResourceConfig{rolloutConfig, analysisConfig})
+		expectedCollectorName := "CustomResource Rollout Collector"
+		RunCollectorTest(t, crc, expectedCollectorName)
+
+		t.Run(testCaseName("Test Rollout", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			expectedData := topology.Data{
+				"name": "test-rollout",
+				"tags": map[string]string{
+					"test":           "label",
+					"cluster-name":   "test-cluster-name",
+					"cluster-type":   "kubernetes",
+					"component-type": "kubernetes-rollout",
+					"namespace":      "test-namespace",
+				},
+				"identifiers": []string{"https://rollout.example.com", "alias-1", "alias-2"},
+				"replicas":    int64(3),
+				"phase":       "Healthy",
+			}
+			if sourcePropertiesEnabled {
+				assert.Equal(t, rolloutExternalID, component.ExternalID)
+				assert.EqualValues(t, expectedData, component.Data)
+				assert.Equal(t, "Rollout", component.SourceProperties["kind"])
+				assert.Equal(t, "argoproj.io/v1alpha1", component.SourceProperties["apiVersion"])
+			} else {
+				expectedData["kind"] = "Rollout"
+				expectedData["uid"] = types.UID("test-rollout")
+				expectedData["creationTimestamp"] = creationTime
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: rolloutExternalID,
+					Type:       topology.Type{Name: "rollout"},
+					Data:       expectedData,
+				}, component)
+			}
+
+			expectedRelations := []*topology.Relation{
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->" + rolloutExternalID,
+					Type:       topology.Type{Name: "encloses"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+					TargetID:   rolloutExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:argo-analysis-template/test-analysis->" + rolloutExternalID,
+					Type:       topology.Type{Name: "controls"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:argo-analysis-template/test-analysis",
+					TargetID:   rolloutExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:deployment/test-deployment->" + rolloutExternalID,
+					Type:       topology.Type{Name: "controls"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:deployment/test-deployment",
+					TargetID:   rolloutExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: rolloutExternalID + "->urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+					Type:       topology.Type{Name: "controls"},
+					SourceID:   rolloutExternalID,
+					TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+					Data:       map[string]interface{}{},
+				},
+			}
+			for _, expectedRelation := range expectedRelations {
+				actualRelation := <-relationChannel
+				assert.EqualValues(t, expectedRelation, actualRelation)
+			}
+		})
+	}
+}
+
+func TestCustomResourceConfigValidate(t *testing.T) {
+	assert.NoError(t, rolloutConfig.Validate())
+	assert.NoError(t, analysisConfig.Validate())
+	assert.Equal(t, "argo-analysis-template", analysisConfig.GetComponentType())
+	assert.Equal(t, "rollout", rolloutConfig.GetComponentType())
+	assert.Equal(t, schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}, rolloutConfig.GroupVersionKind())
+
+	assert.Error(t, CustomResourceConfig{Group: "argoproj.io", Resource: "rollouts", Kind: "Rollout"}.Validate())
+	assert.Error(t, CustomResourceConfig{
+		Version:  "v1",
+		Resource: "rollouts",
+		Kind:     "Rollout",
+		SelectorRelations: []CustomResourceSelectorConfig{
+			{SelectorPath: "spec.selector", TargetKind: "ConfigMap", RelationType: "uses"},
+		},
+	}.Validate())
+}
+
+func TestSelectorFromField(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"labelSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "selected"},
+				"matchExpressions": []interface{}{
+					map[string]interface{}{"key": "tier", "operator": "In", "values": []interface{}{"web"}},
+				},
+			},
+			"plainSelector": map[string]interface{}{"app": "selected"},
+		},
+	}}
+
+	selector, err := selectorFromField(object, "spec.labelSelector")
+	assert.NoError(t, err)
+	assert.Equal(t, "app=selected,tier in (web)", selector.String())
+
+	selector, err = selectorFromField(object, "spec.plainSelector")
+	assert.NoError(t, err)
+	assert.Equal(t, "app=selected", selector.String())
+
+	selector, err = selectorFromField(object, "spec.notThere")
+	assert.NoError(t, err)
+	assert.Nil(t, selector)
+}
+
+type MockCustomResourceAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockCustomResourceAPICollectorClient) GetCustomResources(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	if gvr != rolloutConfig.GroupVersionResource() {
+		return []unstructured.Unstructured{}, nil
+	}
+
+	rollout := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "selected"},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase":   "Healthy",
+			"url":     "https://rollout.example.com",
+			"aliases": []interface{}{"alias-1", "alias-2"},
+		},
+	}}
+	rollout.SetName("test-rollout")
+	rollout.SetNamespace("test-namespace")
+	rollout.SetUID("test-rollout")
+	rollout.SetCreationTimestamp(creationTime)
+	rollout.SetLabels(map[string]string{"test": "label"})
+	rollout.SetOwnerReferences([]v1.OwnerReference{
+		{APIVersion: "argoproj.io/v1alpha1", Kind: "AnalysisTemplate", Name: "test-analysis"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "test-deployment"},
+		{APIVersion: "example.com/v1", Kind: "SomethingUnknown", Name: "test-unknown"},
+		// same kind as a collected custom resource, but from another group
+		{APIVersion: "flagger.app/v1beta1", Kind: "AnalysisTemplate", Name: "test-other-analysis"},
+	})
+
+	return []unstructured.Unstructured{rollout}, nil
+}
+
+func (m MockCustomResourceAPICollectorClient) GetPods() ([]coreV1.Pod, error) {
+	return selectorTestPods(), nil
+}
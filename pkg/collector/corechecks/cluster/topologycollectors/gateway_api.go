@@ -0,0 +1,138 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gatewayAPIGroup is the api group of the Kubernetes Gateway API custom resources
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+// gatewayAPIVersions are the Gateway API versions that are tried in order for GatewayClasses, Gateways and HTTPRoutes
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+// grpcRouteVersions are the Gateway API versions that are tried in order for GRPCRoutes, which were experimental
+// for longer than the other resources
+var grpcRouteVersions = []string{"v1", "v1alpha2"}
+
+// gatewayClassSpec is the part of a GatewayClass that is mapped to topology
+type gatewayClassSpec struct {
+	ControllerName string `json:"controllerName"`
+}
+
+// gatewaySpec is the part of a Gateway that is mapped to topology
+type gatewaySpec struct {
+	GatewayClassName string `json:"gatewayClassName"`
+}
+
+// gatewayStatus is the part of a Gateway status that is mapped to topology
+type gatewayStatus struct {
+	Addresses []struct {
+		Value string `json:"value"`
+	} `json:"addresses"`
+}
+
+// gatewayRouteReference is a reference from a route to a parent Gateway or to a backend
+type gatewayRouteReference struct {
+	Group     *string `json:"group"`
+	Kind      *string `json:"kind"`
+	Namespace *string `json:"namespace"`
+	Name      string  `json:"name"`
+}
+
+// gatewayRouteSpec is the part of a HTTPRoute or GRPCRoute that is mapped to topology
+type gatewayRouteSpec struct {
+	ParentRefs []gatewayRouteReference `json:"parentRefs"`
+	Hostnames  []string                `json:"hostnames"`
+	Rules      []struct {
+		BackendRefs []gatewayRouteReference `json:"backendRefs"`
+	} `json:"rules"`
+}
+
+// isKind returns whether the reference points to the given kind in the given group, using the defaults when the
+// reference leaves them out
+func (r gatewayRouteReference) isKind(group, kind, defaultGroup, defaultKind string) bool {
+	refGroup := defaultGroup
+	if r.Group != nil {
+		refGroup = *r.Group
+	}
+	refKind := defaultKind
+	if r.Kind != nil {
+		refKind = *r.Kind
+	}
+	return refGroup == group && refKind == kind
+}
+
+// namespaceOr returns the namespace of the reference, or the given namespace when the reference leaves it out
+func (r gatewayRouteReference) namespaceOr(namespace string) string {
+	if r.Namespace != nil && *r.Namespace != "" {
+		return *r.Namespace
+	}
+	return namespace
+}
+
+// listGatewayAPIResources lists a Gateway API resource, trying the given versions in order. Clusters without the
+// Gateway API custom resource definitions are not an error, nothing is returned for them.
+func listGatewayAPIResources(collector ClusterTopologyCollector, resource string, versions []string) ([]unstructured.Unstructured, error) {
+	for _, version := range versions {
+		gvr := schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: resource}
+		objects, err := collector.GetAPIClient().GetCustomResources(gvr)
+		if apierrors.IsNotFound(err) {
+			log.Debugf("Gateway API resource %s is not served by the cluster", gvr.String())
+			continue
+		}
+		return objects, err
+	}
+	return nil, nil
+}
+
+// gatewayAPIField converts a field of a Gateway API object to the given struct, a missing field leaves it empty
+func gatewayAPIField(object *unstructured.Unstructured, field string, into interface{}) error {
+	value, found, err := unstructured.NestedMap(object.Object, field)
+	if err != nil || !found {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(value, into)
+}
+
+// gatewayAPIToStackStateComponent Creates a StackState component from a Gateway API object
+func gatewayAPIToStackStateComponent(collector ClusterTopologyCollector, object *unstructured.Unstructured, kind, componentType, externalID string) *topology.Component {
+	log.Tracef("Mapping %s to StackState component: %s/%s", kind, object.GetNamespace(), object.GetName())
+
+	meta := metav1.ObjectMeta{Namespace: object.GetNamespace(), Labels: object.GetLabels()}
+	tags := collector.initTags(meta, metav1.TypeMeta{Kind: kind})
+
+	component := &topology.Component{
+		ExternalID: externalID,
+		Type:       topology.Type{Name: componentType},
+		Data: map[string]interface{}{
+			"name": object.GetName(),
+			"tags": tags,
+		},
+	}
+
+	if collector.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if collector.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(object)
+		} else {
+			sourceProperties = makeSourceProperties(object)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", object.GetKind())
+		component.Data.PutNonEmpty("uid", object.GetUID())
+		component.Data.PutNonEmpty("creationTimestamp", object.GetCreationTimestamp())
+		component.Data.PutNonEmpty("generateName", object.GetGenerateName())
+	}
+
+	return component
+}
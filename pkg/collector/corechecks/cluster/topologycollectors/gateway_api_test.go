@@ -0,0 +1,113 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestListGatewayAPIResources(t *testing.T) {
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	for _, tc := range []struct {
+		name          string
+		served        map[schema.GroupVersionResource][]unstructured.Unstructured
+		failure       error
+		expectedNames []string
+		expectedError bool
+	}{
+		{
+			name: "the first served version is used",
+			served: map[schema.GroupVersionResource][]unstructured.Unstructured{
+				gatewayAPIResource("v1", "gateways"):      {gatewayAPIObject("Gateway", "test-namespace", "gateway-v1", nil)},
+				gatewayAPIResource("v1beta1", "gateways"): {gatewayAPIObject("Gateway", "test-namespace", "gateway-v1beta1", nil)},
+			},
+			expectedNames: []string{"gateway-v1"},
+		},
+		{
+			name: "older versions are used when newer ones are not served",
+			served: map[schema.GroupVersionResource][]unstructured.Unstructured{
+				gatewayAPIResource("v1beta1", "gateways"): {gatewayAPIObject("Gateway", "test-namespace", "gateway-v1beta1", nil)},
+			},
+			expectedNames: []string{"gateway-v1beta1"},
+		},
+		{
+			name:          "clusters without the Gateway API are not an error",
+			served:        map[schema.GroupVersionResource][]unstructured.Unstructured{},
+			expectedNames: []string{},
+		},
+		{
+			name:          "other errors are returned",
+			failure:       fmt.Errorf("connection refused"),
+			expectedError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := MockGatewayAPICollectorClient{served: tc.served, failure: tc.failure}
+			commonClusterCollector := NewTestCommonClusterCollector(mock, componentChannel, relationChannel, false, false)
+
+			objects, err := listGatewayAPIResources(commonClusterCollector, "gateways", gatewayAPIVersions)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			names := make([]string, 0)
+			for _, o := range objects {
+				names = append(names, o.GetName())
+			}
+			assert.Equal(t, tc.expectedNames, names)
+		})
+	}
+}
+
+func gatewayAPIResource(version, resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: resource}
+}
+
+func gatewayAPIObject(kind, namespace, name string, fields map[string]interface{}) unstructured.Unstructured {
+	object := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatewayAPIGroup + "/v1",
+		"kind":       kind,
+	}}
+	for k, v := range fields {
+		object.Object[k] = v
+	}
+	object.SetName(name)
+	object.SetNamespace(namespace)
+	object.SetUID(types.UID(name))
+	object.SetCreationTimestamp(creationTime)
+	object.SetLabels(map[string]string{"test": "label"})
+	return object
+}
+
+// MockGatewayAPICollectorClient serves the configured Gateway API resources, all other resources are not found
+type MockGatewayAPICollectorClient struct {
+	served  map[schema.GroupVersionResource][]unstructured.Unstructured
+	failure error
+	apiserver.APICollectorClient
+}
+
+func (m MockGatewayAPICollectorClient) GetCustomResources(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	if m.failure != nil {
+		return nil, m.failure
+	}
+	objects, ok := m.served[gvr]
+	if !ok {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), "")
+	}
+	return objects, nil
+}
@@ -0,0 +1,57 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// GatewayClassCollector implements the ClusterTopologyCollector interface.
+type GatewayClassCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewGatewayClassCollector creates a new Gateway API GatewayClass collector
+func NewGatewayClassCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &GatewayClassCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*GatewayClassCollector) GetName() string {
+	return "GatewayClass Collector"
+}
+
+// CollectorFunction Collects and publishes GatewayClass components
+func (gcc *GatewayClassCollector) CollectorFunction() error {
+	gatewayClasses, err := listGatewayAPIResources(gcc, "gatewayclasses", gatewayAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	for i := range gatewayClasses {
+		gcc.SubmitComponent(gcc.gatewayClassToStackStateComponent(&gatewayClasses[i]))
+	}
+
+	return nil
+}
+
+// gatewayClassToStackStateComponent Creates a StackState GatewayClass component from a Kubernetes / OpenShift Cluster
+func (gcc *GatewayClassCollector) gatewayClassToStackStateComponent(gatewayClass *unstructured.Unstructured) *topology.Component {
+	gatewayClassExternalID := gcc.buildGatewayClassExternalID(gatewayClass.GetName())
+	component := gatewayAPIToStackStateComponent(gcc, gatewayClass, "GatewayClass", "gateway-class", gatewayClassExternalID)
+
+	var spec gatewayClassSpec
+	if err := gatewayAPIField(gatewayClass, "spec", &spec); err != nil {
+		_ = log.Warnf("Invalid spec for GatewayClass %s: %v", gatewayClass.GetName(), err)
+	}
+	component.Data.PutNonEmpty("controllerName", spec.ControllerName)
+
+	log.Tracef("Created StackState GatewayClass component %s: %v", gatewayClassExternalID, component.JSONString())
+
+	return component
+}
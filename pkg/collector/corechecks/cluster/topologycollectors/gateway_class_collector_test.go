@@ -0,0 +1,70 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGatewayClassCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour).Truncate(time.Second)}
+	gatewayClassExternalID := "urn:kubernetes:/test-cluster-name:gateway-class/test-gateway-class"
+	mock := MockGatewayAPICollectorClient{served: map[schema.GroupVersionResource][]unstructured.Unstructured{
+		gatewayAPIResource("v1beta1", "gatewayclasses"): {
+			gatewayAPIObject("GatewayClass", "", "test-gateway-class", map[string]interface{}{
+				"spec": map[string]interface{}{"controllerName": "example.com/gateway-controller"},
+			}),
+		},
+	}}
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(mock, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		gcc := NewGatewayClassCollector(commonClusterCollector)
+		expectedCollectorName := "GatewayClass Collector"
+		RunCollectorTest(t, gcc, expectedCollectorName)
+
+		t.Run(testCaseName("Test GatewayClass", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			expectedData := topology.Data{
+				"name": "test-gateway-class",
+				"tags": map[string]string{
+					"test":           "label",
+					"cluster-name":   "test-cluster-name",
+					"cluster-type":   "kubernetes",
+					"component-type": "kubernetes-gatewayclass",
+				},
+				"controllerName": "example.com/gateway-controller",
+			}
+			if sourcePropertiesEnabled {
+				assert.Equal(t, gatewayClassExternalID, component.ExternalID)
+				assert.EqualValues(t, expectedData, component.Data)
+				assert.Equal(t, "GatewayClass", component.SourceProperties["kind"])
+			} else {
+				expectedData["kind"] = "GatewayClass"
+				expectedData["uid"] = types.UID("test-gateway-class")
+				expectedData["creationTimestamp"] = creationTime
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: gatewayClassExternalID,
+					Type:       topology.Type{Name: "gateway-class"},
+					Data:       expectedData,
+				}, component)
+			}
+		})
+	}
+}
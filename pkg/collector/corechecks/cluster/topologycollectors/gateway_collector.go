@@ -0,0 +1,100 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// GatewayCollector implements the ClusterTopologyCollector interface.
+type GatewayCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewGatewayCollector creates a new Gateway API Gateway collector
+func NewGatewayCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &GatewayCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*GatewayCollector) GetName() string {
+	return "Gateway Collector"
+}
+
+// CollectorFunction Collects and publishes Gateway components and the GatewayClasses they use
+func (gc *GatewayCollector) CollectorFunction() error {
+	gateways, err := listGatewayAPIResources(gc, "gateways", gatewayAPIVersions)
+	if err != nil {
+		return err
+	}
+
+	for i := range gateways {
+		gateway := &gateways[i]
+
+		var spec gatewaySpec
+		if err := gatewayAPIField(gateway, "spec", &spec); err != nil {
+			_ = log.Warnf("Invalid spec for Gateway %s/%s: %v", gateway.GetNamespace(), gateway.GetName(), err)
+		}
+		var status gatewayStatus
+		if err := gatewayAPIField(gateway, "status", &status); err != nil {
+			_ = log.Warnf("Invalid status for Gateway %s/%s: %v", gateway.GetNamespace(), gateway.GetName(), err)
+		}
+
+		component := gc.gatewayToStackStateComponent(gateway, spec, status)
+		gc.SubmitComponent(component)
+
+		gc.SubmitRelation(gc.namespaceToGatewayStackStateRelation(gc.buildNamespaceExternalID(gateway.GetNamespace()), component.ExternalID))
+
+		if spec.GatewayClassName != "" {
+			gc.SubmitRelation(gc.gatewayToGatewayClassStackStateRelation(component.ExternalID, gc.buildGatewayClassExternalID(spec.GatewayClassName)))
+		}
+	}
+
+	return nil
+}
+
+// gatewayToStackStateComponent Creates a StackState Gateway component from a Kubernetes / OpenShift Cluster
+func (gc *GatewayCollector) gatewayToStackStateComponent(gateway *unstructured.Unstructured, spec gatewaySpec, status gatewayStatus) *topology.Component {
+	gatewayExternalID := gc.buildGatewayExternalID(gateway.GetNamespace(), gateway.GetName())
+	component := gatewayAPIToStackStateComponent(gc, gateway, "Gateway", "gateway", gatewayExternalID)
+
+	identifiers := make([]string, 0)
+	for _, address := range status.Addresses {
+		if address.Value != "" {
+			identifiers = append(identifiers, gc.buildEndpointExternalID(address.Value))
+		}
+	}
+	component.Data["identifiers"] = identifiers
+	component.Data.PutNonEmpty("gatewayClassName", spec.GatewayClassName)
+
+	log.Tracef("Created StackState Gateway component %s: %v", gatewayExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to Gateway relation
+func (gc *GatewayCollector) namespaceToGatewayStackStateRelation(namespaceExternalID, gatewayExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to gateway relation: %s -> %s", namespaceExternalID, gatewayExternalID)
+
+	relation := gc.CreateRelation(namespaceExternalID, gatewayExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> gateway relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Gateway to the GatewayClass it uses
+func (gc *GatewayCollector) gatewayToGatewayClassStackStateRelation(gatewayExternalID, gatewayClassExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes gateway to gateway class relation: %s -> %s", gatewayExternalID, gatewayClassExternalID)
+
+	relation := gc.CreateRelation(gatewayExternalID, gatewayClassExternalID, "uses")
+
+	log.Tracef("Created StackState gateway -> gateway class relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
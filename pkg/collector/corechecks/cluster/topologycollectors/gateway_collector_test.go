@@ -0,0 +1,102 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGatewayCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour).Truncate(time.Second)}
+	gatewayExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:gateway/test-gateway"
+	mock := MockGatewayAPICollectorClient{served: map[schema.GroupVersionResource][]unstructured.Unstructured{
+		gatewayAPIResource("v1", "gateways"): {
+			gatewayAPIObject("Gateway", "test-namespace", "test-gateway", map[string]interface{}{
+				"spec": map[string]interface{}{"gatewayClassName": "test-gateway-class"},
+				"status": map[string]interface{}{
+					"addresses": []interface{}{
+						map[string]interface{}{"type": "IPAddress", "value": "10.0.0.1"},
+						map[string]interface{}{"type": "Hostname", "value": "gateway.example.com"},
+					},
+				},
+			}),
+		},
+	}}
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(mock, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		gc := NewGatewayCollector(commonClusterCollector)
+		expectedCollectorName := "Gateway Collector"
+		RunCollectorTest(t, gc, expectedCollectorName)
+
+		t.Run(testCaseName("Test Gateway", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			expectedData := topology.Data{
+				"name": "test-gateway",
+				"tags": map[string]string{
+					"test":           "label",
+					"cluster-name":   "test-cluster-name",
+					"cluster-type":   "kubernetes",
+					"component-type": "kubernetes-gateway",
+					"namespace":      "test-namespace",
+				},
+				"identifiers": []string{
+					"urn:endpoint:/test-cluster-name:10.0.0.1",
+					"urn:endpoint:/test-cluster-name:gateway.example.com",
+				},
+				"gatewayClassName": "test-gateway-class",
+			}
+			if sourcePropertiesEnabled {
+				assert.Equal(t, gatewayExternalID, component.ExternalID)
+				assert.EqualValues(t, expectedData, component.Data)
+				assert.Equal(t, "Gateway", component.SourceProperties["kind"])
+			} else {
+				expectedData["kind"] = "Gateway"
+				expectedData["uid"] = types.UID("test-gateway")
+				expectedData["creationTimestamp"] = creationTime
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: gatewayExternalID,
+					Type:       topology.Type{Name: "gateway"},
+					Data:       expectedData,
+				}, component)
+			}
+
+			expectedRelations := []*topology.Relation{
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->" + gatewayExternalID,
+					Type:       topology.Type{Name: "encloses"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+					TargetID:   gatewayExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: gatewayExternalID + "->urn:kubernetes:/test-cluster-name:gateway-class/test-gateway-class",
+					Type:       topology.Type{Name: "uses"},
+					SourceID:   gatewayExternalID,
+					TargetID:   "urn:kubernetes:/test-cluster-name:gateway-class/test-gateway-class",
+					Data:       map[string]interface{}{},
+				},
+			}
+			for _, expectedRelation := range expectedRelations {
+				actualRelation := <-relationChannel
+				assert.EqualValues(t, expectedRelation, actualRelation)
+			}
+		})
+	}
+}
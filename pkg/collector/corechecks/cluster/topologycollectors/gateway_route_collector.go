@@ -0,0 +1,148 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"fmt"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// gatewayRouteKind describes one of the Gateway API route kinds that are collected
+type gatewayRouteKind struct {
+	kind          string
+	resource      string
+	componentType string
+	versions      []string
+}
+
+// httpRouteKind is the Gateway API HTTPRoute
+var httpRouteKind = gatewayRouteKind{kind: "HTTPRoute", resource: "httproutes", componentType: "http-route", versions: gatewayAPIVersions}
+
+// grpcRouteKind is the Gateway API GRPCRoute
+var grpcRouteKind = gatewayRouteKind{kind: "GRPCRoute", resource: "grpcroutes", componentType: "grpc-route", versions: grpcRouteVersions}
+
+// GatewayRouteCollector implements the ClusterTopologyCollector interface.
+type GatewayRouteCollector struct {
+	routeKind gatewayRouteKind
+	ClusterTopologyCollector
+}
+
+// NewHTTPRouteCollector creates a new Gateway API HTTPRoute collector
+func NewHTTPRouteCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &GatewayRouteCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+		routeKind:                httpRouteKind,
+	}
+}
+
+// NewGRPCRouteCollector creates a new Gateway API GRPCRoute collector
+func NewGRPCRouteCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &GatewayRouteCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+		routeKind:                grpcRouteKind,
+	}
+}
+
+// GetName returns the name of the Collector
+func (grc *GatewayRouteCollector) GetName() string {
+	return fmt.Sprintf("%s Collector", grc.routeKind.kind)
+}
+
+// CollectorFunction Collects and publishes route components, the Gateways they are attached to and the Services they
+// route to
+func (grc *GatewayRouteCollector) CollectorFunction() error {
+	routes, err := listGatewayAPIResources(grc, grc.routeKind.resource, grc.routeKind.versions)
+	if err != nil {
+		return err
+	}
+
+	for i := range routes {
+		route := &routes[i]
+
+		var spec gatewayRouteSpec
+		if err := gatewayAPIField(route, "spec", &spec); err != nil {
+			_ = log.Warnf("Invalid spec for %s %s/%s: %v", grc.routeKind.kind, route.GetNamespace(), route.GetName(), err)
+		}
+
+		component := grc.routeToStackStateComponent(route, spec)
+		grc.SubmitComponent(component)
+
+		grc.SubmitRelation(grc.namespaceToRouteStackStateRelation(grc.buildNamespaceExternalID(route.GetNamespace()), component.ExternalID))
+
+		for _, parentRef := range spec.ParentRefs {
+			if !parentRef.isKind(gatewayAPIGroup, "Gateway", gatewayAPIGroup, "Gateway") {
+				continue
+			}
+			gatewayExternalID := grc.buildGatewayExternalID(parentRef.namespaceOr(route.GetNamespace()), parentRef.Name)
+			grc.SubmitRelation(grc.gatewayToRouteStackStateRelation(gatewayExternalID, component.ExternalID))
+		}
+
+		for _, rule := range spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				if !backendRef.isKind("", "Service", "", "Service") {
+					continue
+				}
+				serviceExternalID := grc.buildServiceExternalID(backendRef.namespaceOr(route.GetNamespace()), backendRef.Name)
+				grc.SubmitRelation(grc.routeToServiceStackStateRelation(component.ExternalID, serviceExternalID))
+			}
+		}
+	}
+
+	return nil
+}
+
+// routeToStackStateComponent Creates a StackState route component from a Kubernetes / OpenShift Cluster
+func (grc *GatewayRouteCollector) routeToStackStateComponent(route *unstructured.Unstructured, spec gatewayRouteSpec) *topology.Component {
+	var routeExternalID string
+	if grc.routeKind.kind == grpcRouteKind.kind {
+		routeExternalID = grc.buildGRPCRouteExternalID(route.GetNamespace(), route.GetName())
+	} else {
+		routeExternalID = grc.buildHTTPRouteExternalID(route.GetNamespace(), route.GetName())
+	}
+	component := gatewayAPIToStackStateComponent(grc, route, grc.routeKind.kind, grc.routeKind.componentType, routeExternalID)
+
+	if len(spec.Hostnames) > 0 {
+		component.Data.PutNonEmpty("hostnames", spec.Hostnames)
+	}
+
+	log.Tracef("Created StackState %s component %s: %v", grc.routeKind.kind, routeExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to route relation
+func (grc *GatewayRouteCollector) namespaceToRouteStackStateRelation(namespaceExternalID, routeExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to %s relation: %s -> %s", grc.routeKind.kind, namespaceExternalID, routeExternalID)
+
+	relation := grc.CreateRelation(namespaceExternalID, routeExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> %s relation %s->%s", grc.routeKind.kind, relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Gateway to a route that is attached to it
+func (grc *GatewayRouteCollector) gatewayToRouteStackStateRelation(gatewayExternalID, routeExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes gateway to %s relation: %s -> %s", grc.routeKind.kind, gatewayExternalID, routeExternalID)
+
+	relation := grc.CreateRelation(gatewayExternalID, routeExternalID, "routes")
+
+	log.Tracef("Created StackState gateway -> %s relation %s->%s", grc.routeKind.kind, relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift route to a Service it routes to
+func (grc *GatewayRouteCollector) routeToServiceStackStateRelation(routeExternalID, serviceExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes %s to service relation: %s -> %s", grc.routeKind.kind, routeExternalID, serviceExternalID)
+
+	relation := grc.CreateRelation(routeExternalID, serviceExternalID, "routes_to")
+
+	log.Tracef("Created StackState %s -> service relation %s->%s", grc.routeKind.kind, relation.SourceID, relation.TargetID)
+
+	return relation
+}
@@ -0,0 +1,113 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGatewayRouteCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour).Truncate(time.Second)}
+	routeSpec := map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{"name": "test-gateway"},
+			map[string]interface{}{"name": "shared-gateway", "namespace": "infra"},
+			map[string]interface{}{"name": "test-service-mesh", "kind": "Service", "group": ""},
+		},
+		"hostnames": []interface{}{"app.example.com"},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": "test-service", "port": int64(8080)},
+					map[string]interface{}{"name": "other-service", "namespace": "other-namespace"},
+					map[string]interface{}{"name": "test-bucket", "kind": "Bucket", "group": "storage.example.com"},
+				},
+			},
+		},
+	}
+	mock := MockGatewayAPICollectorClient{served: map[schema.GroupVersionResource][]unstructured.Unstructured{
+		gatewayAPIResource("v1", "httproutes"): {
+			gatewayAPIObject("HTTPRoute", "test-namespace", "test-route", map[string]interface{}{"spec": routeSpec}),
+		},
+		gatewayAPIResource("v1alpha2", "grpcroutes"): {
+			gatewayAPIObject("GRPCRoute", "test-namespace", "test-route", map[string]interface{}{"spec": routeSpec}),
+		},
+	}}
+
+	for _, tc := range []struct {
+		kind          string
+		componentType string
+		collector     func(ClusterTopologyCollector) ClusterTopologyCollector
+	}{
+		{kind: "HTTPRoute", componentType: "http-route", collector: NewHTTPRouteCollector},
+		{kind: "GRPCRoute", componentType: "grpc-route", collector: NewGRPCRouteCollector},
+	} {
+		routeExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:" + tc.componentType + "/test-route"
+
+		for _, sourcePropertiesEnabled := range []bool{false, true} {
+			commonClusterCollector := NewTestCommonClusterCollector(mock, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+			commonClusterCollector.SetUseRelationCache(false)
+			grc := tc.collector(commonClusterCollector)
+			expectedCollectorName := tc.kind + " Collector"
+			RunCollectorTest(t, grc, expectedCollectorName)
+
+			t.Run(testCaseName("Test "+tc.kind, sourcePropertiesEnabled, false), func(t *testing.T) {
+				component := <-componentChannel
+				expectedData := topology.Data{
+					"name": "test-route",
+					"tags": map[string]string{
+						"test":           "label",
+						"cluster-name":   "test-cluster-name",
+						"cluster-type":   "kubernetes",
+						"component-type": "kubernetes-" + strings.ToLower(tc.kind),
+						"namespace":      "test-namespace",
+					},
+					"hostnames": []string{"app.example.com"},
+				}
+				if sourcePropertiesEnabled {
+					assert.Equal(t, routeExternalID, component.ExternalID)
+					assert.EqualValues(t, expectedData, component.Data)
+					assert.Equal(t, tc.kind, component.SourceProperties["kind"])
+				} else {
+					expectedData["kind"] = tc.kind
+					expectedData["uid"] = types.UID("test-route")
+					expectedData["creationTimestamp"] = creationTime
+					assert.EqualValues(t, &topology.Component{
+						ExternalID: routeExternalID,
+						Type:       topology.Type{Name: tc.componentType},
+						Data:       expectedData,
+					}, component)
+				}
+
+				// the parent service (mesh) and the non-service backend have no relation
+				expectedRelations := []*topology.Relation{
+					simpleRelation("urn:kubernetes:/test-cluster-name:namespace/test-namespace", routeExternalID, "encloses"),
+					simpleRelation("urn:kubernetes:/test-cluster-name:test-namespace:gateway/test-gateway", routeExternalID, "routes"),
+					simpleRelation("urn:kubernetes:/test-cluster-name:infra:gateway/shared-gateway", routeExternalID, "routes"),
+					simpleRelation(routeExternalID, "urn:kubernetes:/test-cluster-name:test-namespace:service/test-service", "routes_to"),
+					simpleRelation(routeExternalID, "urn:kubernetes:/test-cluster-name:other-namespace:service/other-service", "routes_to"),
+				}
+				for _, expectedRelation := range expectedRelations {
+					actualRelation := <-relationChannel
+					assert.EqualValues(t, expectedRelation, actualRelation)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,118 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HorizontalPodAutoscalerCollector implements the ClusterTopologyCollector interface.
+type HorizontalPodAutoscalerCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewHorizontalPodAutoscalerCollector creates a new HorizontalPodAutoscaler collector
+func NewHorizontalPodAutoscalerCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &HorizontalPodAutoscalerCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*HorizontalPodAutoscalerCollector) GetName() string {
+	return "HorizontalPodAutoscaler Collector"
+}
+
+// CollectorFunction Collects and publishes HorizontalPodAutoscaler components
+func (hc *HorizontalPodAutoscalerCollector) CollectorFunction() error {
+	hpas, err := hc.GetAPIClient().GetHorizontalPodAutoscalers()
+	if err != nil {
+		return err
+	}
+
+	for _, hpa := range hpas {
+		component := hc.horizontalPodAutoscalerToStackStateComponent(hpa)
+		hc.SubmitComponent(component)
+
+		hc.SubmitRelation(hc.namespaceToHorizontalPodAutoscalerStackStateRelation(hc.buildNamespaceExternalID(hpa.Namespace), component.ExternalID))
+
+		targetRef := hpa.Spec.ScaleTargetRef
+		targetExternalID, err := hc.GetURNBuilder().BuildExternalID(targetRef.Kind, hpa.Namespace, targetRef.Name)
+		if err != nil {
+			log.Debugf("Could not build the scale target of HorizontalPodAutoscaler %s/%s: %v", hpa.Namespace, hpa.Name, err)
+			continue
+		}
+		hc.SubmitRelation(hc.horizontalPodAutoscalerToTargetStackStateRelation(component.ExternalID, targetExternalID))
+	}
+
+	return nil
+}
+
+// horizontalPodAutoscalerToStackStateComponent Creates a StackState HorizontalPodAutoscaler component from a Kubernetes / OpenShift Cluster
+func (hc *HorizontalPodAutoscalerCollector) horizontalPodAutoscalerToStackStateComponent(hpa autoscalingV1.HorizontalPodAutoscaler) *topology.Component {
+	log.Tracef("Mapping HorizontalPodAutoscaler to StackState component: %s", hpa.String())
+
+	tags := hc.initTags(hpa.ObjectMeta, metav1.TypeMeta{Kind: "HorizontalPodAutoscaler"})
+
+	hpaExternalID := hc.buildHorizontalPodAutoscalerExternalID(hpa.Namespace, hpa.Name)
+	component := &topology.Component{
+		ExternalID: hpaExternalID,
+		Type:       topology.Type{Name: "horizontal-pod-autoscaler"},
+		Data: map[string]interface{}{
+			"name": hpa.Name,
+			"tags": tags,
+		},
+	}
+
+	if hc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if hc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&hpa)
+		} else {
+			sourceProperties = makeSourceProperties(&hpa)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", hpa.Kind)
+		component.Data.PutNonEmpty("uid", hpa.UID)
+		component.Data.PutNonEmpty("creationTimestamp", hpa.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", hpa.GenerateName)
+		if hpa.Spec.MinReplicas != nil {
+			component.Data.PutNonEmpty("minReplicas", *hpa.Spec.MinReplicas)
+		}
+		component.Data.PutNonEmpty("maxReplicas", hpa.Spec.MaxReplicas)
+		if hpa.Spec.TargetCPUUtilizationPercentage != nil {
+			component.Data.PutNonEmpty("targetCPUUtilizationPercentage", *hpa.Spec.TargetCPUUtilizationPercentage)
+		}
+	}
+
+	log.Tracef("Created StackState HorizontalPodAutoscaler component %s: %v", hpaExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to HorizontalPodAutoscaler relation
+func (hc *HorizontalPodAutoscalerCollector) namespaceToHorizontalPodAutoscalerStackStateRelation(namespaceExternalID, hpaExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to horizontal pod autoscaler relation: %s -> %s", namespaceExternalID, hpaExternalID)
+
+	relation := hc.CreateRelation(namespaceExternalID, hpaExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> horizontal pod autoscaler relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift HorizontalPodAutoscaler to the workload it scales
+func (hc *HorizontalPodAutoscalerCollector) horizontalPodAutoscalerToTargetStackStateRelation(hpaExternalID, targetExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes horizontal pod autoscaler to scale target relation: %s -> %s", hpaExternalID, targetExternalID)
+
+	relation := hc.CreateRelation(hpaExternalID, targetExternalID, "scales")
+
+	log.Tracef("Created StackState horizontal pod autoscaler -> scale target relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
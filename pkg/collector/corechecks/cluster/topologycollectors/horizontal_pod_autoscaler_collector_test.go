@@ -0,0 +1,168 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestHorizontalPodAutoscalerCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockHorizontalPodAutoscalerAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		hc := NewHorizontalPodAutoscalerCollector(commonClusterCollector)
+		expectedCollectorName := "HorizontalPodAutoscaler Collector"
+		RunCollectorTest(t, hc, expectedCollectorName)
+
+		for _, tc := range []struct {
+			testCase          string
+			expectedNoSP      *topology.Component
+			expectedRelations []*topology.Relation
+		}{
+			{
+				testCase: "Test HorizontalPodAutoscaler 1 - Deployment target",
+				expectedNoSP: &topology.Component{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-1",
+					Type:       topology.Type{Name: "horizontal-pod-autoscaler"},
+					Data: topology.Data{
+						"name":              "test-hpa-1",
+						"kind":              "HorizontalPodAutoscaler",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-horizontalpodautoscaler",
+							"namespace":      "test-namespace",
+						},
+						"uid":                            types.UID("test-hpa-1"),
+						"minReplicas":                    int32(1),
+						"maxReplicas":                    int32(5),
+						"targetCPUUtilizationPercentage": int32(80),
+					},
+				},
+				expectedRelations: []*topology.Relation{
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-1",
+						Type:       topology.Type{Name: "encloses"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-1",
+						Data:       map[string]interface{}{},
+					},
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-1->urn:kubernetes:/test-cluster-name:test-namespace:deployment/test-deployment-1",
+						Type:       topology.Type{Name: "scales"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-1",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:deployment/test-deployment-1",
+						Data:       map[string]interface{}{},
+					},
+				},
+			},
+			{
+				testCase: "Test HorizontalPodAutoscaler 2 - Unknown target kind",
+				expectedNoSP: &topology.Component{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-2",
+					Type:       topology.Type{Name: "horizontal-pod-autoscaler"},
+					Data: topology.Data{
+						"name":              "test-hpa-2",
+						"kind":              "HorizontalPodAutoscaler",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-horizontalpodautoscaler",
+							"namespace":      "test-namespace",
+						},
+						"uid":         types.UID("test-hpa-2"),
+						"maxReplicas": int32(5),
+					},
+				},
+				expectedRelations: []*topology.Relation{
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-2",
+						Type:       topology.Type{Name: "encloses"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:horizontal-pod-autoscaler/test-hpa-2",
+						Data:       map[string]interface{}{},
+					},
+				},
+			},
+		} {
+			t.Run(testCaseName(tc.testCase, sourcePropertiesEnabled, false), func(t *testing.T) {
+				component := <-componentChannel
+				if sourcePropertiesEnabled {
+					assert.Equal(t, tc.expectedNoSP.ExternalID, component.ExternalID)
+					assert.Equal(t, tc.expectedNoSP.Type, component.Type)
+					assert.Equal(t, "HorizontalPodAutoscaler", component.SourceProperties["kind"])
+				} else {
+					assert.EqualValues(t, tc.expectedNoSP, component)
+				}
+
+				for _, expectedRelation := range tc.expectedRelations {
+					actualRelation := <-relationChannel
+					assert.EqualValues(t, expectedRelation, actualRelation)
+				}
+			})
+		}
+	}
+}
+
+type MockHorizontalPodAutoscalerAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockHorizontalPodAutoscalerAPICollectorClient) GetHorizontalPodAutoscalers() ([]autoscalingV1.HorizontalPodAutoscaler, error) {
+	hpas := make([]autoscalingV1.HorizontalPodAutoscaler, 0)
+	for i := 1; i <= 2; i++ {
+		hpa := autoscalingV1.HorizontalPodAutoscaler{
+			TypeMeta: v1.TypeMeta{
+				Kind: "HorizontalPodAutoscaler",
+			},
+			ObjectMeta: v1.ObjectMeta{
+				Name:              fmt.Sprintf("test-hpa-%d", i),
+				CreationTimestamp: creationTime,
+				Namespace:         "test-namespace",
+				Labels: map[string]string{
+					"test": "label",
+				},
+				UID: types.UID(fmt.Sprintf("test-hpa-%d", i)),
+			},
+			Spec: autoscalingV1.HorizontalPodAutoscalerSpec{
+				MaxReplicas: 5,
+			},
+		}
+
+		if i == 1 {
+			minReplicas := int32(1)
+			targetCPU := int32(80)
+			hpa.Spec.MinReplicas = &minReplicas
+			hpa.Spec.TargetCPUUtilizationPercentage = &targetCPU
+			hpa.Spec.ScaleTargetRef = autoscalingV1.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment-1", APIVersion: "apps/v1"}
+		} else {
+			hpa.Spec.ScaleTargetRef = autoscalingV1.CrossVersionObjectReference{Kind: "Rollout", Name: "test-rollout-2", APIVersion: "argoproj.io/v1alpha1"}
+		}
+
+		hpas = append(hpas, hpa)
+	}
+
+	return hpas, nil
+}
@@ -0,0 +1,120 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	netV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NetworkPolicyCollector implements the ClusterTopologyCollector interface.
+type NetworkPolicyCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewNetworkPolicyCollector creates a new NetworkPolicy collector
+func NewNetworkPolicyCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &NetworkPolicyCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*NetworkPolicyCollector) GetName() string {
+	return "NetworkPolicy Collector"
+}
+
+// CollectorFunction Collects and publishes NetworkPolicy components and the pods they select
+func (nc *NetworkPolicyCollector) CollectorFunction() error {
+	networkPolicies, err := nc.GetAPIClient().GetNetworkPolicies()
+	if err != nil {
+		return err
+	}
+
+	pods, err := nc.GetAPIClient().GetPods()
+	if err != nil {
+		return err
+	}
+
+	for _, np := range networkPolicies {
+		component := nc.networkPolicyToStackStateComponent(np)
+		nc.SubmitComponent(component)
+
+		nc.SubmitRelation(nc.namespaceToNetworkPolicyStackStateRelation(nc.buildNamespaceExternalID(np.Namespace), component.ExternalID))
+
+		selectedPods, err := podsMatchingSelector(pods, np.Namespace, &np.Spec.PodSelector)
+		if err != nil {
+			_ = log.Warnf("Invalid pod selector for NetworkPolicy %s/%s: %v", np.Namespace, np.Name, err)
+			continue
+		}
+		for _, pod := range selectedPods {
+			nc.SubmitRelation(nc.networkPolicyToPodStackStateRelation(component.ExternalID, nc.buildPodExternalID(pod.Namespace, pod.Name)))
+		}
+	}
+
+	return nil
+}
+
+// networkPolicyToStackStateComponent Creates a StackState NetworkPolicy component from a Kubernetes / OpenShift Cluster
+func (nc *NetworkPolicyCollector) networkPolicyToStackStateComponent(networkPolicy netV1.NetworkPolicy) *topology.Component {
+	log.Tracef("Mapping NetworkPolicy to StackState component: %s", networkPolicy.String())
+
+	tags := nc.initTags(networkPolicy.ObjectMeta, metav1.TypeMeta{Kind: "NetworkPolicy"})
+
+	networkPolicyExternalID := nc.buildNetworkPolicyExternalID(networkPolicy.Namespace, networkPolicy.Name)
+	component := &topology.Component{
+		ExternalID: networkPolicyExternalID,
+		Type:       topology.Type{Name: "network-policy"},
+		Data: map[string]interface{}{
+			"name": networkPolicy.Name,
+			"tags": tags,
+		},
+	}
+
+	if nc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if nc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&networkPolicy)
+		} else {
+			sourceProperties = makeSourceProperties(&networkPolicy)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", networkPolicy.Kind)
+		component.Data.PutNonEmpty("uid", networkPolicy.UID)
+		component.Data.PutNonEmpty("creationTimestamp", networkPolicy.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", networkPolicy.GenerateName)
+		if len(networkPolicy.Spec.PolicyTypes) > 0 {
+			component.Data.PutNonEmpty("policyTypes", networkPolicy.Spec.PolicyTypes)
+		}
+	}
+
+	log.Tracef("Created StackState NetworkPolicy component %s: %v", networkPolicyExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to NetworkPolicy relation
+func (nc *NetworkPolicyCollector) namespaceToNetworkPolicyStackStateRelation(namespaceExternalID, networkPolicyExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to network policy relation: %s -> %s", namespaceExternalID, networkPolicyExternalID)
+
+	relation := nc.CreateRelation(namespaceExternalID, networkPolicyExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> network policy relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift NetworkPolicy to a Pod it selects
+func (nc *NetworkPolicyCollector) networkPolicyToPodStackStateRelation(networkPolicyExternalID, podExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes network policy to pod relation: %s -> %s", networkPolicyExternalID, podExternalID)
+
+	relation := nc.CreateRelation(networkPolicyExternalID, podExternalID, "selects")
+
+	log.Tracef("Created StackState network policy -> pod relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
@@ -0,0 +1,174 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	netV1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNetworkPolicyCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockNetworkPolicyAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		nc := NewNetworkPolicyCollector(commonClusterCollector)
+		expectedCollectorName := "NetworkPolicy Collector"
+		RunCollectorTest(t, nc, expectedCollectorName)
+
+		for _, tc := range []struct {
+			testCase          string
+			expectedNoSP      *topology.Component
+			expectedRelations []*topology.Relation
+		}{
+			{
+				testCase: "Test NetworkPolicy 1 - Label selector",
+				expectedNoSP: &topology.Component{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-1",
+					Type:       topology.Type{Name: "network-policy"},
+					Data: topology.Data{
+						"name":              "test-network-policy-1",
+						"kind":              "NetworkPolicy",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-networkpolicy",
+							"namespace":      "test-namespace",
+						},
+						"uid":         types.UID("test-network-policy-1"),
+						"policyTypes": []netV1.PolicyType{netV1.PolicyTypeIngress},
+					},
+				},
+				expectedRelations: []*topology.Relation{
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-1",
+						Type:       topology.Type{Name: "encloses"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-1",
+						Data:       map[string]interface{}{},
+					},
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-1->urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+						Type:       topology.Type{Name: "selects"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-1",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+						Data:       map[string]interface{}{},
+					},
+				},
+			},
+			{
+				testCase: "Test NetworkPolicy 2 - Empty selector selects all pods in the namespace",
+				expectedNoSP: &topology.Component{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2",
+					Type:       topology.Type{Name: "network-policy"},
+					Data: topology.Data{
+						"name":              "test-network-policy-2",
+						"kind":              "NetworkPolicy",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-networkpolicy",
+							"namespace":      "test-namespace",
+						},
+						"uid": types.UID("test-network-policy-2"),
+					},
+				},
+				expectedRelations: []*topology.Relation{
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2",
+						Type:       topology.Type{Name: "encloses"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2",
+						Data:       map[string]interface{}{},
+					},
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2->urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+						Type:       topology.Type{Name: "selects"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+						Data:       map[string]interface{}{},
+					},
+					{
+						ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2->urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-other",
+						Type:       topology.Type{Name: "selects"},
+						SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:network-policy/test-network-policy-2",
+						TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-other",
+						Data:       map[string]interface{}{},
+					},
+				},
+			},
+		} {
+			t.Run(testCaseName(tc.testCase, sourcePropertiesEnabled, false), func(t *testing.T) {
+				component := <-componentChannel
+				if sourcePropertiesEnabled {
+					assert.Equal(t, tc.expectedNoSP.ExternalID, component.ExternalID)
+					assert.Equal(t, "NetworkPolicy", component.SourceProperties["kind"])
+				} else {
+					assert.EqualValues(t, tc.expectedNoSP, component)
+				}
+
+				for _, expectedRelation := range tc.expectedRelations {
+					actualRelation := <-relationChannel
+					assert.EqualValues(t, expectedRelation, actualRelation)
+				}
+			})
+		}
+	}
+}
+
+type MockNetworkPolicyAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockNetworkPolicyAPICollectorClient) GetNetworkPolicies() ([]netV1.NetworkPolicy, error) {
+	return []netV1.NetworkPolicy{
+		{
+			TypeMeta:   v1.TypeMeta{Kind: "NetworkPolicy"},
+			ObjectMeta: networkPolicyObjectMeta("test-network-policy-1"),
+			Spec: netV1.NetworkPolicySpec{
+				PodSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "selected"}},
+				PolicyTypes: []netV1.PolicyType{netV1.PolicyTypeIngress},
+			},
+		},
+		{
+			TypeMeta:   v1.TypeMeta{Kind: "NetworkPolicy"},
+			ObjectMeta: networkPolicyObjectMeta("test-network-policy-2"),
+		},
+	}, nil
+}
+
+func (m MockNetworkPolicyAPICollectorClient) GetPods() ([]coreV1.Pod, error) {
+	return selectorTestPods(), nil
+}
+
+func networkPolicyObjectMeta(name string) v1.ObjectMeta {
+	return v1.ObjectMeta{
+		Name:              name,
+		CreationTimestamp: creationTime,
+		Namespace:         "test-namespace",
+		Labels: map[string]string{
+			"test": "label",
+		},
+		UID: types.UID(name),
+	}
+}
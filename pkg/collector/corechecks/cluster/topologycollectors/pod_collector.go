@@ -6,11 +6,19 @@ package topologycollectors
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	v1 "k8s.io/api/core/v1"
 )
 
+const (
+	// PodRestartsHealthSource is the health rule source of the total number of container restarts of a pod
+	PodRestartsHealthSource = "pod_restarts"
+	// PodPhaseHealthSource is the health rule source of the phase of a pod, i.e. "Running" or "Failed"
+	PodPhaseHealthSource = "pod_phase"
+)
+
 // PodCollector implements the ClusterTopologyCollector interface.
 type PodCollector struct {
 	ContainerCorrChan chan<- *ContainerCorrelation
@@ -66,6 +74,7 @@ func (pc *PodCollector) CollectorFunction() error {
 		// creates and publishes StackState pod component with relations
 		component = pc.podToStackStateComponent(pod)
 		pc.SubmitComponent(component)
+		pc.observePodHealth(pod, component.ExternalID)
 
 		// pod could not be scheduled for some reason
 		if pod.Spec.NodeName != "" {
@@ -169,6 +178,16 @@ func (pc *PodCollector) CollectorFunction() error {
 	return nil
 }
 
+// Records the restarts and the phase of a Kubernetes / OpenShift Pod to derive health states from
+func (pc *PodCollector) observePodHealth(pod v1.Pod, podExternalID string) {
+	var restarts int32
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+	pc.ObserveHealth(deriver.Observation{TopologyElementIdentifier: podExternalID, Source: PodRestartsHealthSource, Value: float64(restarts)})
+	pc.ObserveHealth(deriver.Observation{TopologyElementIdentifier: podExternalID, Source: PodPhaseHealthSource, State: string(pod.Status.Phase)})
+}
+
 // Creates a StackState component from a Kubernetes / OpenShift Pod
 func (pc *PodCollector) podToStackStateComponent(pod v1.Pod) *topology.Component {
 	// creates a StackState component for the kubernetes pod
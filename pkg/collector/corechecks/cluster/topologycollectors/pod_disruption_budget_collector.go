@@ -0,0 +1,155 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodDisruptionBudgetCollector implements the ClusterTopologyCollector interface.
+type PodDisruptionBudgetCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewPodDisruptionBudgetCollector creates a new PodDisruptionBudget collector
+func NewPodDisruptionBudgetCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &PodDisruptionBudgetCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*PodDisruptionBudgetCollector) GetName() string {
+	return "PodDisruptionBudget Collector"
+}
+
+// CollectorFunction Collects and publishes PodDisruptionBudget components and the pods they protect
+func (pc *PodDisruptionBudgetCollector) CollectorFunction() error {
+	var pdbs []PodDisruptionBudgetInterface
+	var err error
+	if supported := pc.minimumMinorVersion(21); supported {
+		pdbs, err = pc.getPodDisruptionBudgetsV1()
+	} else {
+		pdbs, err = pc.getPodDisruptionBudgetsV1B1()
+	}
+	if err != nil {
+		return err
+	}
+
+	pods, err := pc.GetAPIClient().GetPods()
+	if err != nil {
+		return err
+	}
+
+	for _, pdb := range pdbs {
+		meta := pdb.GetObjectMeta()
+		component := pc.podDisruptionBudgetToStackStateComponent(pdb)
+		pc.SubmitComponent(component)
+
+		pc.SubmitRelation(pc.namespaceToPodDisruptionBudgetStackStateRelation(pc.buildNamespaceExternalID(meta.Namespace), component.ExternalID))
+
+		protectedPods, err := podsMatchingSelector(pods, meta.Namespace, pdb.GetSelector())
+		if err != nil {
+			_ = log.Warnf("Invalid selector for PodDisruptionBudget %s/%s: %v", meta.Namespace, meta.Name, err)
+			continue
+		}
+		for _, pod := range protectedPods {
+			pc.SubmitRelation(pc.podDisruptionBudgetToPodStackStateRelation(component.ExternalID, pc.buildPodExternalID(pod.Namespace, pod.Name)))
+		}
+	}
+
+	return nil
+}
+
+func (pc *PodDisruptionBudgetCollector) getPodDisruptionBudgetsV1B1() ([]PodDisruptionBudgetInterface, error) {
+	pdbs, err := pc.GetAPIClient().GetPodDisruptionBudgetsV1B1()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PodDisruptionBudgetInterface, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		log.Debugf("Got PodDisruptionBudget '%s' from policy/v1beta1", pdb.Name)
+		result = append(result, PodDisruptionBudgetV1B1{o: pdb})
+	}
+	return result, nil
+}
+
+func (pc *PodDisruptionBudgetCollector) getPodDisruptionBudgetsV1() ([]PodDisruptionBudgetInterface, error) {
+	pdbs, err := pc.GetAPIClient().GetPodDisruptionBudgetsV1()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PodDisruptionBudgetInterface, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		log.Debugf("Got PodDisruptionBudget '%s' from policy/v1", pdb.Name)
+		result = append(result, PodDisruptionBudgetV1{o: pdb})
+	}
+	return result, nil
+}
+
+// podDisruptionBudgetToStackStateComponent Creates a StackState PodDisruptionBudget component from a Kubernetes / OpenShift Cluster
+func (pc *PodDisruptionBudgetCollector) podDisruptionBudgetToStackStateComponent(pdb PodDisruptionBudgetInterface) *topology.Component {
+	log.Tracef("Mapping PodDisruptionBudget to StackState component: %s", pdb.GetString())
+
+	meta := pdb.GetObjectMeta()
+	tags := pc.initTags(meta, metav1.TypeMeta{Kind: "PodDisruptionBudget"})
+
+	pdbExternalID := pc.buildPodDisruptionBudgetExternalID(meta.Namespace, meta.Name)
+	component := &topology.Component{
+		ExternalID: pdbExternalID,
+		Type:       topology.Type{Name: "pod-disruption-budget"},
+		Data: map[string]interface{}{
+			"name": meta.Name,
+			"tags": tags,
+		},
+	}
+
+	if pc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if pc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(pdb.GetKubernetesObject())
+		} else {
+			sourceProperties = makeSourceProperties(pdb.GetKubernetesObject())
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("uid", meta.UID)
+		component.Data.PutNonEmpty("creationTimestamp", meta.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", meta.GenerateName)
+		if minAvailable := pdb.GetMinAvailable(); minAvailable != nil {
+			component.Data.PutNonEmpty("minAvailable", minAvailable.String())
+		}
+		if maxUnavailable := pdb.GetMaxUnavailable(); maxUnavailable != nil {
+			component.Data.PutNonEmpty("maxUnavailable", maxUnavailable.String())
+		}
+	}
+
+	log.Tracef("Created StackState PodDisruptionBudget component %s: %v", pdbExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to PodDisruptionBudget relation
+func (pc *PodDisruptionBudgetCollector) namespaceToPodDisruptionBudgetStackStateRelation(namespaceExternalID, pdbExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to pod disruption budget relation: %s -> %s", namespaceExternalID, pdbExternalID)
+
+	relation := pc.CreateRelation(namespaceExternalID, pdbExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> pod disruption budget relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift PodDisruptionBudget to a Pod it protects
+func (pc *PodDisruptionBudgetCollector) podDisruptionBudgetToPodStackStateRelation(pdbExternalID, podExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes pod disruption budget to pod relation: %s -> %s", pdbExternalID, podExternalID)
+
+	relation := pc.CreateRelation(pdbExternalID, podExternalID, "protects")
+
+	log.Tracef("Created StackState pod disruption budget -> pod relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
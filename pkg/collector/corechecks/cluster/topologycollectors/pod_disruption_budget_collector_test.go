@@ -0,0 +1,150 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	"k8s.io/api/policy/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestPodDisruptionBudgetCollector_20(t *testing.T) {
+	testPodDisruptionBudgetsWithK8SVersion(t, version.Info{Major: "1", Minor: "20"}, "test-pdb-v1beta1")
+}
+
+func TestPodDisruptionBudgetCollector_21(t *testing.T) {
+	testPodDisruptionBudgetsWithK8SVersion(t, version.Info{Major: "1", Minor: "21"}, "test-pdb-v1")
+}
+
+func testPodDisruptionBudgetsWithK8SVersion(t *testing.T, k8sVersion version.Info, expectedName string) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+	pdbExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:pod-disruption-budget/" + expectedName
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollectorWithVersion(MockPodDisruptionBudgetAPICollectorClient{}, sourcePropertiesEnabled, componentChannel, relationChannel, &k8sVersion, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		pc := NewPodDisruptionBudgetCollector(commonClusterCollector)
+		expectedCollectorName := "PodDisruptionBudget Collector"
+		RunCollectorTest(t, pc, expectedCollectorName)
+
+		t.Run(testCaseName("Test PodDisruptionBudget "+expectedName, sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			if sourcePropertiesEnabled {
+				assert.Equal(t, pdbExternalID, component.ExternalID)
+				assert.Equal(t, "PodDisruptionBudget", component.SourceProperties["kind"])
+			} else {
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: pdbExternalID,
+					Type:       topology.Type{Name: "pod-disruption-budget"},
+					Data: topology.Data{
+						"name":              expectedName,
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-poddisruptionbudget",
+							"namespace":      "test-namespace",
+						},
+						"uid":          types.UID(expectedName),
+						"minAvailable": "50%",
+					},
+				}, component)
+			}
+
+			expectedRelations := []*topology.Relation{
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->" + pdbExternalID,
+					Type:       topology.Type{Name: "encloses"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+					TargetID:   pdbExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: pdbExternalID + "->urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+					Type:       topology.Type{Name: "protects"},
+					SourceID:   pdbExternalID,
+					TargetID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-selected",
+					Data:       map[string]interface{}{},
+				},
+			}
+			for _, expectedRelation := range expectedRelations {
+				actualRelation := <-relationChannel
+				assert.EqualValues(t, expectedRelation, actualRelation)
+			}
+		})
+	}
+}
+
+type MockPodDisruptionBudgetAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func pdbObjectMeta(name string) v1.ObjectMeta {
+	return v1.ObjectMeta{
+		Name:              name,
+		CreationTimestamp: creationTime,
+		Namespace:         "test-namespace",
+		Labels: map[string]string{
+			"test": "label",
+		},
+		UID: types.UID(name),
+	}
+}
+
+func (m MockPodDisruptionBudgetAPICollectorClient) GetPodDisruptionBudgetsV1B1() ([]v1beta1.PodDisruptionBudget, error) {
+	minAvailable := intstr.FromString("50%")
+	return []v1beta1.PodDisruptionBudget{
+		{
+			ObjectMeta: pdbObjectMeta("test-pdb-v1beta1"),
+			Spec: v1beta1.PodDisruptionBudgetSpec{
+				MinAvailable: &minAvailable,
+				Selector:     &v1.LabelSelector{MatchLabels: map[string]string{"app": "selected"}},
+			},
+		},
+	}, nil
+}
+
+func (m MockPodDisruptionBudgetAPICollectorClient) GetPodDisruptionBudgetsV1() ([]policyV1.PodDisruptionBudget, error) {
+	minAvailable := intstr.FromString("50%")
+	return []policyV1.PodDisruptionBudget{
+		{
+			ObjectMeta: pdbObjectMeta("test-pdb-v1"),
+			Spec: policyV1.PodDisruptionBudgetSpec{
+				MinAvailable: &minAvailable,
+				Selector:     &v1.LabelSelector{MatchLabels: map[string]string{"app": "selected"}},
+			},
+		},
+	}, nil
+}
+
+func (m MockPodDisruptionBudgetAPICollectorClient) GetPods() ([]coreV1.Pod, error) {
+	return selectorTestPods(), nil
+}
+
+// selectorTestPods returns a pod matching the "app=selected" label, one that doesn't and one with matching labels in
+// another namespace
+func selectorTestPods() []coreV1.Pod {
+	return []coreV1.Pod{
+		{ObjectMeta: v1.ObjectMeta{Name: "test-pod-selected", Namespace: "test-namespace", Labels: map[string]string{"app": "selected"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "test-pod-other", Namespace: "test-namespace", Labels: map[string]string{"app": "other"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "test-pod-selected", Namespace: "other-namespace", Labels: map[string]string{"app": "selected"}}},
+	}
+}
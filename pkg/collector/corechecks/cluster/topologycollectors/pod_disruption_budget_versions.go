@@ -0,0 +1,80 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	v1 "k8s.io/api/policy/v1"
+	"k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type PodDisruptionBudgetInterface interface {
+	GetKubernetesObject() MarshalableKubernetesObject
+	GetMaxUnavailable() *intstr.IntOrString
+	GetMinAvailable() *intstr.IntOrString
+	GetObjectMeta() metav1.ObjectMeta
+	GetSelector() *metav1.LabelSelector
+	GetString() string
+}
+
+type PodDisruptionBudgetV1B1 struct {
+	o v1beta1.PodDisruptionBudget
+}
+
+type PodDisruptionBudgetV1 struct {
+	o v1.PodDisruptionBudget
+}
+
+/* PodDisruptionBudgetV1B1 */
+
+func (in PodDisruptionBudgetV1B1) GetKubernetesObject() MarshalableKubernetesObject {
+	return &in.o
+}
+
+func (in PodDisruptionBudgetV1B1) GetMaxUnavailable() *intstr.IntOrString {
+	return in.o.Spec.MaxUnavailable
+}
+
+func (in PodDisruptionBudgetV1B1) GetMinAvailable() *intstr.IntOrString {
+	return in.o.Spec.MinAvailable
+}
+
+func (in PodDisruptionBudgetV1B1) GetObjectMeta() metav1.ObjectMeta {
+	return in.o.ObjectMeta
+}
+
+func (in PodDisruptionBudgetV1B1) GetSelector() *metav1.LabelSelector {
+	return in.o.Spec.Selector
+}
+
+func (in PodDisruptionBudgetV1B1) GetString() string {
+	return in.o.String()
+}
+
+/* PodDisruptionBudgetV1 */
+
+func (in PodDisruptionBudgetV1) GetKubernetesObject() MarshalableKubernetesObject {
+	return &in.o
+}
+
+func (in PodDisruptionBudgetV1) GetMaxUnavailable() *intstr.IntOrString {
+	return in.o.Spec.MaxUnavailable
+}
+
+func (in PodDisruptionBudgetV1) GetMinAvailable() *intstr.IntOrString {
+	return in.o.Spec.MinAvailable
+}
+
+func (in PodDisruptionBudgetV1) GetObjectMeta() metav1.ObjectMeta {
+	return in.o.ObjectMeta
+}
+
+func (in PodDisruptionBudgetV1) GetSelector() *metav1.LabelSelector {
+	return in.o.Spec.Selector
+}
+
+func (in PodDisruptionBudgetV1) GetString() string {
+	return in.o.String()
+}
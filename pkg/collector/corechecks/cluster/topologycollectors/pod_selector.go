@@ -0,0 +1,27 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podsMatchingSelector returns the pods in the namespace that are selected by the label selector. A nil selector selects
+// no pods, an empty selector selects all the pods in the namespace.
+func podsMatchingSelector(pods []v1.Pod, namespace string, labelSelector *metav1.LabelSelector) ([]v1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]v1.Pod, 0)
+	for _, pod := range pods {
+		if pod.Namespace == namespace && selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, pod)
+		}
+	}
+	return matching, nil
+}
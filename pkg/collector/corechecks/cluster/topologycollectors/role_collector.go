@@ -0,0 +1,145 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	rbacV1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RoleCollector implements the ClusterTopologyCollector interface.
+type RoleCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewRoleCollector creates a new Role collector
+func NewRoleCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &RoleCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*RoleCollector) GetName() string {
+	return "Role Collector"
+}
+
+// CollectorFunction Collects and publishes Role components and the RoleBindings of service accounts as relations
+func (rc *RoleCollector) CollectorFunction() error {
+	roles, err := rc.GetAPIClient().GetRoles()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		component := rc.roleToStackStateComponent(role)
+		rc.SubmitComponent(component)
+
+		rc.SubmitRelation(rc.namespaceToRoleStackStateRelation(rc.buildNamespaceExternalID(role.Namespace), component.ExternalID))
+	}
+
+	roleBindings, err := rc.GetAPIClient().GetRoleBindings()
+	if err != nil {
+		return err
+	}
+
+	for _, roleBinding := range roleBindings {
+		var roleExternalID string
+		switch roleBinding.RoleRef.Kind {
+		case "Role":
+			roleExternalID = rc.buildRoleExternalID(roleBinding.Namespace, roleBinding.RoleRef.Name)
+		case "ClusterRole":
+			roleExternalID = rc.buildClusterRoleExternalID(roleBinding.RoleRef.Name)
+		default:
+			log.Debugf("Skipping RoleBinding %s/%s with unsupported role reference kind '%s'", roleBinding.Namespace, roleBinding.Name, roleBinding.RoleRef.Kind)
+			continue
+		}
+
+		for _, serviceAccountExternalID := range serviceAccountSubjectExternalIDs(rc, roleBinding.Subjects, roleBinding.Namespace) {
+			rc.SubmitRelation(serviceAccountToRoleStackStateRelation(rc, serviceAccountExternalID, roleExternalID, roleBinding.Name))
+		}
+	}
+
+	return nil
+}
+
+// roleToStackStateComponent Creates a StackState Role component from a Kubernetes / OpenShift Cluster
+func (rc *RoleCollector) roleToStackStateComponent(role rbacV1.Role) *topology.Component {
+	log.Tracef("Mapping Role to StackState component: %s", role.String())
+
+	tags := rc.initTags(role.ObjectMeta, metav1.TypeMeta{Kind: "Role"})
+
+	roleExternalID := rc.buildRoleExternalID(role.Namespace, role.Name)
+	component := &topology.Component{
+		ExternalID: roleExternalID,
+		Type:       topology.Type{Name: "role"},
+		Data: map[string]interface{}{
+			"name": role.Name,
+			"tags": tags,
+		},
+	}
+
+	if rc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if rc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&role)
+		} else {
+			sourceProperties = makeSourceProperties(&role)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", role.Kind)
+		component.Data.PutNonEmpty("uid", role.UID)
+		component.Data.PutNonEmpty("creationTimestamp", role.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", role.GenerateName)
+	}
+
+	log.Tracef("Created StackState Role component %s: %v", roleExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to Role relation
+func (rc *RoleCollector) namespaceToRoleStackStateRelation(namespaceExternalID, roleExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to role relation: %s -> %s", namespaceExternalID, roleExternalID)
+
+	relation := rc.CreateRelation(namespaceExternalID, roleExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> role relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// serviceAccountSubjectExternalIDs returns the external ids of the service accounts among the subjects of a (Cluster)RoleBinding.
+// Users and groups are not represented in the topology and are skipped. A service account subject without a namespace
+// refers to the namespace of the binding.
+func serviceAccountSubjectExternalIDs(collector ClusterTopologyCollector, subjects []rbacV1.Subject, bindingNamespace string) []string {
+	externalIDs := make([]string, 0)
+	for _, subject := range subjects {
+		if subject.Kind != rbacV1.ServiceAccountKind {
+			continue
+		}
+		namespace := subject.Namespace
+		if namespace == "" {
+			namespace = bindingNamespace
+		}
+		externalIDs = append(externalIDs, collector.buildServiceAccountExternalID(namespace, subject.Name))
+	}
+	return externalIDs
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift ServiceAccount to the (Cluster)Role it is bound to
+func serviceAccountToRoleStackStateRelation(collector ClusterTopologyCollector, serviceAccountExternalID, roleExternalID, bindingName string) *topology.Relation {
+	log.Tracef("Mapping kubernetes service account to role relation: %s -> %s", serviceAccountExternalID, roleExternalID)
+
+	relation := collector.CreateRelationData(serviceAccountExternalID, roleExternalID, "bound_to", map[string]interface{}{
+		"binding": bindingName,
+	})
+
+	log.Tracef("Created StackState service account -> role relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
@@ -0,0 +1,142 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	rbacV1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRoleCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+	roleExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:role/test-role"
+	clusterRoleExternalID := "urn:kubernetes:/test-cluster-name:cluster-role/test-cluster-role"
+	serviceAccountExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:service-account/test-service-account"
+	otherServiceAccountExternalID := "urn:kubernetes:/test-cluster-name:other-namespace:service-account/test-service-account"
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockRoleAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		rc := NewRoleCollector(commonClusterCollector)
+		expectedCollectorName := "Role Collector"
+		RunCollectorTest(t, rc, expectedCollectorName)
+
+		t.Run(testCaseName("Test Role and RoleBindings", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			if sourcePropertiesEnabled {
+				assert.Equal(t, roleExternalID, component.ExternalID)
+				assert.Equal(t, "Role", component.SourceProperties["kind"])
+			} else {
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: roleExternalID,
+					Type:       topology.Type{Name: "role"},
+					Data: topology.Data{
+						"name":              "test-role",
+						"kind":              "Role",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-role",
+							"namespace":      "test-namespace",
+						},
+						"uid": types.UID("test-role"),
+					},
+				}, component)
+			}
+
+			expectedRelations := []*topology.Relation{
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->" + roleExternalID,
+					Type:       topology.Type{Name: "encloses"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+					TargetID:   roleExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: serviceAccountExternalID + "->" + roleExternalID,
+					Type:       topology.Type{Name: "bound_to"},
+					SourceID:   serviceAccountExternalID,
+					TargetID:   roleExternalID,
+					Data:       map[string]interface{}{"binding": "test-role-binding"},
+				},
+				{
+					ExternalID: otherServiceAccountExternalID + "->" + roleExternalID,
+					Type:       topology.Type{Name: "bound_to"},
+					SourceID:   otherServiceAccountExternalID,
+					TargetID:   roleExternalID,
+					Data:       map[string]interface{}{"binding": "test-role-binding"},
+				},
+				{
+					ExternalID: serviceAccountExternalID + "->" + clusterRoleExternalID,
+					Type:       topology.Type{Name: "bound_to"},
+					SourceID:   serviceAccountExternalID,
+					TargetID:   clusterRoleExternalID,
+					Data:       map[string]interface{}{"binding": "test-cluster-role-binding"},
+				},
+			}
+			for _, expectedRelation := range expectedRelations {
+				actualRelation := <-relationChannel
+				assert.EqualValues(t, expectedRelation, actualRelation)
+			}
+		})
+	}
+}
+
+type MockRoleAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockRoleAPICollectorClient) GetRoles() ([]rbacV1.Role, error) {
+	return []rbacV1.Role{
+		{
+			TypeMeta: v1.TypeMeta{Kind: "Role"},
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "test-role",
+				CreationTimestamp: creationTime,
+				Namespace:         "test-namespace",
+				Labels: map[string]string{
+					"test": "label",
+				},
+				UID: types.UID("test-role"),
+			},
+		},
+	}, nil
+}
+
+func (m MockRoleAPICollectorClient) GetRoleBindings() ([]rbacV1.RoleBinding, error) {
+	return []rbacV1.RoleBinding{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-role-binding", Namespace: "test-namespace"},
+			RoleRef:    rbacV1.RoleRef{Kind: "Role", Name: "test-role"},
+			Subjects: []rbacV1.Subject{
+				{Kind: rbacV1.ServiceAccountKind, Name: "test-service-account"},
+				{Kind: rbacV1.ServiceAccountKind, Name: "test-service-account", Namespace: "other-namespace"},
+				{Kind: rbacV1.UserKind, Name: "jane"},
+			},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-cluster-role-binding", Namespace: "test-namespace"},
+			RoleRef:    rbacV1.RoleRef{Kind: "ClusterRole", Name: "test-cluster-role"},
+			Subjects: []rbacV1.Subject{
+				{Kind: rbacV1.ServiceAccountKind, Name: "test-service-account"},
+				{Kind: rbacV1.GroupKind, Name: "admins"},
+			},
+		},
+	}, nil
+}
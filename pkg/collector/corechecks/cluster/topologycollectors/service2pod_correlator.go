@@ -27,6 +27,8 @@ type ServiceSelectorCorrelation struct {
 	Namespace         string
 	// Only labelMatchers, no labelExpressions for service selection
 	LabelSelector map[string]string
+	// EndpointPodNames are the pods in the namespace that back the service according to its EndpointSlices or Endpoints
+	EndpointPodNames []string
 }
 
 // NewService2PodCorrelator creates correlator creates relation from service to pod
@@ -51,9 +53,9 @@ func (Service2PodCorrelator) GetName() string {
 // podSelectorMatchesPodLabels asserts whether a podSelector matches the podLabels. A podSelector is matched
 // if all selector clauses are contained in the provided podLabels
 func podSelectorMatchesPodLabels(podSelector map[string]string, podLabels map[string]string) bool {
-	// For services without podSelector, we for now do no matches (see https://stackoverflow.com/a/61866213)
-	// We could model this based on endpoints, but that will breaks metric aggregation over services (which
-	// is done based on selectors for time travelling reasons). For now this is just something we do not support.
+	// For services without podSelector, we do no matches (see https://stackoverflow.com/a/61866213)
+	// Those services are related to pods based on their endpoints instead, selectors remain leading for services
+	// that have them because metric aggregation over services is done based on selectors for time travelling reasons.
 	if len(podSelector) == 0 {
 		return false
 	}
@@ -94,8 +96,15 @@ func (crl *Service2PodCorrelator) CorrelateFunction() error {
 		serviceID := svcCorr.ServiceExternalID
 
 		if namespacePods, found := pods[svcCorr.Namespace]; found {
+			endpointPods := make(map[string]bool, len(svcCorr.EndpointPodNames))
+			for _, podName := range svcCorr.EndpointPodNames {
+				endpointPods[podName] = true
+			}
+
 			for _, pod := range namespacePods {
-				if podSelectorMatchesPodLabels(svcCorr.LabelSelector, pod.Labels) {
+				// next to the selector, the pods in the endpoints of the service are exposed by it. This covers services
+				// without a selector whose endpoints are managed by another controller
+				if podSelectorMatchesPodLabels(svcCorr.LabelSelector, pod.Labels) || endpointPods[pod.PodName] {
 					podID := crl.buildPodExternalID(pod.PodNamespace, pod.PodName)
 					relation := crl.CreateRelation(serviceID, podID, "exposes")
 					log.Tracef("Correlated StackState service -> pod relation %s->%s", relation.SourceID, relation.TargetID)
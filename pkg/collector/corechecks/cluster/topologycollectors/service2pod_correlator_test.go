@@ -9,6 +9,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
 	"github.com/stretchr/testify/assert"
 	coreV1 "k8s.io/api/core/v1"
+	discoveryV1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"testing"
@@ -81,6 +82,85 @@ func TestService2PodCorrelator(t *testing.T) {
 	return
 }
 
+func TestService2PodCorrelatorWithEndpoints(t *testing.T) {
+	clusterName := "test-cluster-name"
+	namespace := "default"
+	someTimestamp := metav1.NewTime(time.Now())
+
+	pod1 := podWithLabels(namespace, "backend-1", someTimestamp, map[string]string{"app": "backend"})
+	pod2 := podWithLabels(namespace, "db-proxy-2", someTimestamp, map[string]string{"app": "db-proxy"})
+	pod3 := podWithLabels(namespace, "unrelated-3", someTimestamp, map[string]string{"app": "unrelated"})
+	backend := serviceWithSelector(namespace, "backend", someTimestamp, map[string]string{"app": "backend"})
+	// a service without selector, its endpoints are managed by another controller
+	database := serviceWithSelector(namespace, "database", someTimestamp, nil)
+
+	podRef := func(name string) *coreV1.ObjectReference {
+		return &coreV1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: name}
+	}
+	endpointSlices := []discoveryV1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "backend-abc", Labels: map[string]string{discoveryV1.LabelServiceName: "backend"}},
+			Endpoints:  []discoveryV1.Endpoint{{Addresses: []string{"10.0.0.1"}, TargetRef: podRef("backend-1")}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "database-abc", Labels: map[string]string{discoveryV1.LabelServiceName: "database"}},
+			Endpoints: []discoveryV1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}, TargetRef: podRef("db-proxy-2")},
+				{Addresses: []string{"10.0.0.3"}},
+			},
+		},
+	}
+	endpoints := []coreV1.Endpoints{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "database"},
+			Subsets: []coreV1.EndpointSubset{
+				{NotReadyAddresses: []coreV1.EndpointAddress{{IP: "10.0.0.2", TargetRef: podRef("db-proxy-2")}}},
+			},
+		},
+	}
+
+	expectedPod1ID := fmt.Sprintf("urn:kubernetes:/%s:%s:pod/%s", clusterName, namespace, "backend-1")
+	expectedPod2ID := fmt.Sprintf("urn:kubernetes:/%s:%s:pod/%s", clusterName, namespace, "db-proxy-2")
+	expectedBackendID := fmt.Sprintf("urn:kubernetes:/%s:%s:service/%s", clusterName, namespace, "backend")
+	expectedDatabaseID := fmt.Sprintf("urn:kubernetes:/%s:%s:service/%s", clusterName, namespace, "database")
+
+	for _, tc := range []struct {
+		name   string
+		client MockS2PCorrelatorAPIClient
+	}{
+		{
+			name: "EndpointSlices",
+			client: MockS2PCorrelatorAPIClient{
+				pods: []coreV1.Pod{pod1, pod2, pod3}, services: []coreV1.Service{backend, database},
+				endpointSlices: endpointSlices,
+			},
+		},
+		{
+			name: "Fallback to Endpoints",
+			client: MockS2PCorrelatorAPIClient{
+				pods: []coreV1.Pod{pod1, pod2, pod3}, services: []coreV1.Service{backend, database},
+				endpointSlicesErr: fmt.Errorf("endpointslices.discovery.k8s.io is forbidden"),
+				endpoints:         endpoints,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, relations := executeCorrelationWithClient(t, tc.client)
+
+			exposes := make([]*topology.Relation, 0)
+			for _, relation := range relations {
+				if relation.Type.Name == "exposes" {
+					exposes = append(exposes, relation)
+				}
+			}
+			assert.EqualValues(t, []*topology.Relation{
+				simpleRelation(expectedBackendID, expectedPod1ID, "exposes"),
+				simpleRelation(expectedDatabaseID, expectedPod2ID, "exposes"),
+			}, exposes)
+		})
+	}
+}
+
 func serviceComponentWithSelector(clusterName string, namespace string, externalID string, name string, timestamp metav1.Time) *topology.Component {
 	return &topology.Component{
 		ExternalID: externalID,
@@ -174,16 +254,21 @@ func executeCorrelation(
 	pods []coreV1.Pod,
 	services []coreV1.Service,
 ) ([]*topology.Component, []*topology.Relation) {
+	return executeCorrelationWithClient(t, MockS2PCorrelatorAPIClient{
+		services: services, pods: pods,
+	})
+}
+
+func executeCorrelationWithClient(
+	t *testing.T,
+	clusterAPIClient MockS2PCorrelatorAPIClient,
+) ([]*topology.Component, []*topology.Relation) {
 
 	componentChannel := make(chan *topology.Component)
 	defer close(componentChannel)
 	relationChannel := make(chan *topology.Relation)
 	defer close(relationChannel)
 
-	clusterAPIClient := MockS2PCorrelatorAPIClient{
-		services: services, pods: pods,
-	}
-
 	podCorrChannel := make(chan *PodLabelCorrelation)
 	serviceCorrChannel := make(chan *ServiceSelectorCorrelation)
 	containerCorrChannel := make(chan *ContainerCorrelation)
@@ -203,6 +288,7 @@ func executeCorrelation(
 	svcCollector := NewServiceCollector(
 		serviceCorrChannel,
 		commonClusterCollector,
+		true,
 	)
 
 	collectorsFinishChan := make(chan bool)
@@ -251,11 +337,22 @@ L:
 }
 
 type MockS2PCorrelatorAPIClient struct {
-	pods     []coreV1.Pod
-	services []coreV1.Service
+	pods              []coreV1.Pod
+	services          []coreV1.Service
+	endpointSlices    []discoveryV1.EndpointSlice
+	endpointSlicesErr error
+	endpoints         []coreV1.Endpoints
 	apiserver.APICollectorClient
 }
 
+func (m MockS2PCorrelatorAPIClient) GetEndpointSlices() ([]discoveryV1.EndpointSlice, error) {
+	return m.endpointSlices, m.endpointSlicesErr
+}
+
+func (m MockS2PCorrelatorAPIClient) GetEndpoints() ([]coreV1.Endpoints, error) {
+	return m.endpoints, nil
+}
+
 func (m MockS2PCorrelatorAPIClient) GetServices() ([]coreV1.Service, error) {
 	return m.services, nil
 }
@@ -0,0 +1,125 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultServiceAccountName is the service account a pod runs as when it does not specify one
+const defaultServiceAccountName = "default"
+
+// ServiceAccountCollector implements the ClusterTopologyCollector interface.
+type ServiceAccountCollector struct {
+	ClusterTopologyCollector
+}
+
+// NewServiceAccountCollector creates a new ServiceAccount collector
+func NewServiceAccountCollector(clusterTopologyCollector ClusterTopologyCollector) ClusterTopologyCollector {
+	return &ServiceAccountCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*ServiceAccountCollector) GetName() string {
+	return "ServiceAccount Collector"
+}
+
+// CollectorFunction Collects and publishes ServiceAccount components and the pods that run as them
+func (sac *ServiceAccountCollector) CollectorFunction() error {
+	serviceAccounts, err := sac.GetAPIClient().GetServiceAccounts()
+	if err != nil {
+		return err
+	}
+
+	for _, sa := range serviceAccounts {
+		component := sac.serviceAccountToStackStateComponent(sa)
+		sac.SubmitComponent(component)
+
+		sac.SubmitRelation(sac.namespaceToServiceAccountStackStateRelation(sac.buildNamespaceExternalID(sa.Namespace), component.ExternalID))
+	}
+
+	pods, err := sac.GetAPIClient().GetPods()
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		serviceAccountName := pod.Spec.ServiceAccountName
+		if serviceAccountName == "" {
+			serviceAccountName = defaultServiceAccountName
+		}
+		sac.SubmitRelation(sac.podToServiceAccountStackStateRelation(
+			sac.buildPodExternalID(pod.Namespace, pod.Name),
+			sac.buildServiceAccountExternalID(pod.Namespace, serviceAccountName),
+		))
+	}
+
+	return nil
+}
+
+// serviceAccountToStackStateComponent Creates a StackState ServiceAccount component from a Kubernetes / OpenShift Cluster
+func (sac *ServiceAccountCollector) serviceAccountToStackStateComponent(serviceAccount v1.ServiceAccount) *topology.Component {
+	log.Tracef("Mapping ServiceAccount to StackState component: %s", serviceAccount.String())
+
+	tags := sac.initTags(serviceAccount.ObjectMeta, metav1.TypeMeta{Kind: "ServiceAccount"})
+
+	serviceAccountExternalID := sac.buildServiceAccountExternalID(serviceAccount.Namespace, serviceAccount.Name)
+	component := &topology.Component{
+		ExternalID: serviceAccountExternalID,
+		Type:       topology.Type{Name: "service-account"},
+		Data: map[string]interface{}{
+			"name": serviceAccount.Name,
+			"tags": tags,
+		},
+	}
+
+	if sac.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if sac.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&serviceAccount)
+		} else {
+			sourceProperties = makeSourceProperties(&serviceAccount)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", serviceAccount.Kind)
+		component.Data.PutNonEmpty("uid", serviceAccount.UID)
+		component.Data.PutNonEmpty("creationTimestamp", serviceAccount.CreationTimestamp)
+		component.Data.PutNonEmpty("generateName", serviceAccount.GenerateName)
+		if serviceAccount.AutomountServiceAccountToken != nil {
+			component.Data.PutNonEmpty("automountServiceAccountToken", *serviceAccount.AutomountServiceAccountToken)
+		}
+	}
+
+	log.Tracef("Created StackState ServiceAccount component %s: %v", serviceAccountExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Namespace to ServiceAccount relation
+func (sac *ServiceAccountCollector) namespaceToServiceAccountStackStateRelation(namespaceExternalID, serviceAccountExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes namespace to service account relation: %s -> %s", namespaceExternalID, serviceAccountExternalID)
+
+	relation := sac.CreateRelation(namespaceExternalID, serviceAccountExternalID, "encloses")
+
+	log.Tracef("Created StackState namespace -> service account relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift Pod to the ServiceAccount it runs as
+func (sac *ServiceAccountCollector) podToServiceAccountStackStateRelation(podExternalID, serviceAccountExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes pod to service account relation: %s -> %s", podExternalID, serviceAccountExternalID)
+
+	relation := sac.CreateRelation(podExternalID, serviceAccountExternalID, "runs_as")
+
+	log.Tracef("Created StackState pod -> service account relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
@@ -0,0 +1,126 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestServiceAccountCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+	serviceAccountExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:service-account/test-service-account"
+	defaultServiceAccountExternalID := "urn:kubernetes:/test-cluster-name:test-namespace:service-account/default"
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockServiceAccountAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		sac := NewServiceAccountCollector(commonClusterCollector)
+		expectedCollectorName := "ServiceAccount Collector"
+		RunCollectorTest(t, sac, expectedCollectorName)
+
+		t.Run(testCaseName("Test ServiceAccount", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			if sourcePropertiesEnabled {
+				assert.Equal(t, serviceAccountExternalID, component.ExternalID)
+				assert.Equal(t, "ServiceAccount", component.SourceProperties["kind"])
+			} else {
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: serviceAccountExternalID,
+					Type:       topology.Type{Name: "service-account"},
+					Data: topology.Data{
+						"name":              "test-service-account",
+						"kind":              "ServiceAccount",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-serviceaccount",
+							"namespace":      "test-namespace",
+						},
+						"uid":                          types.UID("test-service-account"),
+						"automountServiceAccountToken": false,
+					},
+				}, component)
+			}
+
+			expectedRelations := []*topology.Relation{
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:namespace/test-namespace->" + serviceAccountExternalID,
+					Type:       topology.Type{Name: "encloses"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:namespace/test-namespace",
+					TargetID:   serviceAccountExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-1->" + serviceAccountExternalID,
+					Type:       topology.Type{Name: "runs_as"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-1",
+					TargetID:   serviceAccountExternalID,
+					Data:       map[string]interface{}{},
+				},
+				{
+					ExternalID: "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-2->" + defaultServiceAccountExternalID,
+					Type:       topology.Type{Name: "runs_as"},
+					SourceID:   "urn:kubernetes:/test-cluster-name:test-namespace:pod/test-pod-2",
+					TargetID:   defaultServiceAccountExternalID,
+					Data:       map[string]interface{}{},
+				},
+			}
+			for _, expectedRelation := range expectedRelations {
+				actualRelation := <-relationChannel
+				assert.EqualValues(t, expectedRelation, actualRelation)
+			}
+		})
+	}
+}
+
+type MockServiceAccountAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockServiceAccountAPICollectorClient) GetServiceAccounts() ([]coreV1.ServiceAccount, error) {
+	automount := false
+	return []coreV1.ServiceAccount{
+		{
+			TypeMeta: v1.TypeMeta{Kind: "ServiceAccount"},
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "test-service-account",
+				CreationTimestamp: creationTime,
+				Namespace:         "test-namespace",
+				Labels: map[string]string{
+					"test": "label",
+				},
+				UID: types.UID("test-service-account"),
+			},
+			AutomountServiceAccountToken: &automount,
+		},
+	}, nil
+}
+
+func (m MockServiceAccountAPICollectorClient) GetPods() ([]coreV1.Pod, error) {
+	return []coreV1.Pod{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-pod-1", Namespace: "test-namespace"},
+			Spec:       coreV1.PodSpec{ServiceAccountName: "test-service-account"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-pod-2", Namespace: "test-namespace"},
+		},
+	}, nil
+}
@@ -11,13 +11,15 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	v1 "k8s.io/api/core/v1"
+	discoveryV1 "k8s.io/api/discovery/v1"
 )
 
 // ServiceCollector implements the ClusterTopologyCollector interface.
 type ServiceCollector struct {
 	SelectorCorrChan chan<- *ServiceSelectorCorrelation
 	ClusterTopologyCollector
-	DNS dns.Resolver
+	DNS              dns.Resolver
+	endpointsEnabled bool
 }
 
 // EndpointID contains the definition of a cluster ip
@@ -26,15 +28,18 @@ type EndpointID struct {
 	RefExternalID string
 }
 
-// NewServiceCollector
+// NewServiceCollector creates a new Service collector. When endpoints are enabled the pods backing a service according to
+// its EndpointSlices (or Endpoints when EndpointSlices are not available) are correlated next to the pods matching its selector.
 func NewServiceCollector(
 	serviceCorrChannel chan *ServiceSelectorCorrelation,
 	clusterTopologyCollector ClusterTopologyCollector,
+	endpointsEnabled bool,
 ) ClusterTopologyCollector {
 	return &ServiceCollector{
 		SelectorCorrChan:         serviceCorrChannel,
 		ClusterTopologyCollector: clusterTopologyCollector,
 		DNS:                      dns.StandardResolver,
+		endpointsEnabled:         endpointsEnabled,
 	}
 }
 
@@ -55,6 +60,7 @@ func (sc *ServiceCollector) CollectorFunction() error {
 	}
 
 	serviceMap := make(map[string][]string)
+	endpointPods := sc.endpointPodsByService()
 
 	for _, service := range services {
 		// creates and publishes StackState service component with relations
@@ -78,6 +84,7 @@ func (sc *ServiceCollector) CollectorFunction() error {
 			ServiceExternalID: component.ExternalID,
 			Namespace:         service.Namespace,
 			LabelSelector:     service.Spec.Selector,
+			EndpointPodNames:  endpointPods[serviceID],
 		}
 
 		serviceMap[serviceID] = append(serviceMap[serviceID], component.ExternalID)
@@ -86,6 +93,63 @@ func (sc *ServiceCollector) CollectorFunction() error {
 	return nil
 }
 
+// endpointPodsByService returns the names of the pods backing each service by service id. EndpointSlices are used when
+// the cluster supports them, with a fallback to the legacy Endpoints. Nil is returned when neither can be read.
+func (sc *ServiceCollector) endpointPodsByService() map[string][]string {
+	if !sc.endpointsEnabled {
+		return nil
+	}
+
+	if sc.minimumMinorVersion(21) {
+		endpointSlices, err := sc.GetAPIClient().GetEndpointSlices()
+		if err == nil {
+			return endpointSlicePodsByService(endpointSlices)
+		}
+		_ = log.Warnf("Could not collect EndpointSlices, falling back to Endpoints: %v", err)
+	}
+
+	endpoints, err := sc.GetAPIClient().GetEndpoints()
+	if err != nil {
+		_ = log.Warnf("Could not collect Endpoints, services are only related to pods matching their selector: %v", err)
+		return nil
+	}
+	return endpointsPodsByService(endpoints)
+}
+
+func endpointSlicePodsByService(endpointSlices []discoveryV1.EndpointSlice) map[string][]string {
+	pods := make(map[string][]string)
+	for _, slice := range endpointSlices {
+		serviceName, ok := slice.Labels[discoveryV1.LabelServiceName]
+		if !ok {
+			continue
+		}
+		serviceID := buildServiceID(slice.Namespace, serviceName)
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				pods[serviceID] = append(pods[serviceID], endpoint.TargetRef.Name)
+			}
+		}
+	}
+	return pods
+}
+
+func endpointsPodsByService(endpoints []v1.Endpoints) map[string][]string {
+	pods := make(map[string][]string)
+	for _, ep := range endpoints {
+		serviceID := buildServiceID(ep.Namespace, ep.Name)
+		for _, subset := range ep.Subsets {
+			for _, addresses := range [][]v1.EndpointAddress{subset.Addresses, subset.NotReadyAddresses} {
+				for _, address := range addresses {
+					if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+						pods[serviceID] = append(pods[serviceID], address.TargetRef.Name)
+					}
+				}
+			}
+		}
+	}
+	return pods
+}
+
 // Creates a StackState component from a Kubernetes / OpenShift Service
 func (sc *ServiceCollector) serviceToStackStateComponent(service v1.Service) *topology.Component {
 	log.Tracef("Mapping kubernetes pod service to StackState component: %s", service.String())
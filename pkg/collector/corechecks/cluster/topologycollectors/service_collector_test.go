@@ -42,6 +42,7 @@ func TestServiceCollector(t *testing.T) {
 					serviceCollector := NewServiceCollector(
 						svcCorrelationChannel,
 						commonCollector,
+						false,
 					)
 					// Mock out DNS resolution function for test
 					serviceCollector.(*ServiceCollector).DNS = func(name string) ([]string, error) {
@@ -0,0 +1,117 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	storageV1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageClassCollector implements the ClusterTopologyCollector interface.
+type StorageClassCollector struct {
+	persistentVolumesEnabled bool
+	ClusterTopologyCollector
+}
+
+// NewStorageClassCollector creates a new StorageClass collector. The relations from persistent volumes to their
+// storage class are only produced when persistent volumes are collected as well.
+func NewStorageClassCollector(clusterTopologyCollector ClusterTopologyCollector, persistentVolumesEnabled bool) ClusterTopologyCollector {
+	return &StorageClassCollector{
+		ClusterTopologyCollector: clusterTopologyCollector,
+		persistentVolumesEnabled: persistentVolumesEnabled,
+	}
+}
+
+// GetName returns the name of the Collector
+func (*StorageClassCollector) GetName() string {
+	return "StorageClass Collector"
+}
+
+// CollectorFunction Collects and publishes StorageClass components and the persistent volumes provisioned by them
+func (scc *StorageClassCollector) CollectorFunction() error {
+	storageClasses, err := scc.GetAPIClient().GetStorageClasses()
+	if err != nil {
+		return err
+	}
+
+	for _, storageClass := range storageClasses {
+		scc.SubmitComponent(scc.storageClassToStackStateComponent(storageClass))
+	}
+
+	if !scc.persistentVolumesEnabled {
+		return nil
+	}
+
+	persistentVolumes, err := scc.GetAPIClient().GetPersistentVolumes()
+	if err != nil {
+		return err
+	}
+
+	for _, pv := range persistentVolumes {
+		if pv.Spec.StorageClassName == "" {
+			continue
+		}
+		scc.SubmitRelation(scc.persistentVolumeToStorageClassStackStateRelation(
+			scc.buildPersistentVolumeExternalID(pv.Name),
+			scc.buildStorageClassExternalID(pv.Spec.StorageClassName),
+		))
+	}
+
+	return nil
+}
+
+// storageClassToStackStateComponent Creates a StackState StorageClass component from a Kubernetes / OpenShift Cluster
+func (scc *StorageClassCollector) storageClassToStackStateComponent(storageClass storageV1.StorageClass) *topology.Component {
+	log.Tracef("Mapping StorageClass to StackState component: %s", storageClass.String())
+
+	tags := scc.initTags(storageClass.ObjectMeta, metav1.TypeMeta{Kind: "StorageClass"})
+
+	storageClassExternalID := scc.buildStorageClassExternalID(storageClass.Name)
+	component := &topology.Component{
+		ExternalID: storageClassExternalID,
+		Type:       topology.Type{Name: "storage-class"},
+		Data: map[string]interface{}{
+			"name": storageClass.Name,
+			"tags": tags,
+		},
+	}
+
+	if scc.IsSourcePropertiesFeatureEnabled() {
+		var sourceProperties map[string]interface{}
+		if scc.IsExposeKubernetesStatusEnabled() {
+			sourceProperties = makeSourcePropertiesFullDetails(&storageClass)
+		} else {
+			sourceProperties = makeSourceProperties(&storageClass)
+		}
+		component.SourceProperties = sourceProperties
+	} else {
+		component.Data.PutNonEmpty("kind", storageClass.Kind)
+		component.Data.PutNonEmpty("uid", storageClass.UID)
+		component.Data.PutNonEmpty("creationTimestamp", storageClass.CreationTimestamp)
+		component.Data.PutNonEmpty("provisioner", storageClass.Provisioner)
+		if storageClass.ReclaimPolicy != nil {
+			component.Data.PutNonEmpty("reclaimPolicy", *storageClass.ReclaimPolicy)
+		}
+		if storageClass.VolumeBindingMode != nil {
+			component.Data.PutNonEmpty("volumeBindingMode", *storageClass.VolumeBindingMode)
+		}
+	}
+
+	log.Tracef("Created StackState StorageClass component %s: %v", storageClassExternalID, component.JSONString())
+
+	return component
+}
+
+// Creates a StackState relation from a Kubernetes / OpenShift PersistentVolume to the StorageClass that provisioned it
+func (scc *StorageClassCollector) persistentVolumeToStorageClassStackStateRelation(persistentVolumeExternalID, storageClassExternalID string) *topology.Relation {
+	log.Tracef("Mapping kubernetes persistent volume to storage class relation: %s -> %s", persistentVolumeExternalID, storageClassExternalID)
+
+	relation := scc.CreateRelation(persistentVolumeExternalID, storageClassExternalID, "provisioned_by")
+
+	log.Tracef("Created StackState persistent volume -> storage class relation %s->%s", relation.SourceID, relation.TargetID)
+
+	return relation
+}
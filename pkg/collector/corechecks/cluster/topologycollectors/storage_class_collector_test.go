@@ -0,0 +1,111 @@
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package topologycollectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/apiserver"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStorageClassCollector(t *testing.T) {
+
+	componentChannel := make(chan *topology.Component)
+	defer close(componentChannel)
+	relationChannel := make(chan *topology.Relation)
+	defer close(relationChannel)
+
+	creationTime = v1.Time{Time: time.Now().Add(-1 * time.Hour)}
+	storageClassExternalID := "urn:kubernetes:/test-cluster-name:storage-class/test-storage-class"
+
+	for _, sourcePropertiesEnabled := range []bool{false, true} {
+		commonClusterCollector := NewTestCommonClusterCollector(MockStorageClassAPICollectorClient{}, componentChannel, relationChannel, sourcePropertiesEnabled, false)
+		commonClusterCollector.SetUseRelationCache(false)
+		scc := NewStorageClassCollector(commonClusterCollector, true)
+		expectedCollectorName := "StorageClass Collector"
+		RunCollectorTest(t, scc, expectedCollectorName)
+
+		t.Run(testCaseName("Test StorageClass", sourcePropertiesEnabled, false), func(t *testing.T) {
+			component := <-componentChannel
+			if sourcePropertiesEnabled {
+				assert.Equal(t, storageClassExternalID, component.ExternalID)
+				assert.Equal(t, "StorageClass", component.SourceProperties["kind"])
+			} else {
+				assert.EqualValues(t, &topology.Component{
+					ExternalID: storageClassExternalID,
+					Type:       topology.Type{Name: "storage-class"},
+					Data: topology.Data{
+						"name":              "test-storage-class",
+						"kind":              "StorageClass",
+						"creationTimestamp": creationTime,
+						"tags": map[string]string{
+							"test":           "label",
+							"cluster-name":   "test-cluster-name",
+							"cluster-type":   "kubernetes",
+							"component-type": "kubernetes-storageclass",
+						},
+						"uid":               types.UID("test-storage-class"),
+						"provisioner":       "kubernetes.io/aws-ebs",
+						"reclaimPolicy":     coreV1.PersistentVolumeReclaimDelete,
+						"volumeBindingMode": storageV1.VolumeBindingWaitForFirstConsumer,
+					},
+				}, component)
+			}
+
+			// the persistent volume without a storage class has no relation
+			actualRelation := <-relationChannel
+			assert.EqualValues(t, &topology.Relation{
+				ExternalID: "urn:kubernetes:/test-cluster-name:persistent-volume/test-persistent-volume-1->" + storageClassExternalID,
+				Type:       topology.Type{Name: "provisioned_by"},
+				SourceID:   "urn:kubernetes:/test-cluster-name:persistent-volume/test-persistent-volume-1",
+				TargetID:   storageClassExternalID,
+				Data:       map[string]interface{}{},
+			}, actualRelation)
+		})
+	}
+}
+
+type MockStorageClassAPICollectorClient struct {
+	apiserver.APICollectorClient
+}
+
+func (m MockStorageClassAPICollectorClient) GetStorageClasses() ([]storageV1.StorageClass, error) {
+	reclaimPolicy := coreV1.PersistentVolumeReclaimDelete
+	bindingMode := storageV1.VolumeBindingWaitForFirstConsumer
+	return []storageV1.StorageClass{
+		{
+			TypeMeta: v1.TypeMeta{Kind: "StorageClass"},
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "test-storage-class",
+				CreationTimestamp: creationTime,
+				Labels: map[string]string{
+					"test": "label",
+				},
+				UID: types.UID("test-storage-class"),
+			},
+			Provisioner:       "kubernetes.io/aws-ebs",
+			ReclaimPolicy:     &reclaimPolicy,
+			VolumeBindingMode: &bindingMode,
+		},
+	}, nil
+}
+
+func (m MockStorageClassAPICollectorClient) GetPersistentVolumes() ([]coreV1.PersistentVolume, error) {
+	return []coreV1.PersistentVolume{
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-persistent-volume-1"},
+			Spec:       coreV1.PersistentVolumeSpec{StorageClassName: "test-storage-class"},
+		},
+		{
+			ObjectMeta: v1.ObjectMeta{Name: "test-persistent-volume-2"},
+		},
+	}, nil
+}
@@ -41,6 +41,17 @@ type Builder interface {
 	BuildExternalVolumeExternalID(volumeType string, volumeComponents ...string) string
 	BuildPersistentVolumeExternalID(persistentVolumeName string) string
 	BuildPersistentVolumeClaimExternalID(namespace, persistentVolumeName string) string
+	BuildHorizontalPodAutoscalerExternalID(namespace, horizontalPodAutoscalerName string) string
+	BuildPodDisruptionBudgetExternalID(namespace, podDisruptionBudgetName string) string
+	BuildNetworkPolicyExternalID(namespace, networkPolicyName string) string
+	BuildServiceAccountExternalID(namespace, serviceAccountName string) string
+	BuildRoleExternalID(namespace, roleName string) string
+	BuildClusterRoleExternalID(clusterRoleName string) string
+	BuildStorageClassExternalID(storageClassName string) string
+	BuildGatewayClassExternalID(gatewayClassName string) string
+	BuildGatewayExternalID(namespace, gatewayName string) string
+	BuildHTTPRouteExternalID(namespace, httpRouteName string) string
+	BuildGRPCRouteExternalID(namespace, grpcRouteName string) string
 	BuildComponentExternalID(component, namespace, name string) string
 	BuildEndpointExternalID(endpointID string) string
 	BuildNodeURNs(node v1.Node) []string
@@ -105,6 +116,28 @@ func (b *urnBuilder) BuildExternalID(kind, namespace, objName string) (string, e
 		urn = b.BuildPersistentVolumeClaimExternalID(namespace, objName)
 	case "Endpoint":
 		urn = b.BuildEndpointExternalID(objName)
+	case "HorizontalPodAutoscaler":
+		urn = b.BuildHorizontalPodAutoscalerExternalID(namespace, objName)
+	case "PodDisruptionBudget":
+		urn = b.BuildPodDisruptionBudgetExternalID(namespace, objName)
+	case "NetworkPolicy":
+		urn = b.BuildNetworkPolicyExternalID(namespace, objName)
+	case "ServiceAccount":
+		urn = b.BuildServiceAccountExternalID(namespace, objName)
+	case "Role":
+		urn = b.BuildRoleExternalID(namespace, objName)
+	case "ClusterRole":
+		urn = b.BuildClusterRoleExternalID(objName)
+	case "StorageClass":
+		urn = b.BuildStorageClassExternalID(objName)
+	case "GatewayClass":
+		urn = b.BuildGatewayClassExternalID(objName)
+	case "Gateway":
+		urn = b.BuildGatewayExternalID(namespace, objName)
+	case "HTTPRoute":
+		urn = b.BuildHTTPRouteExternalID(namespace, objName)
+	case "GRPCRoute":
+		urn = b.BuildGRPCRouteExternalID(namespace, objName)
 	}
 
 	if urn == "" {
@@ -208,6 +241,61 @@ func (b *urnBuilder) BuildPersistentVolumeClaimExternalID(namespace, persistentV
 	return b.BuildComponentExternalID("persistent-volume-claim", namespace, persistentVolumeClaimName)
 }
 
+// BuildHorizontalPodAutoscalerExternalID creates the urn external identifier for a cluster horizontal pod autoscaler
+func (b *urnBuilder) BuildHorizontalPodAutoscalerExternalID(namespace, horizontalPodAutoscalerName string) string {
+	return b.BuildComponentExternalID("horizontal-pod-autoscaler", namespace, horizontalPodAutoscalerName)
+}
+
+// BuildPodDisruptionBudgetExternalID creates the urn external identifier for a cluster pod disruption budget
+func (b *urnBuilder) BuildPodDisruptionBudgetExternalID(namespace, podDisruptionBudgetName string) string {
+	return b.BuildComponentExternalID("pod-disruption-budget", namespace, podDisruptionBudgetName)
+}
+
+// BuildNetworkPolicyExternalID creates the urn external identifier for a cluster network policy
+func (b *urnBuilder) BuildNetworkPolicyExternalID(namespace, networkPolicyName string) string {
+	return b.BuildComponentExternalID("network-policy", namespace, networkPolicyName)
+}
+
+// BuildServiceAccountExternalID creates the urn external identifier for a cluster service account
+func (b *urnBuilder) BuildServiceAccountExternalID(namespace, serviceAccountName string) string {
+	return b.BuildComponentExternalID("service-account", namespace, serviceAccountName)
+}
+
+// BuildRoleExternalID creates the urn external identifier for a cluster role
+func (b *urnBuilder) BuildRoleExternalID(namespace, roleName string) string {
+	return b.BuildComponentExternalID("role", namespace, roleName)
+}
+
+// BuildClusterRoleExternalID creates the urn external identifier for a cluster cluster role
+func (b *urnBuilder) BuildClusterRoleExternalID(clusterRoleName string) string {
+	return b.BuildComponentExternalID("cluster-role", "", clusterRoleName)
+}
+
+// BuildStorageClassExternalID creates the urn external identifier for a cluster storage class
+func (b *urnBuilder) BuildStorageClassExternalID(storageClassName string) string {
+	return b.BuildComponentExternalID("storage-class", "", storageClassName)
+}
+
+// BuildGatewayClassExternalID creates the urn external identifier for a cluster gateway class
+func (b *urnBuilder) BuildGatewayClassExternalID(gatewayClassName string) string {
+	return b.BuildComponentExternalID("gateway-class", "", gatewayClassName)
+}
+
+// BuildGatewayExternalID creates the urn external identifier for a cluster gateway
+func (b *urnBuilder) BuildGatewayExternalID(namespace, gatewayName string) string {
+	return b.BuildComponentExternalID("gateway", namespace, gatewayName)
+}
+
+// BuildHTTPRouteExternalID creates the urn external identifier for a cluster http route
+func (b *urnBuilder) BuildHTTPRouteExternalID(namespace, httpRouteName string) string {
+	return b.BuildComponentExternalID("http-route", namespace, httpRouteName)
+}
+
+// BuildGRPCRouteExternalID creates the urn external identifier for a cluster grpc route
+func (b *urnBuilder) BuildGRPCRouteExternalID(namespace, grpcRouteName string) string {
+	return b.BuildComponentExternalID("grpc-route", namespace, grpcRouteName)
+}
+
 // BuildComponentExternalID creates the urn external identifier for a specific component type
 func (b *urnBuilder) BuildComponentExternalID(component, namespace, name string) string {
 	if namespace != "" {
@@ -105,3 +105,35 @@ func TestGceUrnBuilder_BuildNodeInstanceIdentifier(t *testing.T) {
 		"urn:host:/gke-test-default-pool-bbd2dc11-9wxt-mycluster",
 	}, gceIdentifiers)
 }
+
+func TestUrnBuilder_BuildExternalID(t *testing.T) {
+	builder := NewURNBuilder(Kubernetes, "uurrll")
+
+	for _, tc := range []struct {
+		kind      string
+		namespace string
+		name      string
+		expected  string
+	}{
+		{kind: "HorizontalPodAutoscaler", namespace: "ns", name: "hpa", expected: "urn:kubernetes:/uurrll:ns:horizontal-pod-autoscaler/hpa"},
+		{kind: "PodDisruptionBudget", namespace: "ns", name: "pdb", expected: "urn:kubernetes:/uurrll:ns:pod-disruption-budget/pdb"},
+		{kind: "NetworkPolicy", namespace: "ns", name: "np", expected: "urn:kubernetes:/uurrll:ns:network-policy/np"},
+		{kind: "ServiceAccount", namespace: "ns", name: "sa", expected: "urn:kubernetes:/uurrll:ns:service-account/sa"},
+		{kind: "Role", namespace: "ns", name: "role", expected: "urn:kubernetes:/uurrll:ns:role/role"},
+		{kind: "ClusterRole", name: "cluster-admin", expected: "urn:kubernetes:/uurrll:cluster-role/cluster-admin"},
+		{kind: "StorageClass", name: "gp2", expected: "urn:kubernetes:/uurrll:storage-class/gp2"},
+		{kind: "GatewayClass", name: "istio", expected: "urn:kubernetes:/uurrll:gateway-class/istio"},
+		{kind: "Gateway", namespace: "ns", name: "gw", expected: "urn:kubernetes:/uurrll:ns:gateway/gw"},
+		{kind: "HTTPRoute", namespace: "ns", name: "web", expected: "urn:kubernetes:/uurrll:ns:http-route/web"},
+		{kind: "GRPCRoute", namespace: "ns", name: "rpc", expected: "urn:kubernetes:/uurrll:ns:grpc-route/rpc"},
+	} {
+		t.Run(tc.kind, func(t *testing.T) {
+			externalID, err := builder.BuildExternalID(tc.kind, tc.namespace, tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, externalID)
+		})
+	}
+
+	_, err := builder.BuildExternalID("Unknown", "ns", "name")
+	assert.Error(t, err)
+}
@@ -28,6 +28,7 @@ import (
 	core "github.com/StackVista/stackstate-agent/pkg/collector/corechecks"
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/containers/topology"
 	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
 	"github.com/StackVista/stackstate-agent/pkg/tagger"
 	"github.com/StackVista/stackstate-agent/pkg/tagger/collectors"
@@ -56,6 +57,9 @@ type ContainerdConfig struct {
 	ContainerdFilters        []string `yaml:"filters"`
 	CollectEvents            bool     `yaml:"collect_events"`
 	CollectContainerTopology bool     `yaml:"collect_container_topology"` // sts
+
+	// sts
+	HealthRules []deriver.RuleConfig `yaml:"health_rules"`
 }
 
 func init() {
@@ -97,6 +101,8 @@ func (c *ContainerdCheck) Configure(config, initConfig integration.Data, source
 	}
 	c.filters = fil
 
+	c.topologyCollector.ConfigureHealthRules(c.instance.HealthRules, c.Interval()) // sts
+
 	return nil
 }
 
@@ -10,6 +10,7 @@ package cri
 
 import (
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/containers/topology"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
 	"time"
 
@@ -36,7 +37,8 @@ const (
 type CRIConfig struct {
 	CollectDisk bool `yaml:"collect_disk"`
 	// sts
-	CollectContainerTopology bool `yaml:"collect_container_topology"`
+	CollectContainerTopology bool                 `yaml:"collect_container_topology"`
+	HealthRules              []deriver.RuleConfig `yaml:"health_rules"`
 }
 
 // CRICheck grabs CRI metrics
@@ -85,7 +87,14 @@ func (c *CRICheck) Configure(config, initConfig integration.Data, source string)
 	}
 	c.filter = filter
 
-	return c.instance.Parse(config)
+	if err := c.instance.Parse(config); err != nil {
+		return err
+	}
+
+	// sts
+	c.topologyCollector.ConfigureHealthRules(c.instance.HealthRules, c.Interval())
+
+	return nil
 }
 
 // Run executes the check
@@ -447,6 +447,8 @@ func (d *DockerCheck) Configure(config, initConfig integration.Data, source stri
 
 	d.setOkExitCodes()
 
+	d.topologyCollector.ConfigureHealthRules(d.instance.HealthRules, d.Interval()) // sts
+
 	return nil
 }
 
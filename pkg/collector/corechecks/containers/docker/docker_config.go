@@ -8,7 +8,10 @@
 
 package docker
 
-import "gopkg.in/yaml.v2"
+import (
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
+	"gopkg.in/yaml.v2"
+)
 
 // checkName constants used to call ServiceCheck
 const (
@@ -31,6 +34,9 @@ type DockerConfig struct {
 	FilteredEventType        []string           `yaml:"filtered_event_types"`
 	CappedMetrics            map[string]float64 `yaml:"capped_metrics"`
 	CollectContainerTopology bool               `yaml:"collect_container_topology"` // sts
+
+	// sts
+	HealthRules []deriver.RuleConfig `yaml:"health_rules"`
 }
 
 func (c *DockerConfig) Parse(data []byte) error {
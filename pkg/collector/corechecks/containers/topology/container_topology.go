@@ -6,24 +6,57 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/StackVista/stackstate-agent/pkg/batcher"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks"
+	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/cluster/urn"
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks/containers/spec"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
+	"github.com/StackVista/stackstate-agent/pkg/tagger"
+	"github.com/StackVista/stackstate-agent/pkg/tagger/collectors"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util"
+	"github.com/StackVista/stackstate-agent/pkg/util/containers"
+	"github.com/StackVista/stackstate-agent/pkg/util/kubernetes/clustername"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 )
 
 const (
 	containerType = "container"
+	volumeType    = "volume"
+	// containerStateSource is the health rule source of the state of a container, i.e. "running" or "exited"
+	containerStateSource = "container_state"
+)
+
+// legacyCheckID and legacyInstance are the identifiers the containers of all runtimes and hosts were submitted with
+// before they were submitted as a snapshot per host and runtime. The containers submitted with them are never removed,
+// so the agent submits an empty snapshot for the legacy instance once to remove them.
+var (
+	legacyCheckID         = check.ID(fmt.Sprintf("%s_topology", containerType))
+	legacyInstance        = topology.Instance{Type: containerType, URL: "agents"}
+	legacyInstanceRemoval sync.Once
 )
 
+// PodLookup returns the namespace and name of the Kubernetes pod a container belongs to
+type PodLookup func(containerID string) (namespace, podName string, found bool)
+
 // ContainerTopologyCollector contains the checkID and topology instance for the container topology checks
 type ContainerTopologyCollector struct {
 	corechecks.CheckTopologyCollector
 	Hostname string
 	Runtime  string
+	// ClusterName is the Kubernetes cluster of the host, containers are only related to their pod when it is known
+	ClusterName string
+	// podLookup finds the pod of a container, defaults to the tagger
+	podLookup PodLookup
+	// healthDeriver derives health states from the observed containers, nil when no health rules are configured
+	healthDeriver *deriver.Deriver
 }
 
 // MakeContainerTopologyCollector returns a new instance of DockerTopologyCollector
@@ -34,16 +67,35 @@ func MakeContainerTopologyCollector(runtime string) *ContainerTopologyCollector
 	}
 	return &ContainerTopologyCollector{
 		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector(
-			check.ID(fmt.Sprintf("%s_topology", containerType)), topology.Instance{
+			check.ID(fmt.Sprintf("%s_%s_topology", runtime, containerType)), topology.Instance{
 				Type: containerType,
-				URL:  "agents",
+				// the containers are submitted as a snapshot, so the instance is scoped to the host and runtime
+				URL: fmt.Sprintf("agents:%s:%s", hostname, runtime),
 			}),
-		Hostname: hostname,
-		Runtime:  runtime,
+		Hostname:    hostname,
+		Runtime:     runtime,
+		ClusterName: clustername.GetClusterName(context.TODO(), hostname),
+		podLookup:   taggerPodLookup,
 	}
 }
 
-// BuildContainerTopology collects all docker container topology
+// ConfigureHealthRules sets up the health states that are derived from the containers on every collection. Invalid
+// rules are logged and left out.
+func (ctc *ContainerTopologyCollector) ConfigureHealthRules(configs []deriver.RuleConfig, interval time.Duration) {
+	rules, errs := deriver.NewRules(configs)
+	for _, err := range errs {
+		_ = log.Warnf("Ignoring container health rule for '%s' runtime: %v", ctc.Runtime, err)
+	}
+	if len(rules) == 0 {
+		ctc.healthDeriver = nil
+		return
+	}
+	stream := health.Stream{Urn: fmt.Sprintf("urn:health:%s:%s", containerType, ctc.Hostname), SubStream: ctc.Runtime}
+	ctc.healthDeriver = deriver.NewDeriver(stream, rules, int(interval.Seconds()))
+}
+
+// BuildContainerTopology collects the containers of the runtime and submits them as a topology snapshot, so containers
+// that have gone away are removed. The containers are related to the host, their pod and their volumes.
 func (ctc *ContainerTopologyCollector) BuildContainerTopology(containerUtil spec.ContainerUtil) error {
 	log.Infof("Running container topology collector for '%s' runtime", ctc.Runtime)
 	sender := batcher.GetBatcher()
@@ -51,16 +103,32 @@ func (ctc *ContainerTopologyCollector) BuildContainerTopology(containerUtil spec
 		return errors.New("no batcher instance available, skipping BuildContainerTopology")
 	}
 
-	// collect all containers as topology components
-	containerComponents, err := ctc.collectContainers(containerUtil)
+	// collect all containers as topology components, the snapshot is only started when the collection succeeded
+	components, relations, err := ctc.collectContainers(containerUtil)
 	if err != nil {
 		return err
 	}
 
-	// submit all collected topology components
-	for _, component := range containerComponents {
+	legacyInstanceRemoval.Do(func() {
+		log.Infof("Removing the containers submitted with the legacy %s topology instance", legacyInstance.GoString())
+		sender.SubmitStartSnapshot(legacyCheckID, legacyInstance)
+		sender.SubmitStopSnapshot(legacyCheckID, legacyInstance)
+		sender.SubmitComplete(legacyCheckID)
+	})
+
+	sender.SubmitStartSnapshot(ctc.CheckID, ctc.TopologyInstance)
+	for _, component := range components {
 		sender.SubmitComponent(ctc.CheckID, ctc.TopologyInstance, *component)
 	}
+	for _, relation := range relations {
+		sender.SubmitRelation(ctc.CheckID, ctc.TopologyInstance, *relation)
+	}
+	sender.SubmitStopSnapshot(ctc.CheckID, ctc.TopologyInstance)
+
+	// submit the health derived from the collected containers
+	if ctc.healthDeriver != nil {
+		ctc.healthDeriver.Flush(deriver.NewBatcherSubmitter(sender, ctc.CheckID))
+	}
 
 	sender.SubmitComplete(ctc.CheckID)
 
@@ -111,16 +179,79 @@ func (ctc *ContainerTopologyCollector) MapContainersToComponents(containers []*s
 	return output
 }
 
-// collectContainers collects containers and produces topology.Component
-func (ctc *ContainerTopologyCollector) collectContainers(containerUtil spec.ContainerUtil) ([]*topology.Component, error) {
+// collectContainers collects containers and produces the container and volume components and their relations
+func (ctc *ContainerTopologyCollector) collectContainers(containerUtil spec.ContainerUtil) ([]*topology.Component, []*topology.Relation, error) {
 	cList, err := containerUtil.GetContainers(context.TODO())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	containerComponents := ctc.MapContainersToComponents(cList)
+	components := containerComponents
+	relations := make([]*topology.Relation, 0, len(cList))
+	volumes := make(map[string]bool)
+
+	for i, container := range cList {
+		containerExternalID := containerComponents[i].ExternalID
+		if ctc.Hostname != "" {
+			relations = append(relations, buildRelation(containerExternalID, fmt.Sprintf("urn:host:/%s", ctc.Hostname), "runs_on", nil))
+		}
+
+		if podExternalID, found := ctc.buildPodExternalID(container.ID); found {
+			relations = append(relations, buildRelation(podExternalID, containerExternalID, "encloses", nil))
+		}
+
+		for _, mount := range container.Mounts {
+			if !isHostVolume(mount.Source, mount.Type) {
+				continue
+			}
+			volumeExternalID := ctc.buildVolumeExternalID(mount.Source)
+			if !volumes[volumeExternalID] {
+				volumes[volumeExternalID] = true
+				components = append(components, &topology.Component{
+					ExternalID: volumeExternalID,
+					Type:       topology.Type{Name: volumeType},
+					Data: topology.Data{
+						"name":   filepath.Base(mount.Source),
+						"source": mount.Source,
+						"type":   mount.Type,
+					},
+				})
+			}
+			relations = append(relations, buildRelation(containerExternalID, volumeExternalID, "mounts", map[string]interface{}{
+				"destination": mount.Destination,
+				"options":     mount.Options,
+			}))
+		}
+	}
+
+	if ctc.healthDeriver != nil {
+		for i, container := range cList {
+			ctc.healthDeriver.Observe(deriver.Observation{
+				TopologyElementIdentifier: containerComponents[i].ExternalID,
+				Source:                    containerStateSource,
+				State:                     container.State,
+			})
+		}
+	}
 
-	return containerComponents, nil
+	return components, relations, nil
+}
+
+// buildPodExternalID returns the urn of the Kubernetes pod of a container, matching the one of the kubernetes topology
+func (ctc *ContainerTopologyCollector) buildPodExternalID(containerID string) (string, bool) {
+	if ctc.ClusterName == "" || ctc.podLookup == nil {
+		return "", false
+	}
+	namespace, podName, found := ctc.podLookup(containerID)
+	if !found {
+		return "", false
+	}
+	return urn.NewURNBuilder(urn.Kubernetes, ctc.ClusterName).BuildPodExternalID(namespace, podName), true
+}
+
+func (ctc *ContainerTopologyCollector) buildVolumeExternalID(source string) string {
+	return fmt.Sprintf("urn:%s:/%s:%s", volumeType, ctc.Hostname, source)
 }
 
 func (ctc *ContainerTopologyCollector) buildContainerExternalID(container *spec.Container) string {
@@ -139,6 +270,43 @@ func (ctc *ContainerTopologyCollector) buildProcessAgentContainerIdentifier(cont
 	return fmt.Sprintf("urn:%s:/%s:%s", containerType, ctc.Hostname, containerID), nil
 }
 
+// isHostVolume returns whether a mount is a path or volume of the host, leaving out pseudo filesystems like proc
+func isHostVolume(source, mountType string) bool {
+	return strings.HasPrefix(source, "/") && mountType != "tmpfs"
+}
+
+// taggerPodLookup finds the pod of a container in its orchestrator tags
+func taggerPodLookup(containerID string) (string, string, bool) {
+	tags, err := tagger.Tag(containers.BuildTaggerEntityName(containerID), collectors.OrchestratorCardinality)
+	if err != nil {
+		log.Debugf("Could not get the tags of container %s to find its pod: %s", containerID, err)
+		return "", "", false
+	}
+
+	var namespace, podName string
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "kube_namespace:") {
+			namespace = strings.TrimPrefix(tag, "kube_namespace:")
+		} else if strings.HasPrefix(tag, "pod_name:") {
+			podName = strings.TrimPrefix(tag, "pod_name:")
+		}
+	}
+	return namespace, podName, namespace != "" && podName != ""
+}
+
+func buildRelation(sourceExternalID, targetExternalID, typeName string, data topology.Data) *topology.Relation {
+	if data == nil {
+		data = topology.Data{}
+	}
+	return &topology.Relation{
+		ExternalID: fmt.Sprintf("%s->%s", sourceExternalID, targetExternalID),
+		SourceID:   sourceExternalID,
+		TargetID:   targetExternalID,
+		Type:       topology.Type{Name: typeName},
+		Data:       data,
+	}
+}
+
 func runtimeLabel(runtime string) string {
 	return fmt.Sprintf("runtime:%s", runtime)
 }
@@ -3,13 +3,20 @@ package topology
 
 import (
 	"context"
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/collector/corechecks"
 	cspec "github.com/StackVista/stackstate-agent/pkg/collector/corechecks/containers/spec"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/health/deriver"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
 	"github.com/StackVista/stackstate-agent/pkg/util"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
+	"time"
 )
 
 type MockUtil struct {
@@ -45,14 +52,14 @@ func (m MockUtil) GetContainers(ctx context.Context) ([]*cspec.Container, error)
 func TestMakeContainerTopologyCollector(t *testing.T) {
 	hostname, err := util.GetHostname(context.TODO())
 	assert.NoError(t, err)
-	assert.Equal(t, &ContainerTopologyCollector{
-		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector("container_topology", topology.Instance{
-			Type: "container",
-			URL:  "agents",
-		}),
-		Hostname: hostname,
-		Runtime:  "test",
-	}, MakeContainerTopologyCollector("test"))
+	collector := MakeContainerTopologyCollector("test")
+	assert.Equal(t, corechecks.MakeCheckTopologyCollector("test_container_topology", topology.Instance{
+		Type: "container",
+		URL:  fmt.Sprintf("agents:%s:test", hostname),
+	}), collector.CheckTopologyCollector)
+	assert.Equal(t, hostname, collector.Hostname)
+	assert.Equal(t, "test", collector.Runtime)
+	assert.NotNil(t, collector.podLookup)
 }
 
 func TestBuildContainerTopology(t *testing.T) {
@@ -65,7 +72,7 @@ func TestBuildContainerTopology(t *testing.T) {
 		Runtime:  "test",
 	}
 
-	components, err := collector.collectContainers(MockUtil{})
+	components, relations, err := collector.collectContainers(MockUtil{})
 	assert.NoError(t, err)
 	assert.Equal(t, []*topology.Component{
 		{
@@ -125,4 +132,172 @@ func TestBuildContainerTopology(t *testing.T) {
 			},
 		},
 	}, components)
+	// the mounts are not host paths, so only the relations to the host are produced
+	assert.Equal(t, []*topology.Relation{
+		{
+			ExternalID: "urn:container:containerd:/host:containerId1->urn:host:/host",
+			SourceID:   "urn:container:containerd:/host:containerId1",
+			TargetID:   "urn:host:/host",
+			Type:       topology.Type{Name: "runs_on"},
+			Data:       topology.Data{},
+		},
+		{
+			ExternalID: "urn:container:docker:/host:containerId2->urn:host:/host",
+			SourceID:   "urn:container:docker:/host:containerId2",
+			TargetID:   "urn:host:/host",
+			Type:       topology.Type{Name: "runs_on"},
+			Data:       topology.Data{},
+		},
+	}, relations)
+}
+
+type MockVolumeUtil struct {
+}
+
+func (m MockVolumeUtil) GetContainers(ctx context.Context) ([]*cspec.Container, error) {
+	return []*cspec.Container{
+		{
+			Name:    "nginx",
+			Runtime: "containerd",
+			ID:      "nginxId",
+			Image:   "nginx",
+			Mounts: []specs.Mount{
+				{Source: "/var/lib/kubelet/pods/uid/volumes/data", Destination: "/data", Type: "bind", Options: []string{"rbind"}},
+				{Source: "proc", Destination: "/proc", Type: "proc"},
+				{Source: "/run/tmp", Destination: "/tmp", Type: "tmpfs"},
+			},
+			State: "running",
+		},
+		{
+			Name:    "sidecar",
+			Runtime: "containerd",
+			ID:      "sidecarId",
+			Image:   "sidecar",
+			Mounts: []specs.Mount{
+				{Source: "/var/lib/kubelet/pods/uid/volumes/data", Destination: "/shared", Type: "bind"},
+			},
+			State: "exited",
+		},
+	}, nil
+}
+
+func TestBuildContainerTopologySnapshot(t *testing.T) {
+	mockBatcher := batcher.NewMockBatcher()
+	instance := topology.Instance{Type: "container", URL: "agents:host:containerd"}
+	collector := ContainerTopologyCollector{
+		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector("containerd_container_topology", instance),
+		Hostname:               "host",
+		Runtime:                "containerd",
+		ClusterName:            "cluster",
+		podLookup: func(containerID string) (string, string, bool) {
+			if containerID == "nginxId" {
+				return "default", "nginx-pod", true
+			}
+			return "", "", false
+		},
+	}
+
+	err := collector.BuildContainerTopology(MockVolumeUtil{})
+	assert.NoError(t, err)
+
+	producedTopology := mockBatcher.CollectedTopology.Flush()["containerd_container_topology"].Topology
+	assert.True(t, producedTopology.StartSnapshot)
+	assert.True(t, producedTopology.StopSnapshot)
+	assert.Equal(t, instance, producedTopology.Instance)
+
+	componentIDs := make([]string, 0, len(producedTopology.Components))
+	for _, component := range producedTopology.Components {
+		componentIDs = append(componentIDs, component.ExternalID)
+	}
+	assert.Equal(t, []string{
+		"urn:container:containerd:/host:nginxId",
+		"urn:container:containerd:/host:sidecarId",
+		"urn:volume:/host:/var/lib/kubelet/pods/uid/volumes/data",
+	}, componentIDs)
+	assert.Equal(t, topology.Data{
+		"name":   "data",
+		"source": "/var/lib/kubelet/pods/uid/volumes/data",
+		"type":   "bind",
+	}, producedTopology.Components[2].Data)
+
+	relationIDs := make([]string, 0, len(producedTopology.Relations))
+	for _, relation := range producedTopology.Relations {
+		relationIDs = append(relationIDs, relation.ExternalID)
+	}
+	assert.Equal(t, []string{
+		"urn:container:containerd:/host:nginxId->urn:host:/host",
+		"urn:kubernetes:/cluster:default:pod/nginx-pod->urn:container:containerd:/host:nginxId",
+		"urn:container:containerd:/host:nginxId->urn:volume:/host:/var/lib/kubelet/pods/uid/volumes/data",
+		"urn:container:containerd:/host:sidecarId->urn:host:/host",
+		"urn:container:containerd:/host:sidecarId->urn:volume:/host:/var/lib/kubelet/pods/uid/volumes/data",
+	}, relationIDs)
+	assert.Equal(t, topology.Type{Name: "encloses"}, producedTopology.Relations[1].Type)
+	assert.Equal(t, topology.Data{"destination": "/data", "options": []string{"rbind"}}, producedTopology.Relations[2].Data)
+}
+
+func TestBuildContainerTopologyRemovesLegacyInstance(t *testing.T) {
+	legacyInstanceRemoval = sync.Once{}
+	mockBatcher := batcher.NewMockBatcher()
+	collector := ContainerTopologyCollector{
+		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector("containerd_container_topology",
+			topology.Instance{Type: "container", URL: "agents:host:containerd"}),
+		Hostname: "host",
+		Runtime:  "containerd",
+	}
+
+	// the first collection submits an empty snapshot for the instance all containers were submitted with before
+	assert.NoError(t, collector.BuildContainerTopology(MockVolumeUtil{}))
+	legacyTopology := mockBatcher.CollectedTopology.Flush()["container_topology"].Topology
+	assert.Equal(t, topology.Instance{Type: "container", URL: "agents"}, legacyTopology.Instance)
+	assert.True(t, legacyTopology.StartSnapshot)
+	assert.True(t, legacyTopology.StopSnapshot)
+	assert.Empty(t, legacyTopology.Components)
+	assert.Empty(t, legacyTopology.Relations)
+
+	// only once
+	assert.NoError(t, collector.BuildContainerTopology(MockVolumeUtil{}))
+	assert.NotContains(t, mockBatcher.CollectedTopology.Flush(), check.ID("container_topology"))
+}
+
+func TestBuildContainerTopologyDerivedHealth(t *testing.T) {
+	mockBatcher := batcher.NewMockBatcher()
+	collector := ContainerTopologyCollector{
+		CheckTopologyCollector: corechecks.MakeCheckTopologyCollector("checkName", topology.Instance{
+			Type: "checkName",
+			URL:  "agents",
+		}),
+		Hostname: "host",
+		Runtime:  "test",
+	}
+	collector.ConfigureHealthRules([]deriver.RuleConfig{
+		{Name: "Container state", Source: "container_state", States: map[string]string{"running": "CLEAR"}, DefaultState: "CRITICAL"},
+		{Name: "Invalid", Source: "container_state"},
+	}, 15*time.Second)
+
+	err := collector.BuildContainerTopology(MockUtil{})
+	assert.NoError(t, err)
+
+	stream := health.Stream{Urn: "urn:health:container:host", SubStream: "test"}
+	producedHealth := mockBatcher.CollectedTopology.Flush()["checkName"].Health[stream.GoString()]
+	assert.Equal(t, health.Health{
+		StartSnapshot: &health.StartSnapshotMetadata{RepeatIntervalS: 15},
+		StopSnapshot:  &health.StopSnapshotMetadata{},
+		Stream:        stream,
+		CheckStates: []health.CheckData{
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Container state:urn:container:containerd:/host:containerId1",
+				Message:                   "container_state is running",
+				Health:                    health.Clear,
+				TopologyElementIdentifier: "urn:container:containerd:/host:containerId1",
+				Name:                      "Container state",
+			}},
+			{CheckState: &health.CheckState{
+				CheckStateID:              "Container state:urn:container:docker:/host:containerId2",
+				Message:                   "container_state is running",
+				Health:                    health.Clear,
+				TopologyElementIdentifier: "urn:container:docker:/host:containerId2",
+				Name:                      "Container state",
+			}},
+		},
+	}, producedHealth)
 }
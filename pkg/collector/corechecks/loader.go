@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
 	"github.com/StackVista/stackstate-agent/pkg/collector/loaders"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 )
@@ -73,6 +74,11 @@ func (gl *GoCheckLoader) Load(config integration.Config, instance integration.Da
 		return c, fmt.Errorf(msg)
 	}
 
+	// [sts] register check handler, the check manager is not initialized in the cluster agent
+	if cm := handler.GetCheckManager(); cm != nil {
+		cm.RegisterCheckHandler(c, instance, config.InitConfig)
+	}
+
 	return c, nil
 }
 
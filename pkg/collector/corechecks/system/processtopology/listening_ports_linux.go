@@ -0,0 +1,119 @@
+//go:build linux
+// +build linux
+
+package processtopology
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StackVista/stackstate-agent/pkg/process/util"
+	"github.com/StackVista/stackstate-agent/pkg/util/containers/providers"
+	// register the cgroup container provider used to find the container of a process
+	_ "github.com/StackVista/stackstate-agent/pkg/util/containers/providers/cgroup"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// tcpListen is the state of a listening socket in /proc/net/tcp
+const tcpListen = "0A"
+
+// listeningPortsByPID returns the tcp ports the given processes listen on. The listening sockets are read once per
+// network namespace and matched to the processes through the socket inodes of their file descriptors.
+func listeningPortsByPID(pids []int32) map[int32][]uint16 {
+	procRoot := util.GetProcRoot()
+	portsByNetNs := make(map[uint32]map[string]uint16)
+	portsByPID := make(map[int32][]uint16)
+
+	for _, pid := range pids {
+		nsIno, err := util.GetNetNsInoFromPid(procRoot, int(pid))
+		if err != nil {
+			continue
+		}
+		portsByInode, found := portsByNetNs[nsIno]
+		if !found {
+			portsByInode = make(map[string]uint16)
+			for _, file := range []string{"tcp", "tcp6"} {
+				readListeningSockets(filepath.Join(procRoot, strconv.Itoa(int(pid)), "net", file), portsByInode)
+			}
+			portsByNetNs[nsIno] = portsByInode
+		}
+		if len(portsByInode) == 0 {
+			continue
+		}
+
+		if ports := processListeningPorts(filepath.Join(procRoot, strconv.Itoa(int(pid)), "fd"), portsByInode); len(ports) > 0 {
+			portsByPID[pid] = ports
+		}
+	}
+
+	return portsByPID
+}
+
+// readListeningSockets adds the port of every listening socket in the /proc/net/tcp(6) file by its inode
+func readListeningSockets(path string, portsByInode map[string]uint16) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Debugf("Could not read listening sockets from %s: %s", path, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip the header line
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		separator := strings.LastIndex(fields[1], ":")
+		if separator < 0 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][separator+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		portsByInode[fields[9]] = uint16(port)
+	}
+}
+
+// processListeningPorts returns the sorted, unique ports of the listening sockets among the file descriptors of a process
+func processListeningPorts(fdPath string, portsByInode map[string]uint16) []uint16 {
+	fds, err := ioutil.ReadDir(fdPath)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[uint16]bool)
+	ports := make([]uint16, 0)
+	for _, fd := range fds {
+		link, err := os.Readlink(filepath.Join(fdPath, fd.Name()))
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		port, found := portsByInode[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]
+		if found && !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	return ports
+}
+
+// containerIDForPID returns the id of the container the process runs in, based on its cgroups
+func containerIDForPID(pid int32) string {
+	containerID, err := providers.ContainerImpl().ContainerIDForPID(int(pid))
+	if err != nil {
+		log.Tracef("Could not find the container of process %d: %s", pid, err)
+		return ""
+	}
+	return containerID
+}
@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+package processtopology
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadListeningSockets(t *testing.T) {
+	portsByInode := make(map[string]uint16)
+	readListeningSockets("./testdata/tcp", portsByInode)
+
+	// the established connection on inode 1003 is left out
+	assert.Equal(t, map[string]uint16{"1001": 80, "1002": 8080}, portsByInode)
+}
+
+func TestProcessListeningPorts(t *testing.T) {
+	fdPath, err := ioutil.TempDir("", "process-topology-fd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(fdPath)
+
+	for fd, target := range map[string]string{
+		"0": "/dev/null",
+		"3": "socket:[1002]",
+		"4": "socket:[1001]",
+		"5": "socket:[1001]",
+		"6": "socket:[1003]",
+	} {
+		assert.NoError(t, os.Symlink(target, filepath.Join(fdPath, fd)))
+	}
+
+	ports := processListeningPorts(fdPath, map[string]uint16{"1001": 80, "1002": 8080})
+	assert.Equal(t, []uint16{80, 8080}, ports)
+}
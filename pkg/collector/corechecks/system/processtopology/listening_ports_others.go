@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package processtopology
+
+// listeningPortsByPID is only supported on linux
+func listeningPortsByPID(pids []int32) map[int32][]uint16 {
+	return nil
+}
+
+// containerIDForPID is only supported on linux, processes are related to containers by their main process only
+func containerIDForPID(pid int32) string {
+	return ""
+}
@@ -0,0 +1,320 @@
+// Package processtopology is responsible for gathering the topology of the processes running on the host
+package processtopology
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/StackVista/stackstate-agent/pkg/autodiscovery/integration"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	core "github.com/StackVista/stackstate-agent/pkg/collector/corechecks"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	processconfig "github.com/StackVista/stackstate-agent/pkg/process/config"
+	"github.com/StackVista/stackstate-agent/pkg/process/procutil"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"github.com/StackVista/stackstate-agent/pkg/workloadmeta"
+)
+
+const (
+	checkName   = "process_topology"
+	processType = "process"
+
+	defaultMaxProcesses = 1000
+)
+
+// processTopologyConfig is the instance configuration of the process topology check
+type processTopologyConfig struct {
+	// CollectListeningPorts adds the ports a process listens on to its data and identifiers
+	CollectListeningPorts *bool `yaml:"collect_listening_ports"`
+	// ExcludeProcesses is a list of regular expressions, processes with a matching name are left out
+	ExcludeProcesses []string `yaml:"exclude_processes"`
+	// MaxProcesses is the maximum number of processes in the snapshot, the processes with the lowest pids are kept
+	MaxProcesses *int `yaml:"max_processes"`
+}
+
+// Check produces host, process and container topology from the processes running on the host
+type Check struct {
+	core.CheckBase
+	core.CheckTopologyCollector
+	hostname              string
+	collectListeningPorts bool
+	excludeProcesses      []*regexp.Regexp
+	maxProcesses          int
+	// blacklist and scrubber are configured like the ones of the process-agent, with the process_config settings
+	blacklist []*regexp.Regexp
+	scrubber  *processconfig.DataScrubber
+	probe     procutil.Probe
+	store     workloadmeta.Store
+	// listeningPorts returns the tcp ports the given processes listen on, replaced in the tests
+	listeningPorts func(pids []int32) map[int32][]uint16
+	// containerIDForPID returns the id of the container a process runs in, replaced in the tests
+	containerIDForPID func(pid int32) string
+}
+
+// Configure parses the check configuration and sets up the process probe
+func (c *Check) Configure(data integration.Data, initConfig integration.Data, source string) error {
+	if err := c.CommonConfigure(data, source); err != nil {
+		return err
+	}
+
+	conf := processTopologyConfig{}
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return err
+	}
+
+	c.collectListeningPorts = conf.CollectListeningPorts == nil || *conf.CollectListeningPorts
+	c.excludeProcesses = make([]*regexp.Regexp, 0, len(conf.ExcludeProcesses))
+	for _, expr := range conf.ExcludeProcesses {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid exclude_processes expression '%s': %s", expr, err)
+		}
+		c.excludeProcesses = append(c.excludeProcesses, re)
+	}
+	c.maxProcesses = defaultMaxProcesses
+	if conf.MaxProcesses != nil {
+		c.maxProcesses = *conf.MaxProcesses
+	}
+	c.blacklist, c.scrubber = processAgentFilters()
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		return fmt.Errorf("can't get hostname for the process topology: %s", err)
+	}
+	c.hostname = hostname
+	// the topology is submitted as a snapshot, so it is scoped to the host to leave the processes of other hosts be
+	c.TopologyInstance.URL = fmt.Sprintf("agents:%s", hostname)
+
+	if c.probe == nil {
+		c.probe = procutil.NewProcessProbe()
+	}
+	if c.store == nil {
+		c.store = workloadmeta.GetGlobalStore()
+	}
+
+	return nil
+}
+
+// Run collects the processes of the host and submits them as a topology snapshot
+func (c *Check) Run() error {
+	processes, err := c.probe.ProcessesByPID(time.Now(), false)
+	if err != nil {
+		return fmt.Errorf("could not collect the processes of the host: %s", err)
+	}
+
+	// the scrubber caches the scrubbed command lines, like the process-agent it is reset every few runs
+	defer c.scrubber.IncrementCacheAge()
+
+	return c.WithTransaction(func() error {
+		return c.WithTopologySnapshot(c.TopologyInstance, func() error {
+			components, relations := c.buildTopology(processes, c.containersByID())
+			for _, component := range components {
+				c.SubmitComponent(c.TopologyInstance, component)
+			}
+			for _, relation := range relations {
+				c.SubmitRelation(c.TopologyInstance, relation)
+			}
+			return nil
+		})
+	})
+}
+
+// Cancel closes the process probe
+func (c *Check) Cancel() {
+	if c.probe != nil {
+		c.probe.Close()
+	}
+	c.CommonCancel()
+}
+
+// containersByID returns the containers known to workloadmeta by their id
+func (c *Check) containersByID() map[string]*workloadmeta.Container {
+	containersByID := make(map[string]*workloadmeta.Container)
+	containers, err := c.store.ListContainers()
+	if err != nil {
+		log.Debugf("Could not list the containers for the process topology, processes are not related to containers: %s", err)
+		return containersByID
+	}
+	for _, container := range containers {
+		containersByID[container.ID] = container
+	}
+	return containersByID
+}
+
+// buildTopology creates the host component and a component per process, related to the host, to the container it
+// runs in and to its child processes
+func (c *Check) buildTopology(processes map[int32]*procutil.Process, containers map[string]*workloadmeta.Container) ([]topology.Component, []topology.Relation) {
+	pids := make([]int32, 0, len(processes))
+	for pid, process := range processes {
+		if !c.isExcluded(process) {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	if c.maxProcesses > 0 && len(pids) > c.maxProcesses {
+		_ = c.Warnf("The host runs %d processes, only the %d processes with the lowest pids are collected, "+
+			"increase max_processes or exclude processes to collect the others", len(pids), c.maxProcesses)
+		pids = pids[:c.maxProcesses]
+	}
+
+	var listeningPorts map[int32][]uint16
+	if c.collectListeningPorts {
+		listeningPorts = c.listeningPorts(pids)
+	}
+
+	// the main process of a container is known by workloadmeta, other processes are matched by their cgroup
+	containerPIDs := make(map[int32]string, len(containers))
+	for id, container := range containers {
+		if container.PID > 0 {
+			containerPIDs[int32(container.PID)] = id
+		}
+	}
+
+	hostExternalID := fmt.Sprintf("urn:host:/%s", c.hostname)
+	components := []topology.Component{{
+		ExternalID: hostExternalID,
+		Type:       topology.Type{Name: "host"},
+		Data:       topology.Data{"host": c.hostname},
+	}}
+	relations := make([]topology.Relation, 0, len(pids))
+	processExternalIDs := make(map[int32]string, len(pids))
+
+	for _, pid := range pids {
+		component := c.processComponent(processes[pid], listeningPorts[pid])
+		processExternalIDs[pid] = component.ExternalID
+		relations = append(relations, processRelation(component.ExternalID, hostExternalID, "runs_on"))
+
+		containerID, found := containerPIDs[pid]
+		if !found {
+			containerID = c.containerIDForPID(pid)
+		}
+		if _, found := containers[containerID]; found {
+			component.Data["containerId"] = containerID
+			relations = append(relations, processRelation(component.ExternalID, c.buildContainerExternalID(containerID), "runs_in"))
+		}
+		components = append(components, component)
+	}
+
+	// a parent process is related to its children, when both are part of the snapshot
+	for _, pid := range pids {
+		ppid := processes[pid].Ppid
+		if parentExternalID, found := processExternalIDs[ppid]; found && ppid != pid {
+			relations = append(relations, processRelation(parentExternalID, processExternalIDs[pid], "parent_of"))
+		}
+	}
+
+	return components, relations
+}
+
+// processComponent creates the component of a process, its external id matches the one of the process-agent
+func (c *Check) processComponent(process *procutil.Process, ports []uint16) topology.Component {
+	var createTime int64
+	if process.Stats != nil {
+		createTime = process.Stats.CreateTime
+	}
+
+	data := topology.Data{
+		"name":       process.Name,
+		"pid":        process.Pid,
+		"ppid":       process.Ppid,
+		"exe":        process.Exe,
+		"cwd":        process.Cwd,
+		"cmdline":    c.scrubber.ScrubProcessCommand(process),
+		"createTime": createTime,
+		"host":       c.hostname,
+	}
+	if process.Username != "" {
+		data["username"] = process.Username
+	}
+
+	identifiers := make([]string, 0, len(ports))
+	if len(ports) > 0 {
+		data["listeningPorts"] = ports
+		for _, port := range ports {
+			identifiers = append(identifiers, fmt.Sprintf("urn:endpoint:/%s:%d", c.hostname, port))
+		}
+	}
+	data["identifiers"] = identifiers
+
+	return topology.Component{
+		ExternalID: fmt.Sprintf("urn:%s:/%s:%d:%d", processType, c.hostname, process.Pid, createTime),
+		Type:       topology.Type{Name: processType},
+		Data:       data,
+	}
+}
+
+// isExcluded returns whether the process matches one of the exclude_processes expressions or the blacklist of the
+// process-agent. Kernel threads, which have no command line, are left out like the process-agent does.
+func (c *Check) isExcluded(process *procutil.Process) bool {
+	if len(process.Cmdline) == 0 || processconfig.IsBlacklisted(process.Cmdline, c.blacklist) {
+		return true
+	}
+	for _, re := range c.excludeProcesses {
+		if re.MatchString(process.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// processAgentFilters returns the process blacklist and the command line scrubber configured for the process-agent in
+// process_config, so the process topology does not contain more than the process-agent would send
+func processAgentFilters() ([]*regexp.Regexp, *processconfig.DataScrubber) {
+	blacklist := make([]*regexp.Regexp, 0)
+	for _, pattern := range config.Datadog.GetStringSlice("process_config.blacklist_patterns") {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			_ = log.Warnf("Ignoring invalid process_config.blacklist_patterns pattern: %s", pattern)
+			continue
+		}
+		blacklist = append(blacklist, re)
+	}
+
+	scrubber := processconfig.NewDefaultDataScrubber()
+	if config.Datadog.IsSet("process_config.scrub_args") {
+		scrubber.Enabled = config.Datadog.GetBool("process_config.scrub_args")
+	}
+	if config.Datadog.IsSet("process_config.custom_sensitive_words") {
+		scrubber.AddCustomSensitiveWords(config.Datadog.GetStringSlice("process_config.custom_sensitive_words"))
+	}
+	scrubber.StripAllArguments = config.Datadog.GetBool("process_config.strip_proc_arguments")
+
+	return blacklist, scrubber
+}
+
+// buildContainerExternalID creates the container identifier with the same format as the process-agent
+func (c *Check) buildContainerExternalID(containerID string) string {
+	return fmt.Sprintf("urn:container:/%s:%s", c.hostname, containerID)
+}
+
+func processRelation(sourceExternalID, targetExternalID, typeName string) topology.Relation {
+	return topology.Relation{
+		ExternalID: fmt.Sprintf("%s->%s", sourceExternalID, targetExternalID),
+		SourceID:   sourceExternalID,
+		TargetID:   targetExternalID,
+		Type:       topology.Type{Name: typeName},
+		Data:       topology.Data{},
+	}
+}
+
+func processTopologyFactory() check.Check {
+	return &Check{
+		CheckBase:              core.NewCheckBase(checkName),
+		CheckTopologyCollector: core.MakeCheckProcessTopologyCollector(checkName),
+		maxProcesses:           defaultMaxProcesses,
+		scrubber:               processconfig.NewDefaultDataScrubber(),
+		listeningPorts:         listeningPortsByPID,
+		containerIDForPID:      containerIDForPID,
+	}
+}
+
+func init() {
+	core.RegisterCheck(checkName, processTopologyFactory)
+}
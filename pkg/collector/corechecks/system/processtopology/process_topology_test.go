@@ -0,0 +1,220 @@
+package processtopology
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/process/procutil"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/workloadmeta"
+	workloadmetaTesting "github.com/StackVista/stackstate-agent/pkg/workloadmeta/testing"
+)
+
+type fakeProbe struct {
+	processes map[int32]*procutil.Process
+}
+
+func (p *fakeProbe) Close() {}
+
+func (p *fakeProbe) StatsForPIDs([]int32, time.Time) (map[int32]*procutil.Stats, error) {
+	return nil, nil
+}
+
+func (p *fakeProbe) ProcessesByPID(time.Time, bool) (map[int32]*procutil.Process, error) {
+	return p.processes, nil
+}
+
+func (p *fakeProbe) StatsWithPermByPID([]int32) (map[int32]*procutil.StatsWithPerm, error) {
+	return nil, nil
+}
+
+var testProcesses = map[int32]*procutil.Process{
+	1: {Pid: 1, Ppid: 0, Name: "systemd", Exe: "/usr/lib/systemd/systemd", Cmdline: []string{"/sbin/init"},
+		Stats: &procutil.Stats{CreateTime: 1000}},
+	42: {Pid: 42, Ppid: 1, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx", "-g", "daemon off;"},
+		Stats: &procutil.Stats{CreateTime: 2000}},
+	43: {Pid: 43, Ppid: 42, Name: "nginx-worker", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"},
+		Stats: &procutil.Stats{CreateTime: 2001}},
+	99: {Pid: 99, Ppid: 1, Name: "sshd", Exe: "/usr/sbin/sshd", Cmdline: []string{"sshd"},
+		Stats: &procutil.Stats{CreateTime: 3000}},
+}
+
+func makeTestCheck() *Check {
+	store := workloadmetaTesting.NewStore()
+	store.Set(&workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "nginx-container"},
+		PID:      42,
+		Runtime:  workloadmeta.ContainerRuntimeDocker,
+	})
+
+	c := processTopologyFactory().(*Check)
+	c.hostname = "test-host"
+	c.collectListeningPorts = true
+	c.probe = &fakeProbe{processes: testProcesses}
+	c.store = store
+	c.listeningPorts = func(pids []int32) map[int32][]uint16 {
+		return map[int32][]uint16{42: {80, 443}}
+	}
+	c.containerIDForPID = func(pid int32) string {
+		if pid == 43 {
+			return "nginx-container"
+		}
+		return ""
+	}
+	return c
+}
+
+func TestProcessTopologyBuildTopology(t *testing.T) {
+	c := makeTestCheck()
+	c.excludeProcesses = nil
+
+	components, relations := c.buildTopology(testProcesses, c.containersByID())
+
+	assert.Len(t, components, 5)
+	assert.Equal(t, topology.Component{
+		ExternalID: "urn:host:/test-host",
+		Type:       topology.Type{Name: "host"},
+		Data:       topology.Data{"host": "test-host"},
+	}, components[0])
+	assert.Equal(t, topology.Component{
+		ExternalID: "urn:process:/test-host:42:2000",
+		Type:       topology.Type{Name: "process"},
+		Data: topology.Data{
+			"name":           "nginx",
+			"pid":            int32(42),
+			"ppid":           int32(1),
+			"exe":            "/usr/sbin/nginx",
+			"cwd":            "",
+			"cmdline":        []string{"nginx", "-g", "daemon off;"},
+			"createTime":     int64(2000),
+			"host":           "test-host",
+			"listeningPorts": []uint16{80, 443},
+			"identifiers":    []string{"urn:endpoint:/test-host:80", "urn:endpoint:/test-host:443"},
+			"containerId":    "nginx-container",
+		},
+	}, components[2])
+	assert.Equal(t, []string{}, components[1].Data["identifiers"])
+	assert.NotContains(t, components[1].Data, "containerId")
+
+	relationIDs := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		relationIDs = append(relationIDs, relation.ExternalID)
+	}
+	assert.Equal(t, []string{
+		"urn:process:/test-host:1:1000->urn:host:/test-host",
+		"urn:process:/test-host:42:2000->urn:host:/test-host",
+		"urn:process:/test-host:42:2000->urn:container:/test-host:nginx-container",
+		"urn:process:/test-host:43:2001->urn:host:/test-host",
+		"urn:process:/test-host:43:2001->urn:container:/test-host:nginx-container",
+		"urn:process:/test-host:99:3000->urn:host:/test-host",
+		"urn:process:/test-host:1:1000->urn:process:/test-host:42:2000",
+		"urn:process:/test-host:42:2000->urn:process:/test-host:43:2001",
+		"urn:process:/test-host:1:1000->urn:process:/test-host:99:3000",
+	}, relationIDs)
+	assert.Equal(t, topology.Type{Name: "runs_in"}, relations[2].Type)
+	assert.Equal(t, topology.Relation{
+		ExternalID: "urn:process:/test-host:42:2000->urn:process:/test-host:43:2001",
+		SourceID:   "urn:process:/test-host:42:2000",
+		TargetID:   "urn:process:/test-host:43:2001",
+		Type:       topology.Type{Name: "parent_of"},
+		Data:       topology.Data{},
+	}, relations[7])
+}
+
+func TestProcessTopologyExcludedParentProcess(t *testing.T) {
+	c := makeTestCheck()
+	c.excludeProcesses = []*regexp.Regexp{regexp.MustCompile("^nginx$")}
+
+	// the worker is not related to its excluded parent process
+	_, relations := c.buildTopology(testProcesses, c.containersByID())
+	for _, relation := range relations {
+		if relation.Type.Name == "parent_of" {
+			assert.NotEqual(t, "urn:process:/test-host:43:2001", relation.TargetID)
+		}
+	}
+	assert.Contains(t, relations, processRelation("urn:process:/test-host:1:1000", "urn:process:/test-host:99:3000", "parent_of"))
+}
+
+func TestProcessTopologyConfigure(t *testing.T) {
+	config.Datadog.Set("hostname", "test-host")
+	defer config.Datadog.Set("hostname", "")
+
+	c := makeTestCheck()
+	err := c.Configure([]byte("collect_listening_ports: false\nexclude_processes: ['^ssh', 'worker$']"), []byte(""), "test")
+	assert.NoError(t, err)
+	assert.False(t, c.collectListeningPorts)
+	assert.Equal(t, "test-host", c.hostname)
+	assert.Equal(t, topology.Instance{Type: "process", URL: "agents:test-host"}, c.TopologyInstance)
+
+	components, _ := c.buildTopology(testProcesses, c.containersByID())
+	externalIDs := make([]string, 0, len(components))
+	for _, component := range components {
+		externalIDs = append(externalIDs, component.ExternalID)
+		assert.NotContains(t, component.Data, "listeningPorts")
+	}
+	assert.Equal(t, []string{"urn:host:/test-host", "urn:process:/test-host:1:1000", "urn:process:/test-host:42:2000"}, externalIDs)
+
+	err = c.Configure([]byte("exclude_processes: ['(']"), []byte(""), "test")
+	assert.EqualError(t, err, "invalid exclude_processes expression '(': error parsing regexp: missing closing ): `(`")
+}
+
+func TestProcessTopologyFilters(t *testing.T) {
+	config.Datadog.Set("hostname", "test-host")
+	config.Datadog.Set("process_config.blacklist_patterns", []string{"sshd"})
+	config.Datadog.Set("process_config.custom_sensitive_words", []string{"token"})
+	defer func() {
+		config.Datadog.Set("hostname", "")
+		config.Datadog.Set("process_config.blacklist_patterns", []string{})
+		config.Datadog.Set("process_config.custom_sensitive_words", []string{})
+	}()
+
+	processes := map[int32]*procutil.Process{
+		2: {Pid: 2, Ppid: 0, Name: "kthreadd", Stats: &procutil.Stats{CreateTime: 1000}},
+		7: {Pid: 7, Ppid: 1, Name: "app", Cmdline: []string{"app", "--password=secret", "--token", "abc"},
+			Stats: &procutil.Stats{CreateTime: 4000}},
+	}
+	for pid, process := range testProcesses {
+		processes[pid] = process
+	}
+
+	c := makeTestCheck()
+	assert.NoError(t, c.Configure([]byte("max_processes: 2"), []byte(""), "test"))
+
+	// kernel threads and blacklisted processes are left out, the others are limited to the lowest pids
+	components, _ := c.buildTopology(processes, c.containersByID())
+	assert.Len(t, components, 3)
+	assert.Equal(t, "urn:process:/test-host:1:1000", components[1].ExternalID)
+	assert.Equal(t, "urn:process:/test-host:7:4000", components[2].ExternalID)
+	assert.Equal(t, []string{"app", "--password=********", "--token", "********"}, components[2].Data["cmdline"])
+	assert.Len(t, c.GetWarnings(), 1)
+
+	assert.NoError(t, c.Configure([]byte("max_processes: 0"), []byte(""), "test"))
+	components, _ = c.buildTopology(processes, c.containersByID())
+	assert.Len(t, components, 5)
+	assert.Empty(t, c.GetWarnings())
+}
+
+func TestProcessTopologyRun(t *testing.T) {
+	config.Datadog.Set("check_transactionality_enabled", false)
+	defer config.Datadog.Set("check_transactionality_enabled", true)
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+	mockBatcher := batcher.NewMockBatcher()
+	defer mockBatcher.Shutdown()
+
+	c := makeTestCheck()
+	assert.NoError(t, c.Run())
+
+	actualTopology := mockBatcher.CollectedTopology.Flush()[c.ID()].Topology
+	assert.True(t, actualTopology.StartSnapshot)
+	assert.True(t, actualTopology.StopSnapshot)
+	assert.Equal(t, topology.Instance{Type: "process", URL: "agents"}, actualTopology.Instance)
+	assert.Len(t, actualTopology.Components, 5)
+	assert.Len(t, actualTopology.Relations, 9)
+}
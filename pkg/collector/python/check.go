@@ -167,6 +167,10 @@ func (c *PythonCheck) ConfigSource() string {
 func (c *PythonCheck) GetWarnings() []error {
 	warnings := c.lastWarnings
 	c.lastWarnings = []error{}
+	// [sts] report the warnings of the check handler, i.e. problems found in the submitted topology and health
+	if handler.GetCheckManager() != nil {
+		warnings = append(warnings, handler.GetCheckManager().GetCheckWarnings(c.id)...)
+	}
 	return warnings
 }
 
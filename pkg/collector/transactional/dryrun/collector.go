@@ -0,0 +1,249 @@
+// Package dryrun collects the topology, health and raw metrics that a check submits when it is run with the agent check
+// command, to review them as a graph and a summary without sending them to StackState. [sts]
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+// deletedHealth is the health that is counted for check states that are deleted
+const deletedHealth = "DELETED"
+
+// Collector keeps everything a check submitted, a component or relation that is submitted more than once is kept once
+// with its last submitted value
+type Collector struct {
+	mux        sync.Mutex
+	components map[string]topology.Component
+	relations  map[string]topology.Relation
+	deleteIDs  map[string]bool
+	health     map[string]*streamHealth
+	metrics    map[string]int
+	events     int
+}
+
+// streamHealth are the check states submitted for a health stream, by check state id
+type streamHealth struct {
+	stream      health.Stream
+	checkStates map[string]checkState
+}
+
+// checkState is the part of a submitted check state that is summarized and rendered
+type checkState struct {
+	health                    string
+	topologyElementIdentifier string
+}
+
+// NewCollector returns an empty Collector
+func NewCollector() *Collector {
+	return &Collector{
+		components: make(map[string]topology.Component),
+		relations:  make(map[string]topology.Relation),
+		deleteIDs:  make(map[string]bool),
+		health:     make(map[string]*streamHealth),
+		metrics:    make(map[string]int),
+	}
+}
+
+// AddJSON collects a serialized intake payload
+func (c *Collector) AddJSON(body []byte) error {
+	payload := transactional.NewIntakePayload()
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("could not read the intake payload: %s", err)
+	}
+	c.Add(payload)
+	return nil
+}
+
+// Add collects the topology, health, raw metrics and events of an intake payload
+func (c *Collector) Add(payload transactional.IntakePayload) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, topo := range payload.Topologies {
+		for _, component := range topo.Components {
+			c.components[component.ExternalID] = component
+		}
+		for _, relation := range topo.Relations {
+			c.relations[relation.ExternalID] = relation
+		}
+		for _, deleteID := range topo.DeleteIDs {
+			c.deleteIDs[deleteID] = true
+		}
+	}
+
+	for _, h := range payload.Health {
+		stream, found := c.health[h.Stream.GoString()]
+		if !found {
+			stream = &streamHealth{stream: h.Stream, checkStates: make(map[string]checkState)}
+			c.health[h.Stream.GoString()] = stream
+		}
+		for _, data := range h.CheckStates {
+			id, state := readCheckState(data)
+			stream.checkStates[id] = state
+		}
+	}
+
+	for _, metric := range payload.Metrics {
+		c.metrics[metricName(metric)]++
+	}
+
+	for _, events := range payload.Events {
+		c.events += len(events)
+	}
+}
+
+// Summary counts what was collected
+func (c *Collector) Summary() Summary {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	summary := Summary{
+		Components:        make(map[string]int),
+		Relations:         make(map[string]int),
+		Deletes:           len(c.deleteIDs),
+		DanglingRelations: c.danglingRelations(),
+		Health:            make([]StreamSummary, 0, len(c.health)),
+		Metrics:           make(map[string]int, len(c.metrics)),
+		Events:            c.events,
+	}
+	for name, count := range c.metrics {
+		summary.Metrics[name] = count
+	}
+	for _, component := range c.components {
+		summary.Components[component.Type.Name]++
+	}
+	for _, relation := range c.relations {
+		summary.Relations[relation.Type.Name]++
+	}
+	for _, stream := range c.sortedStreams() {
+		streamSummary := StreamSummary{
+			Stream:      stream.stream,
+			CheckStates: len(stream.checkStates),
+			Health:      make(map[string]int),
+		}
+		for _, state := range stream.checkStates {
+			streamSummary.Health[state.health]++
+		}
+		summary.Health = append(summary.Health, streamSummary)
+	}
+	return summary
+}
+
+// sortedStreams returns the health streams ordered by their urn and sub stream
+func (c *Collector) sortedStreams() []*streamHealth {
+	streams := make([]*streamHealth, 0, len(c.health))
+	for _, stream := range c.health {
+		streams = append(streams, stream)
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].stream.GoString() < streams[j].stream.GoString()
+	})
+	return streams
+}
+
+// danglingRelations returns the external ids of the relations of which the source or target was not submitted as the
+// external id or an identifier of a component
+func (c *Collector) danglingRelations() []string {
+	identifiers := c.identifiers()
+	dangling := make([]string, 0)
+	for externalID, relation := range c.relations {
+		if !identifiers[relation.SourceID] || !identifiers[relation.TargetID] {
+			dangling = append(dangling, externalID)
+		}
+	}
+	sort.Strings(dangling)
+	return dangling
+}
+
+// identifiers returns the external ids and identifiers of the components that relations can refer to
+func (c *Collector) identifiers() map[string]bool {
+	identifiers := make(map[string]bool, len(c.components))
+	for externalID, component := range c.components {
+		identifiers[externalID] = true
+		for _, identifier := range componentIdentifiers(component) {
+			identifiers[identifier] = true
+		}
+	}
+	return identifiers
+}
+
+// elementHealth returns the worst health of the check states of every topology element
+func (c *Collector) elementHealth() map[string]string {
+	elementHealth := make(map[string]string)
+	for _, stream := range c.health {
+		for _, state := range stream.checkStates {
+			if healthSeverity(state.health) > healthSeverity(elementHealth[state.topologyElementIdentifier]) {
+				elementHealth[state.topologyElementIdentifier] = state.health
+			}
+		}
+	}
+	return elementHealth
+}
+
+// readCheckState reads the id and state of health check data, which is unstructured when it is read from a payload
+func readCheckState(data health.CheckData) (string, checkState) {
+	switch {
+	case data.CheckState != nil:
+		return data.CheckState.CheckStateID, checkState{
+			health:                    string(data.CheckState.Health),
+			topologyElementIdentifier: data.CheckState.TopologyElementIdentifier,
+		}
+	case data.CheckStateDeleted != nil:
+		return data.CheckStateDeleted.CheckStateID, checkState{health: deletedHealth}
+	}
+
+	id, _ := data.Unstructured["checkStateId"].(string)
+	if deleted, _ := data.Unstructured["delete"].(bool); deleted {
+		return id, checkState{health: deletedHealth}
+	}
+	state, _ := data.Unstructured["health"].(string)
+	element, _ := data.Unstructured["topologyElementIdentifier"].(string)
+	return id, checkState{health: state, topologyElementIdentifier: element}
+}
+
+// componentIdentifiers returns the identifiers of a component, these are a []interface{} when read from a payload
+func componentIdentifiers(component topology.Component) []string {
+	switch identifiers := component.Data["identifiers"].(type) {
+	case []string:
+		return identifiers
+	case []interface{}:
+		result := make([]string, 0, len(identifiers))
+		for _, identifier := range identifiers {
+			if s, ok := identifier.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// metricName returns the name of a raw metric, which is the first field of an intake metric
+func metricName(metric interface{}) string {
+	if fields, ok := metric.([]interface{}); ok && len(fields) > 0 {
+		if name, ok := fields[0].(string); ok {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// healthSeverity orders the health states from no health to critical
+func healthSeverity(state string) int {
+	switch health.State(state) {
+	case health.Clear:
+		return 1
+	case health.Deviating:
+		return 2
+	case health.Critical:
+		return 3
+	}
+	return 0
+}
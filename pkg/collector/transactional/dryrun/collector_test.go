@@ -0,0 +1,161 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var stream = health.Stream{Urn: "urn:health:mycheck:myhost"}
+
+func testPayload() transactional.IntakePayload {
+	payload := transactional.NewIntakePayload()
+	payload.Topologies = append(payload.Topologies, topology.Topology{
+		StartSnapshot: true,
+		StopSnapshot:  true,
+		Instance:      topology.Instance{Type: "mycheck", URL: "myhost"},
+		Components: []topology.Component{
+			{ExternalID: "urn:host:/myhost", Type: topology.Type{Name: "host"}, Data: topology.Data{"name": "myhost"}},
+			{ExternalID: "urn:process:/myhost:1", Type: topology.Type{Name: "process"},
+				Data: topology.Data{"name": "nginx", "identifiers": []string{"urn:process:/nginx"}}},
+		},
+		Relations: []topology.Relation{
+			{ExternalID: "urn:process:/nginx->urn:host:/myhost", SourceID: "urn:process:/nginx",
+				TargetID: "urn:host:/myhost", Type: topology.Type{Name: "executed_on"}},
+			{ExternalID: "urn:process:/myhost:1->urn:service:/unknown", SourceID: "urn:process:/myhost:1",
+				TargetID: "urn:service:/unknown", Type: topology.Type{Name: "calls"}},
+		},
+		DeleteIDs: []string{"urn:process:/myhost:0"},
+	})
+	payload.Health = append(payload.Health, health.Health{
+		Stream: stream,
+		CheckStates: []health.CheckData{
+			{CheckState: &health.CheckState{CheckStateID: "cpu", Health: health.Clear, TopologyElementIdentifier: "urn:host:/myhost"}},
+			{CheckState: &health.CheckState{CheckStateID: "disk", Health: health.Critical, TopologyElementIdentifier: "urn:host:/myhost"}},
+			{CheckStateDeleted: &health.CheckStateDeleted{CheckStateID: "memory", Delete: true}},
+		},
+	})
+	payload.Metrics = append(payload.Metrics,
+		telemetry.RawMetrics{Name: "nginx.requests", Value: 1}.IntakeMetricJSON(),
+		telemetry.RawMetrics{Name: "nginx.requests", Value: 2}.IntakeMetricJSON(),
+	)
+	payload.Events["nginx"] = []metrics.Event{{Title: "restarted"}}
+	return payload
+}
+
+func TestCollectorSummary(t *testing.T) {
+	collector := NewCollector()
+	collector.Add(testPayload())
+	// a component that is submitted again is counted once
+	body, err := json.Marshal(testPayload())
+	require.NoError(t, err)
+	require.NoError(t, collector.AddJSON(body))
+
+	assert.Equal(t, Summary{
+		Components:        map[string]int{"host": 1, "process": 1},
+		Relations:         map[string]int{"executed_on": 1, "calls": 1},
+		Deletes:           1,
+		DanglingRelations: []string{"urn:process:/myhost:1->urn:service:/unknown"},
+		Health: []StreamSummary{
+			{Stream: stream, CheckStates: 3, Health: map[string]int{"CLEAR": 1, "CRITICAL": 1, "DELETED": 1}},
+		},
+		Metrics: map[string]int{"nginx.requests": 4},
+		Events:  2,
+	}, collector.Summary())
+
+	assert.Error(t, collector.AddJSON([]byte("not json")))
+}
+
+func TestCollectorWriteDot(t *testing.T) {
+	collector := NewCollector()
+	collector.Add(testPayload())
+
+	var b bytes.Buffer
+	require.NoError(t, collector.WriteGraph(&b, FormatDot))
+	assert.Equal(t, `digraph topology {
+  rankdir=LR;
+  node [shape=box];
+  "urn:host:/myhost" [label="myhost\n(host)", color=red];
+  "urn:process:/myhost:1" [label="nginx\n(process)"];
+  "urn:service:/unknown" [label="urn:service:/unknown\n(not submitted)", style=dashed];
+  "urn:process:/myhost:1" -> "urn:service:/unknown" [label="calls"];
+  "urn:process:/myhost:1" -> "urn:host:/myhost" [label="executed_on"];
+}
+`, b.String())
+}
+
+func TestCollectorWriteGraphML(t *testing.T) {
+	collector := NewCollector()
+	collector.Add(testPayload())
+
+	var b bytes.Buffer
+	require.NoError(t, collector.WriteGraph(&b, FormatGraphML))
+
+	var doc graphML
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &doc))
+	assert.Len(t, doc.Keys, 4)
+	assert.Equal(t, []graphMLNode{
+		{ID: "urn:host:/myhost", Data: []graphMLData{{Key: "name", Value: "myhost"}, {Key: "type", Value: "host"}, {Key: "health", Value: "CRITICAL"}}},
+		{ID: "urn:process:/myhost:1", Data: []graphMLData{{Key: "name", Value: "nginx"}, {Key: "type", Value: "process"}}},
+		{ID: "urn:service:/unknown", Data: []graphMLData{{Key: "name", Value: "urn:service:/unknown"}, {Key: "missing", Value: "true"}}},
+	}, doc.Graph.Nodes)
+	assert.Equal(t, []graphMLEdge{
+		{ID: "urn:process:/myhost:1->urn:service:/unknown", Source: "urn:process:/myhost:1", Target: "urn:service:/unknown",
+			Data: []graphMLData{{Key: "type", Value: "calls"}}},
+		{ID: "urn:process:/nginx->urn:host:/myhost", Source: "urn:process:/myhost:1", Target: "urn:host:/myhost",
+			Data: []graphMLData{{Key: "type", Value: "executed_on"}}},
+	}, doc.Graph.Edges)
+}
+
+func TestCollectorWriteJSONSummary(t *testing.T) {
+	collector := NewCollector()
+	collector.Add(testPayload())
+
+	var b bytes.Buffer
+	require.NoError(t, collector.WriteGraph(&b, FormatJSONSummary))
+	var summary Summary
+	require.NoError(t, json.Unmarshal(b.Bytes(), &summary))
+	assert.Equal(t, collector.Summary(), summary)
+}
+
+func TestSummaryWriteTable(t *testing.T) {
+	collector := NewCollector()
+	collector.Add(testPayload())
+
+	var b bytes.Buffer
+	require.NoError(t, collector.Summary().WriteTable(&b))
+	assert.Equal(t, `KIND        TYPE / NAME     COUNT
+component   host            1
+component   process         1
+relation    calls           1
+relation    executed_on     1
+delete                      1
+raw metric  nginx.requests  2
+event                       1
+
+HEALTH STREAM              SUB STREAM  CHECK STATES
+urn:health:mycheck:myhost              3 (CLEAR: 1, CRITICAL: 1, DELETED: 1)
+
+DANGLING RELATIONS
+urn:process:/myhost:1->urn:service:/unknown
+`, b.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("graphml")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatGraphML, format)
+	assert.Equal(t, ".graphml", format.Extension())
+
+	_, err = ParseFormat("svg")
+	assert.EqualError(t, err, "unknown topology format 'svg', expected one of dot, graphml or json-summary")
+}
@@ -0,0 +1,254 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+// Format is a format the collected topology is written in
+type Format string
+
+const (
+	// FormatDot is a Graphviz dot graph of the components and relations, colored by their health
+	FormatDot Format = "dot"
+	// FormatGraphML is a GraphML graph of the components and relations, with their type and health
+	FormatGraphML Format = "graphml"
+	// FormatJSONSummary is the Summary as JSON
+	FormatJSONSummary Format = "json-summary"
+)
+
+// ParseFormat returns the Format with the given name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatDot, FormatGraphML, FormatJSONSummary:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown topology format '%s', expected one of %s, %s or %s", name, FormatDot,
+		FormatGraphML, FormatJSONSummary)
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatGraphML:
+		return ".graphml"
+	case FormatJSONSummary:
+		return ".json"
+	}
+	return ".dot"
+}
+
+// node is a component, or the missing source or target of a dangling relation, in the graph
+type node struct {
+	id, name, typeName, health string
+	missing                    bool
+}
+
+// edge is a relation in the graph between the nodes of its source and target
+type edge struct {
+	id, source, target, typeName string
+}
+
+// WriteGraph writes what was collected in the given format
+func (c *Collector) WriteGraph(w io.Writer, format Format) error {
+	if format == FormatJSONSummary {
+		b, err := json.MarshalIndent(c.Summary(), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	nodes, edges := c.graph()
+	if format == FormatGraphML {
+		return writeGraphML(w, nodes, edges)
+	}
+	return writeDot(w, nodes, edges)
+}
+
+// graph returns the nodes and edges ordered by their id. The source and target of relations that refer to an identifier
+// of a component are resolved to that component.
+func (c *Collector) graph() ([]node, []edge) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	elementHealth := c.elementHealth()
+	componentIDs := make(map[string]string, len(c.components))
+	nodesByID := make(map[string]node, len(c.components))
+	for externalID, component := range c.components {
+		name, _ := component.Data["name"].(string)
+		if name == "" {
+			name = externalID
+		}
+		nodesByID[externalID] = node{id: externalID, name: name, typeName: component.Type.Name, health: elementHealth[externalID]}
+		componentIDs[externalID] = externalID
+	}
+	for externalID, component := range c.components {
+		for _, identifier := range componentIdentifiers(component) {
+			if _, found := componentIDs[identifier]; !found {
+				componentIDs[identifier] = externalID
+			}
+		}
+	}
+
+	// resolve returns the component of a relation endpoint, or adds a missing node when it was not submitted
+	resolve := func(id string) string {
+		if componentID, found := componentIDs[id]; found {
+			return componentID
+		}
+		nodesByID[id] = node{id: id, name: id, missing: true}
+		return id
+	}
+
+	edges := make([]edge, 0, len(c.relations))
+	for externalID, relation := range c.relations {
+		edges = append(edges, edge{
+			id:       externalID,
+			source:   resolve(relation.SourceID),
+			target:   resolve(relation.TargetID),
+			typeName: relation.Type.Name,
+		})
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].id < edges[j].id })
+
+	nodes := make([]node, 0, len(nodesByID))
+	for _, n := range nodesByID {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+	return nodes, edges
+}
+
+func writeDot(w io.Writer, nodes []node, edges []edge) error {
+	var b strings.Builder
+	b.WriteString("digraph topology {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range nodes {
+		if n.missing {
+			fmt.Fprintf(&b, "  %s [label=%s, style=dashed];\n", dotQuote(n.id), dotQuote(n.name+"\n(not submitted)"))
+			continue
+		}
+		attributes := fmt.Sprintf("label=%s", dotQuote(fmt.Sprintf("%s\n(%s)", n.name, n.typeName)))
+		if color := dotColor(n.health); color != "" {
+			attributes += fmt.Sprintf(", color=%s", color)
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.id), attributes)
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.source), dotQuote(e.target), dotQuote(e.typeName))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns a quoted dot string, in which newlines are line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func dotColor(state string) string {
+	switch health.State(state) {
+	case health.Clear:
+		return "green"
+	case health.Deviating:
+		return "orange"
+	case health.Critical:
+		return "red"
+	}
+	return ""
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func writeGraphML(w io.Writer, nodes []node, edges []edge) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "type", For: "all", AttrName: "type", AttrType: "string"},
+			{ID: "health", For: "node", AttrName: "health", AttrType: "string"},
+			{ID: "missing", For: "node", AttrName: "missing", AttrType: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "topology", EdgeDefault: "directed"},
+	}
+	for _, n := range nodes {
+		data := []graphMLData{{Key: "name", Value: n.name}}
+		if n.missing {
+			data = append(data, graphMLData{Key: "missing", Value: "true"})
+		} else {
+			data = append(data, graphMLData{Key: "type", Value: n.typeName})
+		}
+		if n.health != "" {
+			data = append(data, graphMLData{Key: "health", Value: n.health})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: n.id, Data: data})
+	}
+	for _, e := range edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     e.id,
+			Source: e.source,
+			Target: e.target,
+			Data:   []graphMLData{{Key: "type", Value: e.typeName}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,89 @@
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+// Summary counts the topology, health and raw metrics submitted by a check
+type Summary struct {
+	// Components are the number of components by type
+	Components map[string]int `json:"components"`
+	// Relations are the number of relations by type
+	Relations map[string]int `json:"relations"`
+	// Deletes is the number of deleted topology elements
+	Deletes int `json:"deletes"`
+	// DanglingRelations are the external ids of the relations of which the source or target was not submitted
+	DanglingRelations []string `json:"dangling_relations"`
+	// Health are the check states of every health stream
+	Health []StreamSummary `json:"health"`
+	// Metrics are the number of raw metric values by name
+	Metrics map[string]int `json:"metrics"`
+	// Events is the number of submitted events
+	Events int `json:"events"`
+}
+
+// StreamSummary counts the check states submitted for a health stream
+type StreamSummary struct {
+	Stream      health.Stream  `json:"stream"`
+	CheckStates int            `json:"check_states"`
+	Health      map[string]int `json:"health"`
+}
+
+// WriteTable writes the summary as a table of the counts, a table of the health streams and the dangling relations
+func (s Summary) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tTYPE / NAME\tCOUNT")
+	for _, typeName := range sortedCounts(s.Components) {
+		fmt.Fprintf(tw, "component\t%s\t%d\n", typeName, s.Components[typeName])
+	}
+	for _, typeName := range sortedCounts(s.Relations) {
+		fmt.Fprintf(tw, "relation\t%s\t%d\n", typeName, s.Relations[typeName])
+	}
+	fmt.Fprintf(tw, "delete\t\t%d\n", s.Deletes)
+	for _, name := range sortedCounts(s.Metrics) {
+		fmt.Fprintf(tw, "raw metric\t%s\t%d\n", name, s.Metrics[name])
+	}
+	fmt.Fprintf(tw, "event\t\t%d\n", s.Events)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HEALTH STREAM\tSUB STREAM\tCHECK STATES")
+	for _, stream := range s.Health {
+		states := make([]string, 0, len(stream.Health))
+		for _, state := range sortedCounts(stream.Health) {
+			states = append(states, fmt.Sprintf("%s: %d", state, stream.Health[state]))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d (%s)\n", stream.Stream.Urn, stream.Stream.SubStream, stream.CheckStates,
+			strings.Join(states, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(s.DanglingRelations) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "DANGLING RELATIONS")
+		for _, externalID := range s.DanglingRelations {
+			fmt.Fprintln(w, externalID)
+		}
+	}
+	return nil
+}
+
+func sortedCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,6 +3,7 @@ package transactional
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/health"
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
@@ -19,6 +20,7 @@ const (
 type PayloadTransaction struct {
 	ActionID             string
 	CompletedTransaction bool
+	CheckID              check.ID
 }
 
 // IntakePayload is a Go representation of the Receiver Intake structure
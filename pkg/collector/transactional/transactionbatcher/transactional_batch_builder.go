@@ -8,6 +8,16 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
 	"github.com/StackVista/stackstate-agent/pkg/telemetry"
 	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+var (
+	tlmCheckBytes = telemetry.NewCounter("transactional_batcher", "check_bytes",
+		[]string{"check"}, "Serialized size in bytes of the data batched per check")
+	tlmCheckSizeFlushes = telemetry.NewCounter("transactional_batcher", "check_size_flushes",
+		[]string{"check"}, "Count of flushes triggered by the maximum payload size per check")
+	tlmCheckOversizedElements = telemetry.NewCounter("transactional_batcher", "check_oversized_elements",
+		[]string{"check"}, "Count of topology, health, metric or event elements larger than the maximum payload size per check")
 )
 
 // BatchTransaction keeps state of the transaction for a given check
@@ -45,14 +55,25 @@ type TransactionBatchBuilder struct {
 	elementCount int
 	// Amount of elements when we flush
 	maxCapacity int
+	// Serialized size in bytes of the elements we gathered
+	payloadSize int
+	// Serialized size in bytes of the elements when we flush, 0 disables flushing on size
+	maxPayloadSize int
 }
 
 // NewTransactionalBatchBuilder constructs a TransactionBatchBuilder
 func NewTransactionalBatchBuilder(maxCapacity int) TransactionBatchBuilder {
+	return NewSizedTransactionalBatchBuilder(maxCapacity, 0)
+}
+
+// NewSizedTransactionalBatchBuilder constructs a TransactionBatchBuilder that also flushes when the serialized size of
+// the gathered elements reaches maxPayloadSize bytes
+func NewSizedTransactionalBatchBuilder(maxCapacity, maxPayloadSize int) TransactionBatchBuilder {
 	return TransactionBatchBuilder{
-		states:       make(map[check.ID]TransactionCheckInstanceBatchState),
-		elementCount: 0,
-		maxCapacity:  maxCapacity,
+		states:         make(map[check.ID]TransactionCheckInstanceBatchState),
+		elementCount:   0,
+		maxCapacity:    maxCapacity,
+		maxPayloadSize: maxPayloadSize,
 	}
 }
 
@@ -150,16 +171,18 @@ func (builder *TransactionBatchBuilder) getOrCreateEvents(checkID check.ID, tran
 
 // AddComponent adds a component
 func (builder *TransactionBatchBuilder) AddComponent(checkID check.ID, transactionID string, instance topology.Instance, component topology.Component) TransactionCheckInstanceBatchStates {
-	topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
-	topologyData.Components = append(topologyData.Components, component)
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, component, func() {
+		topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
+		topologyData.Components = append(topologyData.Components, component)
+	})
 }
 
 // AddRelation adds a relation
 func (builder *TransactionBatchBuilder) AddRelation(checkID check.ID, transactionID string, instance topology.Instance, relation topology.Relation) TransactionCheckInstanceBatchStates {
-	topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
-	topologyData.Relations = append(topologyData.Relations, relation)
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, relation, func() {
+		topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
+		topologyData.Relations = append(topologyData.Relations, relation)
+	})
 }
 
 // TopologyStartSnapshot starts a snapshot
@@ -178,17 +201,19 @@ func (builder *TransactionBatchBuilder) TopologyStopSnapshot(checkID check.ID, t
 
 // Delete deletes a topology element
 func (builder *TransactionBatchBuilder) Delete(checkID check.ID, transactionID string, instance topology.Instance, deleteID string) TransactionCheckInstanceBatchStates {
-	topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
-	topologyData.DeleteIDs = append(topologyData.DeleteIDs, deleteID)
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, deleteID, func() {
+		topologyData := builder.getOrCreateTopology(checkID, transactionID, instance)
+		topologyData.DeleteIDs = append(topologyData.DeleteIDs, deleteID)
+	})
 }
 
 // AddHealthCheckData adds a component
 func (builder *TransactionBatchBuilder) AddHealthCheckData(checkID check.ID, transactionID string, stream health.Stream, data health.CheckData) TransactionCheckInstanceBatchStates {
-	healthData := builder.getOrCreateHealth(checkID, transactionID, stream)
-	healthData.CheckStates = append(healthData.CheckStates, data)
-	builder.states[checkID].Health[stream.GoString()] = healthData
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, data, func() {
+		healthData := builder.getOrCreateHealth(checkID, transactionID, stream)
+		healthData.CheckStates = append(healthData.CheckStates, data)
+		builder.states[checkID].Health[stream.GoString()] = healthData
+	})
 }
 
 // HealthStartSnapshot starts a Health snapshot
@@ -212,16 +237,18 @@ func (builder *TransactionBatchBuilder) HealthStopSnapshot(checkID check.ID, tra
 
 // AddRawMetricsData adds raw metric data
 func (builder *TransactionBatchBuilder) AddRawMetricsData(checkID check.ID, transactionID string, rawMetric telemetry.RawMetrics) TransactionCheckInstanceBatchStates {
-	rawMetricsData := builder.getOrCreateRawMetrics(checkID, transactionID)
-	rawMetricsData.Values = append(rawMetricsData.Values, rawMetric)
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, rawMetric, func() {
+		rawMetricsData := builder.getOrCreateRawMetrics(checkID, transactionID)
+		rawMetricsData.Values = append(rawMetricsData.Values, rawMetric)
+	})
 }
 
 // AddEvent adds a event
 func (builder *TransactionBatchBuilder) AddEvent(checkID check.ID, transactionID string, event metrics.Event) TransactionCheckInstanceBatchStates {
-	events := builder.getOrCreateEvents(checkID, transactionID)
-	events.Events = append(events.Events, event)
-	return builder.incrementAndTryFlush()
+	return builder.addElementAndTryFlush(checkID, event, func() {
+		events := builder.getOrCreateEvents(checkID, transactionID)
+		events.Events = append(events.Events, event)
+	})
 }
 
 // Flush the collected data. Returning the data and wiping the current build up Topology
@@ -229,6 +256,7 @@ func (builder *TransactionBatchBuilder) Flush() TransactionCheckInstanceBatchSta
 	data := builder.states
 	builder.states = make(map[check.ID]TransactionCheckInstanceBatchState)
 	builder.elementCount = 0
+	builder.payloadSize = 0
 	return data
 }
 
@@ -242,6 +270,54 @@ func (builder *TransactionBatchBuilder) incrementAndTryFlush() TransactionCheckI
 	return nil
 }
 
+// addElementAndTryFlush adds an element to the batch using the add function. When the element does not fit within the
+// maximum payload size, the data gathered so far is flushed first and the element starts the next batch, which
+// results in another action within the same transactions.
+func (builder *TransactionBatchBuilder) addElementAndTryFlush(checkID check.ID, element interface{}, add func()) TransactionCheckInstanceBatchStates {
+	size := builder.elementSize(checkID, element)
+
+	var flushed TransactionCheckInstanceBatchStates
+	if builder.elementCount > 0 && builder.payloadSize+size > builder.maxPayloadSize && builder.maxPayloadSize > 0 {
+		log.Debugf("Flushing transactional batch of %d bytes, adding %d bytes for check %s exceeds the maximum payload size of %d bytes",
+			builder.payloadSize, size, checkID, builder.maxPayloadSize)
+		tlmCheckSizeFlushes.Inc(string(checkID))
+		flushed = builder.Flush()
+	}
+
+	add()
+	builder.payloadSize = builder.payloadSize + size
+
+	if flushed != nil {
+		builder.elementCount = builder.elementCount + 1
+		return flushed
+	}
+
+	return builder.incrementAndTryFlush()
+}
+
+// elementSize returns the serialized size of an element, it is only measured when there is a maximum payload size
+func (builder *TransactionBatchBuilder) elementSize(checkID check.ID, element interface{}) int {
+	if builder.maxPayloadSize <= 0 {
+		return 0
+	}
+
+	data, err := json.Marshal(element)
+	if err != nil {
+		// the error surfaces when the payload is serialized, it is not accounted for here
+		return 0
+	}
+
+	size := len(data)
+	tlmCheckBytes.Add(float64(size), string(checkID))
+	if size > builder.maxPayloadSize {
+		_ = log.Warnf("Check %s produced an element of %d bytes, which exceeds the maximum payload size of %d bytes",
+			checkID, size, builder.maxPayloadSize)
+		tlmCheckOversizedElements.Inc(string(checkID))
+	}
+
+	return size
+}
+
 // StartTransaction creates a batch transaction for the given check ID
 func (builder *TransactionBatchBuilder) StartTransaction(checkID check.ID, transactionID string) TransactionCheckInstanceBatchStates {
 	state := builder.getOrCreateState(checkID, transactionID)
@@ -8,6 +8,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/StackVista/stackstate-agent/pkg/metrics"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"github.com/google/uuid"
 	"sync"
@@ -16,6 +17,12 @@ import (
 var (
 	batcherInstance TransactionalBatcher
 	batcherInit     *sync.Once
+
+	tlmPayloadBytes = telemetry.NewHistogram("transactional_batcher", "payload_bytes",
+		[]string{}, "Serialized size in bytes of the transactional payloads",
+		[]float64{1024, 16 * 1024, 64 * 1024, 256 * 1024, 1024 * 1024, 4 * 1024 * 1024, 16 * 1024 * 1024})
+	tlmCheckPayloads = telemetry.NewCounter("transactional_batcher", "check_payloads",
+		[]string{"check"}, "Count of transactional payloads containing data of a check")
 )
 
 func init() {
@@ -44,12 +51,14 @@ func NewMockTransactionalBatcher() *MockTransactionalBatcher {
 
 // newTransactionalBatcher returns an instance of the transactionalBatcher and starts listening for submissions
 func newTransactionalBatcher(hostname, agentName string, maxCapacity int) *transactionalBatcher {
+	maxPayloadSize := config.Datadog.GetInt("transactional_batcher_max_payload_size_bytes")
 	ctb := &transactionalBatcher{
-		Hostname:    hostname,
-		agentName:   agentName,
-		Input:       make(chan interface{}, maxCapacity),
-		builder:     NewTransactionalBatchBuilder(maxCapacity),
-		maxCapacity: maxCapacity,
+		Hostname:       hostname,
+		agentName:      agentName,
+		Input:          make(chan interface{}, maxCapacity),
+		builder:        NewSizedTransactionalBatchBuilder(maxCapacity, maxPayloadSize),
+		maxCapacity:    maxCapacity,
+		maxPayloadSize: maxPayloadSize,
 	}
 
 	go ctb.Start()
@@ -63,6 +72,7 @@ type transactionalBatcher struct {
 	Input               chan interface{}
 	builder             TransactionBatchBuilder
 	maxCapacity         int
+	maxPayloadSize      int
 }
 
 // Start starts the transactional transactionbatcher
@@ -127,6 +137,17 @@ func (ctb *transactionalBatcher) SubmitState(states TransactionCheckInstanceBatc
 			}
 		}
 
+		// The builder flushes before the payload size is exceeded, but data of multiple checks can still add up to a
+		// payload that is too large. Send the data of every check in a payload of its own in that case.
+		if ctb.maxPayloadSize > 0 && len(payload) > ctb.maxPayloadSize && len(states) > 1 {
+			log.Debugf("Splitting transactional payload of %d bytes for %d checks, it exceeds the maximum payload size of %d bytes",
+				len(payload), len(states), ctb.maxPayloadSize)
+			for checkID, state := range states {
+				ctb.SubmitState(TransactionCheckInstanceBatchStates{checkID: state})
+			}
+			return
+		}
+
 		// Catering for the edge case where the data produced for a given check and transaction was published in a
 		// previous payload. There is a very likely possibility that this payload is still in the forwarder being sent
 		// to StackState. The best way to guarantee that we're not prematurely marking a transaction as complete is to
@@ -152,11 +173,12 @@ func (ctb *transactionalBatcher) SubmitState(states TransactionCheckInstanceBatc
 
 		// create a transaction -> action map that can be used to acknowledge / reject actions
 		transactionPayloadMap := make(map[string]transactional.PayloadTransaction, len(states))
-		for _, state := range states {
+		for checkID, state := range states {
 			actionID := uuid.New().String()
 			transactionPayloadMap[state.Transaction.TransactionID] = transactional.PayloadTransaction{
 				ActionID:             actionID,
 				CompletedTransaction: state.Transaction.CompletedTransaction,
+				CheckID:              checkID,
 			}
 			// commit an action for each of the transactions in this transactionbatcher state
 			transactionmanager.GetTransactionManager().CommitAction(state.Transaction.TransactionID, actionID)
@@ -164,6 +186,11 @@ func (ctb *transactionalBatcher) SubmitState(states TransactionCheckInstanceBatc
 
 		log.Debugf("Marshalled payload for transactions: %v, payload: %s", transactionPayloadMap, string(payload))
 
+		tlmPayloadBytes.Observe(float64(len(payload)))
+		for checkID := range states {
+			tlmCheckPayloads.Inc(string(checkID))
+		}
+
 		ctb.submitPayload(payload, transactionPayloadMap)
 	}
 }
@@ -190,9 +190,13 @@ func testBatcher(t *testing.T, transactionState map[string]bool, expectedPayload
 	// assert the transaction map produced by the batcher contains the correct action id and completed status
 	expectedTransactionMap := make(map[string]transactional.PayloadTransaction, len(commitActions))
 	for i, ca := range commitActions {
+		// every action in the payload should be attributed to the check that produced it
+		actualCheckID := payload.TransactionActionMap[ca.TransactionID].CheckID
+		assert.NotEmpty(t, actualCheckID)
 		expectedTransactionMap[ca.TransactionID] = transactional.PayloadTransaction{
 			ActionID:             ca.ActionID,
 			CompletedTransaction: transactionState[i],
+			CheckID:              actualCheckID,
 		}
 	}
 
@@ -721,3 +725,109 @@ func TestBatchClearState(t *testing.T) {
 	batcher.Stop()
 
 }
+
+func TestBatchFlushOnMaxPayloadSize(t *testing.T) {
+	componentSize, _ := json.Marshal(testComponent)
+	component2Size, _ := json.Marshal(testComponent2)
+	config.Datadog.Set("transactional_batcher_max_payload_size_bytes", len(componentSize)+len(component2Size))
+	defer config.Datadog.Set("transactional_batcher_max_payload_size_bytes", config.DefaultTxBatcherMaxPayloadSizeBytes)
+
+	batcher := newTransactionalBatcher(testHost, testAgent, 100)
+
+	batcher.SubmitComponent(testID, testTransactionID, testInstance, testComponent)
+	batcher.SubmitComponent(testID, testTransactionID, testInstance, testComponent2)
+	// the relation does not fit in the payload anymore, the components are flushed in an action of their own
+	batcher.SubmitRelation(testID, testTransactionID, testInstance, testRelation)
+
+	expectedPayload := transactional.NewIntakePayload()
+	expectedPayload.InternalHostname = "myhost"
+	expectedPayload.Topologies = []topology.Topology{
+		{
+			StartSnapshot: false,
+			StopSnapshot:  false,
+			Instance:      testInstance,
+			Components:    []topology.Component{testComponent, testComponent2},
+			Relations:     []topology.Relation{},
+			DeleteIDs:     []string{},
+		},
+	}
+
+	testBatcher(t, map[string]bool{testTransactionID: false}, expectedPayload)
+
+	// the relation is sent in a second action of the same transaction
+	batcher.SubmitCompleteTransaction(testID, testTransactionID)
+
+	expectedPayload = transactional.NewIntakePayload()
+	expectedPayload.InternalHostname = "myhost"
+	expectedPayload.Topologies = []topology.Topology{
+		{
+			StartSnapshot: false,
+			StopSnapshot:  false,
+			Instance:      testInstance,
+			Components:    []topology.Component{},
+			Relations:     []topology.Relation{testRelation},
+			DeleteIDs:     []string{},
+		},
+	}
+
+	testBatcher(t, map[string]bool{testTransactionID: true}, expectedPayload)
+
+	batcher.Stop()
+}
+
+func TestBatchSplitPayloadPerCheck(t *testing.T) {
+	componentSize, _ := json.Marshal(testComponent)
+	component2Size, _ := json.Marshal(testComponent2)
+	// both components fit in the batch, but not in a single payload together with the payload envelope
+	maxPayloadSize := len(componentSize) + len(component2Size) + 10
+	config.Datadog.Set("transactional_batcher_max_payload_size_bytes", maxPayloadSize)
+	defer config.Datadog.Set("transactional_batcher_max_payload_size_bytes", config.DefaultTxBatcherMaxPayloadSizeBytes)
+
+	tm := transactionmanager.GetTransactionManager().(*transactionmanager.MockTransactionManager)
+	fwd := transactionforwarder.GetTransactionalForwarder().(*transactionforwarder.MockTransactionalForwarder)
+
+	batcher := newTransactionalBatcher(testHost, testAgent, 100)
+
+	batcher.SubmitComponent(testID, testTransactionID, testInstance, testComponent)
+	batcher.SubmitComponent(testID2, testTransaction2ID, testInstance2, testComponent2)
+	batcher.SubmitCompleteTransaction(testID, testTransactionID)
+
+	committedTransactions := make([]string, 0, 2)
+	payloadTransactions := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		committedTransactions = append(committedTransactions, tm.NextAction().(transactionmanager.CommitAction).TransactionID)
+
+		payload := fwd.NextPayload()
+		assert.Len(t, payload.TransactionActionMap, 1)
+		for transactionID := range payload.TransactionActionMap {
+			payloadTransactions = append(payloadTransactions, transactionID)
+		}
+
+		actualPayload := transactional.NewIntakePayload()
+		assert.NoError(t, json.Unmarshal(payload.Body, &actualPayload))
+		assert.Len(t, actualPayload.Topologies, 1)
+		assert.Len(t, actualPayload.Topologies[0].Components, 1)
+	}
+
+	sort.Strings(committedTransactions)
+	sort.Strings(payloadTransactions)
+	assert.Equal(t, []string{testTransactionID, testTransaction2ID}, committedTransactions)
+	assert.Equal(t, []string{testTransactionID, testTransaction2ID}, payloadTransactions)
+
+	batcher.Stop()
+}
+
+func TestBatchBuilderOversizedElement(t *testing.T) {
+	builder := NewSizedTransactionalBatchBuilder(100, 10)
+
+	// an element that is larger than the maximum payload size is batched on its own
+	assert.Nil(t, builder.AddComponent(testID, testTransactionID, testInstance, testComponent))
+	states := builder.AddComponent(testID, testTransactionID, testInstance, testComponent2)
+	assert.Len(t, states, 1)
+	assert.Equal(t, []topology.Component{testComponent}, states[testID].Topology.Components)
+	assert.Equal(t, testTransactionID, states[testID].Transaction.TransactionID)
+
+	states = builder.Flush()
+	assert.Equal(t, []topology.Component{testComponent2}, states[testID].Topology.Components)
+	assert.Equal(t, testTransactionID, states[testID].Transaction.TransactionID)
+}
@@ -1,6 +1,7 @@
 package transactionforwarder
 
 import (
+	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
 	"github.com/StackVista/stackstate-agent/pkg/config"
@@ -8,6 +9,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"regexp"
 	"sync"
+	"time"
 )
 
 const apiKeyReplacement = "\"apiKey\":\"*************************$1"
@@ -36,6 +38,8 @@ type Forwarder struct {
 	stsClient       httpclient.RetryableHTTPClient
 	PayloadChannel  chan TransactionalPayload
 	ShutdownChannel chan ShutdownForwarder
+	spool           *diskSpool
+	spoolConfig     SpoolConfig
 }
 
 var (
@@ -77,10 +81,27 @@ func NewPrintingTransactionalForwarder() *PrintingTransactionalForwarder {
 
 // newTransactionalForwarder returns a instance of the forwarder
 func newTransactionalForwarder() *Forwarder {
+	return NewForwarder(GetSpoolConfig())
+}
+
+// NewForwarder returns a running forwarder that is not the global instance, for processes other than the agent that
+// send payloads to the intake. It spools with the given spool configuration, which must not share its path with the
+// spool of another forwarder. The caller owns the forwarder and stops it.
+func NewForwarder(spoolConfig SpoolConfig) *Forwarder {
 	fwd := &Forwarder{
 		stsClient:       httpclient.NewStackStateClient(),
 		PayloadChannel:  make(chan TransactionalPayload, 100),
 		ShutdownChannel: make(chan ShutdownForwarder, 1),
+		spoolConfig:     spoolConfig,
+	}
+
+	if fwd.spoolConfig.Enabled {
+		spool, err := newDiskSpool(fwd.spoolConfig)
+		if err != nil {
+			_ = log.Errorf("Unable to create the transactional forwarder spool, unsent payloads will be rejected: %s", err)
+		} else {
+			fwd.spool = spool
+		}
 	}
 
 	go fwd.Start()
@@ -90,33 +111,26 @@ func newTransactionalForwarder() *Forwarder {
 
 // Start initialize and runs the transactional forwarder.
 func (f *Forwarder) Start() {
+	// the replay ticker is only set up when the spool is enabled, a nil channel never fires
+	var replayTicker <-chan time.Time
+	if f.spool != nil {
+		ticker := time.NewTicker(f.spoolConfig.RetryInterval)
+		defer ticker.Stop()
+		replayTicker = ticker.C
+	}
+
 forwardHandler:
 	for {
 		select {
 		case payload := <-f.PayloadChannel:
-			log.Debugf("Attempting to send transactional payload,\ntransactions: %v,content: %v",
-				payload.TransactionActionMap, apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement))
-
-			response := f.stsClient.Post(payload.Path, payload.Body)
-			if response.Err != nil {
-				// Payload failed, reject action
-				for transactionID, payloadTransaction := range payload.TransactionActionMap {
-					log.Debugf("Sending transactional payload failed, rejecting action %s for transaction %s",
-						payloadTransaction.ActionID, transactionID)
-					transactionmanager.GetTransactionManager().RejectAction(transactionID, payloadTransaction.ActionID, response.Err.Error())
-				}
-				_ = log.Errorf("Sending transactional payload failed, content: %v. %s",
-					apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement), response.Err.Error())
-			} else {
-				f.ProgressTransactions(payload.TransactionActionMap)
-
-				log.Infof("Sent transactional payload, size: %d bytes.", len(payload.Body))
-				if config.Datadog.GetBool("log_payloads") {
-					log.Debugf("Sent transactional payload, response status: %s (%d).", response.Response.Status,
-						response.Response.StatusCode)
-					log.Debugf("Sent transactional payload, content: %v", apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement))
-				}
+			if f.spool != nil && f.spool.Len() > 0 {
+				// there are older payloads waiting to be replayed, spool this payload to preserve the ordering
+				f.spoolPayload(payload, "payloads are waiting in the transactional forwarder spool")
+				continue
 			}
+			f.sendPayload(payload)
+		case <-replayTicker:
+			f.replaySpool()
 		case sf := <-f.ShutdownChannel:
 			log.Infof("Shutting down forwarder %v", sf)
 			break forwardHandler
@@ -124,6 +138,79 @@ forwardHandler:
 	}
 }
 
+// sendPayload posts the payload to StackState, on failure the payload is spooled or its actions are rejected
+func (f *Forwarder) sendPayload(payload TransactionalPayload) {
+	log.Debugf("Attempting to send transactional payload,\ntransactions: %v,content: %v",
+		payload.TransactionActionMap, apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement))
+
+	response := f.stsClient.Post(payload.Path, payload.Body)
+	if response.Err != nil {
+		_ = log.Errorf("Sending transactional payload failed, content: %v. %s",
+			apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement), response.Err.Error())
+		if f.spool != nil {
+			f.spoolPayload(payload, response.Err.Error())
+			return
+		}
+		// Payload failed, reject action
+		f.rejectPayload(payload, response.Err.Error())
+		return
+	}
+
+	f.ProgressTransactions(payload.TransactionActionMap)
+
+	log.Infof("Sent transactional payload, size: %d bytes.", len(payload.Body))
+	if config.Datadog.GetBool("log_payloads") {
+		log.Debugf("Sent transactional payload, response status: %s (%d).", response.Response.Status,
+			response.Response.StatusCode)
+		log.Debugf("Sent transactional payload, content: %v", apiKeyRegExp.ReplaceAllString(string(payload.Body), apiKeyReplacement))
+	}
+}
+
+// spoolPayload persists the payload in the spool, the actions of the payload are rejected when the spool policy does
+// not allow the payload to be spooled
+func (f *Forwarder) spoolPayload(payload TransactionalPayload, reason string) {
+	if err := f.spool.Enqueue(payload); err != nil {
+		spoolDropped.Add(1)
+		tlmSpoolEvents.Inc("dropped")
+		f.rejectPayload(payload, fmt.Sprintf("%s. Unable to spool transactional payload: %s", reason, err))
+		return
+	}
+	log.Debugf("Spooled transactional payload for transactions %v, %d payloads waiting in the spool",
+		payload.TransactionActionMap, f.spool.Len())
+}
+
+// replaySpool rejects the spooled payloads that exceeded the maximum age and replays the remaining payloads in order
+// until StackState can not be reached
+func (f *Forwarder) replaySpool() {
+	for _, entry := range f.spool.Expire(time.Now()) {
+		f.rejectPayload(entry.Payload, fmt.Sprintf("spooled transactional payload expired after %s",
+			f.spoolConfig.MaxAge.String()))
+	}
+
+	for entry := f.spool.Peek(); entry != nil; entry = f.spool.Peek() {
+		response := f.stsClient.Post(entry.Payload.Path, entry.Payload.Body)
+		if response.Err != nil {
+			log.Debugf("Replaying spooled transactional payload failed, %d payloads waiting in the spool: %s",
+				f.spool.Len(), response.Err.Error())
+			return
+		}
+
+		f.spool.Pop()
+		f.ProgressTransactions(entry.Payload.TransactionActionMap)
+		log.Infof("Replayed spooled transactional payload, size: %d bytes. %d payloads waiting in the spool.",
+			len(entry.Payload.Body), f.spool.Len())
+	}
+}
+
+// rejectPayload rejects all the actions of a payload, resulting in a failure of the transactions in the payload
+func (f *Forwarder) rejectPayload(payload TransactionalPayload, reason string) {
+	for transactionID, payloadTransaction := range payload.TransactionActionMap {
+		log.Debugf("Sending transactional payload failed, rejecting action %s for transaction %s",
+			payloadTransaction.ActionID, transactionID)
+		transactionmanager.GetTransactionManager().RejectAction(transactionID, payloadTransaction.ActionID, reason)
+	}
+}
+
 // ProgressTransactions is called on a successful payload post or when OnlyMarkTransactions is set to true. It acknowledges
 // the actions within a transaction and completes a completed transaction.
 func (f *Forwarder) ProgressTransactions(transactionMap map[string]transactional.PayloadTransaction) {
@@ -146,8 +233,10 @@ func (f *Forwarder) Stop() {
 	// Shut down the forwardHandler
 	f.ShutdownChannel <- ShutdownForwarder{}
 
-	// reset the forwarder to re-init it later
-	tfInit = new(sync.Once)
+	// reset the global forwarder to re-init it later, a forwarder created with NewForwarder leaves the global one be
+	if transactionalForwarderInstance == TransactionalForwarder(f) {
+		tfInit = new(sync.Once)
+	}
 }
 
 // SubmitTransactionalIntake publishes the Payload to the PayloadChannel
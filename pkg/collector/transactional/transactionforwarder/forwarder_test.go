@@ -1,10 +1,12 @@
 package transactionforwarder
 
 import (
+	"compress/gzip"
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
 	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -320,3 +322,61 @@ func TestForwarder_Multiple(t *testing.T) {
 	}
 
 }
+
+func TestForwarder_CompressedPayload(t *testing.T) {
+	type receivedRequest struct {
+		contentEncoding string
+		body            []byte
+		err             error
+	}
+	received := make(chan receivedRequest, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			request := receivedRequest{contentEncoding: req.Header.Get("Content-Encoding")}
+			reader, err := gzip.NewReader(req.Body)
+			if err == nil {
+				request.body, err = ioutil.ReadAll(reader)
+			}
+			request.err = err
+			received <- request
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer server.Close()
+
+	manager := transactionmanager.NewMockTransactionManager()
+
+	config.Datadog.Set("sts_url", server.URL)
+	config.Datadog.Set("api_key", "my-test-api-key")
+
+	fwd := newTransactionalForwarder()
+	defer fwd.Stop()
+
+	body := []byte(`{"topologies":[{"components":[{"externalId":"id","type":{"name":"typename"}}]}]}`)
+	fwd.SubmitTransactionalIntake(TransactionalPayload{
+		Body: body,
+		Path: transactional.IntakePath,
+		TransactionActionMap: map[string]transactional.PayloadTransaction{
+			testTransactionID: {ActionID: testActionID},
+		},
+	})
+
+	request := <-received
+	assert.Equal(t, "gzip", request.contentEncoding)
+	assert.NoError(t, request.err)
+	assert.Equal(t, body, request.body)
+
+	ackAction := manager.NextAction().(transactionmanager.AckAction)
+	assert.Equal(t, testActionID, ackAction.ActionID)
+}
+
+func TestForwarder_StopLeavesGlobalForwarder(t *testing.T) {
+	global := NewMockTransactionalForwarder()
+	initOnce := tfInit
+
+	// stopping a forwarder that is not the global instance does not reset the global forwarder
+	fwd := NewForwarder(SpoolConfig{})
+	fwd.Stop()
+	assert.Same(t, initOnce, tfInit)
+	assert.Same(t, global, GetTransactionalForwarder())
+}
@@ -15,6 +15,8 @@ func createPrintingForwarder() *PrintingTransactionalForwarder {
 // PrintingTransactionalForwarder is a implementation of the transactional forwarder that prints the payload
 type PrintingTransactionalForwarder struct {
 	PayloadChan chan TransactionalPayload
+	// Collect receives the payloads instead of them being printed when it is set
+	Collect func(payload transactional.IntakePayload)
 }
 
 // Start is a noop
@@ -36,6 +38,11 @@ func (mf *PrintingTransactionalForwarder) SubmitTransactionalIntake(payload Tran
 	actualPayload := transactional.NewIntakePayload()
 	_ = json.Unmarshal(payload.Body, &actualPayload)
 
+	if mf.Collect != nil {
+		mf.Collect(actualPayload)
+		return
+	}
+
 	fmt.Fprintln(color.Output, fmt.Sprintf("=== %s ===", color.BlueString("Topology")))
 	j, _ := json.MarshalIndent(actualPayload, "", "  ")
 	fmt.Println(string(j))
@@ -0,0 +1,302 @@
+package transactionforwarder
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/telemetry"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const spoolFileExtension = ".spool"
+
+var (
+	// TransactionalForwarderExpvars is the root for expvars in the transactional forwarder.
+	TransactionalForwarderExpvars = expvar.NewMap("transactionalforwarder")
+
+	spoolEnabled  = expvar.Int{}
+	spoolDepth    = expvar.Int{}
+	spoolBytes    = expvar.Int{}
+	spoolSpooled  = expvar.Int{}
+	spoolReplayed = expvar.Int{}
+	spoolExpired  = expvar.Int{}
+	spoolDropped  = expvar.Int{}
+	spoolByCheck  = expvar.Map{}
+
+	tlmSpoolDepth = telemetry.NewGauge("transactional_forwarder", "spool_payloads",
+		[]string{}, "Amount of transactional payloads waiting in the spool")
+	tlmSpoolBytes = telemetry.NewGauge("transactional_forwarder", "spool_bytes",
+		[]string{}, "Size in bytes of the transactional payloads waiting in the spool")
+	tlmSpoolCheckDepth = telemetry.NewGauge("transactional_forwarder", "spool_check_payloads",
+		[]string{"check"}, "Amount of transactional payloads waiting in the spool per check")
+	tlmSpoolEvents = telemetry.NewCounter("transactional_forwarder", "spool_events",
+		[]string{"event"}, "Count of transactional payloads spooled, replayed, expired or dropped")
+)
+
+func init() {
+	TransactionalForwarderExpvars.Set("SpoolEnabled", &spoolEnabled)
+	TransactionalForwarderExpvars.Set("SpoolDepth", &spoolDepth)
+	TransactionalForwarderExpvars.Set("SpoolBytes", &spoolBytes)
+	TransactionalForwarderExpvars.Set("SpoolSpooled", &spoolSpooled)
+	TransactionalForwarderExpvars.Set("SpoolReplayed", &spoolReplayed)
+	TransactionalForwarderExpvars.Set("SpoolExpired", &spoolExpired)
+	TransactionalForwarderExpvars.Set("SpoolDropped", &spoolDropped)
+	TransactionalForwarderExpvars.Set("SpoolDepthByCheck", &spoolByCheck)
+}
+
+// SpoolFull is returned when a payload does not fit in the spool anymore.
+type SpoolFull struct {
+	PayloadSize, MaxSizeBytes int64
+}
+
+// Error returns a string representation of the SpoolFull error and implements Error.
+func (s SpoolFull) Error() string {
+	return fmt.Sprintf("transactional forwarder spool is full, payload of %d bytes exceeds the maximum spool size of %d bytes",
+		s.PayloadSize, s.MaxSizeBytes)
+}
+
+// SpoolCheckLimitReached is returned when a check has reached the maximum amount of spooled payloads.
+type SpoolCheckLimitReached struct {
+	CheckID     check.ID
+	MaxPayloads int
+}
+
+// Error returns a string representation of the SpoolCheckLimitReached error and implements Error.
+func (s SpoolCheckLimitReached) Error() string {
+	return fmt.Sprintf("check %s reached the maximum of %d spooled transactional payloads", s.CheckID, s.MaxPayloads)
+}
+
+// spoolEntry is a single transactional payload persisted in the spool
+type spoolEntry struct {
+	Sequence  uint64
+	SpooledAt time.Time
+	Payload   TransactionalPayload
+	size      int64
+}
+
+// checkIDs returns the checks that produced data in the spooled payload
+func (e *spoolEntry) checkIDs() []check.ID {
+	checkIDs := make([]check.ID, 0, len(e.Payload.TransactionActionMap))
+	for _, payloadTransaction := range e.Payload.TransactionActionMap {
+		if payloadTransaction.CheckID != "" {
+			checkIDs = append(checkIDs, payloadTransaction.CheckID)
+		}
+	}
+	return checkIDs
+}
+
+// diskSpool persists transactional payloads that could not be delivered to StackState, so they can be replayed in order
+// once the receiver is reachable again. It is bounded in size, in age and in the amount of payloads per check. The
+// diskSpool is not thread safe and is only used from within the forwarder's receiving loop.
+type diskSpool struct {
+	config       SpoolConfig
+	entries      []*spoolEntry
+	checkCounts  map[check.ID]int
+	sizeBytes    int64
+	nextSequence uint64
+}
+
+// newDiskSpool creates the spool directory and loads all the payloads that were spooled by a previous run of the agent
+func newDiskSpool(config SpoolConfig) (*diskSpool, error) {
+	if err := os.MkdirAll(config.Path, 0700); err != nil {
+		return nil, fmt.Errorf("could not create transactional forwarder spool directory %s: %s", config.Path, err)
+	}
+
+	spool := &diskSpool{
+		config:      config,
+		entries:     make([]*spoolEntry, 0),
+		checkCounts: make(map[check.ID]int),
+	}
+
+	if err := spool.load(); err != nil {
+		return nil, err
+	}
+
+	spoolEnabled.Set(1)
+	spool.updateStats()
+
+	return spool, nil
+}
+
+// load reads all the spooled payloads from disk, ordered by their sequence number
+func (s *diskSpool) load() error {
+	files, err := ioutil.ReadDir(s.config.Path)
+	if err != nil {
+		return fmt.Errorf("could not read transactional forwarder spool directory %s: %s", s.config.Path, err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), spoolFileExtension) {
+			continue
+		}
+
+		path := filepath.Join(s.config.Path, file.Name())
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			_ = log.Warnf("Could not read spooled transactional payload %s, skipping it: %s", path, err)
+			continue
+		}
+
+		entry := &spoolEntry{}
+		if err := json.Unmarshal(content, entry); err != nil {
+			_ = log.Warnf("Could not decode spooled transactional payload %s, removing it: %s", path, err)
+			_ = os.Remove(path)
+			continue
+		}
+		entry.size = file.Size()
+
+		s.add(entry)
+		if entry.Sequence >= s.nextSequence {
+			s.nextSequence = entry.Sequence + 1
+		}
+	}
+
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].Sequence < s.entries[j].Sequence
+	})
+
+	if len(s.entries) > 0 {
+		log.Infof("Loaded %d spooled transactional payloads (%d bytes) from %s", len(s.entries), s.sizeBytes, s.config.Path)
+	}
+
+	return nil
+}
+
+// Len returns the amount of payloads in the spool
+func (s *diskSpool) Len() int {
+	return len(s.entries)
+}
+
+// Enqueue persists the payload at the end of the spool, or returns an error if the spool policy does not allow it
+func (s *diskSpool) Enqueue(payload TransactionalPayload) error {
+	entry := &spoolEntry{
+		Sequence:  s.nextSequence,
+		SpooledAt: time.Now(),
+		Payload:   payload,
+	}
+
+	for _, checkID := range entry.checkIDs() {
+		if s.checkCounts[checkID] >= s.config.MaxPayloadsPerCheck {
+			return SpoolCheckLimitReached{CheckID: checkID, MaxPayloads: s.config.MaxPayloadsPerCheck}
+		}
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not serialize transactional payload for the spool: %s", err)
+	}
+	entry.size = int64(len(content))
+
+	if s.sizeBytes+entry.size > s.config.MaxSizeBytes {
+		return SpoolFull{PayloadSize: entry.size, MaxSizeBytes: s.config.MaxSizeBytes}
+	}
+
+	if err := ioutil.WriteFile(s.pathForEntry(entry), content, 0600); err != nil {
+		return fmt.Errorf("could not write transactional payload to the spool: %s", err)
+	}
+
+	s.nextSequence++
+	s.add(entry)
+	s.updateStats()
+
+	spoolSpooled.Add(1)
+	tlmSpoolEvents.Inc("spooled")
+
+	return nil
+}
+
+// Peek returns the oldest payload in the spool without removing it, or nil if the spool is empty
+func (s *diskSpool) Peek() *spoolEntry {
+	if len(s.entries) == 0 {
+		return nil
+	}
+	return s.entries[0]
+}
+
+// Pop removes the oldest payload from the spool once it has been replayed
+func (s *diskSpool) Pop() {
+	if len(s.entries) == 0 {
+		return
+	}
+	s.remove(s.entries[0])
+	s.entries = s.entries[1:]
+	s.updateStats()
+
+	spoolReplayed.Add(1)
+	tlmSpoolEvents.Inc("replayed")
+}
+
+// Expire removes and returns all the payloads that have been in the spool for longer than the configured maximum age
+func (s *diskSpool) Expire(now time.Time) []*spoolEntry {
+	expired := make([]*spoolEntry, 0)
+	remaining := make([]*spoolEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.SpooledAt.Before(now.Add(-s.config.MaxAge)) {
+			s.remove(entry)
+			expired = append(expired, entry)
+		} else {
+			remaining = append(remaining, entry)
+		}
+	}
+	s.entries = remaining
+
+	if len(expired) > 0 {
+		s.updateStats()
+		spoolExpired.Add(int64(len(expired)))
+		tlmSpoolEvents.Add(float64(len(expired)), "expired")
+	}
+
+	return expired
+}
+
+// add keeps track of an entry that has been persisted to disk
+func (s *diskSpool) add(entry *spoolEntry) {
+	s.entries = append(s.entries, entry)
+	s.sizeBytes += entry.size
+	for _, checkID := range entry.checkIDs() {
+		s.checkCounts[checkID]++
+	}
+}
+
+// remove deletes the file of an entry and stops keeping track of it. The caller is responsible for removing the entry
+// from the entries slice.
+func (s *diskSpool) remove(entry *spoolEntry) {
+	if err := os.Remove(s.pathForEntry(entry)); err != nil && !os.IsNotExist(err) {
+		_ = log.Warnf("Could not remove spooled transactional payload %d: %s", entry.Sequence, err)
+	}
+	s.sizeBytes -= entry.size
+	for _, checkID := range entry.checkIDs() {
+		s.checkCounts[checkID]--
+		if s.checkCounts[checkID] <= 0 {
+			delete(s.checkCounts, checkID)
+			spoolByCheck.Delete(string(checkID))
+			tlmSpoolCheckDepth.Delete(string(checkID))
+		}
+	}
+}
+
+// pathForEntry returns the file for an entry. The sequence is zero padded so the files sort in the spooled order.
+func (s *diskSpool) pathForEntry(entry *spoolEntry) string {
+	return filepath.Join(s.config.Path, fmt.Sprintf("%020d%s", entry.Sequence, spoolFileExtension))
+}
+
+// updateStats publishes the current spool depth in the expvars and telemetry
+func (s *diskSpool) updateStats() {
+	spoolDepth.Set(int64(len(s.entries)))
+	spoolBytes.Set(s.sizeBytes)
+	tlmSpoolDepth.Set(float64(len(s.entries)))
+	tlmSpoolBytes.Set(float64(s.sizeBytes))
+	for checkID, count := range s.checkCounts {
+		depth := &expvar.Int{}
+		depth.Set(int64(count))
+		spoolByCheck.Set(string(checkID), depth)
+		tlmSpoolCheckDepth.Set(float64(count), string(checkID))
+	}
+}
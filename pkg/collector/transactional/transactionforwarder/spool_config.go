@@ -0,0 +1,28 @@
+package transactionforwarder
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"time"
+)
+
+// SpoolConfig contains all the configuration for the transactional forwarder disk spool
+type SpoolConfig struct {
+	Enabled             bool
+	Path                string
+	MaxSizeBytes        int64
+	MaxAge              time.Duration
+	MaxPayloadsPerCheck int
+	RetryInterval       time.Duration
+}
+
+// GetSpoolConfig returns the configuration for the transactional forwarder disk spool
+func GetSpoolConfig() SpoolConfig {
+	return SpoolConfig{
+		Enabled:             config.Datadog.GetBool("transactional_forwarder_spool_enabled"),
+		Path:                config.Datadog.GetString("transactional_forwarder_spool_path"),
+		MaxSizeBytes:        config.Datadog.GetInt64("transactional_forwarder_spool_max_size_bytes"),
+		MaxAge:              config.Datadog.GetDuration("transactional_forwarder_spool_max_age"),
+		MaxPayloadsPerCheck: config.Datadog.GetInt("transactional_forwarder_spool_max_payloads_per_check"),
+		RetryInterval:       config.Datadog.GetDuration("transactional_forwarder_spool_retry_interval"),
+	}
+}
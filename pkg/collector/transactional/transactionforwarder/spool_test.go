@@ -0,0 +1,159 @@
+package transactionforwarder
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionmanager"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testSpoolConfig(t *testing.T) SpoolConfig {
+	return SpoolConfig{
+		Enabled:             true,
+		Path:                t.TempDir(),
+		MaxSizeBytes:        1024 * 1024,
+		MaxAge:              time.Minute,
+		MaxPayloadsPerCheck: 2,
+		RetryInterval:       100 * time.Millisecond,
+	}
+}
+
+func testSpoolPayload(checkID check.ID, transactionID, actionID string) TransactionalPayload {
+	return TransactionalPayload{
+		Body: []byte("{}"),
+		Path: transactional.IntakePath,
+		TransactionActionMap: map[string]transactional.PayloadTransaction{
+			transactionID: {
+				ActionID:             actionID,
+				CompletedTransaction: true,
+				CheckID:              checkID,
+			},
+		},
+	}
+}
+
+func TestDiskSpool_EnqueueAndReplayInOrder(t *testing.T) {
+	spool, err := newDiskSpool(testSpoolConfig(t))
+	require.NoError(t, err)
+
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check1", "tx1", "action1")))
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check2", "tx2", "action2")))
+	assert.Equal(t, 2, spool.Len())
+
+	assert.Equal(t, "action1", spool.Peek().Payload.TransactionActionMap["tx1"].ActionID)
+	spool.Pop()
+	assert.Equal(t, "action2", spool.Peek().Payload.TransactionActionMap["tx2"].ActionID)
+	spool.Pop()
+	assert.Nil(t, spool.Peek())
+	assert.Equal(t, int64(0), spool.sizeBytes)
+}
+
+func TestDiskSpool_LoadFromDisk(t *testing.T) {
+	spoolConfig := testSpoolConfig(t)
+	spool, err := newDiskSpool(spoolConfig)
+	require.NoError(t, err)
+
+	for _, tx := range []string{"tx1", "tx2"} {
+		require.NoError(t, spool.Enqueue(testSpoolPayload("check1", tx, "action-"+tx)))
+	}
+
+	// a new spool on the same path picks up the payloads of the previous one, in the same order
+	reloaded, err := newDiskSpool(spoolConfig)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reloaded.Len())
+	assert.Equal(t, spool.sizeBytes, reloaded.sizeBytes)
+	assert.Equal(t, "action-tx1", reloaded.Peek().Payload.TransactionActionMap["tx1"].ActionID)
+	assert.Equal(t, uint64(2), reloaded.nextSequence)
+}
+
+func TestDiskSpool_Limits(t *testing.T) {
+	spoolConfig := testSpoolConfig(t)
+	spool, err := newDiskSpool(spoolConfig)
+	require.NoError(t, err)
+
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check1", "tx1", "action1")))
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check1", "tx2", "action2")))
+	err = spool.Enqueue(testSpoolPayload("check1", "tx3", "action3"))
+	assert.Equal(t, SpoolCheckLimitReached{CheckID: "check1", MaxPayloads: 2}, err)
+
+	// other checks can still use the spool
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check2", "tx4", "action4")))
+
+	spoolConfig.Path = t.TempDir()
+	spoolConfig.MaxSizeBytes = 10
+	smallSpool, err := newDiskSpool(spoolConfig)
+	require.NoError(t, err)
+	err = smallSpool.Enqueue(testSpoolPayload("check1", "tx1", "action1"))
+	assert.IsType(t, SpoolFull{}, err)
+	assert.Equal(t, 0, smallSpool.Len())
+}
+
+func TestDiskSpool_Expire(t *testing.T) {
+	spool, err := newDiskSpool(testSpoolConfig(t))
+	require.NoError(t, err)
+
+	require.NoError(t, spool.Enqueue(testSpoolPayload("check1", "tx1", "action1")))
+	assert.Empty(t, spool.Expire(time.Now()))
+
+	expired := spool.Expire(time.Now().Add(2 * time.Minute))
+	assert.Len(t, expired, 1)
+	assert.Equal(t, 0, spool.Len())
+	assert.Empty(t, spool.checkCounts)
+}
+
+func TestForwarder_SpoolAndReplay(t *testing.T) {
+	var available, attempts int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			if atomic.LoadInt32(&available) == 1 {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}),
+	)
+	defer server.Close()
+
+	manager := transactionmanager.NewMockTransactionManager()
+	defer manager.Stop()
+
+	config.Datadog.Set("sts_url", server.URL)
+	config.Datadog.Set("api_key", "my-test-api-key")
+	config.Datadog.Set("transactional_forwarder_retry_min", 10*time.Millisecond)
+	config.Datadog.Set("transactional_forwarder_retry_max", 20*time.Millisecond)
+	config.Datadog.Set("transactional_forwarder_spool_enabled", true)
+	config.Datadog.Set("transactional_forwarder_spool_path", t.TempDir())
+	config.Datadog.Set("transactional_forwarder_spool_retry_interval", 100*time.Millisecond)
+	defer config.Datadog.Set("transactional_forwarder_spool_enabled", false)
+
+	fwd := newTransactionalForwarder()
+	defer fwd.Stop()
+
+	fwd.SubmitTransactionalIntake(testSpoolPayload("check1", testTransactionID, testActionID))
+	fwd.SubmitTransactionalIntake(testSpoolPayload("check1", testTransaction2ID, testActionID2))
+
+	// wait until the first payload has been attempted, then make the receiver available again
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&attempts) > 0 }, 5*time.Second, 10*time.Millisecond)
+	atomic.StoreInt32(&available, 1)
+
+	// the payloads are replayed in the order they were submitted
+	for _, expected := range []struct{ transactionID, actionID string }{
+		{testTransactionID, testActionID},
+		{testTransaction2ID, testActionID2},
+	} {
+		ackAction := manager.NextAction().(transactionmanager.AckAction)
+		assert.Equal(t, expected.transactionID, ackAction.TransactionID)
+		assert.Equal(t, expected.actionID, ackAction.ActionID)
+
+		completedTx := manager.NextAction().(transactionmanager.CompleteTransaction)
+		assert.Equal(t, expected.transactionID, completedTx.TransactionID)
+	}
+}
@@ -0,0 +1,112 @@
+package transactionmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const journalFileExtension = ".json"
+
+// invalidJournalChars are the characters of a transaction id that are left out of its journal file name
+var invalidJournalChars = regexp.MustCompile("[^a-zA-Z0-9_-]")
+
+// transactionJournal persists the status of the transactions and their actions, one file per transaction, so the
+// transaction manager can tell what happened to them after an agent restart. A nil transactionJournal is a disabled
+// journal, all of its operations are no-ops.
+type transactionJournal struct {
+	path string
+}
+
+// newTransactionJournal creates the journal directory, it returns a nil journal when the journal is disabled
+func newTransactionJournal(config JournalConfig) (*transactionJournal, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if err := os.MkdirAll(config.Path, 0700); err != nil {
+		return nil, fmt.Errorf("could not create transaction manager journal directory %s: %s", config.Path, err)
+	}
+	return &transactionJournal{path: config.Path}, nil
+}
+
+// fileForTransaction returns the journal file of a transaction
+func (j *transactionJournal) fileForTransaction(transactionID string) string {
+	return filepath.Join(j.path, invalidJournalChars.ReplaceAllString(transactionID, "")+journalFileExtension)
+}
+
+// write persists the current status of the transaction. The file is replaced atomically, so a restart halfway through
+// never leaves a partially written transaction behind.
+func (j *transactionJournal) write(transaction *IntakeTransaction) {
+	if j == nil {
+		return
+	}
+
+	content, err := json.Marshal(newTransactionSnapshot(transaction))
+	if err != nil {
+		_ = log.Errorf("Could not serialize transaction %s for the journal: %s", transaction.TransactionID, err)
+		return
+	}
+
+	path := j.fileForTransaction(transaction.TransactionID)
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		_ = log.Errorf("Could not write transaction %s to the journal: %s", transaction.TransactionID, err)
+		return
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = log.Errorf("Could not write transaction %s to the journal: %s", transaction.TransactionID, err)
+	}
+}
+
+// remove deletes the transaction from the journal
+func (j *transactionJournal) remove(transactionID string) {
+	if j == nil {
+		return
+	}
+
+	if err := os.Remove(j.fileForTransaction(transactionID)); err != nil && !os.IsNotExist(err) {
+		_ = log.Warnf("Could not remove transaction %s from the journal: %s", transactionID, err)
+	}
+}
+
+// ReadJournal reads all the transactions from the transaction manager journal at the given path, ordered by the time
+// they were last updated. Files that cannot be read or decoded are skipped.
+func ReadJournal(path string) ([]TransactionSnapshot, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read transaction manager journal directory %s: %s", path, err)
+	}
+
+	transactions := make([]TransactionSnapshot, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), journalFileExtension) {
+			continue
+		}
+
+		filePath := filepath.Join(path, file.Name())
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			_ = log.Warnf("Could not read journaled transaction %s, skipping it: %s", filePath, err)
+			continue
+		}
+
+		var transaction TransactionSnapshot
+		if err := json.Unmarshal(content, &transaction); err != nil {
+			_ = log.Warnf("Could not decode journaled transaction %s, skipping it: %s", filePath, err)
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].LastUpdatedTimestamp.Before(transactions[j].LastUpdatedTimestamp)
+	})
+
+	return transactions, nil
+}
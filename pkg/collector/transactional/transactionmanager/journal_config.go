@@ -0,0 +1,19 @@
+package transactionmanager
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/config"
+)
+
+// JournalConfig contains all the configuration for the transaction manager journal
+type JournalConfig struct {
+	Enabled bool
+	Path    string
+}
+
+// GetJournalConfig returns the configuration for the transaction manager journal
+func GetJournalConfig() JournalConfig {
+	return JournalConfig{
+		Enabled: config.Datadog.GetBool("transaction_manager_journal_enabled"),
+		Path:    config.Datadog.GetString("transaction_manager_journal_path"),
+	}
+}
@@ -0,0 +1,153 @@
+package transactionmanager
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	checkState "github.com/StackVista/stackstate-agent/pkg/collector/check/state"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJournal(t *testing.T) *transactionJournal {
+	path, err := ioutil.TempDir("", "transaction-journal")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(path) })
+
+	journal, err := newTransactionJournal(JournalConfig{Enabled: true, Path: path})
+	require.NoError(t, err)
+	return journal
+}
+
+func TestTransactionJournal_Disabled(t *testing.T) {
+	journal, err := newTransactionJournal(JournalConfig{Enabled: false, Path: "/does/not/matter"})
+	assert.NoError(t, err)
+	assert.Nil(t, journal)
+
+	// a disabled journal ignores all operations
+	journal.write(&IntakeTransaction{TransactionID: "tx"})
+	journal.remove("tx")
+}
+
+func TestTransactionJournal_JournalsTransactions(t *testing.T) {
+	journal := newTestJournal(t)
+	txManager := newJournaledTransactionManager(100, 100*time.Millisecond, 1*time.Second, 1*time.Second,
+		journal).(*transactionManager)
+	defer txManager.Stop()
+
+	txID := uuid.New().String()
+	txNotifyChannel := make(chan interface{}, 10)
+	txManager.StartTransaction("checkID", txID, txNotifyChannel)
+	txManager.CommitAction(txID, "action-1")
+	txManager.CommitAction(txID, "action-2")
+	txManager.AcknowledgeAction(txID, "action-1")
+	txManager.SetState(txID, "checkID:state", "{\"offset\": 5}")
+
+	assert.Eventually(t, func() bool {
+		transactions, err := ReadJournal(journal.path)
+		return err == nil && len(transactions) == 1 && transactions[0].State != nil
+	}, 1*time.Second, 10*time.Millisecond)
+
+	transactions, err := ReadJournal(journal.path)
+	require.NoError(t, err)
+	transaction := transactions[0]
+	assert.Equal(t, txID, transaction.TransactionID)
+	assert.Equal(t, "checkID", string(transaction.CheckID))
+	assert.Equal(t, InProgress, transaction.Status)
+	assert.Equal(t, &TransactionState{Key: "checkID:state", State: "{\"offset\": 5}"}, transaction.State)
+	assert.Len(t, transaction.Actions, 2)
+	assert.Equal(t, Acknowledged, transaction.Actions["action-1"].Status)
+	assert.Equal(t, Committed, transaction.Actions["action-2"].Status)
+
+	txManager.AcknowledgeAction(txID, "action-2")
+	txManager.CompleteTransaction(txID)
+	<-txNotifyChannel
+
+	transactions, err = ReadJournal(journal.path)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, Succeeded, transactions[0].Status)
+
+	// the succeeded transaction is removed from the journal once it is cleaned up
+	assert.Eventually(t, func() bool {
+		transactions, err := ReadJournal(journal.path)
+		return err == nil && len(transactions) == 0
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
+func TestTransactionJournal_RecoverTransactions(t *testing.T) {
+	statePath, err := ioutil.TempDir("", "transaction-journal-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(statePath)
+	config.Datadog.Set("check_state_root_path", statePath)
+	checkState.InitCheckStateManager()
+
+	journal := newTestJournal(t)
+	now := time.Now()
+	// a succeeded transaction of which the state may not have been committed before the restart
+	journal.write(&IntakeTransaction{
+		TransactionID:        "succeeded-tx",
+		CheckID:              "check-a",
+		Status:               Succeeded,
+		Actions:              map[string]*Action{"action": {ActionID: "action", Status: Acknowledged}},
+		LastUpdatedTimestamp: now.Add(-2 * time.Minute),
+		State:                &TransactionState{Key: "check-a:recovered", State: "{\"offset\": 10}"},
+	})
+	// a transaction that was still in progress when the agent stopped
+	journal.write(&IntakeTransaction{
+		TransactionID:        "in-progress-tx",
+		CheckID:              "check-a",
+		Status:               InProgress,
+		Actions:              map[string]*Action{"action": {ActionID: "action", Status: Committed}},
+		LastUpdatedTimestamp: now.Add(-1 * time.Minute),
+	})
+	// a failed transaction, the check was already notified about it
+	journal.write(&IntakeTransaction{
+		TransactionID:        "failed-tx",
+		CheckID:              "check-b",
+		Status:               Failed,
+		Actions:              map[string]*Action{},
+		LastUpdatedTimestamp: now,
+	})
+
+	txManager := newJournaledTransactionManager(100, 100*time.Millisecond, 1*time.Second, 1*time.Second,
+		journal).(*transactionManager)
+	defer txManager.Stop()
+
+	// the state of the succeeded transaction is committed
+	state, err := checkState.GetCheckStateManager().GetState("check-a:recovered")
+	require.NoError(t, err)
+	assert.Equal(t, "{\"offset\": 10}", state)
+
+	// the recovered transactions are removed from the journal
+	transactions, err := ReadJournal(journal.path)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	// checks without discarded transactions are not notified
+	checkBChannel := make(chan interface{}, 10)
+	txManager.StartTransaction("check-b", uuid.New().String(), checkBChannel)
+
+	// the check with the in progress transaction is notified when it starts its next transaction
+	checkAChannel := make(chan interface{}, 10)
+	txManager.StartTransaction("check-a", uuid.New().String(), checkAChannel)
+	select {
+	case msg := <-checkAChannel:
+		assert.Equal(t, RecoveredTransactionDiscarded{
+			TransactionID: "in-progress-tx",
+			Reason:        "transaction was in progress when the agent stopped",
+		}, msg)
+	case <-time.After(1 * time.Second):
+		t.Fatal("expected a notification for the discarded transaction")
+	}
+
+	// the notification is only delivered once
+	txManager.StartTransaction("check-a", uuid.New().String(), checkAChannel)
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, checkAChannel)
+	assert.Empty(t, checkBChannel)
+}
@@ -38,6 +38,11 @@ func (ttm *MockTransactionManager) TransactionCount() int {
 	return 0
 }
 
+// Transactions returns nil
+func (ttm *MockTransactionManager) Transactions() []TransactionSnapshot {
+	return nil
+}
+
 // Start is a noop
 func (ttm *MockTransactionManager) Start() {
 }
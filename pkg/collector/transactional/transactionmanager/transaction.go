@@ -18,6 +18,8 @@ const (
 	Succeeded
 	// Stale is used to represent a Stale transaction
 	Stale
+	// Evicted is used to represent a Stale transaction that has been evicted from the transaction manager
+	Evicted
 )
 
 // String returns a string representation of TransactionStatus
@@ -29,6 +31,8 @@ func (state TransactionStatus) String() string {
 		return "succeeded"
 	case Stale:
 		return "stale"
+	case Evicted:
+		return "evicted"
 	default:
 		return "in progress"
 	}
@@ -77,6 +81,32 @@ type IntakeTransaction struct {
 	State                *TransactionState // the State of the TransactionState will be updated each time, no need for a pointer
 }
 
+// TransactionSnapshot is a point in time copy of an IntakeTransaction, as it is journaled and exposed by the agent API
+type TransactionSnapshot struct {
+	TransactionID        string
+	CheckID              check.ID
+	Status               TransactionStatus
+	Actions              map[string]Action
+	LastUpdatedTimestamp time.Time
+	State                *TransactionState
+}
+
+// newTransactionSnapshot copies the transaction and its actions
+func newTransactionSnapshot(transaction *IntakeTransaction) TransactionSnapshot {
+	actions := make(map[string]Action, len(transaction.Actions))
+	for actionID, action := range transaction.Actions {
+		actions[actionID] = *action
+	}
+	return TransactionSnapshot{
+		TransactionID:        transaction.TransactionID,
+		CheckID:              transaction.CheckID,
+		Status:               transaction.Status,
+		Actions:              actions,
+		LastUpdatedTimestamp: transaction.LastUpdatedTimestamp,
+		State:                transaction.State,
+	}
+}
+
 // TransactionState keeps the state for a given key
 type TransactionState struct {
 	Key, State string
@@ -120,6 +150,12 @@ type EvictedTransaction struct {
 	TransactionID string
 }
 
+// RecoveredTransactionDiscarded is sent to a check when it starts a transaction after an agent restart and one of its
+// journaled transactions was discarded, because it was still in progress when the agent stopped.
+type RecoveredTransactionDiscarded struct {
+	TransactionID, Reason string
+}
+
 // DiscardTransaction rolls back a transaction and marks a transaction as a failure.
 type DiscardTransaction struct {
 	TransactionID, Reason string
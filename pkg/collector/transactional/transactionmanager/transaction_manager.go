@@ -3,11 +3,15 @@ package transactionmanager
 import (
 	"fmt"
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	checkState "github.com/StackVista/stackstate-agent/pkg/collector/check/state"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
 	"sync"
 	"time"
 )
 
+// maxEvictedTransactions is the amount of evicted transactions that is kept for inspection
+const maxEvictedTransactions = 50
+
 var (
 	tmInstance TransactionManager
 	tmInit     *sync.Once
@@ -26,6 +30,20 @@ func InitTransactionManager(transactionChannelBufferSize int, tickerInterval, tr
 	})
 }
 
+// InitJournaledTransactionManager initializes the transaction manager with a journal on disk. Transactions that were
+// journaled by a previous run of the agent are recovered before the transaction manager starts.
+func InitJournaledTransactionManager(transactionChannelBufferSize int, tickerInterval, transactionTimeoutDuration,
+	transactionEvictionDuration time.Duration, journalConfig JournalConfig) {
+	tmInit.Do(func() {
+		journal, err := newTransactionJournal(journalConfig)
+		if err != nil {
+			_ = log.Errorf("Transaction manager journal is disabled: %s", err)
+		}
+		tmInstance = newJournaledTransactionManager(transactionChannelBufferSize, tickerInterval,
+			transactionTimeoutDuration, transactionEvictionDuration, journal)
+	})
+}
+
 // GetTransactionManager returns a handle on the global transactionbatcher Instance
 func GetTransactionManager() TransactionManager {
 	return tmInstance
@@ -42,14 +60,27 @@ func NewMockTransactionManager() *MockTransactionManager {
 // newTransactionManager returns an instance of a TransactionManager
 func newTransactionManager(transactionChannelBufferSize int, tickerInterval, transactionTimeoutDuration,
 	transactionEvictionDuration time.Duration) TransactionManager {
+	return newJournaledTransactionManager(transactionChannelBufferSize, tickerInterval, transactionTimeoutDuration,
+		transactionEvictionDuration, nil)
+}
+
+// newJournaledTransactionManager returns an instance of a TransactionManager that journals its transactions, a nil
+// journal disables journaling
+func newJournaledTransactionManager(transactionChannelBufferSize int, tickerInterval, transactionTimeoutDuration,
+	transactionEvictionDuration time.Duration, journal *transactionJournal) TransactionManager {
 	tm := &transactionManager{
 		transactionChannel:          make(chan interface{}, transactionChannelBufferSize),
 		transactionTicker:           time.NewTicker(tickerInterval),
 		transactions:                make(map[string]*IntakeTransaction),
 		transactionTimeoutDuration:  transactionTimeoutDuration,
 		transactionEvictionDuration: transactionEvictionDuration,
+		journal:                     journal,
+		recoveredDiscards:           make(map[check.ID][]RecoveredTransactionDiscarded),
+		commitState:                 commitCheckState,
 	}
 
+	tm.recoverTransactions()
+
 	go tm.Start()
 
 	return tm
@@ -63,6 +94,13 @@ type transactionManager struct {
 	transactionTimeoutDuration  time.Duration
 	transactionEvictionDuration time.Duration
 	mux                         sync.RWMutex
+	journal                     *transactionJournal
+	// recoveredDiscards are the notifications of discarded journaled transactions, per check, that are delivered when
+	// the check starts its next transaction
+	recoveredDiscards map[check.ID][]RecoveredTransactionDiscarded
+	commitState       func(key, state string) error
+	// evictedTransactions are the most recently evicted transactions, kept for inspection
+	evictedTransactions []TransactionSnapshot
 }
 
 // Start sets up the transaction checkmanager to consume messages on the txm.transactionChannel. It consumes one message at
@@ -139,16 +177,20 @@ transactionHandler:
 						transaction.TransactionID, transaction.CheckID)
 					// delete the transaction, already notified on success or failure status so no need to notify again
 					delete(txm.transactions, transaction.TransactionID)
+					txm.journal.remove(transaction.TransactionID)
 				} else if transaction.Status != Stale && transaction.LastUpdatedTimestamp.Before(time.Now().Add(-txm.transactionTimeoutDuration)) {
 					// last updated timestamp is before current time - checkmanager timeout duration => Tx is stale
 					_ = log.Warnf("Transaction: %s for check %s has become stale, last updated %s",
 						transaction.TransactionID, transaction.CheckID, transaction.LastUpdatedTimestamp.String())
 					transaction.Status = Stale
+					txm.journal.write(transaction)
 				} else if transaction.Status == Stale && transaction.LastUpdatedTimestamp.Before(time.Now().Add(-txm.transactionEvictionDuration)) {
 					// last updated timestamp is before current time - checkmanager eviction duration => Tx can be evicted
 					_ = log.Warnf("Transaction: %s for check %s is stale and will be evicted, last updated %s",
 						transaction.TransactionID, transaction.CheckID, transaction.LastUpdatedTimestamp.String())
 					delete(txm.transactions, transaction.TransactionID)
+					txm.journal.remove(transaction.TransactionID)
+					txm.addEvictedTransaction(transaction)
 					transaction.NotifyChannel <- EvictedTransaction{TransactionID: transaction.TransactionID}
 				}
 			}
@@ -172,6 +214,13 @@ func (txm *transactionManager) startTransaction(transactionID string, checkID ch
 	txm.mux.Lock()
 	txm.transactions[transaction.TransactionID] = transaction
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
+
+	// let the check know about the transactions it lost in a restart of the agent
+	for _, discarded := range txm.recoveredDiscards[checkID] {
+		notify <- discarded
+	}
+	delete(txm.recoveredDiscards, checkID)
 
 	return transaction, nil
 }
@@ -192,6 +241,7 @@ func (txm *transactionManager) commitAction(transactionID, actionID string) erro
 	}
 	txm.updateTransaction(transaction, action, InProgress)
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 
 	return nil
 }
@@ -223,6 +273,7 @@ func (txm *transactionManager) ackAction(transactionID, actionID string) error {
 	txm.updateTransaction(transaction, action, InProgress)
 
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 
 	return nil
 }
@@ -242,6 +293,7 @@ func (txm *transactionManager) setTransactionState(transactionID, key string, st
 	transaction.Status = InProgress
 	transaction.LastUpdatedTimestamp = time.Now()
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 
 	return nil
 }
@@ -265,6 +317,7 @@ func (txm *transactionManager) rejectAction(transactionID, actionID string) erro
 	action.StatusUpdatedTimestamp = time.Now()
 
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 
 	return nil
 }
@@ -291,6 +344,7 @@ func (txm *transactionManager) completeTransaction(transactionID string) error {
 	transaction.LastUpdatedTimestamp = time.Now()
 	state := transaction.State
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 	transaction.NotifyChannel <- CompleteTransaction{TransactionID: transactionID, State: state}
 
 	return nil
@@ -307,7 +361,70 @@ func (txm *transactionManager) discardTransaction(transactionID, reason string)
 	transaction.Status = Failed
 	transaction.LastUpdatedTimestamp = time.Now()
 	txm.mux.Unlock()
+	txm.journal.write(transaction)
 	transaction.NotifyChannel <- DiscardTransaction{TransactionID: transactionID, Reason: reason}
 
 	return nil
 }
+
+// addEvictedTransaction keeps a snapshot of the evicted transaction, dropping the oldest one when there are more than
+// maxEvictedTransactions. It is called while holding the txm.mux lock.
+func (txm *transactionManager) addEvictedTransaction(transaction *IntakeTransaction) {
+	snapshot := newTransactionSnapshot(transaction)
+	snapshot.Status = Evicted
+	txm.evictedTransactions = append(txm.evictedTransactions, snapshot)
+	if len(txm.evictedTransactions) > maxEvictedTransactions {
+		txm.evictedTransactions = txm.evictedTransactions[len(txm.evictedTransactions)-maxEvictedTransactions:]
+	}
+}
+
+// recoverTransactions handles the transactions journaled by a previous run of the agent. The state of succeeded
+// transactions is committed again, because the restart may have interrupted the check handler before it did so.
+// Transactions that were still in progress can not be resumed, the check run that produced them is gone. They are
+// discarded and the check is notified when it starts its next transaction.
+func (txm *transactionManager) recoverTransactions() {
+	if txm.journal == nil {
+		return
+	}
+
+	transactions, err := ReadJournal(txm.journal.path)
+	if err != nil {
+		_ = log.Errorf("Could not recover journaled transactions: %s", err)
+		return
+	}
+
+	for _, transaction := range transactions {
+		switch transaction.Status {
+		case Succeeded:
+			if transaction.State != nil {
+				if err := txm.commitState(transaction.State.Key, transaction.State.State); err != nil {
+					_ = log.Errorf("Could not commit state of recovered transaction %s for check %s: %s",
+						transaction.TransactionID, transaction.CheckID, err)
+				} else {
+					log.Infof("Committed state of recovered transaction %s for check %s", transaction.TransactionID,
+						transaction.CheckID)
+				}
+			}
+		case Failed:
+			// the check was already notified of the failure
+		default:
+			_ = log.Warnf("Discarding recovered transaction %s for check %s that was %s when the agent stopped",
+				transaction.TransactionID, transaction.CheckID, transaction.Status)
+			txm.recoveredDiscards[transaction.CheckID] = append(txm.recoveredDiscards[transaction.CheckID],
+				RecoveredTransactionDiscarded{
+					TransactionID: transaction.TransactionID,
+					Reason:        fmt.Sprintf("transaction was %s when the agent stopped", transaction.Status),
+				})
+		}
+		txm.journal.remove(transaction.TransactionID)
+	}
+}
+
+// commitCheckState commits state to the check state manager
+func commitCheckState(key, state string) error {
+	stateManager := checkState.GetCheckStateManager()
+	if stateManager == nil {
+		return fmt.Errorf("check state manager is not initialized")
+	}
+	return stateManager.SetState(key, state)
+}
@@ -3,6 +3,7 @@ package transactionmanager
 import (
 	"github.com/StackVista/stackstate-agent/pkg/collector/check"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	"sort"
 	"sync"
 )
 
@@ -10,6 +11,7 @@ import (
 type TransactionManager interface {
 	Start()
 	TransactionAPI
+	Transactions() []TransactionSnapshot
 	Stop()
 }
 
@@ -51,6 +53,23 @@ func (txm *transactionManager) TransactionCount() int {
 	return count
 }
 
+// Transactions returns a snapshot of the transactions in the Transaction Manager followed by the most recently evicted
+// transactions, ordered by the time they were last updated.
+func (txm *transactionManager) Transactions() []TransactionSnapshot {
+	txm.mux.RLock()
+	snapshots := make([]TransactionSnapshot, 0, len(txm.transactions)+len(txm.evictedTransactions))
+	for _, transaction := range txm.transactions {
+		snapshots = append(snapshots, newTransactionSnapshot(transaction))
+	}
+	snapshots = append(snapshots, txm.evictedTransactions...)
+	txm.mux.RUnlock()
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].LastUpdatedTimestamp.Before(snapshots[j].LastUpdatedTimestamp)
+	})
+	return snapshots
+}
+
 // StartTransaction begins a transaction for a given check
 func (txm *transactionManager) StartTransaction(checkID check.ID, transactionID string, notifyChannel chan interface{}) {
 	txm.transactionChannel <- StartTransaction{
@@ -226,6 +226,37 @@ func TestTransactionManager_TransactionTimeout(t *testing.T) {
 	txManager.Stop()
 }
 
+func TestTransactionManager_Transactions(t *testing.T) {
+	staleTimeout := 100 * time.Millisecond
+	txManager := newTransactionManager(100, 10*time.Millisecond, staleTimeout,
+		300*time.Millisecond).(*transactionManager)
+	defer txManager.Stop()
+
+	txNotifyChannel := make(chan interface{}, 10)
+	txID := uuid.New().String()
+	txManager.StartTransaction("CheckID", txID, txNotifyChannel)
+	commitAssertAction(t, txManager, txID, "action-1", map[string]*Action{})
+
+	transactions := txManager.Transactions()
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, txID, transactions[0].TransactionID)
+	assert.Equal(t, "CheckID", string(transactions[0].CheckID))
+	assert.Equal(t, InProgress, transactions[0].Status)
+	assert.Equal(t, Committed, transactions[0].Actions["action-1"].Status)
+	assert.False(t, transactions[0].Actions["action-1"].CommittedTimestamp.IsZero())
+
+	// wait for the eviction notification, the evicted transaction is still listed
+	notify := <-txNotifyChannel
+	assert.Equal(t, EvictedTransaction{TransactionID: txID}, notify)
+	assert.Equal(t, 0, txManager.TransactionCount())
+
+	transactions = txManager.Transactions()
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, txID, transactions[0].TransactionID)
+	assert.Equal(t, Evicted, transactions[0].Status)
+	assert.Len(t, transactions[0].Actions, 1)
+}
+
 func TestTransactionManager_ErrorHandling(t *testing.T) {
 	txManager := newTransactionManager(100, 100*time.Millisecond, 1*time.Second,
 		1*time.Second).(*transactionManager)
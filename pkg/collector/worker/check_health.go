@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/collector/runner/expvars"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// [sts] health stream with the run status of every scheduled check
+
+// checkRunHealthName is the name of the check state with the run status of a check
+const checkRunHealthName = "Check run status"
+
+// checkRunHealthExpiryIntervals is the number of check intervals after which the run status of a check expires when the
+// check is no longer scheduled
+const checkRunHealthExpiryIntervals = 3
+
+// checkRunHealthStream returns the agent-integration health stream of the agent, with a sub stream per check
+func checkRunHealthStream(hostname string, checkID check.ID) health.Stream {
+	return health.Stream{Urn: fmt.Sprintf("urn:health:agent-integration:%s", hostname), SubStream: string(checkID)}
+}
+
+// checkRunHealthCheckID is the id the run status of a check is batched with, every check gets its own batch so the
+// snapshots of checks finishing at the same time on different workers do not interleave
+func checkRunHealthCheckID(checkID check.ID) check.ID {
+	return check.ID(fmt.Sprintf("%s_run_health", checkID))
+}
+
+// checkRunHealthIdentifier returns the component the run status of a check is mapped onto, the agent-integration
+// component the check submitted or otherwise the host of the agent, i.e. for go checks that do not submit one
+func checkRunHealthIdentifier(checkID check.ID, hostname string) string {
+	if cm := handler.GetCheckManager(); cm != nil {
+		if externalID, found := cm.GetAgentIntegrationID(checkID); found {
+			return externalID
+		}
+	}
+	return fmt.Sprintf("urn:host:/%s", hostname)
+}
+
+// checkRunHealth derives the run status of a check from its stats. A failed run is CRITICAL, a run with warnings or
+// that took longer than the interval of the check is DEVIATING.
+func checkRunHealth(stats *check.Stats, interval time.Duration, topologyElementIdentifier string) health.CheckData {
+	state := health.Clear
+	message := fmt.Sprintf("Last run succeeded in %dms", stats.LastExecutionTime)
+	lastExecutionTime := time.Duration(stats.LastExecutionTime) * time.Millisecond
+
+	switch {
+	case stats.LastError != "":
+		state = health.Critical
+		message = fmt.Sprintf("Last run failed: %s", stats.LastError)
+	case len(stats.LastWarnings) > 0:
+		state = health.Deviating
+		message = fmt.Sprintf("Last run had %d warning(s): %s", len(stats.LastWarnings), strings.Join(stats.LastWarnings, "; "))
+	case interval > 0 && lastExecutionTime > interval:
+		state = health.Deviating
+		message = fmt.Sprintf("Last run took %s, longer than the collection interval of %s", lastExecutionTime, interval)
+	}
+
+	return health.CheckData{CheckState: &health.CheckState{
+		CheckStateID:              fmt.Sprintf("%s:%s", checkRunHealthName, stats.CheckID),
+		Message:                   message,
+		Health:                    state,
+		TopologyElementIdentifier: topologyElementIdentifier,
+		Name:                      checkRunHealthName,
+	}}
+}
+
+// submitCheckRunHealth submits the run status of a check as a snapshot of its sub stream. The snapshot repeats with the
+// interval of the check and expires when the check is unscheduled and no longer runs.
+func submitCheckRunHealth(c check.Check, hostname string) {
+	if !config.Datadog.GetBool("check_run_health_enabled") || c.Interval() == 0 {
+		return
+	}
+	stats, found := expvars.CheckStats(c.ID())
+	if !found {
+		return
+	}
+	b := batcher.GetBatcher()
+	if b == nil {
+		log.Debugf("No batcher instance available, skipping the run status health of check %s", c.ID())
+		return
+	}
+
+	checkID := checkRunHealthCheckID(c.ID())
+	stream := checkRunHealthStream(hostname, c.ID())
+	intervalSeconds := int(c.Interval().Seconds())
+	b.SubmitHealthStartSnapshot(checkID, stream, intervalSeconds, checkRunHealthExpiryIntervals*intervalSeconds)
+	b.SubmitHealthCheckData(checkID, stream, checkRunHealth(stats, c.Interval(), checkRunHealthIdentifier(c.ID(), hostname)))
+	b.SubmitHealthStopSnapshot(checkID, stream)
+	b.SubmitComplete(checkID)
+}
@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check/handler"
+	"github.com/StackVista/stackstate-agent/pkg/collector/runner/expvars"
+	"github.com/StackVista/stackstate-agent/pkg/collector/runner/tracker"
+	"github.com/StackVista/stackstate-agent/pkg/config"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+)
+
+// intervalCheck is a test check that runs with a collection interval of whole seconds
+type intervalCheck struct {
+	*testCheck
+	interval time.Duration
+}
+
+func (c *intervalCheck) Interval() time.Duration { return c.interval }
+
+func TestCheckRunHealth(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		stats           *check.Stats
+		expectedHealth  health.State
+		expectedMessage string
+	}{
+		{
+			name:            "successful run",
+			stats:           &check.Stats{CheckID: "cpu", CheckName: "cpu", LastExecutionTime: 12},
+			expectedHealth:  health.Clear,
+			expectedMessage: "Last run succeeded in 12ms",
+		},
+		{
+			name:            "failed run",
+			stats:           &check.Stats{CheckID: "cpu", CheckName: "cpu", LastError: "no cpu found", LastWarnings: []string{"slow"}},
+			expectedHealth:  health.Critical,
+			expectedMessage: "Last run failed: no cpu found",
+		},
+		{
+			name:            "run with warnings",
+			stats:           &check.Stats{CheckID: "cpu", CheckName: "cpu", LastWarnings: []string{"slow", "partial"}},
+			expectedHealth:  health.Deviating,
+			expectedMessage: "Last run had 2 warning(s): slow; partial",
+		},
+		{
+			name:            "run longer than the interval",
+			stats:           &check.Stats{CheckID: "cpu", CheckName: "cpu", LastExecutionTime: 20000},
+			expectedHealth:  health.Deviating,
+			expectedMessage: "Last run took 20s, longer than the collection interval of 15s",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, health.CheckData{CheckState: &health.CheckState{
+				CheckStateID:              "Check run status:cpu",
+				Message:                   tc.expectedMessage,
+				Health:                    tc.expectedHealth,
+				TopologyElementIdentifier: "urn:host:/myhost",
+				Name:                      "Check run status",
+			}}, checkRunHealth(tc.stats, 15*time.Second, "urn:host:/myhost"))
+		})
+	}
+}
+
+func TestWorkerCheckRunHealth(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+	config.Datadog.Set("check_run_health_enabled", true)
+	defer config.Datadog.Set("check_run_health_enabled", false)
+	mockBatcher := batcher.NewMockBatcher()
+
+	pendingChecksChan := make(chan check.Check, 10)
+	failingCheck := &intervalCheck{testCheck: newCheck(t, "failing:123", true, nil), interval: 15 * time.Second}
+	longRunningCheck := &testCheck{t: t, id: "long_running:123", longRunning: true, doErr: true}
+	pendingChecksChan <- failingCheck
+	pendingChecksChan <- longRunningCheck
+	close(pendingChecksChan)
+
+	worker, err := NewWorker(100, 200, pendingChecksChan, tracker.NewRunningChecksTracker(), func(id check.ID) bool { return true })
+	require.Nil(t, err)
+	worker.Run()
+
+	produced := mockBatcher.CollectedTopology.Flush()
+	// long running checks have no interval to repeat their run status with
+	assert.NotContains(t, produced, checkRunHealthCheckID(longRunningCheck.ID()))
+
+	stream := health.Stream{Urn: "urn:health:agent-integration:myhost", SubStream: "failing:123"}
+	producedHealth := produced[checkRunHealthCheckID(failingCheck.ID())].Health[stream.GoString()]
+	assert.Equal(t, &health.StartSnapshotMetadata{RepeatIntervalS: 15, ExpiryIntervalS: 45}, producedHealth.StartSnapshot)
+	assert.Equal(t, &health.StopSnapshotMetadata{}, producedHealth.StopSnapshot)
+	assert.Equal(t, stream, producedHealth.Stream)
+	require.Len(t, producedHealth.CheckStates, 1)
+	assert.Equal(t, health.Critical, producedHealth.CheckStates[0].CheckState.Health)
+	assert.Equal(t, "urn:host:/myhost", producedHealth.CheckStates[0].CheckState.TopologyElementIdentifier)
+
+	config.Datadog.Set("check_run_health_enabled", false)
+	submitCheckRunHealth(failingCheck, "myhost")
+	assert.Empty(t, mockBatcher.CollectedTopology.Flush())
+}
+
+func TestCheckRunHealthIdentifier(t *testing.T) {
+	config.Datadog.Set("check_transactionality_enabled", false)
+	defer config.Datadog.Set("check_transactionality_enabled", true)
+	handler.InitCheckManager()
+	defer handler.GetCheckManager().Stop()
+	_ = batcher.NewMockBatcher()
+
+	instance := topology.Instance{Type: "agent", URL: "integrations"}
+	ch := handler.GetCheckManager().RegisterCheckHandler(&check.STSTestCheck{Name: "mysql"}, nil, nil)
+
+	// checks that do not submit an agent-integration component are mapped onto the host
+	assert.Equal(t, "urn:host:/myhost", checkRunHealthIdentifier(ch.ID(), "myhost"))
+
+	ch.SubmitComponent(instance, topology.Component{ExternalID: "urn:agent-integration:/myhost:mysql",
+		Type: topology.Type{Name: "agent-integration"}, Data: topology.Data{}})
+	assert.Equal(t, "urn:agent-integration:/myhost:mysql", checkRunHealthIdentifier(ch.ID(), "myhost"))
+
+	// the instance component of the check is preferred
+	ch.SubmitComponent(instance, topology.Component{ExternalID: "urn:agent-integration-instance:/myhost:mysql:db1",
+		Type: topology.Type{Name: "agent-integration-instance"}, Data: topology.Data{}})
+	ch.SubmitComponent(instance, topology.Component{ExternalID: "urn:agent-integration:/myhost:mysql",
+		Type: topology.Type{Name: "agent-integration"}, Data: topology.Data{}})
+	assert.Equal(t, "urn:agent-integration-instance:/myhost:mysql:db1", checkRunHealthIdentifier(ch.ID(), "myhost"))
+}
+
+func TestCheckRunHealthWithoutStats(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("check_run_health_enabled", true)
+	defer config.Datadog.Set("check_run_health_enabled", false)
+	mockBatcher := batcher.NewMockBatcher()
+
+	// a check without stats is not scheduled, its run status is left to expire
+	submitCheckRunHealth(&intervalCheck{testCheck: newCheck(t, "unscheduled:123", false, nil), interval: 15 * time.Second}, "myhost")
+	assert.Empty(t, mockBatcher.CollectedTopology.Flush())
+}
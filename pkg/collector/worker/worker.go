@@ -192,6 +192,8 @@ func (w *Worker) Run() {
 			if w.shouldAddCheckStatsFunc(check.ID()) {
 				sStats, _ := check.GetSenderStats()
 				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
+				// [sts] publish the run status of the check as health
+				submitCheckRunHealth(check, hostname)
 			}
 		}
 
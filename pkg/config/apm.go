@@ -90,6 +90,12 @@ func setupAPM(config Config) {
 	config.BindEnv("apm_config.debugger_api_key", "DD_APM_DEBUGGER_API_KEY")
 	config.BindEnv("apm_config.obfuscation.credit_cards.enabled", "DD_APM_OBFUSCATION_CREDIT_CARDS_ENABLED")
 	config.BindEnv("apm_config.obfuscation.credit_cards.luhn", "DD_APM_OBFUSCATION_CREDIT_CARDS_LUHN")
+	// [sts] topology derived from the interpreted spans
+	config.BindEnv("apm_config.trace_topology.enabled", "DD_APM_TRACE_TOPOLOGY_ENABLED")
+	config.BindEnv("apm_config.trace_topology.interval_seconds", "DD_APM_TRACE_TOPOLOGY_INTERVAL_SECONDS")
+	config.BindEnv("apm_config.trace_topology.expiry_seconds", "DD_APM_TRACE_TOPOLOGY_EXPIRY_SECONDS")
+	config.BindEnv("apm_config.trace_topology.error_rate_deviating", "DD_APM_TRACE_TOPOLOGY_ERROR_RATE_DEVIATING")
+	config.BindEnv("apm_config.trace_topology.error_rate_critical", "DD_APM_TRACE_TOPOLOGY_ERROR_RATE_CRITICAL")
 
 	config.SetEnvKeyTransformer("apm_config.ignore_resources", func(in string) interface{} {
 		r, err := splitCSVString(in, ',')
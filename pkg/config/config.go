@@ -86,6 +86,9 @@ const (
 	// DefaultBatcherBufferSize sets the default buffer size of the batcher to 10000
 	// [sts]
 	DefaultBatcherBufferSize = 10000
+	// DefaultBatcherDedupRefreshIntervalSeconds is the interval after which the batcher deduplication forwards all the
+	// topology elements again, 30 minutes by default
+	DefaultBatcherDedupRefreshIntervalSeconds = 60 * 30
 
 	// DefaultTxManagerChannelBufferSize is the concurrent transactions before the tx manager begins backpressure
 	// [sts] transaction manager
@@ -102,6 +105,21 @@ const (
 	DefaultCheckStateExpirationDuration = 10 * time.Minute
 	// DefaultCheckStatePurgeDuration is the amount of time before an element is removed from the Check State cache, 10 minutes by default
 	DefaultCheckStatePurgeDuration = 10 * time.Minute
+
+	// DefaultTxForwarderSpoolMaxSizeBytes is the maximum amount of disk space used by the transactional forwarder spool, 64MB by default
+	// [sts] transactional forwarder spool
+	DefaultTxForwarderSpoolMaxSizeBytes = 64 * 1024 * 1024
+	// DefaultTxForwarderSpoolMaxAge is the amount of time a payload is kept in the spool before it is rejected, 5 minutes by default
+	DefaultTxForwarderSpoolMaxAge = 5 * time.Minute
+	// DefaultTxForwarderSpoolMaxPayloadsPerCheck is the maximum amount of spooled payloads for a single check
+	DefaultTxForwarderSpoolMaxPayloadsPerCheck = 100
+	// DefaultTxForwarderSpoolRetryInterval is the interval in which the spool attempts to replay spooled payloads
+	DefaultTxForwarderSpoolRetryInterval = 10 * time.Second
+
+	// DefaultTxBatcherMaxPayloadSizeBytes is the serialized size of the data after which the transactional batcher
+	// flushes, 4MB by default. The size is measured before the forwarder gzip compresses the body.
+	// [sts] transactional batcher
+	DefaultTxBatcherMaxPayloadSizeBytes = 4 * 1024 * 1024
 )
 
 // Datadog is the global configuration object
@@ -292,6 +310,11 @@ func InitConfig(config Config) {
 
 	// [sts] batcher environment variables
 	config.BindEnvAndSetDefault("batcher_capacity", DefaultBatcherBufferSize)
+	config.BindEnvAndSetDefault("batcher_dedup_enabled", false)
+	config.BindEnvAndSetDefault("batcher_dedup_refresh_interval_seconds", DefaultBatcherDedupRefreshIntervalSeconds)
+
+	// [sts] health rules that derive health states for hosts from the gauges of the checks
+	config.SetKnown("metric_health_rules")
 
 	// overridden in IoT Agent main
 	config.BindEnvAndSetDefault("iot_host", false)
@@ -316,19 +339,37 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("transaction_timeout_duration_seconds", DefaultTxManagerTimeoutDurationSeconds)
 	config.BindEnvAndSetDefault("transaction_eviction_duration_seconds", DefaultTxManagerEvictionDurationSeconds)
 	config.BindEnvAndSetDefault("transaction_ticket_interval_seconds", DefaultTxManagerTickerIntervalSeconds)
+	config.BindEnvAndSetDefault("transaction_manager_journal_enabled", false)
+	config.BindEnvAndSetDefault("transaction_manager_journal_path", filepath.Join(Datadog.GetString("run_path"), "transactions"))
 
 	// [sts] check state manager environment variable
 	config.BindEnvAndSetDefault("check_state_root_path", Datadog.GetString("run_path"))
 	config.BindEnvAndSetDefault("check_state_expiration_duration", DefaultCheckStateExpirationDuration)
 	config.BindEnvAndSetDefault("check_state_purge_duration", DefaultCheckStatePurgeDuration)
+	config.BindEnvAndSetDefault("check_state_backend", "disk")
 
 	// [sts] check manager environment variables
 	config.BindEnvAndSetDefault("check_transactionality_enabled", true)
+	// [sts] health stream with the run status of every scheduled check
+	config.BindEnvAndSetDefault("check_run_health_enabled", false)
+	// [sts] validation of the topology and health submitted by checks: off, warn or reject
+	config.BindEnvAndSetDefault("check_topology_validation", "off")
 
 	// [sts] retryable http client environment variables
 	config.BindEnvAndSetDefault("transactional_forwarder_retry_min", 1*time.Second)
 	config.BindEnvAndSetDefault("transactional_forwarder_retry_max", 10*time.Second)
 
+	// [sts] transactional forwarder disk spool environment variables
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_enabled", false)
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_path", filepath.Join(Datadog.GetString("run_path"), "transactional_spool"))
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_max_size_bytes", DefaultTxForwarderSpoolMaxSizeBytes)
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_max_age", DefaultTxForwarderSpoolMaxAge)
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_max_payloads_per_check", DefaultTxForwarderSpoolMaxPayloadsPerCheck)
+	config.BindEnvAndSetDefault("transactional_forwarder_spool_retry_interval", DefaultTxForwarderSpoolRetryInterval)
+
+	// [sts] transactional batcher environment variables
+	config.BindEnvAndSetDefault("transactional_batcher_max_payload_size_bytes", DefaultTxBatcherMaxPayloadSizeBytes)
+
 	// Python 3 linter timeout, in seconds
 	// NOTE: linter is notoriously slow, in the absence of a better solution we
 	//       can only increase this timeout value. Linting operation is async.
@@ -594,12 +635,15 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("kubelet_fallback_to_unverified_tls", true) // sts
 	config.BindEnvAndSetDefault("kubelet_fallback_to_insecure", true)       // sts
 	config.BindEnvAndSetDefault("collect_kubernetes_events", false)
-	config.BindEnvAndSetDefault("collect_kubernetes_metrics", false)          // sts
-	config.BindEnvAndSetDefault("collect_kubernetes_topology", false)         // sts
-	config.BindEnvAndSetDefault("collect_kubernetes_timeout", 10)             // sts
-	config.BindEnvAndSetDefault("configmap_max_datasize", 0)                  // sts
-	config.BindEnvAndSetDefault("kubernetes_source_properties_enabled", true) // sts
-	config.BindEnvAndSetDefault("kubernetes_csi_pv_mapper_enabled", false)    // sts
+	config.BindEnvAndSetDefault("collect_kubernetes_metrics", false)              // sts
+	config.BindEnvAndSetDefault("collect_kubernetes_topology", false)             // sts
+	config.BindEnvAndSetDefault("collect_kubernetes_timeout", 10)                 // sts
+	config.BindEnvAndSetDefault("configmap_max_datasize", 0)                      // sts
+	config.BindEnvAndSetDefault("kubernetes_source_properties_enabled", true)     // sts
+	config.BindEnvAndSetDefault("kubernetes_csi_pv_mapper_enabled", false)        // sts
+	config.BindEnvAndSetDefault("kubernetes_topology_incremental_enabled", false) // sts
+	config.BindEnvAndSetDefault("kubernetes_topology_full_resync_interval", 30)   // sts, in minutes
+	config.BindEnvAndSetDefault("kubernetes_topology_informer_sync_timeout", 60)  // sts, in seconds
 	config.BindEnvAndSetDefault("kubelet_client_ca", "")
 
 	config.BindEnvAndSetDefault("kubelet_auth_token_path", "")
@@ -884,6 +928,9 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_checks.extra_tags", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
+	config.BindEnvAndSetDefault("cluster_checks.pin_transactional_checks", false)                 // sts
+	config.BindEnvAndSetDefault("cluster_checks.check_state_store_enabled", false)                // sts
+	config.BindEnvAndSetDefault("cluster_checks.check_state_configmap_prefix", "sts-check-state") // sts
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
 	config.BindEnvAndSetDefault("clc_runner_id", "")
@@ -1321,6 +1368,16 @@ func GetMaxCapacity() int {
 	return DefaultBatcherBufferSize
 }
 
+// GetBatcherDedupConfig returns whether the topology deduplication of the batcher is enabled and the interval after which
+// all the topology elements are forwarded again
+// [sts]
+func GetBatcherDedupConfig() (bool, time.Duration) {
+	enabled := Datadog.GetBool("batcher_dedup_enabled")
+	refreshInterval := time.Second * time.Duration(Datadog.GetInt("batcher_dedup_refresh_interval_seconds"))
+
+	return enabled, refreshInterval
+}
+
 // GetTxManagerConfig returns the transaction manager configuration. The buffer size, the time duration and the eviction duration
 // [sts]
 func GetTxManagerConfig() (int, time.Duration, time.Duration, time.Duration) {
@@ -11,6 +11,7 @@ import (
 	"io"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -105,9 +106,41 @@ func GetClusterChecks(w io.Writer, checkName string) error {
 		}
 	}
 
+	printDispatchDecisions(w, cr.Decisions, checkName) // sts
+
 	return nil
 }
 
+// sts
+// printDispatchDecisions prints the latest decisions of the dispatcher, oldest first
+func printDispatchDecisions(w io.Writer, decisions []types.DispatchDecision, checkName string) {
+	filtered := make([]types.DispatchDecision, 0, len(decisions))
+	for _, d := range decisions {
+		if checkName == "" || d.CheckName == checkName {
+			filtered = append(filtered, d)
+		}
+	}
+	if len(filtered) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, fmt.Sprintf("\n===== %s =====", color.HiMagentaString("Dispatching decisions")))
+	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, "Time\tAction\tCheck\tFrom\tTo\tReason")
+	for _, d := range filtered {
+		fmt.Fprintf(table, "%s\t%s\t%s:%s\t%s\t%s\t%s\n", time.Unix(d.Timestamp, 0).UTC().Format(time.RFC3339), d.Action,
+			d.CheckName, d.Digest, orDash(d.SourceNodeName), orDash(d.DestNodeName), d.Reason)
+	}
+	table.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // GetEndpointsChecks dumps the endpointschecks dispatching state to the writer
 func GetEndpointsChecks(w io.Writer, checkName string) error {
 	if !endpointschecksEnabled() {
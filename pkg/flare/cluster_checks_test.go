@@ -0,0 +1,38 @@
+package flare
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/StackVista/stackstate-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestPrintDispatchDecisions(t *testing.T) {
+	color.NoColor = true
+	decisions := []types.DispatchDecision{
+		{Timestamp: 1700000000, Action: "dispatched", CheckName: "http_check", Digest: "abc", DestNodeName: "node1", Reason: "least busy node"},
+		{Timestamp: 1700000600, Action: "pinned", CheckName: "kubernetes", Digest: "def", SourceNodeName: "node1", Reason: "transaction in progress"},
+	}
+
+	var out bytes.Buffer
+	printDispatchDecisions(&out, decisions, "")
+	assert.Equal(t, `
+===== Dispatching decisions =====
+Time                   Action       Check            From    To      Reason
+2023-11-14T22:13:20Z   dispatched   http_check:abc   -       node1   least busy node
+2023-11-14T22:23:20Z   pinned       kubernetes:def   node1   -       transaction in progress
+`, out.String())
+
+	out.Reset()
+	printDispatchDecisions(&out, decisions, "kubernetes")
+	assert.NotContains(t, out.String(), "http_check")
+	assert.Contains(t, out.String(), "kubernetes:def")
+
+	// nothing is printed without decisions for the check
+	out.Reset()
+	printDispatchDecisions(&out, decisions, "unknown")
+	assert.Empty(t, out.String())
+}
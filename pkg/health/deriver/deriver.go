@@ -0,0 +1,158 @@
+// Package deriver derives health states for topology elements from data that is observed locally in the agent, so
+// health streams can be produced without writing a check that computes the health states itself.
+package deriver
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+// Observation is a value or a state that is observed for a topology element
+type Observation struct {
+	// TopologyElementIdentifier is the external id or identifier of the element the derived health is bound to
+	TopologyElementIdentifier string
+	// Source is what was observed, it selects the rules that are evaluated
+	Source string
+	Value  float64
+	State  string
+}
+
+// Submitter receives the derived health snapshots, it is implemented by the check handler
+type Submitter interface {
+	SubmitHealthCheckData(stream health.Stream, data health.CheckData)
+	SubmitHealthStartSnapshot(stream health.Stream, intervalSeconds int, expirySeconds int)
+	SubmitHealthStopSnapshot(stream health.Stream)
+}
+
+type observationKey struct {
+	source, topologyElementIdentifier string
+}
+
+type timedObservation struct {
+	Observation
+	observed time.Time
+}
+
+// Deriver keeps the latest observation per source and topology element and submits the health states derived from
+// them as a health snapshot on every flush.
+type Deriver struct {
+	stream                health.Stream
+	rules                 []Rule
+	sources               map[string]bool
+	repeatIntervalSeconds int
+	// retention is how long observations are kept in between flushes, when zero they are dropped after every flush
+	retention time.Duration
+
+	mux          sync.Mutex
+	observations map[observationKey]timedObservation
+}
+
+// NewDeriver creates a deriver for sources that are observed completely before each flush, i.e. by a check run. Elements
+// that are not observed again before the next flush drop out of the health snapshot.
+func NewDeriver(stream health.Stream, rules []Rule, repeatIntervalSeconds int) *Deriver {
+	return NewRetainingDeriver(stream, rules, repeatIntervalSeconds, 0)
+}
+
+// NewRetainingDeriver creates a deriver for sources that are observed at their own pace, i.e. metrics. Observations are
+// kept for the retention so elements don't drop out of the health snapshot when they are not observed before a flush.
+func NewRetainingDeriver(stream health.Stream, rules []Rule, repeatIntervalSeconds int, retention time.Duration) *Deriver {
+	sources := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		sources[rule.Source()] = true
+	}
+	return &Deriver{
+		stream:                stream,
+		rules:                 rules,
+		sources:               sources,
+		repeatIntervalSeconds: repeatIntervalSeconds,
+		retention:             retention,
+		observations:          make(map[observationKey]timedObservation),
+	}
+}
+
+// Observes returns whether any rule is evaluated against the source, other observations are ignored
+func (d *Deriver) Observes(source string) bool {
+	return d.sources[source]
+}
+
+// Observe records an observation, replacing an earlier observation of the same source for the same element
+func (d *Deriver) Observe(observation Observation) {
+	d.observeAt(observation, time.Now())
+}
+
+// ObserveMetric records the value of a metric reported for a host
+func (d *Deriver) ObserveMetric(name string, value float64, hostname string) {
+	if hostname == "" || !d.Observes(name) {
+		return
+	}
+	d.Observe(Observation{
+		TopologyElementIdentifier: fmt.Sprintf("urn:host:/%s", hostname),
+		Source:                    name,
+		Value:                     value,
+	})
+}
+
+func (d *Deriver) observeAt(observation Observation, now time.Time) {
+	if !d.Observes(observation.Source) {
+		return
+	}
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	key := observationKey{source: observation.Source, topologyElementIdentifier: observation.TopologyElementIdentifier}
+	d.observations[key] = timedObservation{Observation: observation, observed: now}
+}
+
+// Flush submits a health snapshot with the states derived from the observations
+func (d *Deriver) Flush(submitter Submitter) {
+	d.flushAt(submitter, time.Now())
+}
+
+func (d *Deriver) flushAt(submitter Submitter, now time.Time) {
+	d.mux.Lock()
+	if d.retention > 0 {
+		for key, observation := range d.observations {
+			if now.Sub(observation.observed) > d.retention {
+				delete(d.observations, key)
+			}
+		}
+	}
+	checkStates := d.deriveCheckStates()
+	if d.retention == 0 {
+		d.observations = make(map[observationKey]timedObservation)
+	}
+	d.mux.Unlock()
+
+	submitter.SubmitHealthStartSnapshot(d.stream, d.repeatIntervalSeconds, 0)
+	for _, checkState := range checkStates {
+		submitter.SubmitHealthCheckData(d.stream, health.CheckData{CheckState: checkState})
+	}
+	submitter.SubmitHealthStopSnapshot(d.stream)
+}
+
+// deriveCheckStates evaluates all rules against the observations, sorted to submit them in a stable order
+func (d *Deriver) deriveCheckStates() []*health.CheckState {
+	checkStates := make([]*health.CheckState, 0, len(d.observations))
+	for _, observation := range d.observations {
+		for _, rule := range d.rules {
+			state, message, ok := rule.Derive(observation.Observation)
+			if !ok {
+				continue
+			}
+			checkStates = append(checkStates, &health.CheckState{
+				CheckStateID:              fmt.Sprintf("%s:%s", rule.Name(), observation.TopologyElementIdentifier),
+				Message:                   message,
+				Health:                    state,
+				TopologyElementIdentifier: observation.TopologyElementIdentifier,
+				Name:                      rule.Name(),
+			})
+		}
+	}
+	sort.Slice(checkStates, func(i, j int) bool {
+		return checkStates[i].CheckStateID < checkStates[j].CheckStateID
+	})
+	return checkStates
+}
@@ -0,0 +1,116 @@
+package deriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSubmitter struct {
+	stream      health.Stream
+	started     int
+	stopped     int
+	interval    int
+	checkStates []health.CheckState
+}
+
+func (r *recordingSubmitter) SubmitHealthCheckData(_ health.Stream, data health.CheckData) {
+	r.checkStates = append(r.checkStates, *data.CheckState)
+}
+
+func (r *recordingSubmitter) SubmitHealthStartSnapshot(stream health.Stream, intervalSeconds int, _ int) {
+	r.stream = stream
+	r.interval = intervalSeconds
+	r.started++
+}
+
+func (r *recordingSubmitter) SubmitHealthStopSnapshot(health.Stream) {
+	r.stopped++
+}
+
+func testRules(t *testing.T) []Rule {
+	rules, errs := NewRules([]RuleConfig{
+		{Name: "Pod restarts", Source: "pod_restarts", CriticalAbove: float(5)},
+		{Name: "Container state", Source: "container_state", States: map[string]string{"running": "CLEAR", "exited": "CRITICAL"}},
+	})
+	assert.Empty(t, errs)
+	return rules
+}
+
+func TestDeriverFlush(t *testing.T) {
+	stream := health.Stream{Urn: "urn:health:test"}
+	deriver := NewDeriver(stream, testRules(t), 30)
+
+	deriver.Observe(Observation{TopologyElementIdentifier: "urn:pod/b", Source: "pod_restarts", Value: 10})
+	deriver.Observe(Observation{TopologyElementIdentifier: "urn:pod/a", Source: "pod_restarts", Value: 7})
+	deriver.Observe(Observation{TopologyElementIdentifier: "urn:pod/a", Source: "pod_restarts", Value: 1})
+	deriver.Observe(Observation{TopologyElementIdentifier: "urn:container/c", Source: "container_state", State: "exited"})
+	deriver.Observe(Observation{TopologyElementIdentifier: "urn:host/h", Source: "cpu", Value: 100})
+
+	submitter := &recordingSubmitter{}
+	deriver.Flush(submitter)
+
+	assert.Equal(t, stream, submitter.stream)
+	assert.Equal(t, 30, submitter.interval)
+	assert.Equal(t, 1, submitter.started)
+	assert.Equal(t, 1, submitter.stopped)
+	assert.Equal(t, []health.CheckState{
+		{
+			CheckStateID:              "Container state:urn:container/c",
+			Message:                   "container_state is exited",
+			Health:                    health.Critical,
+			TopologyElementIdentifier: "urn:container/c",
+			Name:                      "Container state",
+		},
+		{
+			CheckStateID:              "Pod restarts:urn:pod/a",
+			Message:                   "pod_restarts is 1",
+			Health:                    health.Clear,
+			TopologyElementIdentifier: "urn:pod/a",
+			Name:                      "Pod restarts",
+		},
+		{
+			CheckStateID:              "Pod restarts:urn:pod/b",
+			Message:                   "pod_restarts is 10, above the critical threshold of 5",
+			Health:                    health.Critical,
+			TopologyElementIdentifier: "urn:pod/b",
+			Name:                      "Pod restarts",
+		},
+	}, submitter.checkStates)
+
+	// the observations are dropped after the flush, so the next snapshot is empty
+	submitter = &recordingSubmitter{}
+	deriver.Flush(submitter)
+	assert.Equal(t, 1, submitter.started)
+	assert.Equal(t, 1, submitter.stopped)
+	assert.Empty(t, submitter.checkStates)
+}
+
+func TestRetainingDeriverFlush(t *testing.T) {
+	rules, errs := NewRules([]RuleConfig{{Name: "Load", Source: "system.load.1", CriticalAbove: float(4)}})
+	assert.Empty(t, errs)
+	deriver := NewRetainingDeriver(health.Stream{Urn: "urn:health:test"}, rules, 15, time.Minute)
+
+	start := time.Now()
+	deriver.ObserveMetric("system.load.1", 8, "host-a")
+	deriver.ObserveMetric("system.load.1", 8, "")
+	deriver.ObserveMetric("system.mem.used", 8, "host-a")
+	deriver.observeAt(Observation{TopologyElementIdentifier: "urn:host:/host-b", Source: "system.load.1", Value: 1}, start.Add(-2*time.Minute))
+
+	// the stale observation of host-b is dropped, host-a is kept until it is stale as well
+	submitter := &recordingSubmitter{}
+	deriver.flushAt(submitter, start)
+	assert.Len(t, submitter.checkStates, 1)
+
+	submitter = &recordingSubmitter{}
+	deriver.flushAt(submitter, start.Add(30*time.Second))
+	assert.Len(t, submitter.checkStates, 1)
+	assert.Equal(t, "urn:host:/host-a", submitter.checkStates[0].TopologyElementIdentifier)
+	assert.Equal(t, health.Critical, submitter.checkStates[0].Health)
+
+	submitter = &recordingSubmitter{}
+	deriver.flushAt(submitter, start.Add(2*time.Minute))
+	assert.Empty(t, submitter.checkStates)
+}
@@ -0,0 +1,142 @@
+package deriver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+// RuleConfig is the configuration of a rule that derives a health state from observations of a single source. A rule
+// is either a threshold rule, when one of the thresholds is set, or a state rule, when states are mapped.
+type RuleConfig struct {
+	// Name is the name of the derived check state, it has to be unique within the rules of a deriver
+	Name string `yaml:"name" mapstructure:"name"`
+	// Source is what the rule is evaluated against, i.e. "pod_restarts", "container_state" or a metric name
+	Source string `yaml:"source" mapstructure:"source"`
+	// DeviatingAbove makes the health deviating when the observed value is above it
+	DeviatingAbove *float64 `yaml:"deviating_above" mapstructure:"deviating_above"`
+	// CriticalAbove makes the health critical when the observed value is above it
+	CriticalAbove *float64 `yaml:"critical_above" mapstructure:"critical_above"`
+	// States maps an observed state, i.e. "exited", to a health state
+	States map[string]string `yaml:"states" mapstructure:"states"`
+	// DefaultState is the health state of observed states that are not mapped, when empty they are left out
+	DefaultState string `yaml:"default_state" mapstructure:"default_state"`
+}
+
+// Rule derives a health state from an observation
+type Rule struct {
+	name           string
+	source         string
+	deviatingAbove *float64
+	criticalAbove  *float64
+	states         map[string]health.State
+	defaultState   health.State
+}
+
+// NewRule validates the rule configuration and creates the rule
+func NewRule(config RuleConfig) (Rule, error) {
+	if config.Name == "" || config.Source == "" {
+		return Rule{}, fmt.Errorf("health rule requires a name and a source")
+	}
+	isThreshold := config.DeviatingAbove != nil || config.CriticalAbove != nil
+	isState := len(config.States) > 0
+	if isThreshold == isState {
+		return Rule{}, fmt.Errorf("health rule '%s' requires either thresholds or states", config.Name)
+	}
+	if config.DeviatingAbove != nil && config.CriticalAbove != nil && *config.DeviatingAbove > *config.CriticalAbove {
+		return Rule{}, fmt.Errorf("health rule '%s' has a deviating threshold above the critical threshold", config.Name)
+	}
+
+	rule := Rule{
+		name:           config.Name,
+		source:         config.Source,
+		deviatingAbove: config.DeviatingAbove,
+		criticalAbove:  config.CriticalAbove,
+	}
+	if isState {
+		rule.states = make(map[string]health.State, len(config.States))
+		for observed, state := range config.States {
+			healthState, err := parseState(state)
+			if err != nil {
+				return Rule{}, fmt.Errorf("health rule '%s': %v", config.Name, err)
+			}
+			rule.states[observed] = healthState
+		}
+		if config.DefaultState != "" {
+			healthState, err := parseState(config.DefaultState)
+			if err != nil {
+				return Rule{}, fmt.Errorf("health rule '%s': %v", config.Name, err)
+			}
+			rule.defaultState = healthState
+		}
+	}
+	return rule, nil
+}
+
+// NewRules creates the rules for the configurations. Invalid configurations are returned as errors and left out, the
+// valid ones are still used.
+func NewRules(configs []RuleConfig) ([]Rule, []error) {
+	var rules []Rule
+	var errs []error
+	names := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		rule, err := NewRule(config)
+		if err == nil && names[rule.name] {
+			err = fmt.Errorf("health rule '%s' is configured more than once", rule.name)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names[rule.name] = true
+		rules = append(rules, rule)
+	}
+	return rules, errs
+}
+
+// Name returns the name of the derived check state
+func (r Rule) Name() string {
+	return r.name
+}
+
+// Source returns what the rule is evaluated against
+func (r Rule) Source() string {
+	return r.source
+}
+
+// Derive returns the health state and message for an observation, false when the rule does not apply to it
+func (r Rule) Derive(observation Observation) (health.State, string, bool) {
+	if observation.Source != r.source {
+		return "", "", false
+	}
+
+	if r.states != nil {
+		state, ok := r.states[observation.State]
+		if !ok {
+			if r.defaultState == "" {
+				return "", "", false
+			}
+			state = r.defaultState
+		}
+		return state, fmt.Sprintf("%s is %s", r.source, observation.State), true
+	}
+
+	switch {
+	case r.criticalAbove != nil && observation.Value > *r.criticalAbove:
+		return health.Critical, fmt.Sprintf("%s is %g, above the critical threshold of %g", r.source, observation.Value, *r.criticalAbove), true
+	case r.deviatingAbove != nil && observation.Value > *r.deviatingAbove:
+		return health.Deviating, fmt.Sprintf("%s is %g, above the deviating threshold of %g", r.source, observation.Value, *r.deviatingAbove), true
+	default:
+		return health.Clear, fmt.Sprintf("%s is %g", r.source, observation.Value), true
+	}
+}
+
+func parseState(state string) (health.State, error) {
+	switch s := health.State(strings.ToUpper(state)); s {
+	case health.Clear, health.Deviating, health.Critical:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown health state '%s'", state)
+	}
+}
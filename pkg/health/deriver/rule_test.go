@@ -0,0 +1,94 @@
+package deriver
+
+import (
+	"testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestNewRule(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config RuleConfig
+		valid  bool
+	}{
+		{name: "threshold rule", config: RuleConfig{Name: "restarts", Source: "pod_restarts", CriticalAbove: float(5)}, valid: true},
+		{name: "state rule", config: RuleConfig{Name: "state", Source: "container_state", States: map[string]string{"exited": "critical"}}, valid: true},
+		{name: "missing source", config: RuleConfig{Name: "restarts", CriticalAbove: float(5)}},
+		{name: "thresholds and states", config: RuleConfig{Name: "x", Source: "y", CriticalAbove: float(5), States: map[string]string{"a": "CLEAR"}}},
+		{name: "neither thresholds nor states", config: RuleConfig{Name: "x", Source: "y"}},
+		{name: "deviating above critical", config: RuleConfig{Name: "x", Source: "y", DeviatingAbove: float(10), CriticalAbove: float(5)}},
+		{name: "unknown state", config: RuleConfig{Name: "x", Source: "y", States: map[string]string{"a": "BROKEN"}}},
+		{name: "unknown default state", config: RuleConfig{Name: "x", Source: "y", States: map[string]string{"a": "CLEAR"}, DefaultState: "BROKEN"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRule(tc.config)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	rules, errs := NewRules([]RuleConfig{
+		{Name: "restarts", Source: "pod_restarts", CriticalAbove: float(5)},
+		{Name: "restarts", Source: "pod_restarts", DeviatingAbove: float(1)},
+		{Name: "invalid", Source: "pod_restarts"},
+		{Name: "state", Source: "container_state", States: map[string]string{"exited": "CRITICAL"}},
+	})
+	assert.Len(t, errs, 2)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "restarts", rules[0].Name())
+	assert.Equal(t, "state", rules[1].Name())
+}
+
+func TestThresholdRuleDerive(t *testing.T) {
+	rule, err := NewRule(RuleConfig{Name: "restarts", Source: "pod_restarts", DeviatingAbove: float(0), CriticalAbove: float(5)})
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		value           float64
+		expectedState   health.State
+		expectedMessage string
+	}{
+		{value: 0, expectedState: health.Clear, expectedMessage: "pod_restarts is 0"},
+		{value: 3, expectedState: health.Deviating, expectedMessage: "pod_restarts is 3, above the deviating threshold of 0"},
+		{value: 5, expectedState: health.Deviating, expectedMessage: "pod_restarts is 5, above the deviating threshold of 0"},
+		{value: 6, expectedState: health.Critical, expectedMessage: "pod_restarts is 6, above the critical threshold of 5"},
+	} {
+		state, message, ok := rule.Derive(Observation{Source: "pod_restarts", Value: tc.value})
+		assert.True(t, ok)
+		assert.Equal(t, tc.expectedState, state)
+		assert.Equal(t, tc.expectedMessage, message)
+	}
+
+	_, _, ok := rule.Derive(Observation{Source: "container_state", Value: 10})
+	assert.False(t, ok, "observations of other sources are not evaluated")
+}
+
+func TestStateRuleDerive(t *testing.T) {
+	rule, err := NewRule(RuleConfig{Name: "state", Source: "container_state", States: map[string]string{"running": "clear", "exited": "critical"}})
+	assert.NoError(t, err)
+
+	state, message, ok := rule.Derive(Observation{Source: "container_state", State: "exited"})
+	assert.True(t, ok)
+	assert.Equal(t, health.Critical, state)
+	assert.Equal(t, "container_state is exited", message)
+
+	_, _, ok = rule.Derive(Observation{Source: "container_state", State: "paused"})
+	assert.False(t, ok, "unmapped states are left out without a default state")
+
+	rule, err = NewRule(RuleConfig{Name: "state", Source: "container_state", States: map[string]string{"running": "CLEAR"}, DefaultState: "DEVIATING"})
+	assert.NoError(t, err)
+	state, _, ok = rule.Derive(Observation{Source: "container_state", State: "paused"})
+	assert.True(t, ok)
+	assert.Equal(t, health.Deviating, state)
+}
@@ -0,0 +1,33 @@
+package deriver
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/batcher"
+	"github.com/StackVista/stackstate-agent/pkg/collector/check"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+)
+
+// batcherSubmitter submits the derived health to the batcher for a check
+type batcherSubmitter struct {
+	batcher batcher.Batcher
+	checkID check.ID
+}
+
+// NewBatcherSubmitter creates a Submitter for Go checks that submit to the batcher directly
+func NewBatcherSubmitter(b batcher.Batcher, checkID check.ID) Submitter {
+	return &batcherSubmitter{batcher: b, checkID: checkID}
+}
+
+// SubmitHealthCheckData submits a derived check state
+func (s *batcherSubmitter) SubmitHealthCheckData(stream health.Stream, data health.CheckData) {
+	s.batcher.SubmitHealthCheckData(s.checkID, stream, data)
+}
+
+// SubmitHealthStartSnapshot starts the derived health snapshot
+func (s *batcherSubmitter) SubmitHealthStartSnapshot(stream health.Stream, intervalSeconds int, expirySeconds int) {
+	s.batcher.SubmitHealthStartSnapshot(s.checkID, stream, intervalSeconds, expirySeconds)
+}
+
+// SubmitHealthStopSnapshot stops the derived health snapshot
+func (s *batcherSubmitter) SubmitHealthStopSnapshot(stream health.Stream) {
+	s.batcher.SubmitHealthStopSnapshot(s.checkID, stream)
+}
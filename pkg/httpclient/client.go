@@ -181,7 +181,7 @@ func (rc *retryableHTTPClient) makeRequest(method, path string, body []byte) (*r
 	if body != nil {
 		if gzipped, encodingError := rc.ContentEncoding.encode(body); encodingError != nil {
 			_ = log.Warnf("http client was not able to send payload as %s, reverting to uncompressed payload: %s",
-				rc.ContentEncoding.name(), err)
+				rc.ContentEncoding.name(), encodingError)
 			req, err = retryablehttp.NewRequest(method, url, bytes.NewBuffer(body))
 		} else {
 			log.Debugf("Using %s compression for payload", rc.ContentEncoding.name())
@@ -60,6 +60,7 @@ func FormatStatus(data []byte) (string, error) {
 	renderChecksStats(b, runnerStats, pyLoaderStats, pythonInit, autoConfigStats, checkSchedulerStats, inventoriesStats, "")
 	renderStatusTemplate(b, "/jmxfetch.tmpl", stats)
 	renderStatusTemplate(b, "/forwarder.tmpl", forwarderStats)
+	renderStatusTemplate(b, "/transactionalforwarder.tmpl", stats["transactionalForwarderStats"])
 	renderStatusTemplate(b, "/endpoints.tmpl", endpointsInfos)
 	renderStatusTemplate(b, "/logsagent.tmpl", logsStats)
 	if config.Datadog.GetBool("system_probe_config.enabled") {
@@ -381,6 +381,17 @@ func expvarStats(stats map[string]interface{}) (map[string]interface{}, error) {
 		stats["pythonInit"] = nil
 	}
 
+	// [sts] transactional forwarder spool stats
+	transactionalForwarderData := expvar.Get("transactionalforwarder")
+	if transactionalForwarderData != nil {
+		transactionalForwarderStatsJSON := []byte(transactionalForwarderData.String())
+		transactionalForwarderStats := make(map[string]interface{})
+		json.Unmarshal(transactionalForwarderStatsJSON, &transactionalForwarderStats) //nolint:errcheck
+		stats["transactionalForwarderStats"] = transactionalForwarderStats
+	} else {
+		stats["transactionalForwarderStats"] = nil
+	}
+
 	hostnameStatsJSON := []byte(expvar.Get("hostname").String())
 	hostnameStats := make(map[string]interface{})
 	json.Unmarshal(hostnameStatsJSON, &hostnameStats) //nolint:errcheck
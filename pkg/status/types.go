@@ -21,6 +21,9 @@ type CLCStats struct {
 	AverageExecutionTime int  `json:"AverageExecutionTime"`
 	MetricSamples        int  `json:"MetricSamples"`
 	LastExecFailed       bool `json:"LastExecFailed"`
+	// sts - set by the CLC runner from the check manager and the transaction manager
+	IsTransactional bool `json:"IsTransactional"`
+	InTransaction   bool `json:"InTransaction"`
 }
 
 // UnmarshalJSON overwrites the unmarshall method for CLCStats
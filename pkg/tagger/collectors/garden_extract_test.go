@@ -117,6 +117,22 @@ func (fakeDCAClient) GetKubernetesClusterID() (string, error) {
 	panic("implement me")
 }
 
+func (fakeDCAClient) GetCheckState(key string) (string, error) {
+	panic("implement me")
+}
+
+func (fakeDCAClient) SetCheckState(key, state string) error {
+	panic("implement me")
+}
+
+func (fakeDCAClient) DeleteCheckState(key string) error {
+	panic("implement me")
+}
+
+func (fakeDCAClient) ListCheckStates(check string) (map[string]string, error) {
+	panic("implement me")
+}
+
 // Unused GardenUtilInterface methodes
 func (fakeGardenUtil) ListContainers() ([]*containers.Container, error) {
 	panic("implement me")
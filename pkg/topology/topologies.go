@@ -8,4 +8,7 @@ type Topology struct {
 	Components    []Component `json:"components"`
 	Relations     []Relation  `json:"relations"`
 	DeleteIDs     []string    `json:"delete_ids"`
+	// UnchangedIDs are the external ids of the components and relations that were left out of the batch because they
+	// did not change since they were last sent. Within a snapshot they are still part of the snapshot.
+	UnchangedIDs []string `json:"unchanged_ids,omitempty"`
 }
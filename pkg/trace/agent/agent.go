@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionforwarder" //sts
 	"github.com/StackVista/stackstate-agent/pkg/trace/api"
 	"github.com/StackVista/stackstate-agent/pkg/trace/config"
 	"github.com/StackVista/stackstate-agent/pkg/trace/config/features"
@@ -23,6 +24,7 @@ import (
 	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
 	"github.com/StackVista/stackstate-agent/pkg/trace/sampler"
 	"github.com/StackVista/stackstate-agent/pkg/trace/stats"
+	"github.com/StackVista/stackstate-agent/pkg/trace/tracetopology" //sts
 	"github.com/StackVista/stackstate-agent/pkg/trace/traceutil"
 	"github.com/StackVista/stackstate-agent/pkg/trace/writer"
 	"github.com/StackVista/stackstate-agent/pkg/util/fargate"
@@ -51,6 +53,8 @@ type Agent struct {
 	TraceWriter           *writer.TraceWriter
 	StatsWriter           *writer.StatsWriter
 	SpanInterpreterEngine *interpreter.SpanInterpreterEngine //sts
+	// TraceTopology aggregates the interpreted spans into topology, nil when the trace topology is disabled. [sts]
+	TraceTopology *tracetopology.Aggregator
 
 	// obfuscator is used to obfuscate sensitive data from various span
 	// tags based on their type.
@@ -91,6 +95,11 @@ func NewAgent(ctx context.Context, conf *config.AgentConfig) *Agent {
 		conf:                  conf,
 		ctx:                   ctx,
 	}
+	// [sts] the trace topology is sent through a forwarder of its own, without a spool as every snapshot is repeated
+	if conf.TraceTopology.Enabled {
+		agnt.TraceTopology = tracetopology.NewAggregator(conf.TraceTopology, conf.Hostname,
+			transactionforwarder.NewForwarder(transactionforwarder.SpoolConfig{}))
+	}
 	agnt.Receiver = api.NewHTTPReceiver(conf, dynConf, in, agnt)
 	agnt.OTLPReceiver = api.NewOTLPReceiver(in, conf.OTLPReceiver)
 	return agnt
@@ -114,6 +123,11 @@ func (a *Agent) Run() {
 	go a.TraceWriter.Run()
 	go a.StatsWriter.Run()
 
+	// sts
+	if a.TraceTopology != nil {
+		a.TraceTopology.Start()
+	}
+
 	for i := 0; i < runtime.NumCPU(); i++ {
 		go a.work()
 	}
@@ -175,6 +189,10 @@ func (a *Agent) loop() {
 			} {
 				stopper.Stop()
 			}
+			// sts
+			if a.TraceTopology != nil {
+				a.TraceTopology.Stop()
+			}
 			return
 		}
 	}
@@ -255,6 +273,11 @@ func (a *Agent) Process(p *api.Payload) {
 		// sts - interpret spans
 		chunk.Spans = a.SpanInterpreterEngine.Interpret(chunk.Spans) // sts
 
+		// sts - aggregate the interpreted spans into topology before they are sampled
+		if a.TraceTopology != nil {
+			a.TraceTopology.Add(chunk.Spans, time.Now())
+		}
+
 		{
 			// this section sets up any necessary tags on the root:
 			clientSampleRate := sampler.GetGlobalRate(root)
@@ -27,6 +27,23 @@ import (
 // apiEndpointPrefix is the URL prefix prepended to the default site value from YamlAgentConfig.
 const apiEndpointPrefix = "https://trace.agent."
 
+// TraceTopologyConfig holds the configuration of the topology derived from the interpreted spans. [sts]
+type TraceTopologyConfig struct {
+	// Enabled specifies whether the service components and relations of the interpreted spans are sent as topology.
+	Enabled bool
+
+	// Interval specifies the window over which the spans are aggregated before the topology is sent.
+	Interval time.Duration
+
+	// Expiry specifies how long a service or relation stays in the topology after its last span.
+	Expiry time.Duration
+
+	// ErrorRateDeviating and ErrorRateCritical specify the fraction of failed requests from which the health of a
+	// service is DEVIATING and CRITICAL.
+	ErrorRateDeviating float64
+	ErrorRateCritical  float64
+}
+
 // OTLP holds the configuration for the OpenTelemetry receiver.
 type OTLP struct {
 	// BindHost specifies the host to bind the receiver to.
@@ -352,6 +369,7 @@ func (c *AgentConfig) applyDatadogConfig() error {
 
 	// [sts]
 	c.InterpreterConfig = readInterpreterConfigYaml()
+	c.applyTraceTopologyConfig()
 
 	// undocumented
 	if config.Datadog.IsSet("apm_config.max_cpu_percent") {
@@ -499,11 +517,39 @@ func readInterpreterConfigYaml() *interpreterconfig.Config {
 		if ini.ServiceIdentifiers != nil {
 			conf.ServiceIdentifiers = ini.ServiceIdentifiers
 		}
+		conf.Rules = ini.Rules
 	}
 
 	return conf
 }
 
+// applyTraceTopologyConfig reads the configuration of the topology derived from the interpreted spans. [sts]
+func (c *AgentConfig) applyTraceTopologyConfig() {
+	if config.Datadog.IsSet("apm_config.trace_topology.enabled") {
+		c.TraceTopology.Enabled = config.Datadog.GetBool("apm_config.trace_topology.enabled")
+	}
+	if config.Datadog.IsSet("apm_config.trace_topology.interval_seconds") {
+		if interval := config.Datadog.GetInt("apm_config.trace_topology.interval_seconds"); interval > 0 {
+			c.TraceTopology.Interval = getDuration(interval)
+		} else {
+			log.Warnf("Invalid apm_config.trace_topology.interval_seconds %d, using the default of %s", interval, c.TraceTopology.Interval)
+		}
+	}
+	if config.Datadog.IsSet("apm_config.trace_topology.expiry_seconds") {
+		c.TraceTopology.Expiry = getDuration(config.Datadog.GetInt("apm_config.trace_topology.expiry_seconds"))
+	}
+	if config.Datadog.IsSet("apm_config.trace_topology.error_rate_deviating") {
+		c.TraceTopology.ErrorRateDeviating = config.Datadog.GetFloat64("apm_config.trace_topology.error_rate_deviating")
+	}
+	if config.Datadog.IsSet("apm_config.trace_topology.error_rate_critical") {
+		c.TraceTopology.ErrorRateCritical = config.Datadog.GetFloat64("apm_config.trace_topology.error_rate_critical")
+	}
+	// the services and relations are kept for at least one window
+	if c.TraceTopology.Expiry < c.TraceTopology.Interval {
+		c.TraceTopology.Expiry = c.TraceTopology.Interval
+	}
+}
+
 // compileReplaceRules compiles the regular expressions found in the replace rules.
 // If it fails it returns the first error.
 func compileReplaceRules(rules []*ReplaceRule) error {
@@ -140,6 +140,9 @@ type AgentConfig struct {
 
 	// InterpreterConfig contains span interpreter config. [sts]
 	InterpreterConfig *interpreterconfig.Config
+
+	// TraceTopology holds the configuration of the topology derived from the interpreted spans. [sts]
+	TraceTopology *TraceTopologyConfig
 }
 
 // Tag represents a key/value pair.
@@ -198,6 +201,12 @@ func New() *AgentConfig {
 
 		// [sts] interpreter config
 		InterpreterConfig: interpreterconfig.DefaultInterpreterConfig(),
+		TraceTopology: &TraceTopologyConfig{
+			Interval:           time.Minute,
+			Expiry:             5 * time.Minute,
+			ErrorRateDeviating: 0.05,
+			ErrorRateCritical:  0.25,
+		},
 	}
 }
 
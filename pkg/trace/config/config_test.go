@@ -19,6 +19,7 @@ import (
 
 	"github.com/StackVista/stackstate-agent/pkg/config"
 	"github.com/StackVista/stackstate-agent/pkg/trace/config/features"
+	interpreterconfig "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -310,6 +311,30 @@ func TestFullYamlConfig(t *testing.T) {
 	assert.True(c.Obfuscation.Memcached.Enabled)
 	assert.True(c.Obfuscation.CreditCards.Enabled)
 	assert.True(c.Obfuscation.CreditCards.Luhn)
+
+	assert.Equal(&interpreterconfig.Config{
+		ServiceIdentifiers: []string{"db.instance", "db.user"},
+		Rules: []interpreterconfig.RuleConfig{{
+			Name: "rabbitmq",
+			Match: interpreterconfig.MatchConfig{
+				Source:                 "java",
+				InstrumentationLibrary: "io.opentelemetry.rabbitmq",
+				Attributes:             map[string]string{"messaging.system": "^rabbitmq$"},
+			},
+			ServiceURN:  `urn:service:/rabbitmq:{{ .Meta "messaging.destination" }}`,
+			ServiceName: `RabbitMQ {{ .Meta "messaging.destination" }}`,
+			Kind:        "consumer",
+			Identifiers: []string{`urn:rabbitmq:queue/{{ .Meta "messaging.destination" }}`},
+		}},
+	}, c.InterpreterConfig)
+
+	assert.Equal(&TraceTopologyConfig{
+		Enabled:            true,
+		Interval:           30 * time.Second,
+		Expiry:             10 * time.Minute,
+		ErrorRateDeviating: 0.1,
+		ErrorRateCritical:  0.5,
+	}, c.TraceTopology)
 }
 
 func TestUndocumentedYamlConfig(t *testing.T) {
@@ -4,6 +4,31 @@ package config
 // to interpret and enrich various span types.
 type Config struct {
 	ServiceIdentifiers []string `mapstructure:"service_identifiers"`
+	// Rules interpret the spans of frameworks that have no built in interpreter
+	Rules []RuleConfig `mapstructure:"rules"`
+}
+
+// RuleConfig is a declarative span interpreter. The spans that match it get their service urn, name, type, kind and
+// identifiers from the templates of the rule. The templates are Go templates with the span fields `.Service`, `.Name`,
+// `.Resource` and `.Type` and the span attributes through `.Meta`, e.g. `urn:service:/{{ .Meta "http.host" }}`.
+type RuleConfig struct {
+	Name        string      `mapstructure:"name"`
+	Match       MatchConfig `mapstructure:"match"`
+	ServiceURN  string      `mapstructure:"service_urn"`
+	ServiceName string      `mapstructure:"service_name"`
+	ServiceType string      `mapstructure:"service_type"`
+	Kind        string      `mapstructure:"kind"`
+	Identifiers []string    `mapstructure:"identifiers"`
+}
+
+// MatchConfig selects the spans a rule applies to, all of the configured conditions have to match
+type MatchConfig struct {
+	Source                 string `mapstructure:"source"`
+	Type                   string `mapstructure:"type"`
+	InstrumentationLibrary string `mapstructure:"instrumentation_library"`
+	// Attributes maps span attributes to a regular expression their value has to match, an empty expression only
+	// requires the attribute to be present
+	Attributes map[string]string `mapstructure:"attributes"`
 }
 
 // DefaultInterpreterConfig creates the default config
@@ -29,3 +29,11 @@ func InterpretSpanHTTPError(span *pb.Span) {
 		}
 	}
 }
+
+// GetSpanMetaOrDefault Retrieve optional span data, returning the default value when the span does not have it
+func GetSpanMetaOrDefault(span *pb.Span, spanMetaTarget string, defaultValue string) string {
+	if value, ok := span.Meta[spanMetaTarget]; ok && len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
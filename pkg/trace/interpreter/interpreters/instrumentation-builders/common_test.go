@@ -187,3 +187,16 @@ func TestInterpretHTTPError(t *testing.T) {
 		},
 	}, spanWithError)
 }
+
+func TestRetrieveSpanMetaOrDefault(t *testing.T) {
+	span := &pb.Span{
+		Meta: map[string]string{
+			"net.peer.name": "redis-master",
+			"net.peer.port": "",
+		},
+	}
+
+	assert.EqualValues(t, "redis-master", GetSpanMetaOrDefault(span, "net.peer.name", "localhost"))
+	assert.EqualValues(t, "6379", GetSpanMetaOrDefault(span, "net.peer.port", "6379"))
+	assert.EqualValues(t, "0", GetSpanMetaOrDefault(span, "db.redis.database_index", "0"))
+}
@@ -0,0 +1,34 @@
+package instrumentationbuilders
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+)
+
+// OpenTelemetrySpanBuilder Map span data for the Open Telemetry messaging, rpc and database services.
+// The span becomes the component of the topic, queue, service or database it calls, so the calling and the receiving
+// services get a relation through it
+func OpenTelemetrySpanBuilder(span *pb.Span, serviceName string, namePrefix string, service string, spanType string, kind string, urn string, identifiers string) {
+	// Name of component displayed below the icon
+	span.Meta["span.serviceName"] = fmt.Sprintf("%s: %s", namePrefix, serviceName)
+
+	// Name of the trace displayed on the trace graph line
+	span.Name = fmt.Sprintf("%s: %s", namePrefix, serviceName)
+
+	// Displayed on the trace properties
+	span.Resource = service
+	span.Type = spanType
+
+	// Mapping inside StackPack for capturing certain metrics
+	span.Meta["span.serviceType"] = "open-telemetry"
+	span.Meta["source"] = "open-telemetry"
+
+	span.Service = service
+	span.Meta["service"] = service
+	span.Meta["sts.origin"] = "open-telemetry"
+
+	// General mapping
+	span.Meta["span.kind"] = kind
+	span.Meta["span.serviceURN"] = urn
+	span.Meta["sts.service.identifiers"] = identifiers
+}
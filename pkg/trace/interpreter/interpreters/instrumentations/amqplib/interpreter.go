@@ -0,0 +1,22 @@
+package amqplibinstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	amqplibinstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/amqplib/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-amqplib
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-amqplib"
+
+// InterpretBuilderForAmqplibInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForAmqplibInstrumentation() string {
+	rabbitMQInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, amqplibinstrumentationModules.OpenTelemetryRabbitMQServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-AMQPLIB] Mapping service: %s", rabbitMQInterpreterIdentifier)
+	return rabbitMQInterpreterIdentifier
+}
@@ -0,0 +1,61 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryRabbitMQInterpreter default span interpreter for this data structure
+type OpenTelemetryRabbitMQInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryRabbitMQServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryRabbitMQServiceIdentifier = "RabbitMQ"
+
+// OpenTelemetryRabbitMQInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryRabbitMQInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryRabbitMQServiceIdentifier)
+
+// MakeOpenTelemetryRabbitMQInterpreter creates an instance of the OpenTelemetry RabbitMQ span interpreter
+func MakeOpenTelemetryRabbitMQInterpreter(config *config.Config) *OpenTelemetryRabbitMQInterpreter {
+	return &OpenTelemetryRabbitMQInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryRabbitMQInterpreter
+// Messages published to a named exchange are mapped to the exchange, messages published to the default exchange are
+// routed directly to the queue named by the routing key and are mapped to that queue
+func (t *OpenTelemetryRabbitMQInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [RABBITMQ] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		// The default exchange is an empty destination
+		exchange := instrumentationbuilders.GetSpanMetaOrDefault(span, "messaging.destination", "")
+
+		if exchange != "" {
+			var urn = t.CreateServiceURN(fmt.Sprintf("rabbitmq/exchange/%s", exchange))
+			var identifier = fmt.Sprintf("urn:rabbitmq:exchange/%s", exchange)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, exchange, "RabbitMQ Exchange", "rabbitmq.exchange", "queue", "consumer", urn, identifier)
+		} else if routingKey, routingKeyOk := instrumentationbuilders.GetSpanMeta("RABBITMQ", span, "messaging.rabbitmq.routing_key"); routingKeyOk {
+			var urn = t.CreateServiceURN(fmt.Sprintf("rabbitmq/queue/%s", *routingKey))
+			var identifier = fmt.Sprintf("urn:rabbitmq:queue/%s", *routingKey)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, *routingKey, "RabbitMQ Queue", "rabbitmq.queue", "queue", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [RABBITMQ]: Unable to map the RabbitMQ request")
+			return nil
+		}
+	}
+
+	return spans
+}
@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryRabbitMQSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryRabbitMQInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryRabbitMQInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if both the exchange and the routing key are missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"messaging.system":      "rabbitmq",
+					"messaging.destination": "",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data of a named exchange should be mapped to the exchange",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"messaging.system":               "rabbitmq",
+					"messaging.destination":          "billing",
+					"messaging.rabbitmq.routing_key": "invoice.created",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "RabbitMQ Exchange: billing",
+				Service:  "rabbitmq.exchange",
+				Resource: "rabbitmq.exchange",
+				Type:     "queue",
+				Meta: map[string]string{
+					"sts.origin":                     "open-telemetry",
+					"source":                         "open-telemetry",
+					"messaging.system":               "rabbitmq",
+					"messaging.destination":          "billing",
+					"messaging.rabbitmq.routing_key": "invoice.created",
+					"service":                        "rabbitmq.exchange",
+					"span.kind":                      "consumer",
+					"span.serviceName":               "RabbitMQ Exchange: billing",
+					"span.serviceType":               "open-telemetry",
+					"span.serviceURN":                "urn:service:/rabbitmq/exchange/billing",
+					"sts.service.identifiers":        "urn:rabbitmq:exchange/billing",
+				},
+			}},
+		},
+		{
+			testCase:    "Open Telemetry data of the default exchange should be mapped to the queue of the routing key",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"messaging.system":               "rabbitmq",
+					"messaging.destination":          "",
+					"messaging.rabbitmq.routing_key": "orders",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "RabbitMQ Queue: orders",
+				Service:  "rabbitmq.queue",
+				Resource: "rabbitmq.queue",
+				Type:     "queue",
+				Meta: map[string]string{
+					"sts.origin":                     "open-telemetry",
+					"source":                         "open-telemetry",
+					"messaging.system":               "rabbitmq",
+					"messaging.destination":          "",
+					"messaging.rabbitmq.routing_key": "orders",
+					"service":                        "rabbitmq.queue",
+					"span.kind":                      "consumer",
+					"span.serviceName":               "RabbitMQ Queue: orders",
+					"span.serviceType":               "open-telemetry",
+					"span.serviceURN":                "urn:service:/rabbitmq/queue/orders",
+					"sts.service.identifiers":        "urn:rabbitmq:queue/orders",
+				},
+			}},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package grpcinstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	grpcinstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/grpc/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-grpc
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-grpc"
+
+// InterpretBuilderForGRPCInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForGRPCInstrumentation() string {
+	grpcInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, grpcinstrumentationModules.OpenTelemetryGRPCServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-GRPC] Mapping service: %s", grpcInterpreterIdentifier)
+	return grpcInterpreterIdentifier
+}
@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryGRPCInterpreter default span interpreter for this data structure
+type OpenTelemetryGRPCInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryGRPCServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryGRPCServiceIdentifier = "GRPC"
+
+// OpenTelemetryGRPCInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryGRPCInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryGRPCServiceIdentifier)
+
+// grpcClientErrorCodes The gRPC status codes caused by the caller, the other non OK codes are errors of the service
+// https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+var grpcClientErrorCodes = map[string]bool{
+	"3":  true, // INVALID_ARGUMENT
+	"5":  true, // NOT_FOUND
+	"6":  true, // ALREADY_EXISTS
+	"7":  true, // PERMISSION_DENIED
+	"9":  true, // FAILED_PRECONDITION
+	"11": true, // OUT_OF_RANGE
+	"16": true, // UNAUTHENTICATED
+}
+
+// MakeOpenTelemetryGRPCInterpreter creates an instance of the OpenTelemetry gRPC span interpreter
+func MakeOpenTelemetryGRPCInterpreter(config *config.Config) *OpenTelemetryGRPCInterpreter {
+	return &OpenTelemetryGRPCInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryGRPCInterpreter
+// Both the client and the server spans of a call are mapped to the gRPC service that is called
+func (t *OpenTelemetryGRPCInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [GRPC] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		rpcService, rpcServiceOk := instrumentationbuilders.GetSpanMeta("GRPC", span, "rpc.service")
+
+		if rpcServiceOk {
+			var urn = t.CreateServiceURN(fmt.Sprintf("grpc/%s", *rpcService))
+			var identifier = fmt.Sprintf("urn:grpc:/%s", *rpcService)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, *rpcService, "gRPC Service", "grpc.service", "rpc", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [GRPC]: Unable to map the gRPC request")
+			return nil
+		}
+
+		interpretSpanGRPCError(span)
+	}
+
+	return spans
+}
+
+// interpretSpanGRPCError Maps the gRPC status code of a failed call onto the error class of the http status codes
+func interpretSpanGRPCError(span *pb.Span) {
+	if span.Error == 0 {
+		return
+	}
+	if statusCode, found := span.Meta["rpc.grpc.status_code"]; found && statusCode != "0" {
+		if grpcClientErrorCodes[statusCode] {
+			span.Meta["span.errorClass"] = "4xx"
+		} else {
+			span.Meta["span.errorClass"] = "5xx"
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryGRPCSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryGRPCInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryGRPCInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if the rpc service is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"rpc.system": "grpc",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data should be mapped if all the correct meta data has been passed",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"rpc.system":           "grpc",
+					"rpc.service":          "shop.Checkout",
+					"rpc.method":           "PlaceOrder",
+					"rpc.grpc.status_code": "0",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "gRPC Service: shop.Checkout",
+				Service:  "grpc.service",
+				Resource: "grpc.service",
+				Type:     "rpc",
+				Meta: map[string]string{
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"rpc.system":              "grpc",
+					"rpc.service":             "shop.Checkout",
+					"rpc.method":              "PlaceOrder",
+					"rpc.grpc.status_code":    "0",
+					"service":                 "grpc.service",
+					"span.kind":               "consumer",
+					"span.serviceName":        "gRPC Service: shop.Checkout",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/grpc/shop.Checkout",
+					"sts.service.identifiers": "urn:grpc:/shop.Checkout",
+				},
+			}},
+		},
+		{
+			testCase:    "Should interpret gRPC errors caused by the caller as 4xx and the others as 5xx",
+			interpreter: interpreter,
+			trace: []*pb.Span{
+				{
+					Error: 1,
+					Meta: map[string]string{
+						"rpc.service":          "shop.Checkout",
+						"rpc.grpc.status_code": "5",
+					},
+				},
+				{
+					Error: 1,
+					Meta: map[string]string{
+						"rpc.service":          "shop.Checkout",
+						"rpc.grpc.status_code": "14",
+					},
+				},
+			},
+			expected: []*pb.Span{
+				{
+					Name:     "gRPC Service: shop.Checkout",
+					Service:  "grpc.service",
+					Resource: "grpc.service",
+					Type:     "rpc",
+					Error:    1,
+					Meta: map[string]string{
+						"sts.origin":              "open-telemetry",
+						"source":                  "open-telemetry",
+						"span.errorClass":         "4xx",
+						"rpc.service":             "shop.Checkout",
+						"rpc.grpc.status_code":    "5",
+						"service":                 "grpc.service",
+						"span.kind":               "consumer",
+						"span.serviceName":        "gRPC Service: shop.Checkout",
+						"span.serviceType":        "open-telemetry",
+						"span.serviceURN":         "urn:service:/grpc/shop.Checkout",
+						"sts.service.identifiers": "urn:grpc:/shop.Checkout",
+					},
+				},
+				{
+					Name:     "gRPC Service: shop.Checkout",
+					Service:  "grpc.service",
+					Resource: "grpc.service",
+					Type:     "rpc",
+					Error:    1,
+					Meta: map[string]string{
+						"sts.origin":              "open-telemetry",
+						"source":                  "open-telemetry",
+						"span.errorClass":         "5xx",
+						"rpc.service":             "shop.Checkout",
+						"rpc.grpc.status_code":    "14",
+						"service":                 "grpc.service",
+						"span.kind":               "consumer",
+						"span.serviceName":        "gRPC Service: shop.Checkout",
+						"span.serviceType":        "open-telemetry",
+						"span.serviceURN":         "urn:service:/grpc/shop.Checkout",
+						"sts.service.identifiers": "urn:grpc:/shop.Checkout",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
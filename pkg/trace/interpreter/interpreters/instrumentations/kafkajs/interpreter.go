@@ -0,0 +1,22 @@
+package kafkajsinstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	kafkajsinstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/kafkajs/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-kafkajs
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-kafkajs"
+
+// InterpretBuilderForKafkaJSInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForKafkaJSInstrumentation() string {
+	kafkaInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, kafkajsinstrumentationModules.OpenTelemetryKafkaServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-KAFKAJS] Mapping service: %s", kafkaInterpreterIdentifier)
+	return kafkaInterpreterIdentifier
+}
@@ -0,0 +1,55 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryKafkaInterpreter default span interpreter for this data structure
+type OpenTelemetryKafkaInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryKafkaServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryKafkaServiceIdentifier = "Kafka"
+
+// OpenTelemetryKafkaInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryKafkaInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryKafkaServiceIdentifier)
+
+// MakeOpenTelemetryKafkaInterpreter creates an instance of the OpenTelemetry Kafka span interpreter
+func MakeOpenTelemetryKafkaInterpreter(config *config.Config) *OpenTelemetryKafkaInterpreter {
+	return &OpenTelemetryKafkaInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryKafkaInterpreter
+// Both the producer and the consumer spans of a message are mapped to the topic, which results in the
+// producer -> topic -> consumer relations
+func (t *OpenTelemetryKafkaInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [KAFKA] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		kafkaTopic, kafkaTopicOk := instrumentationbuilders.GetSpanMeta("KAFKA", span, "messaging.destination")
+
+		if kafkaTopicOk {
+			var urn = t.CreateServiceURN(fmt.Sprintf("kafka/topic/%s", *kafkaTopic))
+			var identifier = fmt.Sprintf("urn:kafka:topic/%s", *kafkaTopic)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, *kafkaTopic, "Kafka Topic", "kafka.topic", "queue", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [KAFKA]: Unable to map the Kafka request")
+			return nil
+		}
+	}
+
+	return spans
+}
@@ -0,0 +1,100 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryKafkaSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryKafkaInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryKafkaInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if the topic is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"messaging.system": "kafka",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data of the producer and the consumer should be mapped to the topic",
+			interpreter: interpreter,
+			trace: []*pb.Span{
+				{
+					Meta: map[string]string{
+						"messaging.system":      "kafka",
+						"messaging.destination": "orders",
+					},
+				},
+				{
+					Meta: map[string]string{
+						"messaging.system":          "kafka",
+						"messaging.destination":     "orders",
+						"messaging.operation":       "process",
+						"messaging.kafka.partition": "3",
+					},
+				},
+			},
+			expected: []*pb.Span{
+				{
+					Name:     "Kafka Topic: orders",
+					Service:  "kafka.topic",
+					Resource: "kafka.topic",
+					Type:     "queue",
+					Meta: map[string]string{
+						"sts.origin":              "open-telemetry",
+						"source":                  "open-telemetry",
+						"messaging.system":        "kafka",
+						"messaging.destination":   "orders",
+						"service":                 "kafka.topic",
+						"span.kind":               "consumer",
+						"span.serviceName":        "Kafka Topic: orders",
+						"span.serviceType":        "open-telemetry",
+						"span.serviceURN":         "urn:service:/kafka/topic/orders",
+						"sts.service.identifiers": "urn:kafka:topic/orders",
+					},
+				},
+				{
+					Name:     "Kafka Topic: orders",
+					Service:  "kafka.topic",
+					Resource: "kafka.topic",
+					Type:     "queue",
+					Meta: map[string]string{
+						"sts.origin":                "open-telemetry",
+						"source":                    "open-telemetry",
+						"messaging.system":          "kafka",
+						"messaging.destination":     "orders",
+						"messaging.operation":       "process",
+						"messaging.kafka.partition": "3",
+						"service":                   "kafka.topic",
+						"span.kind":                 "consumer",
+						"span.serviceName":          "Kafka Topic: orders",
+						"span.serviceType":          "open-telemetry",
+						"span.serviceURN":           "urn:service:/kafka/topic/orders",
+						"sts.service.identifiers":   "urn:kafka:topic/orders",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
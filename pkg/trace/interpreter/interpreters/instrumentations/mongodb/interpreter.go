@@ -0,0 +1,22 @@
+package mongodbinstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	mongodbinstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/mongodb/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-mongodb
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-mongodb"
+
+// InterpretBuilderForMongoDBInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForMongoDBInstrumentation() string {
+	mongoDBInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, mongodbinstrumentationModules.OpenTelemetryMongoDBServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-MONGODB] Mapping service: %s", mongoDBInterpreterIdentifier)
+	return mongoDBInterpreterIdentifier
+}
@@ -0,0 +1,59 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryMongoDBInterpreter default span interpreter for this data structure
+type OpenTelemetryMongoDBInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryMongoDBServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryMongoDBServiceIdentifier = "MongoDB"
+
+// OpenTelemetryMongoDBInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryMongoDBInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryMongoDBServiceIdentifier)
+
+// mongoDBDefaultPort The port used when the instrumentation does not report the port of the server
+const mongoDBDefaultPort = "27017"
+
+// MakeOpenTelemetryMongoDBInterpreter creates an instance of the OpenTelemetry MongoDB span interpreter
+func MakeOpenTelemetryMongoDBInterpreter(config *config.Config) *OpenTelemetryMongoDBInterpreter {
+	return &OpenTelemetryMongoDBInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryMongoDBInterpreter
+func (t *OpenTelemetryMongoDBInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [MONGODB] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		mongoDBHost, mongoDBHostOk := instrumentationbuilders.GetSpanMeta("MONGODB", span, "net.peer.name")
+		mongoDBName, mongoDBNameOk := instrumentationbuilders.GetSpanMeta("MONGODB", span, "db.name")
+		mongoDBPort := instrumentationbuilders.GetSpanMetaOrDefault(span, "net.peer.port", mongoDBDefaultPort)
+
+		if mongoDBHostOk && mongoDBNameOk {
+			var database = fmt.Sprintf("%s:%s/%s", *mongoDBHost, mongoDBPort, *mongoDBName)
+			var urn = t.CreateServiceURN(fmt.Sprintf("mongodb/%s", database))
+			var identifier = fmt.Sprintf("mongodb://%s", database)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, *mongoDBName, "MongoDB Database", "mongodb.database", "db", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [MONGODB]: Unable to map the MongoDB request")
+			return nil
+		}
+	}
+
+	return spans
+}
@@ -0,0 +1,77 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryMongoDBSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryMongoDBInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryMongoDBInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if the database is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":     "mongodb",
+					"net.peer.name": "mongo",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data should be mapped if all the correct meta data has been passed",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":             "mongodb",
+					"db.name":               "shop",
+					"db.mongodb.collection": "orders",
+					"db.operation":          "insert",
+					"net.peer.name":         "mongo",
+					"net.peer.port":         "27018",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "MongoDB Database: shop",
+				Service:  "mongodb.database",
+				Resource: "mongodb.database",
+				Type:     "db",
+				Meta: map[string]string{
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"db.system":               "mongodb",
+					"db.name":                 "shop",
+					"db.mongodb.collection":   "orders",
+					"db.operation":            "insert",
+					"net.peer.name":           "mongo",
+					"net.peer.port":           "27018",
+					"service":                 "mongodb.database",
+					"span.kind":               "consumer",
+					"span.serviceName":        "MongoDB Database: shop",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/mongodb/mongo:27018/shop",
+					"sts.service.identifiers": "mongodb://mongo:27018/shop",
+				},
+			}},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
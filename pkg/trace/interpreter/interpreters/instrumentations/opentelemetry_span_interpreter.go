@@ -1,9 +1,15 @@
 package instrumentations
 
 import (
+	amqplibInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/amqplib"
 	awsLambdaInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/aws-lambda"
 	awsSdkInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/aws-sdk"
+	grpcInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/grpc"
 	httpInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/http"
+	kafkajsInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/kafkajs"
+	mongodbInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/mongodb"
+	pgInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/pg"
+	redisInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/redis"
 	stackStateInstrumentation "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/stackstate"
 	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
 	"github.com/StackVista/stackstate-agent/pkg/util/log"
@@ -33,6 +39,30 @@ func InterpretBasedOnInstrumentationLibrary(span *pb.Span, source string) string
 	case stackStateInstrumentation.InstrumentationIdentifier:
 		return stackStateInstrumentation.InterpretBuilderForStackStateInstrumentation()
 
+	// @opentelemetry/instrumentation-kafkajs
+	case kafkajsInstrumentation.InstrumentationIdentifier:
+		return kafkajsInstrumentation.InterpretBuilderForKafkaJSInstrumentation()
+
+	// @opentelemetry/instrumentation-amqplib
+	case amqplibInstrumentation.InstrumentationIdentifier:
+		return amqplibInstrumentation.InterpretBuilderForAmqplibInstrumentation()
+
+	// @opentelemetry/instrumentation-grpc
+	case grpcInstrumentation.InstrumentationIdentifier:
+		return grpcInstrumentation.InterpretBuilderForGRPCInstrumentation()
+
+	// @opentelemetry/instrumentation-redis and @opentelemetry/instrumentation-ioredis
+	case redisInstrumentation.InstrumentationIdentifier, redisInstrumentation.IORedisInstrumentationIdentifier:
+		return redisInstrumentation.InterpretBuilderForRedisInstrumentation()
+
+	// @opentelemetry/instrumentation-mongodb
+	case mongodbInstrumentation.InstrumentationIdentifier:
+		return mongodbInstrumentation.InterpretBuilderForMongoDBInstrumentation()
+
+	// @opentelemetry/instrumentation-pg
+	case pgInstrumentation.InstrumentationIdentifier:
+		return pgInstrumentation.InterpretBuilderForPGInstrumentation()
+
 	default:
 		log.Debugf("[OTEL] [INSTRUMENTATION] Unknown instrumentation library: %s.", instrumentationLibrary)
 	}
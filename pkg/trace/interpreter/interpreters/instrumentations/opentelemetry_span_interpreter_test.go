@@ -120,6 +120,76 @@ func TestSpanInterpreterEngine(t *testing.T) {
 			},
 			expected: "openTelemetryStepFunctions",
 		},
+		{
+			testCase: "Instrumentation library 'instrumentation-kafkajs' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-kafkajs",
+				},
+			},
+			expected: "openTelemetryKafka",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-amqplib' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-amqplib",
+				},
+			},
+			expected: "openTelemetryRabbitMQ",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-grpc' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-grpc",
+				},
+			},
+			expected: "openTelemetryGRPC",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-redis' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-redis",
+				},
+			},
+			expected: "openTelemetryRedis",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-ioredis' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-ioredis",
+				},
+			},
+			expected: "openTelemetryRedis",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-mongodb' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-mongodb",
+				},
+			},
+			expected: "openTelemetryMongoDB",
+		},
+		{
+			testCase: "Instrumentation library 'instrumentation-pg' should map to a specific interpreter id.",
+			source:   api.OpenTelemetrySource,
+			span: pb.Span{
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-pg",
+				},
+			},
+			expected: "openTelemetryPostgreSQL",
+		},
 	} {
 		t.Run(tc.testCase, func(t *testing.T) {
 			trace := &tc.span
@@ -0,0 +1,22 @@
+package pginstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	pginstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/pg/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-pg
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-pg"
+
+// InterpretBuilderForPGInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForPGInstrumentation() string {
+	postgreSQLInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, pginstrumentationModules.OpenTelemetryPostgreSQLServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-PG] Mapping service: %s", postgreSQLInterpreterIdentifier)
+	return postgreSQLInterpreterIdentifier
+}
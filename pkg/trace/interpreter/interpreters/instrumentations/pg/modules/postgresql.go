@@ -0,0 +1,59 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryPostgreSQLInterpreter default span interpreter for this data structure
+type OpenTelemetryPostgreSQLInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryPostgreSQLServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryPostgreSQLServiceIdentifier = "PostgreSQL"
+
+// OpenTelemetryPostgreSQLInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryPostgreSQLInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryPostgreSQLServiceIdentifier)
+
+// postgreSQLDefaultPort The port used when the instrumentation does not report the port of the server
+const postgreSQLDefaultPort = "5432"
+
+// MakeOpenTelemetryPostgreSQLInterpreter creates an instance of the OpenTelemetry PostgreSQL span interpreter
+func MakeOpenTelemetryPostgreSQLInterpreter(config *config.Config) *OpenTelemetryPostgreSQLInterpreter {
+	return &OpenTelemetryPostgreSQLInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryPostgreSQLInterpreter
+func (t *OpenTelemetryPostgreSQLInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [POSTGRESQL] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		postgreSQLHost, postgreSQLHostOk := instrumentationbuilders.GetSpanMeta("POSTGRESQL", span, "net.peer.name")
+		postgreSQLName, postgreSQLNameOk := instrumentationbuilders.GetSpanMeta("POSTGRESQL", span, "db.name")
+		postgreSQLPort := instrumentationbuilders.GetSpanMetaOrDefault(span, "net.peer.port", postgreSQLDefaultPort)
+
+		if postgreSQLHostOk && postgreSQLNameOk {
+			var database = fmt.Sprintf("%s:%s/%s", *postgreSQLHost, postgreSQLPort, *postgreSQLName)
+			var urn = t.CreateServiceURN(fmt.Sprintf("postgresql/%s", database))
+			var identifier = fmt.Sprintf("postgresql://%s", database)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, *postgreSQLName, "PostgreSQL Database", "postgresql.database", "db", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [POSTGRESQL]: Unable to map the PostgreSQL request")
+			return nil
+		}
+	}
+
+	return spans
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryPostgreSQLSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryPostgreSQLInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryPostgreSQLInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if the server is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system": "postgresql",
+					"db.name":   "shop",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data should be mapped to the database with the default port when the port is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":     "postgresql",
+					"db.name":       "shop",
+					"db.statement":  "SELECT * FROM orders",
+					"db.user":       "shop",
+					"net.peer.name": "postgres.db.svc",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "PostgreSQL Database: shop",
+				Service:  "postgresql.database",
+				Resource: "postgresql.database",
+				Type:     "db",
+				Meta: map[string]string{
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"db.system":               "postgresql",
+					"db.name":                 "shop",
+					"db.statement":            "SELECT * FROM orders",
+					"db.user":                 "shop",
+					"net.peer.name":           "postgres.db.svc",
+					"service":                 "postgresql.database",
+					"span.kind":               "consumer",
+					"span.serviceName":        "PostgreSQL Database: shop",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/postgresql/postgres.db.svc:5432/shop",
+					"sts.service.identifiers": "postgresql://postgres.db.svc:5432/shop",
+				},
+			}},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package redisinstrumentation
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	redisinstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/redis/modules"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// InstrumentationIdentifier Identifier for this instrumentation library
+// This identifier is based on the one used within the library using within the wild
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-redis
+var InstrumentationIdentifier = "@opentelemetry/instrumentation-redis"
+
+// IORedisInstrumentationIdentifier Identifier for the instrumentation library of the ioredis client, it produces the
+// same span attributes as the redis client
+// https://www.npmjs.com/package/@opentelemetry/instrumentation-ioredis
+var IORedisInstrumentationIdentifier = "@opentelemetry/instrumentation-ioredis"
+
+// InterpretBuilderForRedisInstrumentation Mapping for modules used within the Instrumentation library
+// Modules are basically sub parts that have certain functionality. Based on that functionality different context
+// values needs to be mapped to be part of the span response
+func InterpretBuilderForRedisInstrumentation() string {
+	redisInterpreterIdentifier := fmt.Sprintf("%s%s", api.OpenTelemetrySource, redisinstrumentationModules.OpenTelemetryRedisServiceIdentifier)
+	log.Debugf("[OTEL] [INSTRUMENTATION-REDIS] Mapping service: %s", redisInterpreterIdentifier)
+	return redisInterpreterIdentifier
+}
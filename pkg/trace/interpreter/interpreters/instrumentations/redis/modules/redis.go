@@ -0,0 +1,58 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/StackVista/stackstate-agent/pkg/trace/api"
+	config "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	interpreter "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
+	instrumentationbuilders "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentation-builders"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// OpenTelemetryRedisInterpreter default span interpreter for this data structure
+type OpenTelemetryRedisInterpreter struct {
+	interpreter.Interpreter
+}
+
+// OpenTelemetryRedisServiceIdentifier The base identifier for this interpreter
+const OpenTelemetryRedisServiceIdentifier = "Redis"
+
+// OpenTelemetryRedisInterpreterSpan An identifier used to direct Open Telemetry interprets to this Interpreter
+var OpenTelemetryRedisInterpreterSpan = fmt.Sprintf("%s%s", api.OpenTelemetrySource, OpenTelemetryRedisServiceIdentifier)
+
+// redisDefaultPort The port used when the instrumentation does not report the port of the server
+const redisDefaultPort = "6379"
+
+// MakeOpenTelemetryRedisInterpreter creates an instance of the OpenTelemetry Redis span interpreter
+func MakeOpenTelemetryRedisInterpreter(config *config.Config) *OpenTelemetryRedisInterpreter {
+	return &OpenTelemetryRedisInterpreter{interpreter.Interpreter{Config: config}}
+}
+
+// Interpret performs the interpretation for the OpenTelemetryRedisInterpreter
+func (t *OpenTelemetryRedisInterpreter) Interpret(spans []*pb.Span) []*pb.Span {
+	log.Debugf("[OTEL] [REDIS] Interpreting and mapping Open Telemetry data")
+
+	for _, span := range spans {
+		// no meta, add a empty map
+		if span.Meta == nil {
+			span.Meta = map[string]string{}
+		}
+
+		redisHost, redisHostOk := instrumentationbuilders.GetSpanMeta("REDIS", span, "net.peer.name")
+		redisPort := instrumentationbuilders.GetSpanMetaOrDefault(span, "net.peer.port", redisDefaultPort)
+
+		if redisHostOk {
+			var server = fmt.Sprintf("%s:%s", *redisHost, redisPort)
+			var urn = t.CreateServiceURN(fmt.Sprintf("redis/%s", server))
+			var identifier = fmt.Sprintf("redis://%s", server)
+
+			instrumentationbuilders.OpenTelemetrySpanBuilder(span, server, "Redis", "redis", "db", "consumer", urn, identifier)
+		} else {
+			_ = log.Errorf("[OTEL] [REDIS]: Unable to map the Redis request")
+			return nil
+		}
+	}
+
+	return spans
+}
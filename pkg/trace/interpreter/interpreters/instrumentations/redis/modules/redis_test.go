@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpenTelemetryRedisSpanInterpreter(t *testing.T) {
+	interpreter := MakeOpenTelemetryRedisInterpreter(config.DefaultInterpreterConfig())
+	for _, tc := range []struct {
+		testCase    string
+		interpreter *OpenTelemetryRedisInterpreter
+		trace       []*pb.Span
+		expected    []*pb.Span
+	}{
+		{
+			testCase:    "Span should not be filled in if the Open Telemetry data is invalid or missing",
+			interpreter: interpreter,
+			trace:       []*pb.Span{},
+			expected:    []*pb.Span{},
+		},
+		{
+			testCase:    "Span should be dropped if the server is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":    "redis",
+					"db.statement": "get order:1",
+				},
+			}},
+			expected: nil,
+		},
+		{
+			testCase:    "Open Telemetry data should be mapped to the server with the default port when the port is missing",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":     "redis",
+					"db.statement":  "get order:1",
+					"net.peer.name": "redis-master",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "Redis: redis-master:6379",
+				Service:  "redis",
+				Resource: "redis",
+				Type:     "db",
+				Meta: map[string]string{
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"db.system":               "redis",
+					"db.statement":            "get order:1",
+					"net.peer.name":           "redis-master",
+					"service":                 "redis",
+					"span.kind":               "consumer",
+					"span.serviceName":        "Redis: redis-master:6379",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/redis/redis-master:6379",
+					"sts.service.identifiers": "redis://redis-master:6379",
+				},
+			}},
+		},
+		{
+			testCase:    "Open Telemetry data should be mapped if all the correct meta data has been passed",
+			interpreter: interpreter,
+			trace: []*pb.Span{{
+				Meta: map[string]string{
+					"db.system":     "redis",
+					"net.peer.name": "redis-master",
+					"net.peer.port": "6380",
+				},
+			}},
+			expected: []*pb.Span{{
+				Name:     "Redis: redis-master:6380",
+				Service:  "redis",
+				Resource: "redis",
+				Type:     "db",
+				Meta: map[string]string{
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"db.system":               "redis",
+					"net.peer.name":           "redis-master",
+					"net.peer.port":           "6380",
+					"service":                 "redis",
+					"span.kind":               "consumer",
+					"span.serviceName":        "Redis: redis-master:6380",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/redis/redis-master:6380",
+					"sts.service.identifiers": "redis://redis-master:6380",
+				},
+			}},
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			actual := tc.interpreter.Interpret(tc.trace)
+			assert.EqualValues(t, tc.expected, actual)
+		})
+	}
+}
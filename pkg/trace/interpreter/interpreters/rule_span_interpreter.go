@@ -0,0 +1,181 @@
+package interpreters
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+// RuleSpanInterpreter interprets spans with a rule defined in the configuration
+type RuleSpanInterpreter struct {
+	Interpreter
+	Name                   string
+	source                 string
+	spanType               string
+	instrumentationLibrary string
+	attributes             map[string]*regexp.Regexp
+	serviceURN             *template.Template
+	serviceName            *template.Template
+	serviceType            *template.Template
+	kind                   *template.Template
+	identifiers            []*template.Template
+}
+
+// ruleTemplateData is what the templates of a rule are rendered with
+type ruleTemplateData struct {
+	Service  string
+	Name     string
+	Resource string
+	Type     string
+	meta     map[string]string
+}
+
+// Meta returns a span attribute, an empty string when the span does not have it
+func (d ruleTemplateData) Meta(key string) string {
+	return d.meta[key]
+}
+
+// MakeRuleSpanInterpreter creates a span interpreter for a rule, it fails when an expression or template of the rule
+// is invalid
+func MakeRuleSpanInterpreter(conf *config.Config, rule config.RuleConfig) (*RuleSpanInterpreter, error) {
+	in := &RuleSpanInterpreter{
+		Interpreter:            Interpreter{Config: conf},
+		Name:                   rule.Name,
+		source:                 rule.Match.Source,
+		spanType:               rule.Match.Type,
+		instrumentationLibrary: rule.Match.InstrumentationLibrary,
+		attributes:             make(map[string]*regexp.Regexp, len(rule.Match.Attributes)),
+	}
+
+	for attribute, expr := range rule.Match.Attributes {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for attribute '%s' in span interpreter rule '%s': %s", attribute, rule.Name, err)
+		}
+		in.attributes[attribute] = re
+	}
+
+	var err error
+	if in.serviceURN, err = parseRuleTemplate(rule.Name, "service_urn", rule.ServiceURN); err != nil {
+		return nil, err
+	}
+	if in.serviceName, err = parseRuleTemplate(rule.Name, "service_name", rule.ServiceName); err != nil {
+		return nil, err
+	}
+	if in.serviceType, err = parseRuleTemplate(rule.Name, "service_type", rule.ServiceType); err != nil {
+		return nil, err
+	}
+	if in.kind, err = parseRuleTemplate(rule.Name, "kind", rule.Kind); err != nil {
+		return nil, err
+	}
+	for _, identifier := range rule.Identifiers {
+		tmpl, err := parseRuleTemplate(rule.Name, "identifiers", identifier)
+		if err != nil {
+			return nil, err
+		}
+		in.identifiers = append(in.identifiers, tmpl)
+	}
+
+	return in, nil
+}
+
+// MakeRuleSpanInterpreters creates the span interpreters for the configured rules, invalid rules are logged and skipped
+func MakeRuleSpanInterpreters(conf *config.Config) []*RuleSpanInterpreter {
+	ruleInterpreters := make([]*RuleSpanInterpreter, 0, len(conf.Rules))
+	for _, rule := range conf.Rules {
+		in, err := MakeRuleSpanInterpreter(conf, rule)
+		if err != nil {
+			_ = log.Warnf("[sts] Skipping span interpreter rule: %s", err)
+			continue
+		}
+		ruleInterpreters = append(ruleInterpreters, in)
+	}
+	return ruleInterpreters
+}
+
+// Matches returns whether the span has the source, type, instrumentation library and attributes of the rule
+func (in *RuleSpanInterpreter) Matches(span *pb.Span) bool {
+	if in.source != "" && span.Meta["source"] != in.source {
+		return false
+	}
+	if in.spanType != "" && span.Type != in.spanType {
+		return false
+	}
+	if in.instrumentationLibrary != "" && span.Meta["instrumentation_library"] != in.instrumentationLibrary {
+		return false
+	}
+	for attribute, re := range in.attributes {
+		value, found := span.Meta[attribute]
+		if !found || !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Interpret sets the service urn, name, type, kind and identifiers of the span from the templates of the rule, the
+// fields of which the template is not configured or renders empty are left as they are
+func (in *RuleSpanInterpreter) Interpret(span *pb.Span) *pb.Span {
+	// no meta, add a empty map
+	if span.Meta == nil {
+		span.Meta = map[string]string{}
+	}
+
+	in.setMeta(span, "span.serviceName", in.serviceName)
+	if in.serviceURN != nil {
+		in.setMeta(span, "span.serviceURN", in.serviceURN)
+	} else if in.serviceName != nil {
+		// create the service identifier using the interpreted name
+		span.Meta["span.serviceURN"] = in.CreateServiceURN(span.Meta["span.serviceName"])
+	}
+	in.setMeta(span, "span.serviceType", in.serviceType)
+	in.setMeta(span, "span.kind", in.kind)
+
+	identifiers := make([]string, 0, len(in.identifiers))
+	for _, tmpl := range in.identifiers {
+		if identifier := in.render(span, tmpl); identifier != "" {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	if len(identifiers) > 0 {
+		span.Meta["sts.service.identifiers"] = strings.Join(identifiers, ",")
+	}
+
+	return span
+}
+
+func (in *RuleSpanInterpreter) setMeta(span *pb.Span, key string, tmpl *template.Template) {
+	if tmpl == nil {
+		return
+	}
+	if value := in.render(span, tmpl); value != "" {
+		span.Meta[key] = value
+	}
+}
+
+func (in *RuleSpanInterpreter) render(span *pb.Span, tmpl *template.Template) string {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, ruleTemplateData{Service: span.Service, Name: span.Name, Resource: span.Resource, Type: span.Type, meta: span.Meta})
+	if err != nil {
+		log.Debugf("[sts] Could not render %s of span interpreter rule '%s': %s", tmpl.Name(), in.Name, err)
+		return ""
+	}
+	return buf.String()
+}
+
+func parseRuleTemplate(ruleName, field, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(field).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template in span interpreter rule '%s': %s", field, ruleName, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,121 @@
+package interpreters
+
+import (
+	"testing"
+
+	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+var rabbitMQRule = config.RuleConfig{
+	Name: "rabbitmq",
+	Match: config.MatchConfig{
+		Source:                 "java",
+		InstrumentationLibrary: "io.opentelemetry.rabbitmq",
+		Attributes: map[string]string{
+			"messaging.system":      "^rabbitmq$",
+			"messaging.destination": "",
+		},
+	},
+	ServiceURN:  `urn:service:/rabbitmq:{{ .Meta "messaging.destination" }}`,
+	ServiceName: `RabbitMQ {{ .Meta "messaging.destination" }}`,
+	ServiceType: "rabbitmq",
+	Kind:        "consumer",
+	Identifiers: []string{
+		`urn:rabbitmq:queue/{{ .Meta "messaging.destination" }}`,
+		`{{ .Meta "messaging.url" }}`,
+		`urn:service:/{{ .Service }}`,
+	},
+}
+
+func TestRuleSpanInterpreterMatches(t *testing.T) {
+	in, err := MakeRuleSpanInterpreter(config.DefaultInterpreterConfig(), rabbitMQRule)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		testCase string
+		meta     map[string]string
+		expected bool
+	}{
+		{
+			testCase: "Should match a span with the source, instrumentation library and attributes of the rule",
+			meta: map[string]string{"source": "java", "instrumentation_library": "io.opentelemetry.rabbitmq",
+				"messaging.system": "rabbitmq", "messaging.destination": "orders"},
+			expected: true,
+		},
+		{
+			testCase: "Should not match a span with another source",
+			meta: map[string]string{"source": "python", "instrumentation_library": "io.opentelemetry.rabbitmq",
+				"messaging.system": "rabbitmq", "messaging.destination": "orders"},
+			expected: false,
+		},
+		{
+			testCase: "Should not match a span of which an attribute does not match the expression",
+			meta: map[string]string{"source": "java", "instrumentation_library": "io.opentelemetry.rabbitmq",
+				"messaging.system": "kafka", "messaging.destination": "orders"},
+			expected: false,
+		},
+		{
+			testCase: "Should not match a span without a required attribute",
+			meta: map[string]string{"source": "java", "instrumentation_library": "io.opentelemetry.rabbitmq",
+				"messaging.system": "rabbitmq"},
+			expected: false,
+		},
+	} {
+		t.Run(tc.testCase, func(t *testing.T) {
+			assert.Equal(t, tc.expected, in.Matches(&pb.Span{Meta: tc.meta}))
+		})
+	}
+}
+
+func TestRuleSpanInterpreterInterpret(t *testing.T) {
+	in, err := MakeRuleSpanInterpreter(config.DefaultInterpreterConfig(), rabbitMQRule)
+	assert.NoError(t, err)
+
+	span := in.Interpret(&pb.Span{
+		Service: "order-consumer",
+		Meta: map[string]string{
+			"messaging.system":      "rabbitmq",
+			"messaging.destination": "orders",
+			"span.kind":             "internal",
+		},
+	})
+	assert.Equal(t, map[string]string{
+		"messaging.system":        "rabbitmq",
+		"messaging.destination":   "orders",
+		"span.serviceURN":         "urn:service:/rabbitmq:orders",
+		"span.serviceName":        "RabbitMQ orders",
+		"span.serviceType":        "rabbitmq",
+		"span.kind":               "consumer",
+		"sts.service.identifiers": "urn:rabbitmq:queue/orders,urn:service:/order-consumer",
+	}, span.Meta)
+
+	// without a service urn template the urn is created from the interpreted service name
+	in, err = MakeRuleSpanInterpreter(config.DefaultInterpreterConfig(), config.RuleConfig{
+		Name:        "name-only",
+		ServiceName: `{{ .Service }}-{{ .Meta "peer.service" }}`,
+	})
+	assert.NoError(t, err)
+	span = in.Interpret(&pb.Span{Service: "api", Meta: map[string]string{"peer.service": "billing"}})
+	assert.Equal(t, "api-billing", span.Meta["span.serviceName"])
+	assert.Equal(t, "urn:service:/api-billing", span.Meta["span.serviceURN"])
+}
+
+func TestMakeRuleSpanInterpreters(t *testing.T) {
+	conf := config.DefaultInterpreterConfig()
+	conf.Rules = []config.RuleConfig{
+		{Name: "invalid-expression", Match: config.MatchConfig{Attributes: map[string]string{"http.url": "("}}},
+		{Name: "invalid-template", ServiceName: "{{ .Meta "},
+		rabbitMQRule,
+	}
+
+	_, err := MakeRuleSpanInterpreter(conf, conf.Rules[0])
+	assert.EqualError(t, err, "invalid expression for attribute 'http.url' in span interpreter rule 'invalid-expression': error parsing regexp: missing closing ): `(`")
+	_, err = MakeRuleSpanInterpreter(conf, conf.Rules[1])
+	assert.Error(t, err)
+
+	ruleInterpreters := MakeRuleSpanInterpreters(conf)
+	assert.Len(t, ruleInterpreters, 1)
+	assert.Equal(t, "rabbitmq", ruleInterpreters[0].Name)
+}
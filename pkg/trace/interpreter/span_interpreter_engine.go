@@ -6,9 +6,15 @@ import (
 	interpreterConfig "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
 	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters"
 	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations"
+	amqplibInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/amqplib/modules"
 	awsLambdaInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/aws-lambda/modules"
 	awsSdkInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/aws-sdk/modules"
+	grpcInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/grpc/modules"
 	httpInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/http/modules"
+	kafkajsInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/kafkajs/modules"
+	mongodbInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/mongodb/modules"
+	pgInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/pg/modules"
+	redisInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/redis/modules"
 	stackStateInstrumentationModules "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/interpreters/instrumentations/stackstate/modules"
 	"github.com/StackVista/stackstate-agent/pkg/trace/interpreter/model"
 	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
@@ -22,6 +28,8 @@ type SpanInterpreterEngine struct {
 	DefaultSpanInterpreter *interpreters.DefaultSpanInterpreter
 	SourceInterpreters     map[string]interpreters.SourceInterpreter
 	TypeInterpreters       map[string]interpreters.TypeInterpreter
+	// RuleInterpreters are the interpreters defined in the configuration, they take precedence over the built in ones
+	RuleInterpreters []*interpreters.RuleSpanInterpreter
 }
 
 // MakeSpanInterpreterEngine creates a SpanInterpreterEngine given the config and interpreters
@@ -31,6 +39,7 @@ func MakeSpanInterpreterEngine(config *interpreterConfig.Config, typeIns map[str
 		SpanInterpreterEngineContext: MakeSpanInterpreterEngineContext(config),
 		SourceInterpreters:           sourceIns,
 		TypeInterpreters:             typeIns,
+		RuleInterpreters:             interpreters.MakeRuleSpanInterpreters(config),
 	}
 }
 
@@ -61,6 +70,18 @@ func NewSpanInterpreterEngine(agentConfig *config.AgentConfig) *SpanInterpreterE
 	// Open Telemetry - StackState Instrumentation - Modules
 	sourceIns[stackStateInstrumentationModules.OpenTelemetryStackStateInterpreterSpan] = stackStateInstrumentationModules.MakeOpenTelemetryStackStateInterpreter(interpreterConf)
 
+	// Open Telemetry - Messaging Instrumentations - Modules
+	sourceIns[kafkajsInstrumentationModules.OpenTelemetryKafkaInterpreterSpan] = kafkajsInstrumentationModules.MakeOpenTelemetryKafkaInterpreter(interpreterConf)
+	sourceIns[amqplibInstrumentationModules.OpenTelemetryRabbitMQInterpreterSpan] = amqplibInstrumentationModules.MakeOpenTelemetryRabbitMQInterpreter(interpreterConf)
+
+	// Open Telemetry - gRPC Instrumentation - Modules
+	sourceIns[grpcInstrumentationModules.OpenTelemetryGRPCInterpreterSpan] = grpcInstrumentationModules.MakeOpenTelemetryGRPCInterpreter(interpreterConf)
+
+	// Open Telemetry - Database Instrumentations - Modules
+	sourceIns[redisInstrumentationModules.OpenTelemetryRedisInterpreterSpan] = redisInstrumentationModules.MakeOpenTelemetryRedisInterpreter(interpreterConf)
+	sourceIns[mongodbInstrumentationModules.OpenTelemetryMongoDBInterpreterSpan] = mongodbInstrumentationModules.MakeOpenTelemetryMongoDBInterpreter(interpreterConf)
+	sourceIns[pgInstrumentationModules.OpenTelemetryPostgreSQLInterpreterSpan] = pgInstrumentationModules.MakeOpenTelemetryPostgreSQLInterpreter(interpreterConf)
+
 	return MakeSpanInterpreterEngine(interpreterConf, typeIns, sourceIns)
 }
 
@@ -83,6 +104,13 @@ func (se *SpanInterpreterEngine) Interpret(origTrace pb.Trace) pb.Trace {
 			log.Info("[sts] Interpreting span %+v", span)
 			se.DefaultSpanInterpreter.Interpret(span)
 
+			// interpret the span with the first matching rule of the configuration
+			if ruleInterpreter, found := se.matchRuleInterpreter(span); found {
+				log.Debugf("[sts] interpreted span %v with rule '%s'", span.SpanID, ruleInterpreter.Name)
+				interpretedTrace = append(interpretedTrace, ruleInterpreter.Interpret(span))
+				continue
+			}
+
 			meta, err := se.extractSpanMetadata(span)
 			// no metadata, let's look for the span's source.
 			if err != nil {
@@ -123,3 +151,13 @@ func (se *SpanInterpreterEngine) Interpret(origTrace pb.Trace) pb.Trace {
 
 	return interpretedTrace
 }
+
+// matchRuleInterpreter returns the first rule interpreter that matches the span
+func (se *SpanInterpreterEngine) matchRuleInterpreter(span *pb.Span) (*interpreters.RuleSpanInterpreter, bool) {
+	for _, ruleInterpreter := range se.RuleInterpreters {
+		if ruleInterpreter.Matches(span) {
+			return ruleInterpreter, true
+		}
+	}
+	return nil, false
+}
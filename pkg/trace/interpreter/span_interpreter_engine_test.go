@@ -3,6 +3,7 @@ package interpreter
 import (
 	"github.com/StackVista/stackstate-agent/pkg/trace/api"
 	"github.com/StackVista/stackstate-agent/pkg/trace/config"
+	interpreterConfig "github.com/StackVista/stackstate-agent/pkg/trace/interpreter/config"
 	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -219,6 +220,92 @@ func TestSpanInterpreterEngine(t *testing.T) {
 				},
 			},
 		},
+		{
+			testCase: "Open Telemetry interpret @opentelemetry/instrumentation-kafkajs",
+			span: pb.Span{
+				Name:     "random-name",
+				Start:    1586441095,
+				Duration: 10000000,
+				Resource: api.OpenTelemetrySource,
+				Type:     api.OpenTelemetrySource,
+				Service:  api.OpenTelemetrySource,
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-kafkajs",
+					"source":                  api.OpenTelemetrySource,
+					"span.hostname":           api.OpenTelemetrySource,
+					"messaging.system":        "kafka",
+					"messaging.destination":   "orders",
+				},
+			},
+			expected: pb.Span{
+				Name:     "Kafka Topic: orders",
+				Service:  "kafka.topic",
+				Resource: "kafka.topic",
+				Start:    1586441095,
+				Duration: 10000000,
+				Error:    0,
+				Type:     "queue",
+				Meta: map[string]string{
+					"messaging.system":        "kafka",
+					"messaging.destination":   "orders",
+					"service":                 "kafka.topic",
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"span.hostname":           "openTelemetry",
+					"span.kind":               "consumer",
+					"span.serviceName":        "Kafka Topic: orders",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/kafka/topic/orders",
+					"sts.service.identifiers": "urn:kafka:topic/orders",
+					"instrumentation_library": "@opentelemetry/instrumentation-kafkajs",
+				},
+			},
+		},
+		{
+			testCase: "Open Telemetry interpret @opentelemetry/instrumentation-pg",
+			span: pb.Span{
+				Name:     "random-name",
+				Start:    1586441095,
+				Duration: 10000000,
+				Resource: api.OpenTelemetrySource,
+				Type:     api.OpenTelemetrySource,
+				Service:  api.OpenTelemetrySource,
+				Meta: map[string]string{
+					"instrumentation_library": "@opentelemetry/instrumentation-pg",
+					"source":                  api.OpenTelemetrySource,
+					"span.hostname":           api.OpenTelemetrySource,
+					"db.system":               "postgresql",
+					"db.name":                 "shop",
+					"net.peer.name":           "postgres",
+					"net.peer.port":           "5433",
+				},
+			},
+			expected: pb.Span{
+				Name:     "PostgreSQL Database: shop",
+				Service:  "postgresql.database",
+				Resource: "postgresql.database",
+				Start:    1586441095,
+				Duration: 10000000,
+				Error:    0,
+				Type:     "db",
+				Meta: map[string]string{
+					"db.system":               "postgresql",
+					"db.name":                 "shop",
+					"net.peer.name":           "postgres",
+					"net.peer.port":           "5433",
+					"service":                 "postgresql.database",
+					"sts.origin":              "open-telemetry",
+					"source":                  "open-telemetry",
+					"span.hostname":           "openTelemetry",
+					"span.kind":               "consumer",
+					"span.serviceName":        "PostgreSQL Database: shop",
+					"span.serviceType":        "open-telemetry",
+					"span.serviceURN":         "urn:service:/postgresql/postgres:5433/shop",
+					"sts.service.identifiers": "postgresql://postgres:5433/shop",
+					"instrumentation_library": "@opentelemetry/instrumentation-pg",
+				},
+			},
+		},
 		{
 			testCase: "Open Telemetry interpret @opentelemetry/instrumentation-http",
 			span: pb.Span{
@@ -315,3 +402,38 @@ func TestSpanInterpreterEngine(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanInterpreterEngineRules(t *testing.T) {
+	agentConfig := config.New()
+	agentConfig.InterpreterConfig.Rules = []interpreterConfig.RuleConfig{
+		{
+			Name:        "cockroachdb",
+			Match:       interpreterConfig.MatchConfig{Type: "sql", Attributes: map[string]string{"db.system": "^cockroachdb$"}},
+			ServiceName: `cockroachdb:{{ .Meta "db.name" }}`,
+			ServiceType: "cockroachdb",
+		},
+	}
+	sie := NewSpanInterpreterEngine(agentConfig)
+
+	meta := map[string]string{
+		"span.starttime": "1586441095",
+		"span.hostname":  "hostname",
+		"span.pid":       "10",
+		"span.kind":      "client",
+		"db.system":      "cockroachdb",
+		"db.name":        "orders",
+	}
+	// the rule takes precedence over the built in sql interpreter
+	actual := sie.Interpret([]*pb.Span{{Service: "CockroachDB", Type: "sql", Meta: meta}})
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "cockroachdb:orders", actual[0].Meta["span.serviceName"])
+	assert.Equal(t, "urn:service:/cockroachdb:orders", actual[0].Meta["span.serviceURN"])
+	assert.Equal(t, "cockroachdb", actual[0].Meta["span.serviceType"])
+
+	// spans that do not match a rule are interpreted by the built in interpreters
+	meta["db.system"] = "postgresql"
+	actual = sie.Interpret([]*pb.Span{{Service: "Postgresql", Type: "sql", Meta: meta}})
+	assert.Len(t, actual, 1)
+	assert.Equal(t, "Postgresql", actual[0].Meta["span.serviceName"])
+	assert.Equal(t, "database", actual[0].Meta["span.serviceType"])
+}
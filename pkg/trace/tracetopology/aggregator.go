@@ -0,0 +1,316 @@
+// Package tracetopology aggregates the interpreted spans into service components and the relations between them, and
+// sends those as a topology snapshot with the request rate and error health of the services. [sts]
+package tracetopology
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionforwarder"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/trace/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+)
+
+const (
+	// instanceType is the topology instance type of the trace topology
+	instanceType = "trace-agent"
+	// relationType is the type of the relations between the services that call each other
+	relationType = "calls"
+	// defaultServiceType is the component type of the services of which the spans do not have a service type
+	defaultServiceType = "service"
+	// healthCheckName is the name of the check state with the error health of a service
+	healthCheckName = "Trace error rate"
+	// healthExpiryIntervals is the number of windows after which the health of the services expires when the trace
+	// agent stops sending it
+	healthExpiryIntervals = 3
+)
+
+// service is a service component seen in the spans, with the requests of the current window
+type service struct {
+	urn         string
+	name        string
+	serviceType string
+	identifiers []string
+	requests    int64
+	errors      int64
+	lastSeen    time.Time
+}
+
+// relation is a call from one service to another seen in the spans, with the calls of the current window
+type relation struct {
+	sourceURN string
+	targetURN string
+	calls     int64
+	errors    int64
+	lastSeen  time.Time
+}
+
+// Aggregator aggregates the interpreted spans over a window into service components and relations and sends them as a
+// topology snapshot through the transactional forwarder
+type Aggregator struct {
+	conf      *config.TraceTopologyConfig
+	hostname  string
+	forwarder transactionforwarder.TransactionalForwarder
+
+	mux       sync.Mutex
+	services  map[string]*service
+	relations map[string]*relation
+	// sentEmpty is set when the last snapshot had no components, an empty snapshot is not repeated
+	sentEmpty bool
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewAggregator returns an Aggregator that sends the topology with the given forwarder, the forwarder is stopped when
+// the aggregator is stopped
+func NewAggregator(conf *config.TraceTopologyConfig, hostname string, forwarder transactionforwarder.TransactionalForwarder) *Aggregator {
+	return &Aggregator{
+		conf:      conf,
+		hostname:  hostname,
+		forwarder: forwarder,
+		services:  make(map[string]*service),
+		relations: make(map[string]*relation),
+		exit:      make(chan struct{}),
+	}
+}
+
+// Start sends the topology every interval until the aggregator is stopped
+func (a *Aggregator) Start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				a.Flush(now)
+			case <-a.exit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops sending the topology and stops the forwarder
+func (a *Aggregator) Stop() {
+	close(a.exit)
+	a.wg.Wait()
+	a.forwarder.Stop()
+}
+
+// Add aggregates the interpreted spans of a trace chunk. A span is a request to its service when its parent is not part
+// of the same service, and a call from the service of the parent to its service when the parent is part of the chunk.
+// Chunks are not buffered by trace id, so a call is only seen when the tracer sends the calling and the called span in
+// the same chunk, i.e. not when they are sent by different services or by a partial flush of a long running trace.
+func (a *Aggregator) Add(spans []*pb.Span, now time.Time) {
+	byID := make(map[uint64]*pb.Span, len(spans))
+	for _, span := range spans {
+		byID[span.SpanID] = span
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	for _, span := range spans {
+		urn := span.Meta["span.serviceURN"]
+		if urn == "" {
+			continue
+		}
+		s := a.service(span, urn, now)
+
+		parentURN := ""
+		if parent, found := byID[span.ParentID]; found && span.ParentID != 0 {
+			parentURN = parent.Meta["span.serviceURN"]
+		}
+		if parentURN == urn {
+			// an internal span of the service
+			continue
+		}
+
+		s.requests++
+		if span.Error != 0 {
+			s.errors++
+		}
+		if parentURN != "" {
+			r := a.relation(parentURN, urn, now)
+			r.calls++
+			if span.Error != 0 {
+				r.errors++
+			}
+		}
+	}
+}
+
+// service returns the service of the span, it is created when it was not seen before
+func (a *Aggregator) service(span *pb.Span, urn string, now time.Time) *service {
+	s, found := a.services[urn]
+	if !found {
+		s = &service{urn: urn}
+		a.services[urn] = s
+	}
+	s.name = span.Meta["span.serviceName"]
+	if s.name == "" {
+		s.name = span.Service
+	}
+	s.serviceType = span.Meta["span.serviceType"]
+	if s.serviceType == "" {
+		s.serviceType = defaultServiceType
+	}
+	if identifiers := span.Meta["sts.service.identifiers"]; identifiers != "" {
+		s.identifiers = strings.Split(identifiers, ",")
+	}
+	s.lastSeen = now
+	return s
+}
+
+// relation returns the relation between two services, it is created when it was not seen before
+func (a *Aggregator) relation(sourceURN, targetURN string, now time.Time) *relation {
+	externalID := fmt.Sprintf("%s->%s", sourceURN, targetURN)
+	r, found := a.relations[externalID]
+	if !found {
+		r = &relation{sourceURN: sourceURN, targetURN: targetURN}
+		a.relations[externalID] = r
+	}
+	r.lastSeen = now
+	return r
+}
+
+// Flush sends the services and relations seen within the expiry as a topology snapshot, with the health of the
+// services that received requests in the window, and starts a new window
+func (a *Aggregator) Flush(now time.Time) {
+	payload, ok := a.intakePayload(now)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		_ = log.Errorf("[sts] Could not serialize the trace topology: %s", err)
+		return
+	}
+
+	log.Debugf("[sts] Sending trace topology with %d components and %d relations",
+		len(payload.Topologies[0].Components), len(payload.Topologies[0].Relations))
+	a.forwarder.SubmitTransactionalIntake(transactionforwarder.TransactionalPayload{
+		Body: body,
+		Path: transactional.IntakePath,
+		// the trace topology is not part of a check transaction
+		TransactionActionMap: map[string]transactional.PayloadTransaction{},
+	})
+}
+
+// intakePayload builds the intake payload of the window and resets the window, it returns false when there is nothing
+// to send
+func (a *Aggregator) intakePayload(now time.Time) (transactional.IntakePayload, bool) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	windowSeconds := a.conf.Interval.Seconds()
+	components := make([]topology.Component, 0, len(a.services))
+	checkStates := make([]health.CheckData, 0, len(a.services))
+	for urn, s := range a.services {
+		if now.Sub(s.lastSeen) > a.conf.Expiry {
+			delete(a.services, urn)
+			continue
+		}
+		data := topology.Data{"name": s.name}
+		if len(s.identifiers) > 0 {
+			data["identifiers"] = s.identifiers
+		}
+		components = append(components, topology.Component{
+			ExternalID: s.urn,
+			Type:       topology.Type{Name: s.serviceType},
+			Data:       data,
+		})
+		if s.requests > 0 {
+			checkStates = append(checkStates, a.serviceHealth(s, windowSeconds))
+		}
+		s.requests, s.errors = 0, 0
+	}
+
+	relations := make([]topology.Relation, 0, len(a.relations))
+	for externalID, r := range a.relations {
+		_, sourceFound := a.services[r.sourceURN]
+		_, targetFound := a.services[r.targetURN]
+		if now.Sub(r.lastSeen) > a.conf.Expiry || !sourceFound || !targetFound {
+			delete(a.relations, externalID)
+			continue
+		}
+		relations = append(relations, topology.Relation{
+			ExternalID: externalID,
+			SourceID:   r.sourceURN,
+			TargetID:   r.targetURN,
+			Type:       topology.Type{Name: relationType},
+			Data: topology.Data{
+				"requestRate": float64(r.calls) / windowSeconds,
+				"errorRate":   errorRate(r.calls, r.errors),
+			},
+		})
+		r.calls, r.errors = 0, 0
+	}
+
+	if len(components) == 0 && a.sentEmpty {
+		return transactional.IntakePayload{}, false
+	}
+	a.sentEmpty = len(components) == 0
+
+	intervalSeconds := int(windowSeconds)
+	payload := transactional.NewIntakePayload()
+	payload.InternalHostname = a.hostname
+	payload.Topologies = append(payload.Topologies, topology.Topology{
+		StartSnapshot: true,
+		StopSnapshot:  true,
+		Instance:      topology.Instance{Type: instanceType, URL: a.hostname},
+		Components:    components,
+		Relations:     relations,
+		DeleteIDs:     []string{},
+	})
+	payload.Health = append(payload.Health, health.Health{
+		StartSnapshot: &health.StartSnapshotMetadata{
+			RepeatIntervalS: intervalSeconds,
+			ExpiryIntervalS: healthExpiryIntervals * intervalSeconds,
+		},
+		StopSnapshot: &health.StopSnapshotMetadata{},
+		Stream:       health.Stream{Urn: fmt.Sprintf("urn:health:%s:%s", instanceType, a.hostname)},
+		CheckStates:  checkStates,
+	})
+	return payload, true
+}
+
+// serviceHealth derives the health of a service from the fraction of its requests that failed in the window
+func (a *Aggregator) serviceHealth(s *service, windowSeconds float64) health.CheckData {
+	rate := errorRate(s.requests, s.errors)
+	state := health.Clear
+	switch {
+	case rate >= a.conf.ErrorRateCritical:
+		state = health.Critical
+	case rate >= a.conf.ErrorRateDeviating:
+		state = health.Deviating
+	}
+
+	return health.CheckData{CheckState: &health.CheckState{
+		CheckStateID: fmt.Sprintf("%s:%s", healthCheckName, s.urn),
+		Message: fmt.Sprintf("%.2f requests/s with an error rate of %.1f%% over the last %s",
+			float64(s.requests)/windowSeconds, rate*100, a.conf.Interval),
+		Health:                    state,
+		TopologyElementIdentifier: s.urn,
+		Name:                      healthCheckName,
+	}}
+}
+
+// errorRate returns the fraction of the requests that failed
+func errorRate(requests, errors int64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(errors) / float64(requests)
+}
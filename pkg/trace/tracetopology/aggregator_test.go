@@ -0,0 +1,223 @@
+package tracetopology
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional"
+	"github.com/StackVista/stackstate-agent/pkg/collector/transactional/transactionforwarder"
+	"github.com/StackVista/stackstate-agent/pkg/health"
+	"github.com/StackVista/stackstate-agent/pkg/topology"
+	"github.com/StackVista/stackstate-agent/pkg/trace/config"
+	"github.com/StackVista/stackstate-agent/pkg/trace/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testForwarder keeps the submitted payloads
+type testForwarder struct {
+	payloads []transactionforwarder.TransactionalPayload
+	stopped  bool
+}
+
+func (f *testForwarder) Start() {}
+func (f *testForwarder) Stop()  { f.stopped = true }
+func (f *testForwarder) SubmitTransactionalIntake(payload transactionforwarder.TransactionalPayload) {
+	f.payloads = append(f.payloads, payload)
+}
+
+// testIntake is the intake payload with the check states of the health snapshots as structured check states
+type testIntake struct {
+	InternalHostname string              `json:"internalHostname"`
+	Topologies       []topology.Topology `json:"topologies"`
+	Health           []struct {
+		StartSnapshot *health.StartSnapshotMetadata `json:"start_snapshot"`
+		StopSnapshot  *health.StopSnapshotMetadata  `json:"stop_snapshot"`
+		Stream        health.Stream                 `json:"stream"`
+		CheckStates   []health.CheckState           `json:"check_states"`
+	} `json:"health"`
+}
+
+func (f *testForwarder) intake(t *testing.T, i int) testIntake {
+	require.True(t, len(f.payloads) > i, "expected payload %d to be submitted", i)
+	assert.Equal(t, transactional.IntakePath, f.payloads[i].Path)
+	assert.Empty(t, f.payloads[i].TransactionActionMap)
+
+	var intake testIntake
+	require.NoError(t, json.Unmarshal(f.payloads[i].Body, &intake))
+	require.Len(t, intake.Topologies, 1)
+	require.Len(t, intake.Health, 1)
+	sort.Slice(intake.Topologies[0].Components, func(i, j int) bool {
+		return intake.Topologies[0].Components[i].ExternalID < intake.Topologies[0].Components[j].ExternalID
+	})
+	sort.Slice(intake.Topologies[0].Relations, func(i, j int) bool {
+		return intake.Topologies[0].Relations[i].ExternalID < intake.Topologies[0].Relations[j].ExternalID
+	})
+	sort.Slice(intake.Health[0].CheckStates, func(i, j int) bool {
+		return intake.Health[0].CheckStates[i].CheckStateID < intake.Health[0].CheckStates[j].CheckStateID
+	})
+	return intake
+}
+
+func testConfig() *config.TraceTopologyConfig {
+	return &config.TraceTopologyConfig{
+		Enabled:            true,
+		Interval:           10 * time.Second,
+		Expiry:             30 * time.Second,
+		ErrorRateDeviating: 0.1,
+		ErrorRateCritical:  0.5,
+	}
+}
+
+func span(id, parentID uint64, urn, name, serviceType string, isError bool) *pb.Span {
+	s := &pb.Span{
+		SpanID:   id,
+		ParentID: parentID,
+		Service:  name,
+		Meta: map[string]string{
+			"span.serviceURN":  urn,
+			"span.serviceName": name,
+			"span.serviceType": serviceType,
+		},
+	}
+	if isError {
+		s.Error = 1
+	}
+	return s
+}
+
+// checkoutTrace is a request to the frontend, that calls the checkout service twice, which queries the database
+func checkoutTrace(checkoutError bool) []*pb.Span {
+	return []*pb.Span{
+		span(1, 0, "urn:service:/frontend", "frontend", "service", false),
+		span(2, 1, "urn:service:/frontend", "frontend", "service", false),
+		span(3, 2, "urn:service:/checkout", "checkout", "service", checkoutError),
+		span(4, 3, "urn:service:/postgresql/db:5432/shop", "PostgreSQL Database: shop", "postgresql", false),
+		span(5, 2, "urn:service:/checkout", "checkout", "service", false),
+		// spans without a service urn are not interpreted and left out
+		{SpanID: 6, ParentID: 1, Meta: map[string]string{}},
+	}
+}
+
+func TestAggregatorSnapshot(t *testing.T) {
+	fwd := &testForwarder{}
+	aggregator := NewAggregator(testConfig(), "myhost", fwd)
+	now := time.Now()
+
+	aggregator.Add(checkoutTrace(true), now)
+	aggregator.Add(checkoutTrace(false), now)
+	aggregator.Flush(now.Add(10 * time.Second))
+
+	intake := fwd.intake(t, 0)
+	assert.Equal(t, "myhost", intake.InternalHostname)
+
+	snapshot := intake.Topologies[0]
+	assert.True(t, snapshot.StartSnapshot)
+	assert.True(t, snapshot.StopSnapshot)
+	assert.Equal(t, topology.Instance{Type: "trace-agent", URL: "myhost"}, snapshot.Instance)
+	assert.Equal(t, []topology.Component{
+		{ExternalID: "urn:service:/checkout", Type: topology.Type{Name: "service"}, Data: topology.Data{"name": "checkout"}},
+		{ExternalID: "urn:service:/frontend", Type: topology.Type{Name: "service"}, Data: topology.Data{"name": "frontend"}},
+		{ExternalID: "urn:service:/postgresql/db:5432/shop", Type: topology.Type{Name: "postgresql"}, Data: topology.Data{"name": "PostgreSQL Database: shop"}},
+	}, snapshot.Components)
+	assert.Equal(t, []topology.Relation{
+		{
+			ExternalID: "urn:service:/checkout->urn:service:/postgresql/db:5432/shop",
+			SourceID:   "urn:service:/checkout",
+			TargetID:   "urn:service:/postgresql/db:5432/shop",
+			Type:       topology.Type{Name: "calls"},
+			Data:       topology.Data{"requestRate": 0.2, "errorRate": 0.0},
+		},
+		{
+			ExternalID: "urn:service:/frontend->urn:service:/checkout",
+			SourceID:   "urn:service:/frontend",
+			TargetID:   "urn:service:/checkout",
+			Type:       topology.Type{Name: "calls"},
+			Data:       topology.Data{"requestRate": 0.4, "errorRate": 0.25},
+		},
+	}, snapshot.Relations)
+
+	healthSnapshot := intake.Health[0]
+	assert.Equal(t, health.Stream{Urn: "urn:health:trace-agent:myhost"}, healthSnapshot.Stream)
+	assert.Equal(t, &health.StartSnapshotMetadata{RepeatIntervalS: 10, ExpiryIntervalS: 30}, healthSnapshot.StartSnapshot)
+	assert.Equal(t, &health.StopSnapshotMetadata{}, healthSnapshot.StopSnapshot)
+	assert.Equal(t, []health.CheckState{
+		{
+			CheckStateID:              "Trace error rate:urn:service:/checkout",
+			Message:                   "0.40 requests/s with an error rate of 25.0% over the last 10s",
+			Health:                    health.Deviating,
+			TopologyElementIdentifier: "urn:service:/checkout",
+			Name:                      "Trace error rate",
+		},
+		{
+			CheckStateID:              "Trace error rate:urn:service:/frontend",
+			Message:                   "0.20 requests/s with an error rate of 0.0% over the last 10s",
+			Health:                    health.Clear,
+			TopologyElementIdentifier: "urn:service:/frontend",
+			Name:                      "Trace error rate",
+		},
+		{
+			CheckStateID:              "Trace error rate:urn:service:/postgresql/db:5432/shop",
+			Message:                   "0.20 requests/s with an error rate of 0.0% over the last 10s",
+			Health:                    health.Clear,
+			TopologyElementIdentifier: "urn:service:/postgresql/db:5432/shop",
+			Name:                      "Trace error rate",
+		},
+	}, healthSnapshot.CheckStates)
+}
+
+func TestAggregatorCriticalErrorRate(t *testing.T) {
+	fwd := &testForwarder{}
+	aggregator := NewAggregator(testConfig(), "myhost", fwd)
+	now := time.Now()
+
+	aggregator.Add([]*pb.Span{span(1, 0, "urn:service:/checkout", "checkout", "service", true)}, now)
+	aggregator.Flush(now)
+
+	checkStates := fwd.intake(t, 0).Health[0].CheckStates
+	require.Len(t, checkStates, 1)
+	assert.Equal(t, health.Critical, checkStates[0].Health)
+}
+
+func TestAggregatorExpiry(t *testing.T) {
+	fwd := &testForwarder{}
+	aggregator := NewAggregator(testConfig(), "myhost", fwd)
+	now := time.Now()
+
+	aggregator.Add(checkoutTrace(false), now)
+	aggregator.Flush(now.Add(10 * time.Second))
+	assert.Len(t, fwd.intake(t, 0).Health[0].CheckStates, 3)
+
+	// without requests in the window the services are kept until they expire, without health
+	aggregator.Add([]*pb.Span{span(1, 0, "urn:service:/frontend", "frontend", "service", false)}, now.Add(20*time.Second))
+	aggregator.Flush(now.Add(30 * time.Second))
+	intake := fwd.intake(t, 1)
+	assert.Len(t, intake.Topologies[0].Components, 3)
+	assert.Len(t, intake.Topologies[0].Relations, 2)
+	assert.Len(t, intake.Health[0].CheckStates, 1)
+
+	aggregator.Flush(now.Add(40 * time.Second))
+	intake = fwd.intake(t, 2)
+	assert.Equal(t, []topology.Component{
+		{ExternalID: "urn:service:/frontend", Type: topology.Type{Name: "service"}, Data: topology.Data{"name": "frontend"}},
+	}, intake.Topologies[0].Components)
+	assert.Empty(t, intake.Topologies[0].Relations)
+	assert.Empty(t, intake.Health[0].CheckStates)
+
+	// the empty snapshot that removes the expired services is sent once
+	aggregator.Flush(now.Add(60 * time.Second))
+	intake = fwd.intake(t, 3)
+	assert.Empty(t, intake.Topologies[0].Components)
+	aggregator.Flush(now.Add(70 * time.Second))
+	assert.Len(t, fwd.payloads, 4)
+}
+
+func TestAggregatorStopStopsForwarder(t *testing.T) {
+	fwd := &testForwarder{}
+	aggregator := NewAggregator(testConfig(), "myhost", fwd)
+	aggregator.Start()
+	aggregator.Stop()
+	assert.True(t, fwd.stopped)
+}
@@ -0,0 +1,64 @@
+package clusteragent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apiv1 "github.com/StackVista/stackstate-agent/pkg/clusteragent/api/v1"
+)
+
+const (
+	dcaCheckStatePath       = "api/v1/checkstate"
+	dcaCheckStateStatePath  = dcaCheckStatePath + "/state"
+	dcaCheckStateStatesPath = dcaCheckStatePath + "/states"
+)
+
+// GetCheckState returns the state of a check state key from the check state store of the Cluster Agent
+func (c *DCAClient) GetCheckState(key string) (string, error) {
+	var response apiv1.CheckStateResponse
+	err := c.doCheckStateRequest("GET", dcaCheckStateStatePath, url.Values{"key": {key}}, nil, &response)
+	return response.State, err
+}
+
+// SetCheckState stores the state of a check state key in the check state store of the Cluster Agent
+func (c *DCAClient) SetCheckState(key, state string) error {
+	body, err := json.Marshal(apiv1.CheckStateRequest{State: state})
+	if err != nil {
+		return err
+	}
+	var response apiv1.CheckStateResponse
+	return c.doCheckStateRequest("POST", dcaCheckStateStatePath, url.Values{"key": {key}}, body, &response)
+}
+
+// DeleteCheckState removes the state of a check state key from the check state store of the Cluster Agent
+func (c *DCAClient) DeleteCheckState(key string) error {
+	var response apiv1.CheckStateResponse
+	return c.doCheckStateRequest("DELETE", dcaCheckStateStatePath, url.Values{"key": {key}}, nil, &response)
+}
+
+// ListCheckStates returns the states of a check from the check state store of the Cluster Agent, by their key
+func (c *DCAClient) ListCheckStates(check string) (map[string]string, error) {
+	var response apiv1.CheckStatesResponse
+	err := c.doCheckStateRequest("GET", dcaCheckStateStatesPath, url.Values{"check": {check}}, nil, &response)
+	return response.States, err
+}
+
+func (c *DCAClient) doCheckStateRequest(method, path string, query url.Values, body []byte, result interface{}) error {
+	// https://host:port/api/v1/checkstate/{state,states}?{key,check}=...
+	rawURL := fmt.Sprintf("%s/%s?%s", c.clusterAgentAPIEndpoint, path, query.Encode())
+	req, err := http.NewRequest(method, rawURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header = c.clusterAgentAPIRequestHeaders
+
+	resp, err := c.clusterAgentAPIClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return parseJSONResponse(resp, result)
+}
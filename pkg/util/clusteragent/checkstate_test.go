@@ -0,0 +1,53 @@
+package clusteragent
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *clusterAgentSuite) TestCheckState() {
+	dca, err := newDummyClusterAgent()
+	require.NoError(suite.T(), err)
+
+	dca.rawResponses["/api/v1/checkstate/state"] = `{"key": "mycheck:abc:cursor", "state": "{\"page\":2}"}`
+	dca.rawResponses["/api/v1/checkstate/states"] = `{"check": "mycheck", "states": {"mycheck:abccursor": "{\"page\":2}"}}`
+
+	ts, p, err := dca.StartTLS()
+	defer ts.Close()
+	require.NoError(suite.T(), err)
+	mockConfig.Set("cluster_agent.url", fmt.Sprintf("https://127.0.0.1:%d", p))
+
+	ca, err := GetClusterAgentClient()
+	require.NoError(suite.T(), err)
+	// checking version on init
+	require.NotNil(suite.T(), dca.PopRequest())
+
+	value, err := ca.GetCheckState("mycheck:abc:cursor")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `{"page":2}`, value)
+	request := dca.PopRequest()
+	assert.Equal(suite.T(), "GET", request.Method)
+	assert.Equal(suite.T(), "mycheck:abc:cursor", request.URL.Query().Get("key"))
+
+	require.NoError(suite.T(), ca.SetCheckState("mycheck:abc:cursor", `{"page":2}`))
+	request = dca.PopRequest()
+	assert.Equal(suite.T(), "POST", request.Method)
+	assert.Equal(suite.T(), "mycheck:abc:cursor", request.URL.Query().Get("key"))
+
+	require.NoError(suite.T(), ca.DeleteCheckState("mycheck:abc:cursor"))
+	request = dca.PopRequest()
+	assert.Equal(suite.T(), "DELETE", request.Method)
+
+	states, err := ca.ListCheckStates("mycheck")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]string{"mycheck:abccursor": `{"page":2}`}, states)
+	request = dca.PopRequest()
+	assert.Equal(suite.T(), "mycheck", request.URL.Query().Get("check"))
+
+	// errors of the check state store are returned
+	delete(dca.rawResponses, "/api/v1/checkstate/state")
+	_, err = ca.GetCheckState("mycheck:abc:cursor")
+	assert.Error(suite.T(), err)
+}
@@ -58,6 +58,12 @@ type DCAClientInterface interface {
 	GetClusterCheckConfigs(ctx context.Context, nodeName string) (types.ConfigResponse, error)
 	GetEndpointsCheckConfigs(ctx context.Context, nodeName string) (types.ConfigResponse, error)
 	GetKubernetesClusterID() (string, error)
+
+	// sts
+	GetCheckState(key string) (string, error)
+	SetCheckState(key, state string) error
+	DeleteCheckState(key string) error
+	ListCheckStates(check string) (map[string]string, error)
 }
 
 // DCAClient is required to query the API of Datadog cluster agent
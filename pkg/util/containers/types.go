@@ -149,6 +149,9 @@ type SwarmService struct {
 	TaskContainers []*SwarmTask
 	DesiredTasks   uint64
 	RunningTasks   uint64
+	Networks       []string `json:",omitempty"`
+	Secrets        []string `json:",omitempty"`
+	Configs        []string `json:",omitempty"`
 }
 
 // SwarmTask represents a Swarm TaskContainer definition
@@ -160,4 +163,56 @@ type SwarmTask struct {
 	ContainerSpec   *swarm.ContainerSpec   `json:",omitempty"`
 	ContainerStatus *swarm.ContainerStatus `json:",omitempty"`
 	DesiredState    swarm.TaskState        `json:",omitempty"`
+	NodeID          string                 `json:",omitempty"`
+	Networks        []string               `json:",omitempty"`
+}
+
+// SwarmNode represents a Swarm Node definition
+// sts
+type SwarmNode struct {
+	ID            string
+	Hostname      string
+	Role          swarm.NodeRole         `json:",omitempty"`
+	Availability  swarm.NodeAvailability `json:",omitempty"`
+	State         swarm.NodeState        `json:",omitempty"`
+	Address       string                 `json:",omitempty"`
+	EngineVersion string                 `json:",omitempty"`
+	Labels        map[string]string      `json:",omitempty"`
+	Leader        bool                   `json:",omitempty"`
+	CreatedAt     time.Time              `json:",omitempty"`
+	UpdatedAt     time.Time              `json:",omitempty"`
+}
+
+// SwarmNetwork represents a Swarm overlay Network definition
+// sts
+type SwarmNetwork struct {
+	ID         string
+	Name       string
+	Driver     string            `json:",omitempty"`
+	Scope      string            `json:",omitempty"`
+	Labels     map[string]string `json:",omitempty"`
+	Ingress    bool              `json:",omitempty"`
+	Internal   bool              `json:",omitempty"`
+	Attachable bool              `json:",omitempty"`
+	CreatedAt  time.Time         `json:",omitempty"`
+}
+
+// SwarmSecret represents a Swarm Secret definition, without its data
+// sts
+type SwarmSecret struct {
+	ID        string
+	Name      string
+	Labels    map[string]string `json:",omitempty"`
+	CreatedAt time.Time         `json:",omitempty"`
+	UpdatedAt time.Time         `json:",omitempty"`
+}
+
+// SwarmConfig represents a Swarm Config definition, without its data
+// sts
+type SwarmConfig struct {
+	ID        string
+	Name      string
+	Labels    map[string]string `json:",omitempty"`
+	CreatedAt time.Time         `json:",omitempty"`
+	UpdatedAt time.Time         `json:",omitempty"`
 }
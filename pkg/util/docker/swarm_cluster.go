@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018-present StackState
+
+//go:build docker
+// +build docker
+
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/StackVista/stackstate-agent/pkg/util/containers"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ListSwarmNodes gets a list of all nodes of the swarm cluster using the Docker APIs.
+func (d *DockerUtil) ListSwarmNodes() ([]*containers.SwarmNode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+
+	return dockerSwarmNodes(ctx, d.cli)
+}
+
+// ListSwarmNetworks gets a list of all overlay networks of the swarm cluster using the Docker APIs.
+func (d *DockerUtil) ListSwarmNetworks() ([]*containers.SwarmNetwork, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+
+	return dockerSwarmNetworks(ctx, d.cli)
+}
+
+// ListSwarmSecrets gets a list of all secrets of the swarm cluster using the Docker APIs, without their data.
+func (d *DockerUtil) ListSwarmSecrets() ([]*containers.SwarmSecret, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+
+	return dockerSwarmSecrets(ctx, d.cli)
+}
+
+// ListSwarmConfigs gets a list of all configs of the swarm cluster using the Docker APIs, without their data.
+func (d *DockerUtil) ListSwarmConfigs() ([]*containers.SwarmConfig, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+
+	return dockerSwarmConfigs(ctx, d.cli)
+}
+
+// dockerSwarmNodes returns all the nodes in the swarm cluster
+func dockerSwarmNodes(ctx context.Context, client SwarmServiceAPIClient) ([]*containers.SwarmNode, error) {
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing swarm nodes: %s", err)
+	}
+
+	ret := make([]*containers.SwarmNode, 0, len(nodes))
+	for _, n := range nodes {
+		node := &containers.SwarmNode{
+			ID:            n.ID,
+			Hostname:      n.Description.Hostname,
+			Role:          n.Spec.Role,
+			Availability:  n.Spec.Availability,
+			State:         n.Status.State,
+			Address:       n.Status.Addr,
+			EngineVersion: n.Description.Engine.EngineVersion,
+			Labels:        n.Spec.Labels,
+			CreatedAt:     n.CreatedAt,
+			UpdatedAt:     n.UpdatedAt,
+		}
+		if n.ManagerStatus != nil {
+			node.Leader = n.ManagerStatus.Leader
+		}
+		ret = append(ret, node)
+	}
+
+	return ret, nil
+}
+
+// dockerSwarmNetworks returns all the overlay networks in the swarm cluster
+func dockerSwarmNetworks(ctx context.Context, client SwarmServiceAPIClient) ([]*containers.SwarmNetwork, error) {
+	networks, err := client.NetworkList(ctx, types.NetworkListOptions{Filters: filters.NewArgs(filters.Arg("driver", "overlay"))})
+	if err != nil {
+		return nil, fmt.Errorf("error listing swarm networks: %s", err)
+	}
+
+	ret := make([]*containers.SwarmNetwork, 0, len(networks))
+	for _, n := range networks {
+		ret = append(ret, &containers.SwarmNetwork{
+			ID:         n.ID,
+			Name:       n.Name,
+			Driver:     n.Driver,
+			Scope:      n.Scope,
+			Labels:     n.Labels,
+			Ingress:    n.Ingress,
+			Internal:   n.Internal,
+			Attachable: n.Attachable,
+			CreatedAt:  n.Created,
+		})
+	}
+
+	return ret, nil
+}
+
+// dockerSwarmSecrets returns all the secrets in the swarm cluster, the secret data is never read
+func dockerSwarmSecrets(ctx context.Context, client SwarmServiceAPIClient) ([]*containers.SwarmSecret, error) {
+	secrets, err := client.SecretList(ctx, types.SecretListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing swarm secrets: %s", err)
+	}
+
+	ret := make([]*containers.SwarmSecret, 0, len(secrets))
+	for _, s := range secrets {
+		ret = append(ret, &containers.SwarmSecret{
+			ID:        s.ID,
+			Name:      s.Spec.Name,
+			Labels:    s.Spec.Labels,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: s.UpdatedAt,
+		})
+	}
+
+	return ret, nil
+}
+
+// dockerSwarmConfigs returns all the configs in the swarm cluster, the config data is left out
+func dockerSwarmConfigs(ctx context.Context, client SwarmServiceAPIClient) ([]*containers.SwarmConfig, error) {
+	configs, err := client.ConfigList(ctx, types.ConfigListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing swarm configs: %s", err)
+	}
+
+	ret := make([]*containers.SwarmConfig, 0, len(configs))
+	for _, c := range configs {
+		ret = append(ret, &containers.SwarmConfig{
+			ID:        c.ID,
+			Name:      c.Spec.Name,
+			Labels:    c.Spec.Labels,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+
+	return ret, nil
+}
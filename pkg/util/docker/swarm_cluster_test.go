@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2018-present StackState
+
+//go:build docker
+// +build docker
+
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/util/containers"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	created = time.Date(2021, time.March, 10, 23, 0, 0, 0, time.UTC)
+	updated = time.Date(2021, time.March, 11, 10, 0, 0, 0, time.UTC)
+)
+
+func TestDockerUtil_dockerSwarmNodes(t *testing.T) {
+	mockSwarmServiceClient := &mockSwarmServiceAPIClient{
+		nodeList: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				{
+					ID:   "node1",
+					Meta: swarm.Meta{CreatedAt: created, UpdatedAt: updated},
+					Spec: swarm.NodeSpec{
+						Annotations:  swarm.Annotations{Labels: map[string]string{"zone": "a"}},
+						Role:         swarm.NodeRoleManager,
+						Availability: swarm.NodeAvailabilityActive,
+					},
+					Description: swarm.NodeDescription{
+						Hostname: "manager-1",
+						Engine:   swarm.EngineDescription{EngineVersion: "20.10.7"},
+					},
+					Status:        swarm.NodeStatus{State: swarm.NodeStateReady, Addr: "10.0.0.1"},
+					ManagerStatus: &swarm.ManagerStatus{Leader: true},
+				},
+				{
+					ID:          "node2",
+					Spec:        swarm.NodeSpec{Role: swarm.NodeRoleWorker, Availability: swarm.NodeAvailabilityDrain},
+					Description: swarm.NodeDescription{Hostname: "worker-1"},
+					Status:      swarm.NodeStatus{State: swarm.NodeStateDown},
+				},
+			}, nil
+		},
+	}
+
+	nodes, err := dockerSwarmNodes(nil, mockSwarmServiceClient)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*containers.SwarmNode{
+		{
+			ID:            "node1",
+			Hostname:      "manager-1",
+			Role:          swarm.NodeRoleManager,
+			Availability:  swarm.NodeAvailabilityActive,
+			State:         swarm.NodeStateReady,
+			Address:       "10.0.0.1",
+			EngineVersion: "20.10.7",
+			Labels:        map[string]string{"zone": "a"},
+			Leader:        true,
+			CreatedAt:     created,
+			UpdatedAt:     updated,
+		},
+		{
+			ID:           "node2",
+			Hostname:     "worker-1",
+			Role:         swarm.NodeRoleWorker,
+			Availability: swarm.NodeAvailabilityDrain,
+			State:        swarm.NodeStateDown,
+		},
+	}, nodes)
+}
+
+func TestDockerUtil_dockerSwarmNetworks(t *testing.T) {
+	mockSwarmServiceClient := &mockSwarmServiceAPIClient{
+		networkList: func() ([]types.NetworkResource, error) {
+			return []types.NetworkResource{
+				{ID: "ingress", Name: "ingress", Driver: "overlay", Scope: "swarm", Ingress: true, Created: created},
+				{ID: "backend", Name: "app_backend", Driver: "overlay", Scope: "swarm", Attachable: true,
+					Labels: map[string]string{"com.docker.stack.namespace": "app"}},
+			}, nil
+		},
+	}
+
+	networks, err := dockerSwarmNetworks(nil, mockSwarmServiceClient)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*containers.SwarmNetwork{
+		{ID: "ingress", Name: "ingress", Driver: "overlay", Scope: "swarm", Ingress: true, CreatedAt: created},
+		{ID: "backend", Name: "app_backend", Driver: "overlay", Scope: "swarm", Attachable: true,
+			Labels: map[string]string{"com.docker.stack.namespace": "app"}},
+	}, networks)
+}
+
+func TestDockerUtil_dockerSwarmSecretsAndConfigs(t *testing.T) {
+	mockSwarmServiceClient := &mockSwarmServiceAPIClient{
+		secretList: func() ([]swarm.Secret, error) {
+			return []swarm.Secret{{
+				ID:   "secret1",
+				Meta: swarm.Meta{CreatedAt: created, UpdatedAt: updated},
+				Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "db-password"}, Data: []byte("hunter2")},
+			}}, nil
+		},
+		configList: func() ([]swarm.Config, error) {
+			return []swarm.Config{{
+				ID:   "config1",
+				Meta: swarm.Meta{CreatedAt: created},
+				Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx-conf", Labels: map[string]string{"app": "nginx"}}, Data: []byte("server {}")},
+			}}, nil
+		},
+	}
+
+	secrets, err := dockerSwarmSecrets(nil, mockSwarmServiceClient)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*containers.SwarmSecret{
+		{ID: "secret1", Name: "db-password", CreatedAt: created, UpdatedAt: updated},
+	}, secrets)
+
+	configs, err := dockerSwarmConfigs(nil, mockSwarmServiceClient)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*containers.SwarmConfig{
+		{ID: "config1", Name: "nginx-conf", Labels: map[string]string{"app": "nginx"}, CreatedAt: created},
+	}, configs)
+
+	mockSwarmServiceClient.secretList = func() ([]swarm.Secret, error) {
+		return nil, errors.New("this node is not a swarm manager")
+	}
+	_, err = dockerSwarmSecrets(nil, mockSwarmServiceClient)
+	assert.EqualError(t, err, "error listing swarm secrets: this node is not a swarm manager")
+}
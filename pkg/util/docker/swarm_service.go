@@ -87,6 +87,10 @@ func dockerSwarmServices(ctx context.Context, client SwarmServiceAPIClient) ([]*
 				ContainerSpec:   task.Spec.ContainerSpec,
 				ContainerStatus: task.Status.ContainerStatus,
 				DesiredState:    task.Status.State,
+				NodeID:          task.NodeID,
+			}
+			for _, attachment := range task.NetworksAttachments {
+				taskComponent.Networks = append(taskComponent.Networks, attachment.Network.ID)
 			}
 			log.Debugf("Creating a task %s for service %s", task.Name, s.Spec.Name)
 			tasksComponents = append(tasksComponents, taskComponent)
@@ -109,6 +113,15 @@ func dockerSwarmServices(ctx context.Context, client SwarmServiceAPIClient) ([]*
 			TaskContainers: tasksComponents,
 			DesiredTasks:   desired,
 			RunningTasks:   running,
+			Networks:       serviceNetworks(s),
+		}
+		if containerSpec := s.Spec.TaskTemplate.ContainerSpec; containerSpec != nil {
+			for _, secret := range containerSpec.Secrets {
+				service.Secrets = append(service.Secrets, secret.SecretID)
+			}
+			for _, config := range containerSpec.Configs {
+				service.Configs = append(service.Configs, config.ConfigID)
+			}
 		}
 
 		ret = append(ret, service)
@@ -117,6 +130,26 @@ func dockerSwarmServices(ctx context.Context, client SwarmServiceAPIClient) ([]*
 	return ret, nil
 }
 
+// serviceNetworks returns the ids of the networks a service is attached to, the network targets of a service spec are
+// stored as network ids by the docker daemon
+func serviceNetworks(s swarm.Service) []string {
+	var networks []string
+	seen := make(map[string]bool)
+	add := func(networkID string) {
+		if networkID != "" && !seen[networkID] {
+			seen[networkID] = true
+			networks = append(networks, networkID)
+		}
+	}
+	for _, network := range s.Spec.TaskTemplate.Networks {
+		add(network.Target)
+	}
+	for _, vip := range s.Endpoint.VirtualIPs {
+		add(vip.NetworkID)
+	}
+	return networks
+}
+
 func getActiveNodes(ctx context.Context, client SwarmServiceAPIClient) (map[string]bool, error) {
 	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
 	if err != nil {
@@ -19,12 +19,18 @@ type SwarmServiceAPIClient interface {
 	ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error)
 	TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error)
 	NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error)
+	NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+	SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error)
+	ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error)
 }
 
 type mockSwarmServiceAPIClient struct {
 	serviceList func() ([]swarm.Service, error)
 	taskList    func() ([]swarm.Task, error)
 	nodeList    func() ([]swarm.Node, error)
+	networkList func() ([]types.NetworkResource, error)
+	secretList  func() ([]swarm.Secret, error)
+	configList  func() ([]swarm.Config, error)
 }
 
 func (m *mockSwarmServiceAPIClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
@@ -38,3 +44,15 @@ func (m *mockSwarmServiceAPIClient) TaskList(ctx context.Context, options types.
 func (m *mockSwarmServiceAPIClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
 	return m.nodeList()
 }
+
+func (m *mockSwarmServiceAPIClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	return m.networkList()
+}
+
+func (m *mockSwarmServiceAPIClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	return m.secretList()
+}
+
+func (m *mockSwarmServiceAPIClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	return m.configList()
+}
@@ -112,6 +112,7 @@ var (
 					Image: "stackstate/stackstate-agent-2-test:stac-12057-swarm-topology@sha256:1d463af3e8c407e08bff9f6127e4959d5286a25018ec5269bfad5324815eb367",
 				},
 				DesiredState: swarm.TaskStateRunning,
+				NodeID:       "NodeStateReady",
 			},
 		},
 		DesiredTasks: 1,
@@ -180,3 +181,36 @@ func TestDockerUtil_dockerSwarmServices(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, expectedServices, []*containers.SwarmService{&swarmServices})
 }
+
+func TestDockerUtil_dockerSwarmServicesReferences(t *testing.T) {
+	service := serviceLists
+	service.Spec.TaskTemplate.Networks = []swarm.NetworkAttachmentConfig{{Target: "backend"}}
+	service.Spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{
+		Image:   "nginx",
+		Secrets: []*swarm.SecretReference{{SecretID: "secret1", SecretName: "db-password"}},
+		Configs: []*swarm.ConfigReference{{ConfigID: "config1", ConfigName: "nginx-conf"}},
+	}
+	service.Endpoint = swarm.Endpoint{VirtualIPs: []swarm.EndpointVirtualIP{{NetworkID: "ingress"}, {NetworkID: "backend"}}}
+	task := taskLists
+	task.NetworksAttachments = []swarm.NetworkAttachment{{Network: swarm.Network{ID: "backend"}}}
+
+	mockSwarmServiceClient := &mockSwarmServiceAPIClient{
+		nodeList: func() ([]swarm.Node, error) {
+			return []swarm.Node{nodeLists}, nil
+		},
+		taskList: func() ([]swarm.Task, error) {
+			return []swarm.Task{task}, nil
+		},
+		serviceList: func() ([]swarm.Service, error) {
+			return []swarm.Service{service}, nil
+		},
+	}
+	services, err := dockerSwarmServices(nil, mockSwarmServiceClient)
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, []string{"backend", "ingress"}, services[0].Networks)
+	assert.Equal(t, []string{"secret1"}, services[0].Secrets)
+	assert.Equal(t, []string{"config1"}, services[0].Configs)
+	assert.Equal(t, "NodeStateReady", services[0].TaskContainers[0].NodeID)
+	assert.Equal(t, []string{"backend"}, services[0].TaskContainers[0].Networks)
+}
@@ -305,7 +305,17 @@ func (c *APIClient) connect() error {
 			log.Infof("Could not get apiserver dynamic client: %v", err)
 			return err
 		}
+	} else if config.Datadog.GetBool("collect_kubernetes_topology") {
+		// [sts] the topology check collects the configured custom resources with the dynamic client, without it only
+		// the custom resources are skipped
+		c.DynamicCl, err = getKubeDynamicClient(time.Duration(c.timeoutSeconds) * time.Second)
+		if err != nil {
+			_ = log.Warnf("Could not get apiserver dynamic client, custom resources are not collected: %v", err)
+			c.DynamicCl = nil
+		}
+	}
 
+	if config.Datadog.GetBool("admission_controller.enabled") || config.Datadog.GetBool("compliance_config.enabled") {
 		c.DiscoveryCl, err = getKubeDiscoveryClient(time.Duration(c.timeoutSeconds) * time.Second)
 		if err != nil {
 			log.Infof("Could not get apiserver discovery client: %v", err)
@@ -10,12 +10,19 @@ package apiserver
 
 import (
 	appsV1 "k8s.io/api/apps/v1"
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
 	batchV1 "k8s.io/api/batch/v1"
 	batchV1B1 "k8s.io/api/batch/v1beta1"
 	coreV1 "k8s.io/api/core/v1"
+	discoveryV1 "k8s.io/api/discovery/v1"
 	extensionsV1B "k8s.io/api/extensions/v1beta1"
 	netV1 "k8s.io/api/networking/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	policyV1B1 "k8s.io/api/policy/v1beta1"
+	rbacV1 "k8s.io/api/rbac/v1"
 	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
 )
 
@@ -28,6 +35,7 @@ type APICollectorClient interface {
 	GetCronJobsV1B1() ([]batchV1B1.CronJob, error)
 	GetCronJobsV1() ([]batchV1.CronJob, error)
 	GetEndpoints() ([]coreV1.Endpoints, error)
+	GetEndpointSlices() ([]discoveryV1.EndpointSlice, error)
 	GetNodes() ([]coreV1.Node, error)
 	GetPods() ([]coreV1.Pod, error)
 	GetServices() ([]coreV1.Service, error)
@@ -39,5 +47,16 @@ type APICollectorClient interface {
 	GetPersistentVolumes() ([]coreV1.PersistentVolume, error)
 	GetPersistentVolumeClaims() ([]coreV1.PersistentVolumeClaim, error)
 	GetVolumeAttachments() ([]storageV1.VolumeAttachment, error)
+	GetStorageClasses() ([]storageV1.StorageClass, error)
+	GetHorizontalPodAutoscalers() ([]autoscalingV1.HorizontalPodAutoscaler, error)
+	GetPodDisruptionBudgetsV1B1() ([]policyV1B1.PodDisruptionBudget, error)
+	GetPodDisruptionBudgetsV1() ([]policyV1.PodDisruptionBudget, error)
+	GetNetworkPolicies() ([]netV1.NetworkPolicy, error)
+	GetServiceAccounts() ([]coreV1.ServiceAccount, error)
+	GetRoles() ([]rbacV1.Role, error)
+	GetRoleBindings() ([]rbacV1.RoleBinding, error)
+	GetClusterRoles() ([]rbacV1.ClusterRole, error)
+	GetClusterRoleBindings() ([]rbacV1.ClusterRoleBinding, error)
+	GetCustomResources(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error)
 	GetVersion() (*version.Info, error)
 }
@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at StackState (https://www.stackstate.com/).
+// Copyright 2019-present StackState
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GetCustomResources retrieves all the objects of the given resource in the Kubernetes / OpenShift cluster across all
+// namespaces using the dynamic client.
+func (c *APIClient) GetCustomResources(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	if c.DynamicCl == nil {
+		return []unstructured.Unstructured{}, fmt.Errorf("dynamic client is not initialized, cannot list %s", gvr.String())
+	}
+
+	crList, err := c.DynamicCl.Resource(gvr).Namespace(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []unstructured.Unstructured{}, err
+	}
+
+	return crList.Items, nil
+}
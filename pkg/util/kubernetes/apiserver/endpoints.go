@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "k8s.io/api/core/v1"
+	discoveryV1 "k8s.io/api/discovery/v1"
 
 	dderrors "github.com/StackVista/stackstate-agent/pkg/errors"
 )
@@ -53,3 +54,13 @@ func (c *APIClient) GetEndpoints() ([]v1.Endpoints, error) {
 
 	return endpointList.Items, nil
 }
+
+// GetEndpointSlices retrieves all the discovery/v1 EndpointSlices in the Kubernetes cluster across all namespaces.
+func (c *APIClient) GetEndpointSlices() ([]discoveryV1.EndpointSlice, error) {
+	endpointSliceList, err := c.Cl.DiscoveryV1().EndpointSlices(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []discoveryV1.EndpointSlice{}, err
+	}
+
+	return endpointSliceList.Items, nil
+}
@@ -127,6 +127,41 @@ func (c *APIClient) RunEventCollection(resVer string, lastListTime time.Time, ev
 	}
 }
 
+// WatchEvents starts a watch of the events from the given resource version. Bookmarks are requested, so the resource
+// version to resume from keeps progressing when no events pass the filter. [sts]
+func (c *APIClient) WatchEvents(ctx context.Context, resVer string, filter string) (watch.Interface, error) {
+	return c.Cl.CoreV1().Events(metav1.NamespaceAll).Watch(ctx, metav1.ListOptions{
+		ResourceVersion:     resVer,
+		FieldSelector:       filter,
+		AllowWatchBookmarks: true,
+	})
+}
+
+// RelistEvents lists a page of at most limit events, starting at the given continue token, and returns the ones that
+// are newer than the given resource version, together with the resource version of the list to resume watching from
+// and the continue token of the next page, which is empty on the last page. [sts]
+func (c *APIClient) RelistEvents(ctx context.Context, resVer string, limit int64, continueToken, filter string) (events []*v1.Event, listResVer, nextContinueToken string, err error) {
+	evList, err := c.Cl.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		Limit:         limit,
+		Continue:      continueToken,
+		FieldSelector: filter,
+	})
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	resVerInt, errConv := strconv.Atoi(resVer)
+	if errConv != nil {
+		// resver is "" when nothing was collected before, all the listed events are new
+		resVerInt = 0
+	}
+	listed := make([]*v1.Event, 0, len(evList.Items))
+	for id := range evList.Items {
+		listed = append(listed, &evList.Items[id])
+	}
+	return diffEvents(resVerInt, listed), evList.ResourceVersion, evList.Continue, nil
+}
+
 func diffEvents(latestStoredRV int, fullList []*v1.Event) []*v1.Event {
 	var diffEvents []*v1.Event
 	for _, ev := range fullList {
@@ -0,0 +1,642 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at StackState (https://www.stackstate.com/).
+// Copyright 2019-present StackState
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/StackVista/stackstate-agent/pkg/util/log"
+	appsV1 "k8s.io/api/apps/v1"
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	batchV1B1 "k8s.io/api/batch/v1beta1"
+	coreV1 "k8s.io/api/core/v1"
+	discoveryV1 "k8s.io/api/discovery/v1"
+	extensionsV1B "k8s.io/api/extensions/v1beta1"
+	netV1 "k8s.io/api/networking/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	policyV1B1 "k8s.io/api/policy/v1beta1"
+	rbacV1 "k8s.io/api/rbac/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InformerCollectorClient is an APICollectorClient that serves the cluster resources from informer caches instead of
+// listing them from the API server on every call. An informer is started the first time a resource is requested, so
+// only the resources that are collected are watched. Until an informer has synced, or when it failed to sync within
+// the sync timeout, the resources are listed with the fallback client.
+type InformerCollectorClient struct {
+	fallback    APICollectorClient
+	factory     informers.SharedInformerFactory
+	syncTimeout time.Duration
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+
+	mux         sync.Mutex
+	informers   map[string]cache.SharedIndexInformer
+	watchBroken bool
+}
+
+// NewInformerCollectorClient creates an InformerCollectorClient on top of the given kubernetes client
+func NewInformerCollectorClient(client kubernetes.Interface, fallback APICollectorClient, syncTimeout time.Duration) *InformerCollectorClient {
+	return &InformerCollectorClient{
+		fallback:    fallback,
+		factory:     informers.NewSharedInformerFactory(client, 0),
+		syncTimeout: syncTimeout,
+		stopCh:      make(chan struct{}),
+		informers:   make(map[string]cache.SharedIndexInformer),
+	}
+}
+
+// Stop stops all the informers of this client, it can be called more than once
+func (c *InformerCollectorClient) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+// WatchBroken returns true if any of the informers had to re-list its resources, or could not list or watch them,
+// since the last call to ResetWatchBroken. Changes may have been missed in that case.
+func (c *InformerCollectorClient) WatchBroken() bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.watchBroken
+}
+
+// ResetWatchBroken clears the broken watch state, typically after a full snapshot of the cluster has been taken
+func (c *InformerCollectorClient) ResetWatchBroken() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.watchBroken = false
+}
+
+// synced registers and starts the informer for the resource the first time it is requested and waits for its cache to
+// be synced. It returns false if the cache could not be synced within the sync timeout.
+func (c *InformerCollectorClient) synced(resource string, informer cache.SharedIndexInformer) bool {
+	c.mux.Lock()
+	if _, ok := c.informers[resource]; !ok {
+		err := informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+			_ = log.Warnf("Watch of %s for the topology collection is broken: %s", resource, err)
+			c.mux.Lock()
+			c.watchBroken = true
+			c.mux.Unlock()
+			cache.DefaultWatchErrorHandler(r, err)
+		})
+		if err != nil {
+			_ = log.Warnf("Could not set the watch error handler for %s: %s", resource, err)
+		}
+		c.informers[resource] = informer
+		c.factory.Start(c.stopCh)
+	}
+	c.mux.Unlock()
+
+	if informer.HasSynced() {
+		return true
+	}
+
+	timeoutCh := make(chan struct{})
+	timer := time.AfterFunc(c.syncTimeout, func() { close(timeoutCh) })
+	defer timer.Stop()
+	if !cache.WaitForCacheSync(timeoutCh, informer.HasSynced) {
+		_ = log.Warnf("Informer cache for %s did not sync within %s, listing them from the API server", resource, c.syncTimeout)
+		c.mux.Lock()
+		c.watchBroken = true
+		c.mux.Unlock()
+		return false
+	}
+
+	return true
+}
+
+// GetDaemonSets returns all the DaemonSets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetDaemonSets() ([]appsV1.DaemonSet, error) {
+	informer := c.factory.Apps().V1().DaemonSets()
+	if !c.synced("daemonsets", informer.Informer()) {
+		return c.fallback.GetDaemonSets()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []appsV1.DaemonSet{}, err
+	}
+	result := make([]appsV1.DaemonSet, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetReplicaSets returns all the ReplicaSets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetReplicaSets() ([]appsV1.ReplicaSet, error) {
+	informer := c.factory.Apps().V1().ReplicaSets()
+	if !c.synced("replicasets", informer.Informer()) {
+		return c.fallback.GetReplicaSets()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []appsV1.ReplicaSet{}, err
+	}
+	result := make([]appsV1.ReplicaSet, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetDeployments returns all the Deployments in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetDeployments() ([]appsV1.Deployment, error) {
+	informer := c.factory.Apps().V1().Deployments()
+	if !c.synced("deployments", informer.Informer()) {
+		return c.fallback.GetDeployments()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []appsV1.Deployment{}, err
+	}
+	result := make([]appsV1.Deployment, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetStatefulSets returns all the StatefulSets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetStatefulSets() ([]appsV1.StatefulSet, error) {
+	informer := c.factory.Apps().V1().StatefulSets()
+	if !c.synced("statefulsets", informer.Informer()) {
+		return c.fallback.GetStatefulSets()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []appsV1.StatefulSet{}, err
+	}
+	result := make([]appsV1.StatefulSet, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetJobs returns all the Jobs in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetJobs() ([]batchV1.Job, error) {
+	informer := c.factory.Batch().V1().Jobs()
+	if !c.synced("jobs", informer.Informer()) {
+		return c.fallback.GetJobs()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []batchV1.Job{}, err
+	}
+	result := make([]batchV1.Job, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetCronJobsV1B1 returns all the batch/v1beta1 CronJobs in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetCronJobsV1B1() ([]batchV1B1.CronJob, error) {
+	informer := c.factory.Batch().V1beta1().CronJobs()
+	if !c.synced("cronjobs.v1beta1", informer.Informer()) {
+		return c.fallback.GetCronJobsV1B1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []batchV1B1.CronJob{}, err
+	}
+	result := make([]batchV1B1.CronJob, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetCronJobsV1 returns all the batch/v1 CronJobs in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetCronJobsV1() ([]batchV1.CronJob, error) {
+	informer := c.factory.Batch().V1().CronJobs()
+	if !c.synced("cronjobs", informer.Informer()) {
+		return c.fallback.GetCronJobsV1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []batchV1.CronJob{}, err
+	}
+	result := make([]batchV1.CronJob, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetEndpointSlices returns all the EndpointSlices in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetEndpointSlices() ([]discoveryV1.EndpointSlice, error) {
+	informer := c.factory.Discovery().V1().EndpointSlices()
+	if !c.synced("endpointslices", informer.Informer()) {
+		return c.fallback.GetEndpointSlices()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []discoveryV1.EndpointSlice{}, err
+	}
+	result := make([]discoveryV1.EndpointSlice, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetEndpoints returns all the Endpoints in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetEndpoints() ([]coreV1.Endpoints, error) {
+	informer := c.factory.Core().V1().Endpoints()
+	if !c.synced("endpoints", informer.Informer()) {
+		return c.fallback.GetEndpoints()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Endpoints{}, err
+	}
+	result := make([]coreV1.Endpoints, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetNodes returns all the Nodes in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetNodes() ([]coreV1.Node, error) {
+	informer := c.factory.Core().V1().Nodes()
+	if !c.synced("nodes", informer.Informer()) {
+		return c.fallback.GetNodes()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Node{}, err
+	}
+	result := make([]coreV1.Node, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetPods returns all the Pods in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetPods() ([]coreV1.Pod, error) {
+	informer := c.factory.Core().V1().Pods()
+	if !c.synced("pods", informer.Informer()) {
+		return c.fallback.GetPods()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Pod{}, err
+	}
+	result := make([]coreV1.Pod, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetServices returns all the Services in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetServices() ([]coreV1.Service, error) {
+	informer := c.factory.Core().V1().Services()
+	if !c.synced("services", informer.Informer()) {
+		return c.fallback.GetServices()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Service{}, err
+	}
+	result := make([]coreV1.Service, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetIngressesExtV1B1 returns all the extensions/v1beta1 Ingresses in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetIngressesExtV1B1() ([]extensionsV1B.Ingress, error) {
+	informer := c.factory.Extensions().V1beta1().Ingresses()
+	if !c.synced("ingresses.extensions", informer.Informer()) {
+		return c.fallback.GetIngressesExtV1B1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []extensionsV1B.Ingress{}, err
+	}
+	result := make([]extensionsV1B.Ingress, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetIngressesNetV1 returns all the networking/v1 Ingresses in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetIngressesNetV1() ([]netV1.Ingress, error) {
+	informer := c.factory.Networking().V1().Ingresses()
+	if !c.synced("ingresses", informer.Informer()) {
+		return c.fallback.GetIngressesNetV1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []netV1.Ingress{}, err
+	}
+	result := make([]netV1.Ingress, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetConfigMaps returns all the ConfigMaps in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetConfigMaps() ([]coreV1.ConfigMap, error) {
+	informer := c.factory.Core().V1().ConfigMaps()
+	if !c.synced("configmaps", informer.Informer()) {
+		return c.fallback.GetConfigMaps()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.ConfigMap{}, err
+	}
+	result := make([]coreV1.ConfigMap, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetSecrets returns all the Secrets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetSecrets() ([]coreV1.Secret, error) {
+	informer := c.factory.Core().V1().Secrets()
+	if !c.synced("secrets", informer.Informer()) {
+		return c.fallback.GetSecrets()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Secret{}, err
+	}
+	result := make([]coreV1.Secret, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetNamespaces returns all the Namespaces in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetNamespaces() ([]coreV1.Namespace, error) {
+	informer := c.factory.Core().V1().Namespaces()
+	if !c.synced("namespaces", informer.Informer()) {
+		return c.fallback.GetNamespaces()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.Namespace{}, err
+	}
+	result := make([]coreV1.Namespace, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetPersistentVolumes returns all the PersistentVolumes in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetPersistentVolumes() ([]coreV1.PersistentVolume, error) {
+	informer := c.factory.Core().V1().PersistentVolumes()
+	if !c.synced("persistentvolumes", informer.Informer()) {
+		return c.fallback.GetPersistentVolumes()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.PersistentVolume{}, err
+	}
+	result := make([]coreV1.PersistentVolume, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetPersistentVolumeClaims returns all the PersistentVolumeClaims in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetPersistentVolumeClaims() ([]coreV1.PersistentVolumeClaim, error) {
+	informer := c.factory.Core().V1().PersistentVolumeClaims()
+	if !c.synced("persistentvolumeclaims", informer.Informer()) {
+		return c.fallback.GetPersistentVolumeClaims()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.PersistentVolumeClaim{}, err
+	}
+	result := make([]coreV1.PersistentVolumeClaim, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetVolumeAttachments returns all the VolumeAttachments in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetVolumeAttachments() ([]storageV1.VolumeAttachment, error) {
+	informer := c.factory.Storage().V1().VolumeAttachments()
+	if !c.synced("volumeattachments", informer.Informer()) {
+		return c.fallback.GetVolumeAttachments()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []storageV1.VolumeAttachment{}, err
+	}
+	result := make([]storageV1.VolumeAttachment, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetStorageClasses returns all the StorageClasses in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetStorageClasses() ([]storageV1.StorageClass, error) {
+	informer := c.factory.Storage().V1().StorageClasses()
+	if !c.synced("storageclasses", informer.Informer()) {
+		return c.fallback.GetStorageClasses()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []storageV1.StorageClass{}, err
+	}
+	result := make([]storageV1.StorageClass, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetHorizontalPodAutoscalers returns all the HorizontalPodAutoscalers in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetHorizontalPodAutoscalers() ([]autoscalingV1.HorizontalPodAutoscaler, error) {
+	informer := c.factory.Autoscaling().V1().HorizontalPodAutoscalers()
+	if !c.synced("horizontalpodautoscalers", informer.Informer()) {
+		return c.fallback.GetHorizontalPodAutoscalers()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []autoscalingV1.HorizontalPodAutoscaler{}, err
+	}
+	result := make([]autoscalingV1.HorizontalPodAutoscaler, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetPodDisruptionBudgetsV1B1 returns all the policy/v1beta1 PodDisruptionBudgets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetPodDisruptionBudgetsV1B1() ([]policyV1B1.PodDisruptionBudget, error) {
+	informer := c.factory.Policy().V1beta1().PodDisruptionBudgets()
+	if !c.synced("poddisruptionbudgets.v1beta1", informer.Informer()) {
+		return c.fallback.GetPodDisruptionBudgetsV1B1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []policyV1B1.PodDisruptionBudget{}, err
+	}
+	result := make([]policyV1B1.PodDisruptionBudget, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetPodDisruptionBudgetsV1 returns all the policy/v1 PodDisruptionBudgets in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetPodDisruptionBudgetsV1() ([]policyV1.PodDisruptionBudget, error) {
+	informer := c.factory.Policy().V1().PodDisruptionBudgets()
+	if !c.synced("poddisruptionbudgets", informer.Informer()) {
+		return c.fallback.GetPodDisruptionBudgetsV1()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []policyV1.PodDisruptionBudget{}, err
+	}
+	result := make([]policyV1.PodDisruptionBudget, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetNetworkPolicies returns all the NetworkPolicies in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetNetworkPolicies() ([]netV1.NetworkPolicy, error) {
+	informer := c.factory.Networking().V1().NetworkPolicies()
+	if !c.synced("networkpolicies", informer.Informer()) {
+		return c.fallback.GetNetworkPolicies()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []netV1.NetworkPolicy{}, err
+	}
+	result := make([]netV1.NetworkPolicy, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetServiceAccounts returns all the ServiceAccounts in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetServiceAccounts() ([]coreV1.ServiceAccount, error) {
+	informer := c.factory.Core().V1().ServiceAccounts()
+	if !c.synced("serviceaccounts", informer.Informer()) {
+		return c.fallback.GetServiceAccounts()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []coreV1.ServiceAccount{}, err
+	}
+	result := make([]coreV1.ServiceAccount, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetRoles returns all the Roles in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetRoles() ([]rbacV1.Role, error) {
+	informer := c.factory.Rbac().V1().Roles()
+	if !c.synced("roles", informer.Informer()) {
+		return c.fallback.GetRoles()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []rbacV1.Role{}, err
+	}
+	result := make([]rbacV1.Role, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetRoleBindings returns all the RoleBindings in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetRoleBindings() ([]rbacV1.RoleBinding, error) {
+	informer := c.factory.Rbac().V1().RoleBindings()
+	if !c.synced("rolebindings", informer.Informer()) {
+		return c.fallback.GetRoleBindings()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []rbacV1.RoleBinding{}, err
+	}
+	result := make([]rbacV1.RoleBinding, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetClusterRoles returns all the ClusterRoles in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetClusterRoles() ([]rbacV1.ClusterRole, error) {
+	informer := c.factory.Rbac().V1().ClusterRoles()
+	if !c.synced("clusterroles", informer.Informer()) {
+		return c.fallback.GetClusterRoles()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []rbacV1.ClusterRole{}, err
+	}
+	result := make([]rbacV1.ClusterRole, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetClusterRoleBindings returns all the ClusterRoleBindings in the cluster from the informer cache.
+func (c *InformerCollectorClient) GetClusterRoleBindings() ([]rbacV1.ClusterRoleBinding, error) {
+	informer := c.factory.Rbac().V1().ClusterRoleBindings()
+	if !c.synced("clusterrolebindings", informer.Informer()) {
+		return c.fallback.GetClusterRoleBindings()
+	}
+	items, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return []rbacV1.ClusterRoleBinding{}, err
+	}
+	result := make([]rbacV1.ClusterRoleBinding, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+	return result, nil
+}
+
+// GetCustomResources retrieves the custom resources with the fallback client, they are not backed by an informer.
+func (c *InformerCollectorClient) GetCustomResources(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	return c.fallback.GetCustomResources(gvr)
+}
+
+// GetVersion retrieves the version of the Kubernetes cluster with the fallback client.
+func (c *InformerCollectorClient) GetVersion() (*version.Info, error) {
+	return c.fallback.GetVersion()
+}
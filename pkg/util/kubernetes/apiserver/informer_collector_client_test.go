@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at StackState (https://www.stackstate.com/).
+// Copyright 2019-present StackState
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testPod(name string) *coreV1.Pod {
+	return &coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+func TestInformerCollectorClient_ServesFromCache(t *testing.T) {
+	client := fake.NewSimpleClientset(testPod("pod-1"))
+	informerClient := NewInformerCollectorClient(client, &APIClient{Cl: client}, 5*time.Second)
+	defer informerClient.Stop()
+
+	pods, err := informerClient.GetPods()
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "pod-1", pods[0].Name)
+
+	// changes are picked up by the watch
+	_, err = client.CoreV1().Pods("default").Create(context.TODO(), testPod("pod-2"), metaV1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		pods, err := informerClient.GetPods()
+		return err == nil && len(pods) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, informerClient.WatchBroken())
+}
+
+func TestInformerCollectorClient_FallbackWhenNotSynced(t *testing.T) {
+	brokenClient := fake.NewSimpleClientset()
+	brokenClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("api server unavailable")
+	})
+	fallbackClient := fake.NewSimpleClientset(testPod("pod-1"))
+	informerClient := NewInformerCollectorClient(brokenClient, &APIClient{Cl: fallbackClient}, 500*time.Millisecond)
+	defer informerClient.Stop()
+
+	pods, err := informerClient.GetPods()
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.True(t, informerClient.WatchBroken())
+
+	informerClient.ResetWatchBroken()
+	assert.False(t, informerClient.WatchBroken())
+}
+
+func TestInformerCollectorClient_StopTwice(t *testing.T) {
+	client := fake.NewSimpleClientset(testPod("pod-1"))
+	informerClient := NewInformerCollectorClient(client, &APIClient{Cl: client}, 5*time.Second)
+
+	// the check is cancelled when it is unscheduled after it was stopped
+	informerClient.Stop()
+	assert.NotPanics(t, informerClient.Stop)
+}
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at StackState (https://www.stackstate.com/).
+// Copyright 2019-present StackState
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"context"
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
+	coreV1 "k8s.io/api/core/v1"
+	netV1 "k8s.io/api/networking/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	policyV1B1 "k8s.io/api/policy/v1beta1"
+	rbacV1 "k8s.io/api/rbac/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetHorizontalPodAutoscalers retrieves all the HorizontalPodAutoscalers in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetHorizontalPodAutoscalers() ([]autoscalingV1.HorizontalPodAutoscaler, error) {
+	hpaList, err := c.Cl.AutoscalingV1().HorizontalPodAutoscalers(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []autoscalingV1.HorizontalPodAutoscaler{}, err
+	}
+
+	return hpaList.Items, nil
+}
+
+// GetPodDisruptionBudgetsV1B1 retrieves all the PodDisruptionBudgets in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetPodDisruptionBudgetsV1B1() ([]policyV1B1.PodDisruptionBudget, error) {
+	pdbList, err := c.Cl.PolicyV1beta1().PodDisruptionBudgets(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []policyV1B1.PodDisruptionBudget{}, err
+	}
+
+	return pdbList.Items, nil
+}
+
+// GetPodDisruptionBudgetsV1 retrieves all the PodDisruptionBudgets in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetPodDisruptionBudgetsV1() ([]policyV1.PodDisruptionBudget, error) {
+	pdbList, err := c.Cl.PolicyV1().PodDisruptionBudgets(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []policyV1.PodDisruptionBudget{}, err
+	}
+
+	return pdbList.Items, nil
+}
+
+// GetNetworkPolicies retrieves all the NetworkPolicies in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetNetworkPolicies() ([]netV1.NetworkPolicy, error) {
+	npList, err := c.Cl.NetworkingV1().NetworkPolicies(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []netV1.NetworkPolicy{}, err
+	}
+
+	return npList.Items, nil
+}
+
+// GetServiceAccounts retrieves all the ServiceAccounts in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetServiceAccounts() ([]coreV1.ServiceAccount, error) {
+	saList, err := c.Cl.CoreV1().ServiceAccounts(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []coreV1.ServiceAccount{}, err
+	}
+
+	return saList.Items, nil
+}
+
+// GetRoles retrieves all the Roles in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetRoles() ([]rbacV1.Role, error) {
+	roleList, err := c.Cl.RbacV1().Roles(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []rbacV1.Role{}, err
+	}
+
+	return roleList.Items, nil
+}
+
+// GetRoleBindings retrieves all the RoleBindings in the Kubernetes / OpenShift cluster across all namespaces.
+func (c *APIClient) GetRoleBindings() ([]rbacV1.RoleBinding, error) {
+	rbList, err := c.Cl.RbacV1().RoleBindings(metaV1.NamespaceAll).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []rbacV1.RoleBinding{}, err
+	}
+
+	return rbList.Items, nil
+}
+
+// GetClusterRoles retrieves all the ClusterRoles in the Kubernetes / OpenShift cluster.
+func (c *APIClient) GetClusterRoles() ([]rbacV1.ClusterRole, error) {
+	crList, err := c.Cl.RbacV1().ClusterRoles().List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []rbacV1.ClusterRole{}, err
+	}
+
+	return crList.Items, nil
+}
+
+// GetClusterRoleBindings retrieves all the ClusterRoleBindings in the Kubernetes / OpenShift cluster.
+func (c *APIClient) GetClusterRoleBindings() ([]rbacV1.ClusterRoleBinding, error) {
+	crbList, err := c.Cl.RbacV1().ClusterRoleBindings().List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []rbacV1.ClusterRoleBinding{}, err
+	}
+
+	return crbList.Items, nil
+}
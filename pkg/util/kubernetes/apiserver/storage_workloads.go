@@ -74,3 +74,13 @@ func (c *APIClient) GetVolumeAttachments() ([]storageV1.VolumeAttachment, error)
 
 	return vaList.Items, nil
 }
+
+// GetStorageClasses() retrieves all the StorageClasses in the Kubernetes / OpenShift cluster.
+func (c *APIClient) GetStorageClasses() ([]storageV1.StorageClass, error) {
+	scList, err := c.Cl.StorageV1().StorageClasses().List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return []storageV1.StorageClass{}, err
+	}
+
+	return scList.Items, nil
+}
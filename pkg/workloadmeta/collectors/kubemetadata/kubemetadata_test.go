@@ -108,6 +108,22 @@ func (f *FakeDCAClient) GetCFAppsMetadataForNode(nodename string) (map[string][]
 	panic("implement me")
 }
 
+func (f *FakeDCAClient) GetCheckState(key string) (string, error) {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) SetCheckState(key, state string) error {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) DeleteCheckState(key string) error {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) ListCheckStates(check string) (map[string]string, error) {
+	panic("implement me")
+}
+
 func TestKubeMetadataCollector_getMetadata(t *testing.T) {
 	type fields struct {
 		dcaClient           clusteragent.DCAClientInterface